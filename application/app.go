@@ -3,16 +3,38 @@ package application
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
+const tracerShutdownTimeout = 5 * time.Second
+
 type App struct {
 	Config *configuration.Configuration
 	DB     *sql.DB
+	Logger *slog.Logger
+	// MetricsRegistry collects the metrics exposed on /metrics
+	MetricsRegistry *prometheus.Registry
+	// TracerProvider creates the spans of the service, it is also the otel global
+	TracerProvider *sdktrace.TracerProvider
+	// FieldCipher encrypts the personal data columns
+	FieldCipher *FieldCipher
+	// Scorecard grades the loan proposals
+	Scorecard *model.Scorecard
+	// CandidateScorecard is dark launched behind the scoring.candidate_scorecard flag, nil when not configured
+	CandidateScorecard *model.Scorecard
 }
 
 func SetupApp(ctx context.Context) (*App, error) {
@@ -26,34 +48,90 @@ func SetupApp(ctx context.Context) (*App, error) {
 	}
 	app.Config = &config
 
+	// setup logger, also used by the slog and log package functions
+	app.Logger = NewLogger(app.Config.Log, os.Stdout)
+	slog.SetDefault(app.Logger)
+
+	// setup field encryption
+	fieldCipher, err := LoadFieldCipher(app.Config.Encryption.KeyfilePath)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load encryption keyfile", "error", err)
+		return nil, err
+	}
+	app.FieldCipher = fieldCipher
+
+	// setup credit scorecards, proposals must not be graded with a scorecard other than the configured one
+	app.Scorecard, app.CandidateScorecard, err = LoadScorecards(app.Config.Scoring)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load scorecard", "error", err)
+		return nil, err
+	}
+
+	// setup tracing
+	tracerProvider, err := NewTracerProvider(ctx, app.Config)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to setup tracing", "error", err)
+		return nil, err
+	}
+	app.TracerProvider = tracerProvider
+
 	// setup database
 	db, err := CreateDBConnection(app.Config.Database)
 	if err != nil {
-		log.Println("failed to create DB connection", err)
+		slog.ErrorContext(ctx, "failed to create DB connection", "error", err)
 		return nil, err
 	}
 	app.DB = db
 
+	// setup metrics
+	app.MetricsRegistry = prometheus.NewRegistry()
+	app.MetricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(app.DB, app.Config.Database.Name),
+	)
+
 	return app, nil
 }
 
 func (app *App) Close() error {
 	app.DB.Close()
 
+	// flush the spans still batched
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+	if err := app.TracerProvider.Shutdown(ctx); err != nil {
+		slog.Error("tracer provider shut down error", "error", err)
+	}
+
 	return nil
 }
 
+// CreateDBConnection opens the connection pool. The connection string is built as a URL so credentials with
+// spaces or quotes are escaped.
 func CreateDBConnection(dbConf configuration.Database) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password='%s' dbname=%s search_path=%s sslmode=%s",
-		dbConf.Host,
-		dbConf.Port,
-		dbConf.Username,
-		dbConf.Password,
-		dbConf.Name,
-		dbConf.Schema,
-		dbConf.SSLMode,
-	)
+	query := url.Values{"sslmode": {dbConf.SSLMode}}
+	if dbConf.Schema != "" {
+		query.Set("search_path", dbConf.Schema)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dbConf.Username, dbConf.Password),
+		Host:     net.JoinHostPort(dbConf.Host, strconv.Itoa(dbConf.Port)),
+		Path:     "/" + dbConf.Name,
+		RawQuery: query.Encode(),
+	}
+
+	db, err := sql.Open(dbConf.Driver, dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(dbConf.MaxOpenConns)
+	db.SetMaxIdleConns(dbConf.MaxIdleConns)
+	db.SetConnMaxLifetime(dbConf.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(dbConf.ConnMaxIdleTime)
 
-	return sql.Open(dbConf.Driver, dsn)
+	return db, nil
 }
@@ -0,0 +1,232 @@
+package application
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ciphertextVersion prefixes every ciphertext so the format can change later.
+const ciphertextVersion = "v1"
+
+// encryptionKeySize is the AES-256 key size of the key encryption keys, the data keys and the blind index key.
+const encryptionKeySize = 32
+
+var (
+	ErrEncryptionKeyNotFound = errors.New("encryption key is not found")
+	ErrCiphertextInvalid     = errors.New("ciphertext is invalid")
+)
+
+// keyfile is the JSON keyfile, keys are base64 encoded 32 byte keys. Old keys stay in the file until the
+// re-encryption job has moved every value to the active key.
+type keyfile struct {
+	ActiveKeyID   string            `json:"active_key_id"`
+	Keys          map[string]string `json:"keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// FieldCipher encrypts single column values with envelope encryption: every value gets its own data key,
+// which is stored next to the value encrypted with a key encryption key of the keyfile. The id of that key
+// is part of the ciphertext, so values encrypted with a rotated key can still be read.
+type FieldCipher struct {
+	activeKeyID   string
+	keys          map[string][]byte
+	blindIndexKey []byte
+}
+
+// LoadFieldCipher reads the keyfile at path.
+func LoadFieldCipher(path string) (*FieldCipher, error) {
+	keyfileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFieldCipher(keyfileBytes)
+}
+
+// NewFieldCipher parses the keyfile and checks that the active key is in it.
+func NewFieldCipher(keyfileBytes []byte) (*FieldCipher, error) {
+	kf := keyfile{}
+	if err := json.Unmarshal(keyfileBytes, &kf); err != nil {
+		return nil, fmt.Errorf("keyfile is not valid JSON: %w", err)
+	}
+
+	fc := &FieldCipher{
+		activeKeyID: kf.ActiveKeyID,
+		keys:        make(map[string][]byte, len(kf.Keys)),
+	}
+
+	for keyID, encodedKey := range kf.Keys {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, fmt.Errorf("keyfile key id %q must not be empty or contain a colon", keyID)
+		}
+
+		key, err := decodeEncryptionKey(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("keyfile key %q %w", keyID, err)
+		}
+		fc.keys[keyID] = key
+	}
+
+	if _, ok := fc.keys[fc.activeKeyID]; !ok {
+		return nil, fmt.Errorf("keyfile active key %q is not one of its keys", fc.activeKeyID)
+	}
+
+	blindIndexKey, err := decodeEncryptionKey(kf.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("keyfile blind index key %w", err)
+	}
+	fc.blindIndexKey = blindIndexKey
+
+	return fc, nil
+}
+
+func decodeEncryptionKey(encodedKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("must be %d base64 encoded bytes", encryptionKeySize)
+	}
+
+	return key, nil
+}
+
+// ActiveKeyID is the id of the key new values are encrypted with.
+func (fc *FieldCipher) ActiveKeyID() string {
+	return fc.activeKeyID
+}
+
+// Encrypt encrypts the value of field with the active key, field is authenticated with the value so a
+// ciphertext cannot be moved to another column. An empty value stays empty.
+func (fc *FieldCipher) Encrypt(field string, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	sealedValue, err := seal(dataKey, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	sealedDataKey, err := seal(fc.keys[fc.activeKeyID], dataKey, []byte(fc.activeKeyID))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		ciphertextVersion,
+		fc.activeKeyID,
+		base64.RawStdEncoding.EncodeToString(sealedDataKey),
+		base64.RawStdEncoding.EncodeToString(sealedValue),
+	}, ":"), nil
+}
+
+// Decrypt reverses Encrypt with whichever key of the keyfile the value was encrypted with.
+func (fc *FieldCipher) Decrypt(field string, ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	parts := strings.Split(ciphertext, ":")
+	if len(parts) != 4 || parts[0] != ciphertextVersion {
+		return "", ErrCiphertextInvalid
+	}
+
+	keyID := parts[1]
+	key, ok := fc.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrEncryptionKeyNotFound, keyID)
+	}
+
+	sealedDataKey, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrCiphertextInvalid
+	}
+
+	sealedValue, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", ErrCiphertextInvalid
+	}
+
+	dataKey, err := open(key, sealedDataKey, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, sealedValue, []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// BlindIndex is a keyed hash of the value of field for equality lookups and unique constraints on encrypted
+// columns. Values are trimmed and lowercased first, so lookups ignore case and surrounding spaces. An empty
+// value has no index.
+func (fc *FieldCipher) BlindIndex(field string, value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, fc.blindIndexKey)
+	mac.Write([]byte(field + ":" + value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// seal encrypts with AES-GCM and prepends the random nonce.
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCiphertextInvalid
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrCiphertextInvalid
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/frencius/loan-service/configuration"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID stores the id of the request being served, it is added to every log written with the context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+type clientIPKey struct{}
+
+// WithClientIP stores the address of the client making the request.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+func ClientIP(ctx context.Context) string {
+	clientIP, _ := ctx.Value(clientIPKey{}).(string)
+	return clientIP
+}
+
+const redacted = "[REDACTED]"
+
+// redactedKeys are attribute keys whose values are personal data and never logged.
+var redactedKeys = map[string]bool{
+	"nik":          true,
+	"npwp":         true,
+	"email":        true,
+	"phone":        true,
+	"phone_number": true,
+}
+
+// piiPatterns find personal data inside free text such as error messages.
+var piiPatterns = []*regexp.Regexp{
+	// email
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	// NPWP, formatted 99.999.999.9-999.999
+	regexp.MustCompile(`\b\d{2}\.\d{3}\.\d{3}\.\d-\d{3}\.\d{3}\b`),
+	// NIK and unformatted NPWP, 15 or 16 digits
+	regexp.MustCompile(`\b\d{15,16}\b`),
+	// Indonesian phone number
+	regexp.MustCompile(`(\+62|\b62|\b0)8\d{7,11}\b`),
+}
+
+// NewLogger creates the JSON (or text) logger of the service writing to w. Records carry the request id and
+// user id of their context and personal data is redacted.
+func NewLogger(config configuration.Log, w io.Writer) *slog.Logger {
+	level := slog.LevelInfo
+	_ = level.UnmarshalText([]byte(config.Level))
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	var handler slog.Handler = slog.NewJSONHandler(w, options)
+	if config.Format == "text" {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// contextHandler adds the request id, user id and trace id of the context to the record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (ch *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+
+	if userID, ok := ctx.Value("userID").(string); ok && userID != "" {
+		record.AddAttrs(slog.String("user_id", userID))
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()), slog.String("span_id", spanContext.SpanID().String()))
+	}
+
+	return ch.Handler.Handle(ctx, record)
+}
+
+func (ch *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: ch.Handler.WithAttrs(attrs)}
+}
+
+func (ch *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: ch.Handler.WithGroup(name)}
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if redactedKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactPII(attr.Value.String()))
+	case slog.KindAny:
+		return slog.Attr{Key: attr.Key, Value: redactAny(attr.Value.Any())}
+	}
+
+	return attr
+}
+
+// redactAny redacts the personal data of a value logged with slog.Any. Structs, pointers and maps other than
+// string maps are never logged because their fields can hold personal data the patterns do not find, e.g. a
+// name, types that have safe fields to log implement slog.LogValuer instead.
+func redactAny(value any) slog.Value {
+	switch value := value.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case error:
+		return slog.StringValue(RedactPII(value.Error()))
+	case fmt.Stringer:
+		return slog.StringValue(RedactPII(value.String()))
+	case []string:
+		values := make([]string, 0, len(value))
+		for _, text := range value {
+			values = append(values, RedactPII(text))
+		}
+
+		return slog.AnyValue(values)
+	case map[string]string:
+		values := make(map[string]string, len(value))
+		for key, text := range value {
+			if redactedKeys[strings.ToLower(key)] {
+				text = redacted
+			}
+			values[key] = RedactPII(text)
+		}
+
+		return slog.AnyValue(values)
+	}
+
+	kind := reflect.TypeOf(value).Kind()
+	if kind == reflect.Slice || kind == reflect.Array {
+		switch reflect.TypeOf(value).Elem().Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			return slog.AnyValue(value)
+		}
+	}
+
+	return slog.StringValue(redacted)
+}
+
+// RedactPII masks emails, NIK, NPWP and phone numbers found in text.
+func RedactPII(text string) string {
+	for _, pattern := range piiPatterns {
+		text = pattern.ReplaceAllString(text, redacted)
+	}
+
+	return text
+}
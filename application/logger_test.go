@@ -0,0 +1,122 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+)
+
+var _ = Describe("RedactPII", func() {
+	DescribeTable("should mask personal data inside text",
+		func(text string, expected string) {
+			Expect(application.RedactPII(text)).To(Equal(expected))
+		},
+		Entry("NIK", "borrower 3171234567890123 is blocked", "borrower [REDACTED] is blocked"),
+		Entry("formatted NPWP", "npwp 01.234.567.8-901.234 is taken", "npwp [REDACTED] is taken"),
+		Entry("unformatted NPWP", "npwp 012345678901234 is taken", "npwp [REDACTED] is taken"),
+		Entry("email", "send to budi.santoso+loan@example.co.id failed", "send to [REDACTED] failed"),
+		Entry("local phone number", "call 081234567890 later", "call [REDACTED] later"),
+		Entry("international phone number", "call +6281234567890 later", "call [REDACTED] later"),
+		Entry("several values", "budi@example.com/081234567890", "[REDACTED]/[REDACTED]"),
+		Entry("ids and amounts", "loan 42 of 5000000 is approved", "loan 42 of 5000000 is approved"),
+	)
+})
+
+var _ = Describe("NewLogger", func() {
+	var (
+		output *bytes.Buffer
+		record map[string]any
+	)
+
+	logged := func(args ...any) map[string]any {
+		logger := application.NewLogger(configuration.Log{Level: "info", Format: "json"}, output)
+		logger.InfoContext(context.Background(), "message", args...)
+
+		record = map[string]any{}
+		Expect(json.Unmarshal(output.Bytes(), &record)).To(Succeed())
+
+		return record
+	}
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+	})
+
+	DescribeTable("should redact the values of personal data keys",
+		func(key string) {
+			Expect(logged(key, "budi")).To(HaveKeyWithValue(key, "[REDACTED]"))
+		},
+		Entry("nik", "nik"),
+		Entry("npwp", "npwp"),
+		Entry("email", "email"),
+		Entry("phone", "phone"),
+		Entry("phone_number", "phone_number"),
+		Entry("upper case key", "NIK"),
+	)
+
+	It("should redact personal data inside strings", func() {
+		Expect(logged("reason", "duplicate nik 3171234567890123")).To(HaveKeyWithValue("reason", "duplicate nik [REDACTED]"))
+	})
+
+	It("should redact personal data inside errors", func() {
+		err := fmt.Errorf("create borrower: %w", errors.New("email budi@example.com is taken"))
+
+		Expect(logged("error", err)).To(HaveKeyWithValue("error", "create borrower: email [REDACTED] is taken"))
+	})
+
+	It("should redact personal data inside string slices and maps", func() {
+		record := logged(
+			"loan_ids", []string{"1", "081234567890"},
+			"config", map[string]string{"APP_NAME": "loan-service", "email": "budi", "NOTE": "budi@example.com"},
+		)
+
+		Expect(record).To(HaveKeyWithValue("loan_ids", []any{"1", "[REDACTED]"}))
+		Expect(record).To(HaveKeyWithValue("config", map[string]any{
+			"APP_NAME": "loan-service",
+			"email":    "[REDACTED]",
+			"NOTE":     "[REDACTED]",
+		}))
+	})
+
+	It("should not log structs, pointers and maps whose fields may hold personal data", func() {
+		type borrower struct {
+			Name string
+			NIK  string
+		}
+
+		record := logged(
+			"borrower", borrower{Name: "Budi Santoso", NIK: "3171234567890123"},
+			"borrower_ref", &borrower{Name: "Budi Santoso"},
+			"attributes", map[string]any{"name": "Budi Santoso"},
+		)
+
+		Expect(record).To(HaveKeyWithValue("borrower", "[REDACTED]"))
+		Expect(record).To(HaveKeyWithValue("borrower_ref", "[REDACTED]"))
+		Expect(record).To(HaveKeyWithValue("attributes", "[REDACTED]"))
+		Expect(output.String()).NotTo(ContainSubstring("Budi"))
+	})
+
+	It("should keep values without personal data", func() {
+		record := logged("loan_id", "42", "count", 3, "amounts", []float64{1.5, 2})
+
+		Expect(record).To(HaveKeyWithValue("loan_id", "42"))
+		Expect(record).To(HaveKeyWithValue("count", float64(3)))
+		Expect(record).To(HaveKeyWithValue("amounts", []any{1.5, float64(2)}))
+	})
+
+	It("should add the request id of the context", func() {
+		logger := application.NewLogger(configuration.Log{Level: "info", Format: "json"}, output)
+		logger.InfoContext(application.WithRequestID(context.Background(), "request-1"), "message")
+
+		Expect(json.Unmarshal(output.Bytes(), &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("request_id", "request-1"))
+	})
+})
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+)
+
+// LoadScorecards reads the configured scorecards, it is the only place the files are read and checked. The
+// scorecard is the built-in one when SCORING_SCORECARD_PATH is empty, the candidate is nil when
+// SCORING_CANDIDATE_SCORECARD_PATH is. A file that does not load fails, proposals must not be graded with a
+// fallback scorecard.
+func LoadScorecards(config configuration.Scoring) (scorecard *model.Scorecard, candidate *model.Scorecard, err error) {
+	scorecard = model.DefaultScorecard()
+	if config.ScorecardPath != "" {
+		scorecard, err = model.LoadScorecard(config.ScorecardPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("SCORING_SCORECARD_PATH is not a valid scorecard: %w", err)
+		}
+	}
+
+	if config.CandidateScorecardPath != "" {
+		candidate, err = model.LoadScorecard(config.CandidateScorecardPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("SCORING_CANDIDATE_SCORECARD_PATH is not a valid scorecard: %w", err)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package application_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+)
+
+var _ = Describe("LoadScorecards", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeScorecard := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+
+		return path
+	}
+
+	It("should use the built-in scorecard without a candidate when no file is configured", func() {
+		scorecard, candidate, err := application.LoadScorecards(configuration.Scoring{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scorecard).To(Equal(model.DefaultScorecard()))
+		Expect(candidate).To(BeNil())
+	})
+
+	It("should load the scorecard files", func() {
+		content := `{"version": "v2", "grades": [{"grade": "A", "min_score": 0, "min_interest_rate": 10, "max_interest_rate": 12}]}`
+
+		scorecard, candidate, err := application.LoadScorecards(configuration.Scoring{
+			ScorecardPath:          writeScorecard("scorecard.json", content),
+			CandidateScorecardPath: writeScorecard("candidate.json", content),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scorecard.Version).To(Equal("v2"))
+		Expect(candidate.Version).To(Equal("v2"))
+	})
+
+	DescribeTable("should reject a missing, malformed or invalid scorecard file",
+		func(scorecardContent string, candidateContent string, expected string) {
+			config := configuration.Scoring{ScorecardPath: filepath.Join(dir, "missing.json")}
+			if scorecardContent != "" {
+				config.ScorecardPath = writeScorecard("scorecard.json", scorecardContent)
+			}
+			if candidateContent != "" {
+				config.CandidateScorecardPath = writeScorecard("candidate.json", candidateContent)
+			}
+
+			scorecard, candidate, err := application.LoadScorecards(config)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+			Expect(scorecard).To(BeNil())
+			Expect(candidate).To(BeNil())
+		},
+		Entry("missing", "", "", "SCORING_SCORECARD_PATH is not a valid scorecard"),
+		Entry("malformed", `{"version": `, "", "SCORING_SCORECARD_PATH is not a valid scorecard"),
+		Entry("invalid candidate", `{"version": "v2", "grades": [{"grade": "A", "min_score": 0, "min_interest_rate": 10, "max_interest_rate": 12}]}`,
+			`{"version": "no grades"}`, "SCORING_CANDIDATE_SCORECARD_PATH is not a valid scorecard: scorecard is invalid"),
+	)
+})
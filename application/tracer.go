@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/frencius/loan-service/configuration"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+// NewTracerProvider creates the tracer provider of the service and installs it, along with the W3C trace
+// context and baggage propagators, as the otel global. Spans are only exported when an exporter is configured.
+func NewTracerProvider(ctx context.Context, config *configuration.Configuration) (*sdktrace.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	serviceResource, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.AppName),
+		semconv.ServiceVersion(config.AppVersion),
+		semconv.DeploymentEnvironment(config.Environment),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Tracing.SampleRatio))),
+	}
+
+	switch config.Tracing.Exporter {
+	case TracingExporterNone, "":
+	case TracingExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		// synchronous, so spans show up next to the logs of the request
+		options = append(options, sdktrace.WithSyncer(exporter))
+	case TracingExporterOTLP:
+		exporterOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Tracing.OTLPEndpoint)}
+		if config.Tracing.OTLPInsecure {
+			exporterOptions = append(exporterOptions, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Tracing.Exporter)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider, nil
+}
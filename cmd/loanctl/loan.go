@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+func getLoan(ctx context.Context, app *application.App, args []string) (err error) {
+	if len(args) != 1 {
+		return errors.New("usage: loan get <loan-id>")
+	}
+	if err = parseUUIDArg("loan_id", args[0]); err != nil {
+		return
+	}
+
+	loanResponse, err := service.NewLoanService(app).GetLoan(ctx, &model.GetLoanRequest{LoanID: args[0]})
+	if err != nil {
+		return
+	}
+
+	return printJSON(loanResponse)
+}
+
+func forceLoanState(ctx context.Context, app *application.App, args []string) (err error) {
+	flags := flag.NewFlagSet("loan force-state", flag.ContinueOnError)
+	reason := flags.String("reason", "", "why the loan is forced, required")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: loan force-state -reason <reason> <loan-id> <state>")
+	}
+	if err = parseUUIDArg("loan_id", flags.Arg(0)); err != nil {
+		return
+	}
+
+	updateLoanStateResponse, err := service.NewLoanService(app).ForceLoanState(ctx, &model.ForceLoanStateRequest{
+		LoanID: flags.Arg(0),
+		State:  flags.Arg(1),
+		Reason: *reason,
+	})
+	if err != nil {
+		return
+	}
+
+	return printJSON(updateLoanStateResponse)
+}
+
+// resendAgreementEmail queues the agreement_ready email of the loan to its investors again. It does not
+// generate the agreement, there is no agreement generator in this tree yet.
+func resendAgreementEmail(ctx context.Context, app *application.App, args []string) (err error) {
+	if len(args) != 1 {
+		return errors.New("usage: loan resend-agreement-email <loan-id>")
+	}
+	if err = parseUUIDArg("loan_id", args[0]); err != nil {
+		return
+	}
+
+	err = service.NewNotificationService(app).ResendAgreementReady(ctx, args[0])
+	if err != nil {
+		return
+	}
+
+	fmt.Println("agreement ready email queued")
+
+	return
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
@@ -0,0 +1,171 @@
+// Command loanctl is the operations tool of the loan service. It runs against the database configured by
+// the same environment as the service and acts as an employee, the operator, who is recorded on every change
+// and on the audit log entry each command writes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/frencius/loan-service/service"
+	"github.com/google/uuid"
+)
+
+const usage = `usage: loanctl [-operator employee-id] <command>
+
+commands:
+  loan get <loan-id>
+  loan force-state -reason <reason> <loan-id> <state>
+  loan resend-agreement-email <loan-id>
+  outbox replay (-event <event-id> | -aggregate <aggregate-id>)
+  report loans [-state <state>]
+  report summary
+  report audit
+  personal-data reencrypt
+  seed
+
+The operator defaults to LOANCTL_OPERATOR, seed also needs LOANCTL_ALLOW_SEED=true.`
+
+func main() {
+	flags := flag.NewFlagSet("loanctl", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	operatorID := flags.String("operator", os.Getenv("LOANCTL_OPERATOR"), "employee id of the operator")
+	_ = flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx := application.WithRequestID(context.Background(), "loanctl-"+uuid.NewString())
+	app, err := application.SetupApp(ctx)
+	if err != nil {
+		log.Fatalf("failed to initiate app: %v", err)
+	}
+	// stdout carries the output of the command, e.g. a CSV report
+	app.Logger = application.NewLogger(app.Config.Log, os.Stderr)
+	slog.SetDefault(app.Logger)
+
+	loanctl := &cli{
+		app:                app,
+		employeeRepository: repository.NewEmployeeRepository(app),
+		auditService:       service.NewAuditService(app),
+		allowSeed:          os.Getenv("LOANCTL_ALLOW_SEED") == "true",
+	}
+	err = loanctl.run(ctx, *operatorID, args)
+	app.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loanctl %s: %v\n", strings.Join(args, " "), err)
+		os.Exit(1)
+	}
+}
+
+// cli runs the commands, the operator is checked against employeeRepository and every command is recorded
+// through auditService.
+type cli struct {
+	app                *application.App
+	employeeRepository repository.IEmployeeRepository
+	auditService       service.IAuditService
+	// allowSeed is set by LOANCTL_ALLOW_SEED=true, seed writes fixed records and must be asked for explicitly
+	allowSeed bool
+}
+
+func (c *cli) run(ctx context.Context, operatorID string, args []string) (err error) {
+	command := args[0]
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		command += " " + args[1]
+	}
+
+	ctx, err = c.withOperator(ctx, operatorID)
+	if err != nil {
+		return
+	}
+	defer func() { err = c.audit(ctx, command, args, err) }()
+
+	switch command {
+	case "loan get":
+		return getLoan(ctx, c.app, args[2:])
+	case "loan force-state":
+		return forceLoanState(ctx, c.app, args[2:])
+	case "loan resend-agreement-email":
+		return resendAgreementEmail(ctx, c.app, args[2:])
+	case "outbox replay":
+		return replayEvents(ctx, c.app, args[2:])
+	case "report loans":
+		return reportLoans(ctx, c.app, args[2:])
+	case "report summary":
+		return reportSummary(ctx, c.app)
+	case "report audit":
+		return reportAudit(ctx, c.app)
+	case "personal-data reencrypt":
+		return reencryptPersonalData(ctx, c.app)
+	case "seed":
+		if !c.allowSeed {
+			return errors.New("seed is disabled, set LOANCTL_ALLOW_SEED=true to run it")
+		}
+		return seed(ctx, c.app)
+	default:
+		return fmt.Errorf("unknown command\n%s", usage)
+	}
+}
+
+// withOperator checks that the operator is an active employee and acts as them, the services read the
+// actor from the userID context value as they do for API requests.
+func (c *cli) withOperator(ctx context.Context, operatorID string) (context.Context, error) {
+	if operatorID == "" {
+		return ctx, errors.New("the operator is required, pass -operator or set LOANCTL_OPERATOR")
+	}
+	if _, err := uuid.Parse(operatorID); err != nil {
+		return ctx, fmt.Errorf("operator %q is not an employee id", operatorID)
+	}
+
+	employee, err := c.employeeRepository.GetEmployeeByID(ctx, operatorID)
+	if err != nil {
+		return ctx, err
+	}
+	if !employee.IsActive {
+		return ctx, model.ErrorEmployeeInactive
+	}
+
+	return context.WithValue(ctx, "userID", operatorID), nil
+}
+
+// audit records the command with its arguments and outcome in the audit log under the operator and returns
+// the error of the command. A command whose record cannot be written fails, an operator must not be able to
+// act unaudited.
+func (c *cli) audit(ctx context.Context, command string, args []string, err error) error {
+	operatorCommand := &model.OperatorCommand{
+		Command: command,
+		Args:    args,
+		Outcome: model.OperatorCommandSucceeded,
+	}
+	if err != nil {
+		operatorCommand.Outcome = model.OperatorCommandFailed
+		operatorCommand.Error = err.Error()
+	}
+
+	if auditErr := c.auditService.RecordOperatorCommand(ctx, operatorCommand); auditErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to audit the command: %w", auditErr))
+	}
+
+	return err
+}
+
+// parseUUIDArg checks a positional id the way the HTTP controllers check path ids.
+func parseUUIDArg(name string, value string) error {
+	if _, err := uuid.Parse(value); err != nil {
+		return model.InvalidFieldError(name, "uuid")
+	}
+
+	return nil
+}
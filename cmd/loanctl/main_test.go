@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("loanctl", func() {
+	const operatorID = "7d3f1c2a-5b4e-4f6a-9c8d-1e2f3a4b5c6d"
+
+	var (
+		mockCtrl         *gomock.Controller
+		mockEmployeeRepo *mock.MockIEmployeeRepository
+		mockAuditSvc     *mock.MockIAuditService
+		loanctl          *cli
+		ctx              context.Context
+		expectAudit      func(command string, outcome string) *gomock.Call
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEmployeeRepo = mock.NewMockIEmployeeRepository(mockCtrl)
+		mockAuditSvc = mock.NewMockIAuditService(mockCtrl)
+		ctx = application.WithRequestID(context.Background(), "loanctl-1")
+
+		loanctl = &cli{
+			app:                &application.App{Config: &configuration.Configuration{}},
+			employeeRepository: mockEmployeeRepo,
+			auditService:       mockAuditSvc,
+		}
+
+		expectAudit = func(command string, outcome string) *gomock.Call {
+			return mockAuditSvc.EXPECT().
+				RecordOperatorCommand(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, operatorCommand *model.OperatorCommand) error {
+					Expect(ctx.Value("userID")).To(Equal(operatorID))
+					Expect(operatorCommand.Command).To(Equal(command))
+					Expect(operatorCommand.Outcome).To(Equal(outcome))
+					return nil
+				})
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("withOperator", func() {
+		It("should act as an active employee", func() {
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(ctx, operatorID).Return(&model.Employee{ID: operatorID, IsActive: true}, nil)
+
+			operatorCtx, err := loanctl.withOperator(ctx, operatorID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorCtx.Value("userID")).To(Equal(operatorID))
+		})
+
+		It("should require the operator", func() {
+			_, err := loanctl.withOperator(ctx, "")
+			Expect(err).To(MatchError(ContainSubstring("the operator is required")))
+		})
+
+		It("should reject an operator that is not an employee id", func() {
+			_, err := loanctl.withOperator(ctx, "root")
+			Expect(err).To(MatchError(`operator "root" is not an employee id`))
+		})
+
+		It("should reject an unknown employee", func() {
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(ctx, operatorID).Return(nil, model.ErrorEmployeeNotFound)
+
+			_, err := loanctl.withOperator(ctx, operatorID)
+			Expect(err).To(MatchError(model.ErrorEmployeeNotFound))
+		})
+
+		It("should reject an inactive employee", func() {
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(ctx, operatorID).Return(&model.Employee{ID: operatorID, IsActive: false}, nil)
+
+			_, err := loanctl.withOperator(ctx, operatorID)
+			Expect(err).To(MatchError(model.ErrorEmployeeInactive))
+		})
+	})
+
+	Context("run", func() {
+		BeforeEach(func() {
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(gomock.Any(), operatorID).Return(&model.Employee{ID: operatorID, IsActive: true}, nil).AnyTimes()
+		})
+
+		It("should not run nor audit a command without a valid operator", func() {
+			err := loanctl.run(ctx, "", []string{"seed"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should validate the operator of seed", func() {
+			loanctl.allowSeed = true
+
+			err := loanctl.run(ctx, "not-an-id", []string{"seed"})
+			Expect(err).To(MatchError(`operator "not-an-id" is not an employee id`))
+		})
+
+		It("should refuse seed without LOANCTL_ALLOW_SEED and audit the attempt", func() {
+			expectAudit("seed", model.OperatorCommandFailed)
+
+			err := loanctl.run(ctx, operatorID, []string{"seed"})
+			Expect(err).To(MatchError(ContainSubstring("LOANCTL_ALLOW_SEED=true")))
+		})
+
+		It("should refuse seed in production even when allowed", func() {
+			loanctl.allowSeed = true
+			loanctl.app.Config.Environment = "production"
+			expectAudit("seed", model.OperatorCommandFailed)
+
+			err := loanctl.run(ctx, operatorID, []string{"seed"})
+			Expect(err).To(MatchError("seed is disabled in production"))
+		})
+
+		It("should audit an invalid argument with its error", func() {
+			expectAudit("loan force-state", model.OperatorCommandFailed).
+				Do(func(_ context.Context, operatorCommand *model.OperatorCommand) {
+					Expect(operatorCommand.Args).To(Equal([]string{"loan", "force-state", "-reason", "typo", "not-a-loan", "proposed"}))
+					Expect(operatorCommand.Error).To(Equal(model.ErrorValidationFailed.Error()))
+				})
+
+			err := loanctl.run(ctx, operatorID, []string{"loan", "force-state", "-reason", "typo", "not-a-loan", "proposed"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should audit an unknown command", func() {
+			expectAudit("loan resend-agreement", model.OperatorCommandFailed)
+
+			err := loanctl.run(ctx, operatorID, []string{"loan", "resend-agreement", operatorID})
+			Expect(err).To(MatchError(ContainSubstring("unknown command")))
+		})
+
+		It("should fail the command if it cannot be audited", func() {
+			mockAuditSvc.EXPECT().RecordOperatorCommand(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+
+			err := loanctl.run(ctx, operatorID, []string{"report", "loans", "-state", "unknown"})
+			Expect(err).To(MatchError(model.ErrorLoanStateInvalid))
+			Expect(err).To(MatchError(ContainSubstring("failed to audit the command: db down")))
+		})
+	})
+
+	Context("loanReportRow", func() {
+		It("should write the loan in the order of the header with UTC times", func() {
+			createdAt := time.Date(2025, 6, 1, 17, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+			row := loanReportRow(&model.Loan{
+				ID:              "loan-1",
+				State:           model.LoanStateProposed,
+				BorrowerID:      "borrower-1",
+				ProductID:       "product-1",
+				TenorMonths:     6,
+				PrincipalAmount: 5000000,
+				InterestRate:    18,
+				ROIRate:         12,
+				Risk:            &model.RiskAssessment{Grade: model.RiskGradeB},
+				CreatedAt:       &createdAt,
+			})
+			Expect(row).To(HaveLen(len(loanReportHeader)))
+			Expect(row).To(Equal([]string{
+				"loan-1", "proposed", "borrower-1", "product-1", "6", "5000000.00", "0.00",
+				"18.00", "12.00", "B", "2025-06-01T10:00:00Z", "", "", "",
+			}))
+		})
+	})
+})
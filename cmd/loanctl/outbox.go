@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+func replayEvents(ctx context.Context, app *application.App, args []string) (err error) {
+	flags := flag.NewFlagSet("outbox replay", flag.ContinueOnError)
+	eventID := flags.String("event", "", "id of the event to replay")
+	aggregateID := flags.String("aggregate", "", "replay every event of this aggregate, e.g. a loan id")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: outbox replay (-event <event-id> | -aggregate <aggregate-id>)")
+	}
+	if *eventID != "" {
+		if err = parseUUIDArg("event_id", *eventID); err != nil {
+			return
+		}
+	}
+	if *aggregateID != "" {
+		if err = parseUUIDArg("aggregate_id", *aggregateID); err != nil {
+			return
+		}
+	}
+
+	replayed, err := service.NewEventService(app).ReplayEvents(ctx, &model.ReplayEventsRequest{
+		EventID:     *eventID,
+		AggregateID: *aggregateID,
+	})
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("%d events queued for the relay\n", replayed)
+
+	return
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/service"
+)
+
+// reencryptPersonalData runs the re-encryption job right away, e.g. after the active key was rotated.
+func reencryptPersonalData(ctx context.Context, app *application.App) (err error) {
+	reencrypted, err := service.NewPersonalDataService(app).ReencryptPersonalData(ctx)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("re-encrypted %d borrowers and investors with key %s\n", reencrypted, app.FieldCipher.ActiveKeyID())
+
+	return
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/frencius/loan-service/service"
+)
+
+// reportStates lists the states in lifecycle order.
+var reportStates = []model.LoanState{
+	model.LoanStateProposed,
+	model.LoanStateApproved,
+	model.LoanStatePublished,
+	model.LoanStateInvested,
+	model.LoanStateDisbursed,
+	model.LoanStateRejected,
+	model.LoanStateCanceled,
+}
+
+var loanReportHeader = []string{
+	"loan_id", "state", "borrower_id", "product_id", "tenor_months", "principal_amount", "total_invested_amount",
+	"interest_rate", "roi_rate", "risk_grade", "created_at", "published_at", "funding_deadline", "disbursed_at",
+}
+
+// reportLoans writes the loans as CSV to stdout, every state unless one is given.
+func reportLoans(ctx context.Context, app *application.App, args []string) (err error) {
+	flags := flag.NewFlagSet("report loans", flag.ContinueOnError)
+	state := flags.String("state", "", "only loans in this state")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	states := reportStates
+	if *state != "" {
+		if !model.ValidLoanState[model.LoanState(*state)] {
+			return model.ErrorLoanStateInvalid
+		}
+		states = []model.LoanState{model.LoanState(*state)}
+	}
+
+	loanRepository := repository.NewLoanRepository(app)
+	writer := csv.NewWriter(os.Stdout)
+	if err = writer.Write(loanReportHeader); err != nil {
+		return
+	}
+
+	for _, loanState := range states {
+		var loans []*model.Loan
+		loans, err = loanRepository.ListLoansByState(ctx, loanState)
+		if err != nil {
+			return
+		}
+
+		for _, loan := range loans {
+			if err = writer.Write(loanReportRow(loan)); err != nil {
+				return
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func loanReportRow(loan *model.Loan) []string {
+	riskGrade := ""
+	if loan.Risk != nil {
+		riskGrade = string(loan.Risk.Grade)
+	}
+
+	return []string{
+		loan.ID,
+		string(loan.State),
+		loan.BorrowerID,
+		loan.ProductID,
+		strconv.FormatInt(loan.TenorMonths, 10),
+		strconv.FormatFloat(loan.PrincipalAmount, 'f', 2, 64),
+		strconv.FormatFloat(loan.TotalInvestedAmount, 'f', 2, 64),
+		strconv.FormatFloat(loan.InterestRate, 'f', 2, 64),
+		strconv.FormatFloat(loan.ROIRate, 'f', 2, 64),
+		riskGrade,
+		formatReportTime(loan.CreatedAt),
+		formatReportTime(loan.PublishedAt),
+		formatReportTime(loan.FundingDeadline),
+		formatReportTime(loan.DisbursedAt),
+	}
+}
+
+func formatReportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// reportSummary prints the number of loans per state.
+func reportSummary(ctx context.Context, app *application.App) (err error) {
+	counts, err := repository.NewLoanRepository(app).CountLoansByState(ctx)
+	if err != nil {
+		return
+	}
+
+	var total int64
+	for _, loanState := range reportStates {
+		fmt.Printf("%-10s %d\n", loanState, counts[loanState])
+		total += counts[loanState]
+	}
+	fmt.Printf("%-10s %d\n", "total", total)
+
+	return
+}
+
+// reportAudit verifies the hash chain of the audit log and prints the result, a broken chain fails the command.
+func reportAudit(ctx context.Context, app *application.App) (err error) {
+	auditVerificationResponse, err := service.NewAuditService(app).VerifyAuditLogs(ctx)
+	if err != nil {
+		return
+	}
+
+	if err = printJSON(auditVerificationResponse); err != nil {
+		return
+	}
+	if !auditVerificationResponse.Valid {
+		return errors.New("the audit log chain is broken")
+	}
+
+	return
+}
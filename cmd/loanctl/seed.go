@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/frencius/loan-service/service"
+)
+
+// seedEmployees, seedBorrowers and seedInvestors are created through SeedService, which audits them under the
+// operator, the repositories encrypt the personal data of the borrowers and investors.
+var (
+	seedEmployees = []*model.Employee{
+		{Name: "Loanctl Seed", EmployeeNumber: "SEED-001"},
+		{Name: "Field Officer Seed", EmployeeNumber: "SEED-002"},
+	}
+	seedBorrowers = []*model.Borrower{
+		{ID: "5eed0000-0000-4000-8000-000000000101", Name: "Budi Seed", Address: "Jl. Merdeka 1, Jakarta", Occupation: "Shop owner", NIK: "3171000000000101", DOB: seedDate(1985, time.April, 12), Email: "budi.seed@example.com"},
+		{ID: "5eed0000-0000-4000-8000-000000000102", Name: "Sari Seed", Address: "Jl. Asia Afrika 2, Bandung", Occupation: "Caterer", NIK: "3273000000000102", DOB: seedDate(1990, time.September, 30), Email: "sari.seed@example.com"},
+	}
+	seedInvestors = []*model.Investor{
+		{ID: "5eed0000-0000-4000-8000-000000000201", Name: "Andi Seed", NIK: "3171000000000201", NPWP: "0000000000000201", Email: "andi.seed@example.com", PhoneNumber: "081200000201"},
+		{ID: "5eed0000-0000-4000-8000-000000000202", Name: "Dewi Seed", NIK: "3171000000000202", NPWP: "0000000000000202", Email: "dewi.seed@example.com", PhoneNumber: "081200000202"},
+	}
+)
+
+func seedDate(year int, month time.Month, day int) *time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// seedLoans are proposed through LoanService, so they are scored and emit their events like API loans.
+var seedLoans = []model.CreateLoanRequest{
+	{BorrowerID: "5eed0000-0000-4000-8000-000000000101", TenorMonths: 6, PrincipalAmount: 5000000, InterestRate: 18, ROIRate: 12},
+	{BorrowerID: "5eed0000-0000-4000-8000-000000000102", TenorMonths: 12, PrincipalAmount: 25000000, InterestRate: 20, ROIRate: 14},
+}
+
+const seedProductCode = "PRODUCTIVE_SME"
+
+// seed creates employees, borrowers and investors and proposes a loan per borrower. The people are only
+// created once, the loans are proposed on every run.
+func seed(ctx context.Context, app *application.App) (err error) {
+	if app.Config.Environment == "production" {
+		return errors.New("seed is disabled in production")
+	}
+
+	if err = seedPeople(ctx, service.NewSeedService(app)); err != nil {
+		return
+	}
+
+	products, err := repository.NewLoanProductRepository(app).ListLoanProducts(ctx, true)
+	if err != nil {
+		return
+	}
+
+	productID := ""
+	for _, product := range products {
+		if product.Code == seedProductCode {
+			productID = product.ID
+		}
+	}
+	if productID == "" {
+		return fmt.Errorf("active product %s not found", seedProductCode)
+	}
+
+	loanService := service.NewLoanService(app)
+	for _, seedLoan := range seedLoans {
+		createLoanRequest := seedLoan
+		createLoanRequest.ProductID = productID
+
+		var createLoanResponse *model.CreateLoanResponse
+		createLoanResponse, err = loanService.CreateLoan(ctx, &createLoanRequest)
+		if err != nil {
+			return
+		}
+		fmt.Printf("proposed loan %s for borrower %s\n", createLoanResponse.LoanID, createLoanRequest.BorrowerID)
+	}
+
+	return
+}
+
+// seedPeople creates the employees, borrowers and investors that are not registered yet.
+func seedPeople(ctx context.Context, seedService service.ISeedService) (err error) {
+	for _, employee := range seedEmployees {
+		if _, err = seedService.SeedEmployee(ctx, employee); err != nil {
+			return
+		}
+	}
+
+	for _, borrower := range seedBorrowers {
+		if _, err = seedService.SeedBorrower(ctx, borrower); err != nil {
+			return
+		}
+	}
+
+	for _, investor := range seedInvestors {
+		if _, err = seedService.SeedInvestor(ctx, investor); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
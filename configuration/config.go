@@ -1,17 +1,38 @@
 package configuration
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	env "github.com/Netflix/go-env"
 )
 
 type (
 	Configuration struct {
-		AppHTTPPort int    `env:"APP_HTTP_PORT"`
-		AppVersion  string `env:"APP_VERSION"`
-		AppName     string `env:"APP_NAME"`
-		Environment string `env:"ENVIRONMENT"`
-		Database    Database
-		Feature     Feature
+		// ConfigFile is a YAML file of settings keyed by their variable name, the environment overrides it
+		ConfigFile   string `env:"CONFIG_FILE"`
+		AppHTTPPort  int    `env:"APP_HTTP_PORT"`
+		AppVersion   string `env:"APP_VERSION"`
+		AppName      string `env:"APP_NAME"`
+		Environment  string `env:"ENVIRONMENT"`
+		Database     Database
+		HealthCheck  HealthCheck
+		Scoring      Scoring
+		Loan         Loan
+		Job          Job
+		Event        Event
+		Webhook      Webhook
+		Notification Notification
+		SMTP         SMTP
+		Idempotency  Idempotency
+		Log          Log
+		Tracing      Tracing
+		FeatureFlag  FeatureFlag
+		HTTP         HTTP
+		RateLimit    RateLimit
+		Encryption   Encryption
 	}
 
 	Database struct {
@@ -21,21 +42,226 @@ type (
 		Password string `env:"DB_PASSWORD"`
 		Name     string `env:"DB_NAME"`
 		Schema   string `env:"DB_SCHEMA"`
-		Driver   string `env:"DB_DRIVER"`
-		SSLMode  string `env:"DB_SSLMODE"`
+		Driver   string `env:"DB_DRIVER,default=postgres"`
+		SSLMode  string `env:"DB_SSLMODE,default=require"`
+		// MaxOpenConns caps the connections of the pool, zero is unlimited
+		MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS,default=20"`
+		MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS,default=10"`
+		ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME,default=30m"`
+		ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME,default=5m"`
+		// AutoMigrate applies pending migrations on boot, replicas wait on the migration lock for each other
+		AutoMigrate          bool          `env:"DB_AUTO_MIGRATE,default=false"`
+		MigrationLockTimeout time.Duration `env:"DB_MIGRATION_LOCK_TIMEOUT,default=5m"`
+	}
+
+	HealthCheck struct {
+		// Timeout bounds every dependency check of /readyz
+		Timeout time.Duration `env:"HEALTH_CHECK_TIMEOUT,default=2s"`
+		// JobQueueMaxLag is how long a due job may wait before the job queue is reported degraded
+		JobQueueMaxLag time.Duration `env:"HEALTH_CHECK_JOB_QUEUE_MAX_LAG,default=5m"`
+	}
+
+	Loan struct {
+		// ExpirySchedule is the cron schedule of the funding window expiry job
+		ExpirySchedule string `env:"LOAN_EXPIRY_SCHEDULE,default=* * * * *"`
+	}
+
+	Job struct {
+		WorkerConcurrency int           `env:"JOB_WORKER_CONCURRENCY,default=4"`
+		PollInterval      time.Duration `env:"JOB_POLL_INTERVAL,default=1s"`
+		LockTimeout       time.Duration `env:"JOB_LOCK_TIMEOUT,default=5m"`
+		MaxAttempts       int           `env:"JOB_MAX_ATTEMPTS,default=5"`
+		BackoffBase       time.Duration `env:"JOB_BACKOFF_BASE,default=10s"`
+		BackoffMax        time.Duration `env:"JOB_BACKOFF_MAX,default=1h"`
+		ShutdownTimeout   time.Duration `env:"JOB_SHUTDOWN_TIMEOUT,default=30s"`
+	}
+
+	Event struct {
+		RelayInterval  time.Duration `env:"EVENT_RELAY_INTERVAL,default=1s"`
+		RelayBatchSize int           `env:"EVENT_RELAY_BATCH_SIZE,default=100"`
+		RelayLease     time.Duration `env:"EVENT_RELAY_LEASE,default=1m"`
+		BackoffBase    time.Duration `env:"EVENT_BACKOFF_BASE,default=5s"`
+		BackoffMax     time.Duration `env:"EVENT_BACKOFF_MAX,default=30m"`
+		LogSink        bool          `env:"EVENT_LOG_SINK,default=true"`
+		// WebhookURL receives every event as a JSON POST, the webhook sink is disabled when empty
+		WebhookURL     string        `env:"EVENT_WEBHOOK_URL"`
+		WebhookTimeout time.Duration `env:"EVENT_WEBHOOK_TIMEOUT,default=10s"`
+	}
+
+	// Webhook configures deliveries to partner webhook subscriptions
+	Webhook struct {
+		Timeout              time.Duration `env:"WEBHOOK_TIMEOUT,default=10s"`
+		MaxAttempts          int           `env:"WEBHOOK_MAX_ATTEMPTS,default=8"`
+		BackoffBase          time.Duration `env:"WEBHOOK_BACKOFF_BASE,default=30s"`
+		BackoffMax           time.Duration `env:"WEBHOOK_BACKOFF_MAX,default=6h"`
+		DisableAfterFailures int           `env:"WEBHOOK_DISABLE_AFTER_FAILURES,default=20"`
+	}
+
+	Notification struct {
+		// DefaultLanguage is used for recipients without a preference, id or en
+		DefaultLanguage string `env:"NOTIFICATION_DEFAULT_LANGUAGE,default=id"`
+		// RepaymentReminderSchedule is the cron schedule of the repayment due reminder job
+		RepaymentReminderSchedule string `env:"NOTIFICATION_REPAYMENT_REMINDER_SCHEDULE,default=0 1 * * *"`
+		RepaymentReminderDays     int    `env:"NOTIFICATION_REPAYMENT_REMINDER_DAYS,default=3"`
+	}
+
+	// SMTP configures email delivery, emails are only logged when the host is empty
+	SMTP struct {
+		Host     string        `env:"SMTP_HOST"`
+		Port     int           `env:"SMTP_PORT,default=587"`
+		Username string        `env:"SMTP_USERNAME"`
+		Password string        `env:"SMTP_PASSWORD"`
+		From     string        `env:"SMTP_FROM,default=Loan Service <no-reply@loan-service.local>"`
+		StartTLS bool          `env:"SMTP_STARTTLS,default=true"`
+		Timeout  time.Duration `env:"SMTP_TIMEOUT,default=10s"`
+	}
+
+	Idempotency struct {
+		// KeyTTL is how long a response is replayed for the same Idempotency-Key
+		KeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL,default=24h"`
+		// ProcessingLease is how long a request holds its key, a key still processing after it is taken over
+		// by a retry, it must outlast HTTP_WRITE_TIMEOUT
+		ProcessingLease time.Duration `env:"IDEMPOTENCY_PROCESSING_LEASE,default=1m"`
+		// PurgeSchedule is the cron schedule of the expired key clean up job
+		PurgeSchedule string `env:"IDEMPOTENCY_PURGE_SCHEDULE,default=0 * * * *"`
+	}
+
+	Log struct {
+		// Level is the minimum level written, debug, info, warn or error
+		Level string `env:"LOG_LEVEL,default=info"`
+		// Format is json for log collectors or text for local development
+		Format string `env:"LOG_FORMAT,default=json"`
+	}
+
+	// Tracing exports OpenTelemetry spans, nothing is exported when the exporter is none
+	Tracing struct {
+		// Exporter is none, stdout for local development or otlp
+		Exporter string `env:"TRACING_EXPORTER,default=none"`
+		// OTLPEndpoint is the host:port of the OTLP/HTTP collector
+		OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT,default=localhost:4318"`
+		OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE,default=false"`
+		SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+	}
+
+	Scoring struct {
+		// ScorecardPath points to a JSON scorecard, the built-in scorecard is used when empty
+		ScorecardPath string `env:"SCORING_SCORECARD_PATH"`
+		// CandidateScorecardPath points to a JSON scorecard dark launched behind the scoring.candidate_scorecard flag
+		CandidateScorecardPath string `env:"SCORING_CANDIDATE_SCORECARD_PATH"`
+	}
+
+	HTTP struct {
+		ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT,default=5s"`
+		ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT,default=15s"`
+		WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT,default=30s"`
+		IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT,default=2m"`
+		// MaxBodyBytes caps request bodies, larger requests are answered with 413
+		MaxBodyBytes int64 `env:"HTTP_MAX_BODY_BYTES,default=1048576"`
+		// CORSAllowedOrigins is a comma separated list of origins allowed to call the API, * allows any,
+		// cross-origin requests are not allowed when empty
+		CORSAllowedOrigins string `env:"HTTP_CORS_ALLOWED_ORIGINS"`
+		// ClientIPHeader is the header carrying the client IP set by a trusted proxy, e.g. X-Forwarded-For, the
+		// connection address is used when empty
+		ClientIPHeader string `env:"HTTP_CLIENT_IP_HEADER"`
+		// TrustedProxies is the number of proxies in front of the service that append to ClientIPHeader, the
+		// client is that many addresses from the right since anything further left is set by the client
+		TrustedProxies int `env:"HTTP_TRUSTED_PROXIES,default=1"`
+	}
+
+	// RateLimit limits the /v1 requests of every client per route with a token bucket
+	RateLimit struct {
+		Enabled           bool    `env:"RATE_LIMIT_ENABLED,default=true"`
+		RequestsPerSecond float64 `env:"RATE_LIMIT_RPS,default=10"`
+		Burst             int     `env:"RATE_LIMIT_BURST,default=20"`
+		// Routes overrides the limit of routes, e.g. POST /v1/loans=1:5,POST /v1/loans/{id}/investments=2:10
+		// gives the route a rate of 1 request per second with a burst of 5
+		Routes string `env:"RATE_LIMIT_ROUTES"`
 	}
 
-	Feature struct {
-		FeatureHealthCheck bool `env:"FEATURE_HEALTH_CHECK"`
+	FeatureFlag struct {
+		// CacheTTL is how long flags are cached, a change takes effect on every instance within it
+		CacheTTL time.Duration `env:"FEATURE_FLAG_CACHE_TTL,default=30s"`
+	}
+
+	// Encryption protects the personal data of borrowers and investors
+	Encryption struct {
+		// KeyfilePath points to the JSON keyfile of the key encryption keys and the blind index key
+		KeyfilePath string `env:"ENCRYPTION_KEYFILE_PATH"`
+		// ReencryptSchedule is the cron schedule of the job moving personal data to the active key
+		ReencryptSchedule  string `env:"ENCRYPTION_REENCRYPT_SCHEDULE,default=*/15 * * * *"`
+		ReencryptBatchSize int    `env:"ENCRYPTION_REENCRYPT_BATCH_SIZE,default=500"`
 	}
 )
 
-func LoadConfig() (Configuration, error) {
-	config := Configuration{}
-	_, err := env.UnmarshalFromEnviron(&config)
+// LoadConfig reads the configuration from, by increasing precedence, the defaults, the CONFIG_FILE file, the
+// environment and the files named by *_FILE variables, then validates it.
+func LoadConfig() (config Configuration, err error) {
+	envSet, err := env.EnvironToEnvSet(os.Environ())
 	if err != nil {
-		return config, err
+		return
+	}
+
+	envSet, err = layerConfigFile(envSet)
+	if err != nil {
+		return
+	}
+
+	err = readSecretFiles(envSet)
+	if err != nil {
+		return
+	}
+
+	err = env.Unmarshal(envSet, &config)
+	if err != nil {
+		return
+	}
+
+	err = config.Validate()
+	return
+}
+
+// AllowedOrigins lists the CORS origins.
+func (h HTTP) AllowedOrigins() (origins []string) {
+	for _, origin := range strings.Split(h.CORSAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return
+}
+
+// RouteRate is the token bucket of a route, Burst requests at once refilled at RequestsPerSecond.
+type RouteRate struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// Default is the rate of routes without an override.
+func (rl RateLimit) Default() RouteRate {
+	return RouteRate{RequestsPerSecond: rl.RequestsPerSecond, Burst: rl.Burst}
+}
+
+// RouteRates parses the route overrides, keyed by method and chi route pattern.
+func (rl RateLimit) RouteRates() (routeRates map[string]RouteRate, err error) {
+	routeRates = map[string]RouteRate{}
+	for _, override := range strings.Split(rl.Routes, ",") {
+		if override = strings.TrimSpace(override); override == "" {
+			continue
+		}
+
+		route, rate, ok := strings.Cut(override, "=")
+		if !ok || len(strings.Fields(route)) != 2 {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES entry %q must be METHOD /route=rps:burst", override)
+		}
+
+		routeRate := RouteRate{}
+		if _, err = fmt.Sscanf(rate, "%g:%d", &routeRate.RequestsPerSecond, &routeRate.Burst); err != nil || routeRate.RequestsPerSecond <= 0 || routeRate.Burst < 1 {
+			return nil, fmt.Errorf("RATE_LIMIT_ROUTES entry %q must have a positive rps and a burst of at least 1", override)
+		}
+
+		routeRates[strings.Join(strings.Fields(route), " ")] = routeRate
 	}
 
-	return config, nil
+	return
 }
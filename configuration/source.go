@@ -0,0 +1,138 @@
+package configuration
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	env "github.com/Netflix/go-env"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFileKey    = "CONFIG_FILE"
+	secretFileSuffix = "_FILE"
+	redacted         = "[REDACTED]"
+)
+
+// secretKeys are masked in the configuration dump, pass them through their *_FILE variant in production
+var secretKeys = map[string]bool{
+	"DB_PASSWORD":   true,
+	"SMTP_PASSWORD": true,
+}
+
+// settingKeys are the variable names of every setting of the configuration.
+func settingKeys() (keys map[string]bool, err error) {
+	envSet, err := env.Marshal(&Configuration{})
+	if err != nil {
+		return
+	}
+
+	keys = make(map[string]bool, len(envSet))
+	for key := range envSet {
+		keys[key] = true
+	}
+
+	return
+}
+
+// layerConfigFile puts the settings of the CONFIG_FILE file under the environment. The file is a flat YAML
+// map of variable names, an unknown name is rejected so a typo does not silently fall back to the default.
+func layerConfigFile(environment env.EnvSet) (envSet env.EnvSet, err error) {
+	path := environment[configFileKey]
+	if path == "" {
+		return environment, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	values := map[string]any{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	keys, err := settingKeys()
+	if err != nil {
+		return
+	}
+
+	envSet = env.EnvSet{}
+	for key, value := range values {
+		if !keys[key] && !keys[strings.TrimSuffix(key, secretFileSuffix)] {
+			return nil, fmt.Errorf("config file %s: unknown setting %s", path, key)
+		}
+
+		switch value.(type) {
+		case nil:
+			continue
+		case map[string]any, []any:
+			return nil, fmt.Errorf("config file %s: %s must be a single value", path, key)
+		}
+
+		envSet[key] = fmt.Sprint(value)
+	}
+
+	for key, value := range environment {
+		envSet[key] = value
+	}
+
+	return
+}
+
+// readSecretFiles sets every setting X from the file named by X_FILE, e.g. a mounted Kubernetes or Docker
+// secret, so the secret is neither in the environment nor in the config file.
+func readSecretFiles(envSet env.EnvSet) (err error) {
+	keys, err := settingKeys()
+	if err != nil {
+		return
+	}
+
+	for key := range keys {
+		path, ok := envSet[key+secretFileSuffix]
+		if !ok {
+			continue
+		}
+		if _, ok := envSet[key]; ok {
+			return fmt.Errorf("%s and %s%s are both set, set only one", key, key, secretFileSuffix)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", key, secretFileSuffix, err)
+		}
+
+		// secret files usually end with a newline
+		envSet[key] = strings.TrimRight(string(content), "\r\n")
+		delete(envSet, key+secretFileSuffix)
+	}
+
+	return
+}
+
+// Redacted returns the effective settings by variable name for the boot log, with secrets and the
+// credentials of URLs masked.
+func (config Configuration) Redacted() (settings map[string]string) {
+	envSet, err := env.Marshal(&config)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+
+	settings = make(map[string]string, len(envSet))
+	for key, value := range envSet {
+		switch {
+		case secretKeys[key] && value != "":
+			value = redacted
+		case strings.Contains(value, "://"):
+			if u, err := url.Parse(value); err == nil {
+				value = u.Redacted()
+			}
+		}
+		settings[key] = value
+	}
+
+	return
+}
@@ -0,0 +1,154 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// configErrors collects every invalid setting so a bad deploy shows all of them at once.
+type configErrors []error
+
+func (ce *configErrors) check(ok bool, format string, args ...any) {
+	if !ok {
+		*ce = append(*ce, fmt.Errorf(format, args...))
+	}
+}
+
+func (ce *configErrors) port(key string, port int) {
+	ce.check(port >= 1 && port <= 65535, "%s must be between 1 and 65535, got %d", key, port)
+}
+
+func (ce *configErrors) positive(key string, duration time.Duration) {
+	ce.check(duration > 0, "%s must be a positive duration, got %s", key, duration)
+}
+
+func (ce *configErrors) atLeast(key string, value int, minimum int) {
+	ce.check(value >= minimum, "%s must be at least %d, got %d", key, minimum, value)
+}
+
+func (ce *configErrors) oneOf(key string, value string, allowed ...string) {
+	ce.check(slices.Contains(allowed, value), "%s must be one of %v, got %q", key, allowed, value)
+}
+
+func (ce *configErrors) required(key string, value string) {
+	ce.check(value != "", "%s is required", key)
+}
+
+func (ce *configErrors) schedule(key string, spec string) {
+	_, err := cron.ParseStandard(spec)
+	ce.check(err == nil, "%s is not a cron schedule: %v", key, err)
+}
+
+func (ce *configErrors) backoff(baseKey string, base time.Duration, maxKey string, maximum time.Duration) {
+	ce.positive(baseKey, base)
+	ce.check(maximum >= base, "%s must not be shorter than %s, got %s < %s", maxKey, baseKey, maximum, base)
+}
+
+// Validate reports every invalid setting by its variable name.
+func (config Configuration) Validate() error {
+	ce := configErrors{}
+
+	ce.port("APP_HTTP_PORT", config.AppHTTPPort)
+
+	database := config.Database
+	ce.required("DB_HOST", database.Host)
+	ce.port("DB_PORT", database.Port)
+	ce.required("DB_USERNAME", database.Username)
+	ce.required("DB_NAME", database.Name)
+	// the connection string is built for lib/pq
+	ce.oneOf("DB_DRIVER", database.Driver, "postgres")
+	ce.oneOf("DB_SSLMODE", database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	ce.atLeast("DB_MAX_OPEN_CONNS", database.MaxOpenConns, 0)
+	ce.atLeast("DB_MAX_IDLE_CONNS", database.MaxIdleConns, 0)
+	ce.check(database.MaxOpenConns == 0 || database.MaxIdleConns <= database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS, got %d > %d", database.MaxIdleConns, database.MaxOpenConns)
+	ce.check(database.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative, got %s", database.ConnMaxLifetime)
+	ce.check(database.ConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME must not be negative, got %s", database.ConnMaxIdleTime)
+	ce.positive("DB_MIGRATION_LOCK_TIMEOUT", database.MigrationLockTimeout)
+
+	ce.positive("HEALTH_CHECK_TIMEOUT", config.HealthCheck.Timeout)
+	ce.positive("HEALTH_CHECK_JOB_QUEUE_MAX_LAG", config.HealthCheck.JobQueueMaxLag)
+
+	ce.schedule("LOAN_EXPIRY_SCHEDULE", config.Loan.ExpirySchedule)
+
+	job := config.Job
+	ce.atLeast("JOB_WORKER_CONCURRENCY", job.WorkerConcurrency, 1)
+	ce.positive("JOB_POLL_INTERVAL", job.PollInterval)
+	ce.positive("JOB_LOCK_TIMEOUT", job.LockTimeout)
+	ce.atLeast("JOB_MAX_ATTEMPTS", job.MaxAttempts, 1)
+	ce.backoff("JOB_BACKOFF_BASE", job.BackoffBase, "JOB_BACKOFF_MAX", job.BackoffMax)
+	ce.positive("JOB_SHUTDOWN_TIMEOUT", job.ShutdownTimeout)
+
+	event := config.Event
+	ce.positive("EVENT_RELAY_INTERVAL", event.RelayInterval)
+	ce.atLeast("EVENT_RELAY_BATCH_SIZE", event.RelayBatchSize, 1)
+	ce.positive("EVENT_RELAY_LEASE", event.RelayLease)
+	ce.backoff("EVENT_BACKOFF_BASE", event.BackoffBase, "EVENT_BACKOFF_MAX", event.BackoffMax)
+	if event.WebhookURL != "" {
+		u, err := url.Parse(event.WebhookURL)
+		ce.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"EVENT_WEBHOOK_URL must be an http or https URL")
+	}
+	ce.positive("EVENT_WEBHOOK_TIMEOUT", event.WebhookTimeout)
+
+	webhook := config.Webhook
+	ce.positive("WEBHOOK_TIMEOUT", webhook.Timeout)
+	ce.atLeast("WEBHOOK_MAX_ATTEMPTS", webhook.MaxAttempts, 1)
+	ce.backoff("WEBHOOK_BACKOFF_BASE", webhook.BackoffBase, "WEBHOOK_BACKOFF_MAX", webhook.BackoffMax)
+	ce.atLeast("WEBHOOK_DISABLE_AFTER_FAILURES", webhook.DisableAfterFailures, 1)
+
+	ce.oneOf("NOTIFICATION_DEFAULT_LANGUAGE", config.Notification.DefaultLanguage, "id", "en")
+	ce.schedule("NOTIFICATION_REPAYMENT_REMINDER_SCHEDULE", config.Notification.RepaymentReminderSchedule)
+	ce.atLeast("NOTIFICATION_REPAYMENT_REMINDER_DAYS", config.Notification.RepaymentReminderDays, 0)
+
+	if config.SMTP.Host != "" {
+		ce.port("SMTP_PORT", config.SMTP.Port)
+		_, err := mail.ParseAddress(config.SMTP.From)
+		ce.check(err == nil, "SMTP_FROM is not an email address: %v", err)
+		ce.positive("SMTP_TIMEOUT", config.SMTP.Timeout)
+	}
+
+	ce.positive("IDEMPOTENCY_KEY_TTL", config.Idempotency.KeyTTL)
+	ce.check(config.Idempotency.ProcessingLease >= config.HTTP.WriteTimeout,
+		"IDEMPOTENCY_PROCESSING_LEASE must not be shorter than HTTP_WRITE_TIMEOUT, got %s", config.Idempotency.ProcessingLease)
+	ce.schedule("IDEMPOTENCY_PURGE_SCHEDULE", config.Idempotency.PurgeSchedule)
+
+	ce.oneOf("LOG_LEVEL", config.Log.Level, "debug", "info", "warn", "error")
+	ce.oneOf("LOG_FORMAT", config.Log.Format, "json", "text")
+
+	ce.oneOf("TRACING_EXPORTER", config.Tracing.Exporter, "none", "stdout", "otlp")
+	ce.check(config.Tracing.SampleRatio >= 0 && config.Tracing.SampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", config.Tracing.SampleRatio)
+
+	ce.positive("FEATURE_FLAG_CACHE_TTL", config.FeatureFlag.CacheTTL)
+
+	ce.positive("HTTP_READ_HEADER_TIMEOUT", config.HTTP.ReadHeaderTimeout)
+	ce.positive("HTTP_READ_TIMEOUT", config.HTTP.ReadTimeout)
+	ce.positive("HTTP_WRITE_TIMEOUT", config.HTTP.WriteTimeout)
+	ce.positive("HTTP_IDLE_TIMEOUT", config.HTTP.IdleTimeout)
+	ce.check(config.HTTP.MaxBodyBytes > 0, "HTTP_MAX_BODY_BYTES must be positive, got %d", config.HTTP.MaxBodyBytes)
+	ce.atLeast("HTTP_TRUSTED_PROXIES", config.HTTP.TrustedProxies, 1)
+
+	if config.RateLimit.Enabled {
+		ce.check(config.RateLimit.RequestsPerSecond > 0, "RATE_LIMIT_RPS must be positive, got %g", config.RateLimit.RequestsPerSecond)
+		ce.atLeast("RATE_LIMIT_BURST", config.RateLimit.Burst, 1)
+		_, err := config.RateLimit.RouteRates()
+		ce.check(err == nil, "%v", err)
+	}
+
+	ce.required("ENCRYPTION_KEYFILE_PATH", config.Encryption.KeyfilePath)
+	ce.schedule("ENCRYPTION_REENCRYPT_SCHEDULE", config.Encryption.ReencryptSchedule)
+	ce.atLeast("ENCRYPTION_REENCRYPT_BATCH_SIZE", config.Encryption.ReencryptBatchSize, 1)
+
+	if len(ce) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(ce...))
+}
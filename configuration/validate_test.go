@@ -0,0 +1,41 @@
+package configuration_test
+
+import (
+	env "github.com/Netflix/go-env"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+)
+
+// validEnvSet holds the settings without a default, the rest of a valid configuration are the defaults.
+func validEnvSet() env.EnvSet {
+	return env.EnvSet{
+		"APP_HTTP_PORT":           "8080",
+		"DB_HOST":                 "localhost",
+		"DB_PORT":                 "5432",
+		"DB_USERNAME":             "loan",
+		"DB_NAME":                 "loan",
+		"ENCRYPTION_KEYFILE_PATH": "/run/secrets/keyfile.json",
+	}
+}
+
+func unmarshalConfig(envSet env.EnvSet) configuration.Configuration {
+	config := configuration.Configuration{}
+	Expect(env.Unmarshal(envSet, &config)).To(Succeed())
+
+	return config
+}
+
+var _ = Describe("Configuration", func() {
+	It("should accept the defaults with the required settings", func() {
+		Expect(unmarshalConfig(validEnvSet()).Validate()).To(Succeed())
+	})
+
+	It("should reject an idempotency processing lease shorter than the write timeout", func() {
+		envSet := validEnvSet()
+		envSet["IDEMPOTENCY_PROCESSING_LEASE"] = "10s"
+
+		Expect(unmarshalConfig(envSet).Validate()).To(MatchError(ContainSubstring("IDEMPOTENCY_PROCESSING_LEASE must not be shorter than HTTP_WRITE_TIMEOUT")))
+	})
+})
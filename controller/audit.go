@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+type IAuditController interface {
+	ListAuditLogs(w http.ResponseWriter, r *http.Request)
+	VerifyAuditLogs(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditController struct {
+	AuditService service.IAuditService
+}
+
+func NewAuditController(app *application.App) IAuditController {
+	return &AuditController{
+		AuditService: service.NewAuditService(app),
+	}
+}
+
+func (ac *AuditController) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	// parse query params
+	query := r.URL.Query()
+	listAuditLogsRequest := model.ListAuditLogsRequest{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		ActorID:    query.Get("actor_id"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		value, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("from", "datetime"))
+			return
+		}
+		listAuditLogsRequest.From = &value
+	}
+
+	if to := query.Get("to"); to != "" {
+		value, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("to", "datetime"))
+			return
+		}
+		listAuditLogsRequest.To = &value
+	}
+
+	if beforeID := query.Get("before_id"); beforeID != "" {
+		value, err := strconv.ParseInt(beforeID, 10, 64)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("before_id", "number"))
+			return
+		}
+		listAuditLogsRequest.BeforeID = value
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("limit", "number"))
+			return
+		}
+		listAuditLogsRequest.Limit = value
+	}
+
+	// call business logic
+	resp, err := ac.AuditService.ListAuditLogs(r.Context(), &listAuditLogsRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ac *AuditController) VerifyAuditLogs(w http.ResponseWriter, r *http.Request) {
+	// call business logic
+	resp, err := ac.AuditService.VerifyAuditLogs(r.Context())
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type IEmployeeController interface {
+	CreateEmployee(w http.ResponseWriter, r *http.Request)
+	GetEmployee(w http.ResponseWriter, r *http.Request)
+	ListEmployees(w http.ResponseWriter, r *http.Request)
+	UpdateEmployee(w http.ResponseWriter, r *http.Request)
+	DeactivateEmployee(w http.ResponseWriter, r *http.Request)
+}
+
+type EmployeeController struct {
+	EmployeeService service.IEmployeeService
+}
+
+func NewEmployeeController(app *application.App) IEmployeeController {
+	return &EmployeeController{
+		EmployeeService: service.NewEmployeeService(app),
+	}
+}
+
+func (ec *EmployeeController) CreateEmployee(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	createEmployeeRequest := model.CreateEmployeeRequest{}
+	err := json.NewDecoder(r.Body).Decode(&createEmployeeRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(createEmployeeRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// call business logic
+	resp, err := ec.EmployeeService.CreateEmployee(r.Context(), &createEmployeeRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ec *EmployeeController) GetEmployee(w http.ResponseWriter, r *http.Request) {
+	// get employee id path param
+	employeeID := chi.URLParam(r, "id")
+	_, err := uuid.Parse(employeeID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	// call business logic
+	resp, err := ec.EmployeeService.GetEmployee(r.Context(), &model.GetEmployeeRequest{EmployeeID: employeeID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ec *EmployeeController) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	// parse query params
+	listEmployeesRequest := model.ListEmployeesRequest{}
+	if activeOnly := r.URL.Query().Get("active_only"); activeOnly != "" {
+		value, err := strconv.ParseBool(activeOnly)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("active_only", "boolean"))
+			return
+		}
+		listEmployeesRequest.ActiveOnly = value
+	}
+
+	// call business logic
+	resp, err := ec.EmployeeService.ListEmployees(r.Context(), &listEmployeesRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ec *EmployeeController) UpdateEmployee(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	updateEmployeeRequest := model.UpdateEmployeeRequest{}
+	err := json.NewDecoder(r.Body).Decode(&updateEmployeeRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(updateEmployeeRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get employee id path param
+	employeeID := chi.URLParam(r, "id")
+	_, err = uuid.Parse(employeeID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	updateEmployeeRequest.EmployeeID = employeeID
+
+	// call business logic
+	resp, err := ec.EmployeeService.UpdateEmployee(r.Context(), &updateEmployeeRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ec *EmployeeController) DeactivateEmployee(w http.ResponseWriter, r *http.Request) {
+	// get employee id path param
+	employeeID := chi.URLParam(r, "id")
+	_, err := uuid.Parse(employeeID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	// call business logic
+	resp, err := ec.EmployeeService.DeactivateEmployee(r.Context(), &model.DeactivateEmployeeRequest{EmployeeID: employeeID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
@@ -1,49 +1,40 @@
 package controller
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/frencius/loan-service/model"
 )
 
-func getErrorResponse(err error) (string, int) {
-	var errMsg string
-
-	respCode := http.StatusBadRequest
-	switch err {
-	case model.ErrorBorrowerNotFound:
-		errMsg = model.ErrorBorrowerNotFound.Error()
-		respCode = http.StatusNotFound
-	case model.ErrorLoanNotFound:
-		errMsg = model.ErrorLoanNotFound.Error()
-		respCode = http.StatusNotFound
-	case model.ErrorLoanStateInvalid:
-		errMsg = model.ErrorLoanStateInvalid.Error()
-		respCode = http.StatusBadRequest
-	case model.ErrorLoanStateTransitionNotAllowed:
-		errMsg = model.ErrorLoanStateTransitionNotAllowed.Error()
-		respCode = http.StatusBadRequest
-	case model.ErrorStateTransitionRequirementNotFulfilled:
-		errMsg = model.ErrorStateTransitionRequirementNotFulfilled.Error()
-		respCode = http.StatusBadRequest
-	case model.ErrorTransitionToTheSameState:
-		errMsg = model.ErrorTransitionToTheSameState.Error()
-		respCode = http.StatusBadRequest
-	case model.ErrorInvestorNotFound:
-		errMsg = model.ErrorInvestorNotFound.Error()
-		respCode = http.StatusNotFound
-	case model.ErrorStateMustBePublished:
-		errMsg = model.ErrorStateMustBePublished.Error()
-		respCode = http.StatusBadRequest
-	case model.ErrorInvestmentNotFound:
-		errMsg = model.ErrorInvestmentNotFound.Error()
-		respCode = http.StatusNotFound
-	case model.ErrorInvestmentExist:
-		errMsg = model.ErrorInvestmentExist.Error()
-		respCode = http.StatusBadRequest
-	default:
-		errMsg = "Something wrong in the system!"
-		respCode = http.StatusInternalServerError
+// getErrorResponse finds the domain error in the chain of err, anything else is an internal error.
+func getErrorResponse(err error) *model.DomainError {
+	var domainErr *model.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr
 	}
-	return errMsg, respCode
+
+	return model.ErrorInternal
+}
+
+// WriteErrorResponse writes the response of err in the language of the Accept-Language header, as RFC 7807
+// problem details when the client accepts them.
+func WriteErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	domainErr := getErrorResponse(err)
+	if domainErr.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "WriteErrorResponse", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	language := model.AcceptedLanguage(r.Header.Get(model.AcceptLanguageHeader))
+	w.Header().Set(model.ContentLanguageHeader, string(language))
+
+	if strings.Contains(r.Header.Get("Accept"), model.ProblemContentType) {
+		WriteProblemResponse(w, model.ComposeProblemResponse(domainErr, err.Error(), language))
+		return
+	}
+
+	result := model.ComposeDomainErrorResponse(domainErr, err.Error(), language)
+	WriteHTTPResponse(w, domainErr.Status, result)
 }
@@ -0,0 +1,129 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/controller"
+	"github.com/frencius/loan-service/model"
+)
+
+var _ = Describe("DomainError", func() {
+	It("should be found through errors.As when wrapped", func() {
+		err := fmt.Errorf("approve loan: %w", model.ErrorLoanNotFound)
+
+		var domainErr *model.DomainError
+		Expect(errors.As(err, &domainErr)).To(BeTrue())
+		Expect(domainErr).To(BeIdenticalTo(model.ErrorLoanNotFound))
+	})
+
+	It("should match its sentinel through errors.Is after WithDetail", func() {
+		err := model.ErrorLoanVersionMismatch.WithDetail("current_version", int64(3))
+
+		Expect(errors.Is(err, model.ErrorLoanVersionMismatch)).To(BeTrue())
+		Expect(errors.Is(fmt.Errorf("update: %w", err), model.ErrorLoanVersionMismatch)).To(BeTrue())
+		Expect(errors.Is(err, model.ErrorLoanNotFound)).To(BeFalse())
+		Expect(err.Details).To(Equal(map[string]any{"current_version": int64(3)}))
+		Expect(model.ErrorLoanVersionMismatch.Details).To(BeNil())
+	})
+})
+
+var _ = Describe("WriteErrorResponse", func() {
+	var request *http.Request
+
+	BeforeEach(func() {
+		request = httptest.NewRequest(http.MethodGet, "/v1/loans/1", nil)
+	})
+
+	writeError := func(err error) (*httptest.ResponseRecorder, map[string]any) {
+		recorder := httptest.NewRecorder()
+		controller.WriteErrorResponse(recorder, request, err)
+
+		body := map[string]any{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+
+		return recorder, body
+	}
+
+	It("should report a wrapped domain error with its status and code", func() {
+		recorder, body := writeError(fmt.Errorf("get loan: %w", model.ErrorLoanNotFound))
+
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json; charset=UTF-8"))
+		Expect(recorder.Header().Get(model.ContentLanguageHeader)).To(Equal("en"))
+		Expect(body["error"]).To(HaveKeyWithValue("code", "loan_not_found"))
+		Expect(body["error"]).To(HaveKeyWithValue("message", "loan is not found"))
+		Expect(body["error"]).To(HaveKeyWithValue("detail", "get loan: loan is not found"))
+	})
+
+	It("should carry the details of a domain error", func() {
+		recorder, body := writeError(model.ErrorLoanVersionMismatch.WithDetail("current_version", 3))
+
+		Expect(recorder.Code).To(Equal(http.StatusPreconditionFailed))
+		Expect(body["error"]).To(HaveKeyWithValue("code", "loan_version_mismatch"))
+		Expect(body["error"]).To(HaveKeyWithValue("details", map[string]any{"current_version": float64(3)}))
+	})
+
+	It("should report an unknown error as internal_error", func() {
+		recorder, body := writeError(errors.New("pq: connection refused"))
+
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		Expect(body["error"]).To(HaveKeyWithValue("code", "internal_error"))
+	})
+
+	It("should answer with problem details when the client accepts them", func() {
+		request.Header.Set("Accept", "application/problem+json, application/json")
+
+		recorder, body := writeError(model.ErrorLoanNotFound)
+
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal(model.ProblemContentType))
+		Expect(body).To(Equal(map[string]any{
+			"type":   "urn:loan-service:error:loan_not_found",
+			"title":  "loan is not found",
+			"status": float64(http.StatusNotFound),
+			"detail": "loan is not found",
+			"code":   "loan_not_found",
+		}))
+	})
+
+	It("should answer with the JSON envelope when problem details are not accepted", func() {
+		request.Header.Set("Accept", "application/json")
+
+		recorder, body := writeError(model.ErrorLoanNotFound)
+
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json; charset=UTF-8"))
+		Expect(body).To(HaveKey("error"))
+		Expect(body).NotTo(HaveKey("type"))
+	})
+
+	It("should list every invalid field of a request", func() {
+		_, err := model.IsValid(model.RejectErasureRequestRequest{})
+		Expect(err).NotTo(BeNil())
+
+		recorder, body := writeError(err)
+
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		Expect(body["error"]).To(HaveKeyWithValue("code", "validation_failed"))
+		Expect(body["error"]).To(HaveKeyWithValue("fields", []any{
+			map[string]any{"field": "reason", "rule": "required", "message": "reason is a required field"},
+		}))
+	})
+
+	It("should list the field errors in the problem details", func() {
+		request.Header.Set("Accept", model.ProblemContentType)
+
+		recorder, body := writeError(model.InvalidFieldError("loan_id", "uuid"))
+
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		Expect(body["fields"]).To(Equal([]any{
+			map[string]any{"field": "loan_id", "rule": "uuid", "message": "loan_id must be a valid UUID"},
+		}))
+	})
+})
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+)
+
+type IFeatureFlagController interface {
+	GetFeatureFlag(w http.ResponseWriter, r *http.Request)
+	ListFeatureFlags(w http.ResponseWriter, r *http.Request)
+	UpdateFeatureFlag(w http.ResponseWriter, r *http.Request)
+}
+
+type FeatureFlagController struct {
+	FeatureFlagService service.IFeatureFlagService
+}
+
+func NewFeatureFlagController(app *application.App) IFeatureFlagController {
+	return &FeatureFlagController{
+		FeatureFlagService: service.NewFeatureFlagService(app),
+	}
+}
+
+func (ffc *FeatureFlagController) GetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	// call business logic
+	resp, err := ffc.FeatureFlagService.GetFeatureFlag(r.Context(), &model.GetFeatureFlagRequest{Key: chi.URLParam(r, "key")})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ffc *FeatureFlagController) ListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	// call business logic
+	resp, err := ffc.FeatureFlagService.ListFeatureFlags(r.Context())
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (ffc *FeatureFlagController) UpdateFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	updateFeatureFlagRequest := model.UpdateFeatureFlagRequest{}
+	err := json.NewDecoder(r.Body).Decode(&updateFeatureFlagRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(updateFeatureFlagRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// call business logic
+	updateFeatureFlagRequest.Key = chi.URLParam(r, "key")
+	resp, err := ffc.FeatureFlagService.UpdateFeatureFlag(r.Context(), &updateFeatureFlagRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type IHealthCheckController interface {
-	Ping(w http.ResponseWriter, r *http.Request)
+	Live(w http.ResponseWriter, r *http.Request)
+	Ready(w http.ResponseWriter, r *http.Request)
 }
 type HealthCheckController struct {
 	HealthCheckService service.IHealthCheckService
@@ -21,16 +22,22 @@ func NewHealthCheckController(app *application.App) IHealthCheckController {
 	}
 }
 
-func (hcc *HealthCheckController) Ping(w http.ResponseWriter, r *http.Request) {
-	resp, err := hcc.HealthCheckService.Ping()
-	if err != nil {
-		errMsg, respCode := getErrorResponse(err)
-		result := model.ComposeErrorResponse(respCode, err.Error(), errMsg)
-		WriteHTTPResponse(w, respCode, result)
-		return
-	}
+func (hcc *HealthCheckController) Live(w http.ResponseWriter, r *http.Request) {
+	resp := hcc.HealthCheckService.Live(r.Context())
+
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+// Ready responds 503 only when the service is down, a degraded service keeps receiving traffic.
+func (hcc *HealthCheckController) Ready(w http.ResponseWriter, r *http.Request) {
+	resp := hcc.HealthCheckService.Ready(r.Context())
 
 	respCode := http.StatusOK
+	if resp.Status == model.HealthStatusDown {
+		respCode = http.StatusServiceUnavailable
+	}
 	result := model.ComposeResponse(resp, respCode)
 	WriteHTTPResponse(w, respCode, result)
 }
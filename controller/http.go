@@ -2,6 +2,8 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/frencius/loan-service/model"
@@ -21,6 +23,20 @@ func WriteHTTPResponse(w http.ResponseWriter, statusCode int, body interface{})
 	_, _ = w.Write(bodyBytes)
 }
 
+func WriteProblemResponse(w http.ResponseWriter, problem *model.ProblemResponse) {
+	bodyBytes, err := json.Marshal(problem)
+	if err != nil {
+		respCode := http.StatusInternalServerError
+		result := model.ComposeErrorResponse(respCode, err.Error(), "JSON marshal failed")
+		WriteHTTPResponse(w, respCode, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", model.ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_, _ = w.Write(bodyBytes)
+}
+
 func WriteResponseFile(w http.ResponseWriter, fileName, fileType string, response []byte, status int) {
 	w.Header().Set("Content-Type", "text/"+fileType)
 	w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
@@ -29,3 +45,44 @@ func WriteResponseFile(w http.ResponseWriter, fileName, fileType string, respons
 
 	_, _ = w.Write(response)
 }
+
+// WriteResponseZip sends the zip archive as a download. The archive holds personal data, so it must not be
+// kept by the browser or a proxy cache.
+func WriteResponseZip(w http.ResponseWriter, fileName string, archive []byte) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(archive)
+}
+
+// DecodeRequestBody decodes the JSON body strictly, unknown fields and anything after the JSON value are
+// rejected.
+func DecodeRequestBody(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return RequestBodyError(err)
+	}
+
+	if decoder.Decode(&struct{}{}) != io.EOF {
+		return model.ErrorRequestBodyInvalid.Wrap(errors.New("unexpected data after the JSON body"))
+	}
+
+	return nil
+}
+
+// RequestBodyError reports an error reading the body, a body over the size limit is too large and anything
+// else is invalid.
+func RequestBodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return model.ErrorRequestBodyTooLarge.Wrap(err)
+	}
+
+	return model.ErrorRequestBodyInvalid.Wrap(err)
+}
@@ -0,0 +1,25 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/controller"
+)
+
+var _ = Describe("WriteResponseZip", func() {
+	It("should send the archive as a download that is never cached", func() {
+		recorder := httptest.NewRecorder()
+
+		controller.WriteResponseZip(recorder, "personal-data-borrower-1.zip", []byte("PK"))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/zip"))
+		Expect(recorder.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="personal-data-borrower-1.zip"`))
+		Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-store"))
+		Expect(recorder.Body.String()).To(Equal("PK"))
+	})
+})
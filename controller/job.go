@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// JobHandler runs one attempt of a background job, a returned error schedules a retry.
+type JobHandler func(ctx context.Context, job *model.Job) error
+
+type IJobController interface {
+	ListJobs(w http.ResponseWriter, r *http.Request)
+	RetryJob(w http.ResponseWriter, r *http.Request)
+	CancelExpiredLoans(ctx context.Context, job *model.Job) error
+	DeliverWebhook(ctx context.Context, job *model.Job) error
+	SendEmailNotification(ctx context.Context, job *model.Job) error
+	SendRepaymentReminders(ctx context.Context, job *model.Job) error
+	PurgeIdempotencyKeys(ctx context.Context, job *model.Job) error
+	ReencryptPersonalData(ctx context.Context, job *model.Job) error
+}
+
+type JobController struct {
+	JobService          service.IJobService
+	LoanService         service.ILoanService
+	WebhookService      service.IWebhookService
+	NotificationService service.INotificationService
+	IdempotencyService  service.IIdempotencyService
+	PersonalDataService service.IPersonalDataService
+}
+
+func NewJobController(app *application.App) IJobController {
+	return &JobController{
+		JobService:          service.NewJobService(app),
+		LoanService:         service.NewLoanService(app),
+		WebhookService:      service.NewWebhookService(app),
+		NotificationService: service.NewNotificationService(app),
+		IdempotencyService:  service.NewIdempotencyService(app),
+		PersonalDataService: service.NewPersonalDataService(app),
+	}
+}
+
+func (jc *JobController) ListJobs(w http.ResponseWriter, r *http.Request) {
+	listJobsRequest := model.ListJobsRequest{
+		Status: model.JobStatus(r.URL.Query().Get("status")),
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("limit", "number"))
+			return
+		}
+		listJobsRequest.Limit = value
+	}
+
+	// call business logic
+	resp, err := jc.JobService.ListJobs(r.Context(), &listJobsRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (jc *JobController) RetryJob(w http.ResponseWriter, r *http.Request) {
+	// get job id path param
+	jobID := chi.URLParam(r, "id")
+	_, err := uuid.Parse(jobID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	// call business logic
+	resp, err := jc.JobService.RetryJob(r.Context(), &model.RetryJobRequest{JobID: jobID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+// CancelExpiredLoans handles model.JobTypeCancelExpiredLoans.
+func (jc *JobController) CancelExpiredLoans(ctx context.Context, job *model.Job) error {
+	canceledLoanIDs, err := jc.LoanService.CancelExpiredLoans(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(canceledLoanIDs) > 0 {
+		slog.InfoContext(ctx, "expired loans canceled", "job_id", job.ID, "count", len(canceledLoanIDs), "loan_ids", canceledLoanIDs)
+	}
+
+	return nil
+}
+
+// DeliverWebhook handles model.JobTypeDeliverWebhook.
+func (jc *JobController) DeliverWebhook(ctx context.Context, job *model.Job) error {
+	payload := model.DeliverWebhookPayload{}
+	err := json.Unmarshal(job.Payload, &payload)
+	if err != nil {
+		return err
+	}
+
+	return jc.WebhookService.DeliverWebhook(ctx, payload.DeliveryID)
+}
+
+// SendEmailNotification handles model.JobTypeSendEmailNotification.
+func (jc *JobController) SendEmailNotification(ctx context.Context, job *model.Job) error {
+	payload := model.SendEmailNotificationPayload{}
+	err := json.Unmarshal(job.Payload, &payload)
+	if err != nil {
+		return err
+	}
+
+	return jc.NotificationService.SendEmailNotification(ctx, payload.NotificationID)
+}
+
+// SendRepaymentReminders handles model.JobTypeSendRepaymentReminders.
+func (jc *JobController) SendRepaymentReminders(ctx context.Context, job *model.Job) error {
+	notified, err := jc.NotificationService.SendRepaymentReminders(ctx)
+	if err != nil {
+		return err
+	}
+
+	if notified > 0 {
+		slog.InfoContext(ctx, "repayment reminders sent", "job_id", job.ID, "count", notified)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys handles model.JobTypePurgeIdempotencyKeys.
+func (jc *JobController) PurgeIdempotencyKeys(ctx context.Context, job *model.Job) error {
+	purged, err := jc.IdempotencyService.PurgeExpiredKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		slog.InfoContext(ctx, "expired idempotency keys purged", "job_id", job.ID, "count", purged)
+	}
+
+	return nil
+}
+
+// ReencryptPersonalData handles model.JobTypeReencryptPersonalData.
+func (jc *JobController) ReencryptPersonalData(ctx context.Context, job *model.Job) error {
+	reencrypted, err := jc.PersonalDataService.ReencryptPersonalData(ctx)
+	if err != nil {
+		return err
+	}
+
+	if reencrypted > 0 {
+		slog.InfoContext(ctx, "personal data re-encrypted", "job_id", job.ID, "count", reencrypted)
+	}
+
+	return nil
+}
@@ -1,8 +1,8 @@
 package controller
 
 import (
-	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
@@ -15,6 +15,8 @@ type ILoanController interface {
 	CreateLoan(w http.ResponseWriter, r *http.Request)
 	UpdateLoanState(w http.ResponseWriter, r *http.Request)
 	CreateLoanInvestment(w http.ResponseWriter, r *http.Request)
+	GetLoan(w http.ResponseWriter, r *http.Request)
+	ListPublishedLoans(w http.ResponseWriter, r *http.Request)
 }
 
 type LoanController struct {
@@ -30,37 +32,35 @@ func NewLoanController(app *application.App) ILoanController {
 func (acc *LoanController) CreateLoan(w http.ResponseWriter, r *http.Request) {
 	// decode body request
 	createLoanRequest := model.CreateLoanRequest{}
-	err := json.NewDecoder(r.Body).Decode(&createLoanRequest)
+	err := DecodeRequestBody(r, &createLoanRequest)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	_, err = uuid.Parse(createLoanRequest.BorrowerID)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Borrower ID invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, model.InvalidFieldError("borrower_id", "uuid"))
+		return
+	}
+
+	_, err = uuid.Parse(createLoanRequest.ProductID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("product_id", "uuid"))
 		return
 	}
 
 	// validate request
 	valid, err := model.IsValid(createLoanRequest)
 	if !valid {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	// call business logic
 	resp, err := acc.LoanService.CreateLoan(r.Context(), &createLoanRequest)
 	if err != nil {
-		errMsg, respCode := getErrorResponse(err)
-		result := model.ComposeErrorResponse(respCode, err.Error(), errMsg)
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
@@ -73,20 +73,16 @@ func (acc *LoanController) CreateLoan(w http.ResponseWriter, r *http.Request) {
 func (acc *LoanController) UpdateLoanState(w http.ResponseWriter, r *http.Request) {
 	// decode body request
 	updateLoanStateRequest := model.UpdateLoanStateRequest{}
-	err := json.NewDecoder(r.Body).Decode(&updateLoanStateRequest)
+	err := DecodeRequestBody(r, &updateLoanStateRequest)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	// validate request
 	valid, err := model.IsValid(updateLoanStateRequest)
 	if !valid {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
@@ -94,24 +90,35 @@ func (acc *LoanController) UpdateLoanState(w http.ResponseWriter, r *http.Reques
 	loanID := chi.URLParam(r, "id")
 	_, err = uuid.Parse(loanID)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Loan ID invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
 		return
 	}
 
 	updateLoanStateRequest.LoanID = loanID
 
+	// the client must send the ETag of the loan it read
+	ifMatch := r.Header.Get(model.IfMatchHeader)
+	if ifMatch == "" {
+		err = model.ErrorLoanIfMatchRequired
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	updateLoanStateRequest.ExpectedVersion, err = model.ParseLoanETag(ifMatch)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
 	// call business logic
 	resp, err := acc.LoanService.UpdateLoanState(r.Context(), &updateLoanStateRequest)
 	if err != nil {
-		errMsg, respCode := getErrorResponse(err)
-		result := model.ComposeErrorResponse(respCode, err.Error(), errMsg)
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	// return response
+	w.Header().Set(model.ETagHeader, model.LoanETag(resp.Version))
 	respCode := http.StatusOK
 	result := model.ComposeResponse(resp, respCode)
 	WriteHTTPResponse(w, respCode, result)
@@ -120,28 +127,22 @@ func (acc *LoanController) UpdateLoanState(w http.ResponseWriter, r *http.Reques
 func (acc *LoanController) CreateLoanInvestment(w http.ResponseWriter, r *http.Request) {
 	// decode body request
 	createLoanInvestmentRequest := model.CreateLoanInvestmentRequest{}
-	err := json.NewDecoder(r.Body).Decode(&createLoanInvestmentRequest)
+	err := DecodeRequestBody(r, &createLoanInvestmentRequest)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	// validate request
 	valid, err := model.IsValid(createLoanInvestmentRequest)
 	if !valid {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Request body invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
 	_, err = uuid.Parse(createLoanInvestmentRequest.InvestorID)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Investor ID invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, model.InvalidFieldError("investor_id", "uuid"))
 		return
 	}
 
@@ -149,9 +150,7 @@ func (acc *LoanController) CreateLoanInvestment(w http.ResponseWriter, r *http.R
 	loanID := chi.URLParam(r, "id")
 	_, err = uuid.Parse(loanID)
 	if err != nil {
-		respCode := http.StatusBadRequest
-		result := model.ComposeErrorResponse(respCode, err.Error(), "Loan ID invalid")
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
 		return
 	}
 
@@ -160,9 +159,49 @@ func (acc *LoanController) CreateLoanInvestment(w http.ResponseWriter, r *http.R
 	// call business logic
 	resp, err := acc.LoanService.CreateLoanInvestment(r.Context(), &createLoanInvestmentRequest)
 	if err != nil {
-		errMsg, respCode := getErrorResponse(err)
-		result := model.ComposeErrorResponse(respCode, err.Error(), errMsg)
-		WriteHTTPResponse(w, respCode, result)
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (acc *LoanController) GetLoan(w http.ResponseWriter, r *http.Request) {
+	// get loan id path param
+	loanID := chi.URLParam(r, "id")
+	_, err := uuid.Parse(loanID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	// call business logic
+	resp, err := acc.LoanService.GetLoan(r.Context(), &model.GetLoanRequest{LoanID: loanID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	w.Header().Set(model.ETagHeader, model.LoanETag(resp.Version))
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (acc *LoanController) ListPublishedLoans(w http.ResponseWriter, r *http.Request) {
+	// parse query params
+	listPublishedLoansRequest := model.ListPublishedLoansRequest{
+		RiskGrade: strings.ToUpper(r.URL.Query().Get("risk_grade")),
+	}
+
+	// call business logic
+	resp, err := acc.LoanService.ListPublishedLoans(r.Context(), &listPublishedLoansRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
 		return
 	}
 
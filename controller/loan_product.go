@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type ILoanProductController interface {
+	CreateLoanProduct(w http.ResponseWriter, r *http.Request)
+	GetLoanProduct(w http.ResponseWriter, r *http.Request)
+	ListLoanProducts(w http.ResponseWriter, r *http.Request)
+	ListAllLoanProducts(w http.ResponseWriter, r *http.Request)
+	UpdateLoanProduct(w http.ResponseWriter, r *http.Request)
+}
+
+type LoanProductController struct {
+	LoanProductService service.ILoanProductService
+}
+
+func NewLoanProductController(app *application.App) ILoanProductController {
+	return &LoanProductController{
+		LoanProductService: service.NewLoanProductService(app),
+	}
+}
+
+func (lpc *LoanProductController) CreateLoanProduct(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	createLoanProductRequest := model.CreateLoanProductRequest{}
+	err := DecodeRequestBody(r, &createLoanProductRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(createLoanProductRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// call business logic
+	resp, err := lpc.LoanProductService.CreateLoanProduct(r.Context(), &createLoanProductRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (lpc *LoanProductController) GetLoanProduct(w http.ResponseWriter, r *http.Request) {
+	// get product id path param
+	productID := chi.URLParam(r, "id")
+	_, err := uuid.Parse(productID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	// call business logic
+	resp, err := lpc.LoanProductService.GetLoanProduct(r.Context(), &model.GetLoanProductRequest{ProductID: productID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+// ListLoanProducts returns the catalogue offered to borrowers, i.e. active products only.
+func (lpc *LoanProductController) ListLoanProducts(w http.ResponseWriter, r *http.Request) {
+	lpc.listLoanProducts(w, r, &model.ListLoanProductsRequest{ActiveOnly: true})
+}
+
+// ListAllLoanProducts returns every product including the inactive ones for admins.
+func (lpc *LoanProductController) ListAllLoanProducts(w http.ResponseWriter, r *http.Request) {
+	lpc.listLoanProducts(w, r, &model.ListLoanProductsRequest{ActiveOnly: false})
+}
+
+func (lpc *LoanProductController) listLoanProducts(w http.ResponseWriter, r *http.Request, listLoanProductsRequest *model.ListLoanProductsRequest) {
+	// call business logic
+	resp, err := lpc.LoanProductService.ListLoanProducts(r.Context(), listLoanProductsRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (lpc *LoanProductController) UpdateLoanProduct(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	updateLoanProductRequest := model.UpdateLoanProductRequest{}
+	err := DecodeRequestBody(r, &updateLoanProductRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(updateLoanProductRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get product id path param
+	productID := chi.URLParam(r, "id")
+	_, err = uuid.Parse(productID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	updateLoanProductRequest.ProductID = productID
+
+	// call business logic
+	resp, err := lpc.LoanProductService.UpdateLoanProduct(r.Context(), &updateLoanProductRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type INotificationController interface {
+	GetNotificationPreference(w http.ResponseWriter, r *http.Request)
+	UpdateNotificationPreference(w http.ResponseWriter, r *http.Request)
+}
+
+type NotificationController struct {
+	NotificationService service.INotificationService
+}
+
+func NewNotificationController(app *application.App) INotificationController {
+	return &NotificationController{
+		NotificationService: service.NewNotificationService(app),
+	}
+}
+
+func (nc *NotificationController) GetNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	// get recipient path params
+	recipientType, recipientID, ok := notificationRecipient(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	resp, err := nc.NotificationService.GetNotificationPreference(r.Context(), &model.GetNotificationPreferenceRequest{
+		RecipientType: recipientType,
+		RecipientID:   recipientID,
+	})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (nc *NotificationController) UpdateNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	updateNotificationPreferenceRequest := model.UpdateNotificationPreferenceRequest{}
+	err := json.NewDecoder(r.Body).Decode(&updateNotificationPreferenceRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(updateNotificationPreferenceRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get recipient path params
+	recipientType, recipientID, ok := notificationRecipient(w, r)
+	if !ok {
+		return
+	}
+
+	updateNotificationPreferenceRequest.RecipientType = recipientType
+	updateNotificationPreferenceRequest.RecipientID = recipientID
+
+	// call business logic
+	resp, err := nc.NotificationService.UpdateNotificationPreference(r.Context(), &updateNotificationPreferenceRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func notificationRecipient(w http.ResponseWriter, r *http.Request) (recipientType model.RecipientType, recipientID string, ok bool) {
+	recipientType = model.RecipientType(chi.URLParam(r, "recipient_type"))
+	if !model.ValidRecipientTypes[recipientType] {
+		WriteErrorResponse(w, r, model.ErrorRecipientTypeInvalid)
+		return
+	}
+
+	recipientID = chi.URLParam(r, "id")
+	_, err := uuid.Parse(recipientID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	return recipientType, recipientID, true
+}
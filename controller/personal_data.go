@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type IPersonalDataController interface {
+	ExportPersonalData(w http.ResponseWriter, r *http.Request)
+	CreateErasureRequest(w http.ResponseWriter, r *http.Request)
+	ListErasureRequests(w http.ResponseWriter, r *http.Request)
+	CompleteErasureRequest(w http.ResponseWriter, r *http.Request)
+	RejectErasureRequest(w http.ResponseWriter, r *http.Request)
+}
+
+type PersonalDataController struct {
+	PersonalDataService service.IPersonalDataService
+}
+
+func NewPersonalDataController(app *application.App) IPersonalDataController {
+	return &PersonalDataController{
+		PersonalDataService: service.NewPersonalDataService(app),
+	}
+}
+
+func (pdc *PersonalDataController) ExportPersonalData(w http.ResponseWriter, r *http.Request) {
+	// get subject path params
+	subjectType, subjectID, ok := notificationRecipient(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	export, err := pdc.PersonalDataService.ExportPersonalData(r.Context(), &model.ExportPersonalDataRequest{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+	})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return archive
+	WriteResponseZip(w, export.FileName, export.Content)
+}
+
+func (pdc *PersonalDataController) CreateErasureRequest(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	createErasureRequestRequest := model.CreateErasureRequestRequest{}
+	err := DecodeRequestBody(r, &createErasureRequestRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get subject path params
+	subjectType, subjectID, ok := notificationRecipient(w, r)
+	if !ok {
+		return
+	}
+
+	createErasureRequestRequest.SubjectType = subjectType
+	createErasureRequestRequest.SubjectID = subjectID
+
+	// call business logic
+	resp, err := pdc.PersonalDataService.CreateErasureRequest(r.Context(), &createErasureRequestRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (pdc *PersonalDataController) ListErasureRequests(w http.ResponseWriter, r *http.Request) {
+	// call business logic
+	resp, err := pdc.PersonalDataService.ListErasureRequests(r.Context(), &model.ListErasureRequestsRequest{
+		Status: model.ErasureRequestStatus(r.URL.Query().Get("status")),
+	})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (pdc *PersonalDataController) CompleteErasureRequest(w http.ResponseWriter, r *http.Request) {
+	// get erasure request id path param
+	erasureRequestID, ok := erasureRequestID(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	resp, err := pdc.PersonalDataService.CompleteErasureRequest(r.Context(), &model.CompleteErasureRequestRequest{ID: erasureRequestID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (pdc *PersonalDataController) RejectErasureRequest(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	rejectErasureRequestRequest := model.RejectErasureRequestRequest{}
+	err := DecodeRequestBody(r, &rejectErasureRequestRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(rejectErasureRequestRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get erasure request id path param
+	erasureRequestID, ok := erasureRequestID(w, r)
+	if !ok {
+		return
+	}
+
+	rejectErasureRequestRequest.ID = erasureRequestID
+
+	// call business logic
+	resp, err := pdc.PersonalDataService.RejectErasureRequest(r.Context(), &rejectErasureRequestRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func erasureRequestID(w http.ResponseWriter, r *http.Request) (id string, ok bool) {
+	id = chi.URLParam(r, "id")
+	_, err := uuid.Parse(id)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	return id, true
+}
@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type IWebhookController interface {
+	CreateWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	GetWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request)
+	UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request)
+	ListWebhookDeliveries(w http.ResponseWriter, r *http.Request)
+	GetWebhookDelivery(w http.ResponseWriter, r *http.Request)
+	RedeliverWebhook(w http.ResponseWriter, r *http.Request)
+}
+
+type WebhookController struct {
+	WebhookService service.IWebhookService
+}
+
+func NewWebhookController(app *application.App) IWebhookController {
+	return &WebhookController{
+		WebhookService: service.NewWebhookService(app),
+	}
+}
+
+func (wc *WebhookController) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	createWebhookSubscriptionRequest := model.CreateWebhookSubscriptionRequest{}
+	err := json.NewDecoder(r.Body).Decode(&createWebhookSubscriptionRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(createWebhookSubscriptionRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// call business logic
+	resp, err := wc.WebhookService.CreateWebhookSubscription(r.Context(), &createWebhookSubscriptionRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) GetWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	// get subscription id path param
+	subscriptionID, ok := webhookSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	resp, err := wc.WebhookService.GetWebhookSubscription(r.Context(), &model.GetWebhookSubscriptionRequest{SubscriptionID: subscriptionID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	// call business logic
+	resp, err := wc.WebhookService.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	// decode body request
+	updateWebhookSubscriptionRequest := model.UpdateWebhookSubscriptionRequest{}
+	err := json.NewDecoder(r.Body).Decode(&updateWebhookSubscriptionRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, model.ErrorRequestBodyInvalid.Wrap(err))
+		return
+	}
+
+	// validate request
+	valid, err := model.IsValid(updateWebhookSubscriptionRequest)
+	if !valid {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// get subscription id path param
+	subscriptionID, ok := webhookSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	updateWebhookSubscriptionRequest.SubscriptionID = subscriptionID
+
+	// call business logic
+	resp, err := wc.WebhookService.UpdateWebhookSubscription(r.Context(), &updateWebhookSubscriptionRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	// get subscription id path param
+	subscriptionID, ok := webhookSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	err := wc.WebhookService.DeleteWebhookSubscription(r.Context(), &model.DeleteWebhookSubscriptionRequest{SubscriptionID: subscriptionID})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(nil, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	// get subscription id path param
+	subscriptionID, ok := webhookSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	listWebhookDeliveriesRequest := model.ListWebhookDeliveriesRequest{
+		SubscriptionID: subscriptionID,
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			WriteErrorResponse(w, r, model.InvalidFieldError("limit", "number"))
+			return
+		}
+		listWebhookDeliveriesRequest.Limit = value
+	}
+
+	// call business logic
+	resp, err := wc.WebhookService.ListWebhookDeliveries(r.Context(), &listWebhookDeliveriesRequest)
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	// get subscription and delivery id path params
+	subscriptionID, deliveryID, ok := webhookDeliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	resp, err := wc.WebhookService.GetWebhookDelivery(r.Context(), &model.GetWebhookDeliveryRequest{
+		SubscriptionID: subscriptionID,
+		DeliveryID:     deliveryID,
+	})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func (wc *WebhookController) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	// get subscription and delivery id path params
+	subscriptionID, deliveryID, ok := webhookDeliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	// call business logic
+	resp, err := wc.WebhookService.RedeliverWebhook(r.Context(), &model.RedeliverWebhookRequest{
+		SubscriptionID: subscriptionID,
+		DeliveryID:     deliveryID,
+	})
+	if err != nil {
+		WriteErrorResponse(w, r, err)
+		return
+	}
+
+	// return response
+	respCode := http.StatusOK
+	result := model.ComposeResponse(resp, respCode)
+	WriteHTTPResponse(w, respCode, result)
+}
+
+func webhookSubscriptionID(w http.ResponseWriter, r *http.Request) (subscriptionID string, ok bool) {
+	subscriptionID = chi.URLParam(r, "id")
+	_, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("id", "uuid"))
+		return
+	}
+
+	return subscriptionID, true
+}
+
+func webhookDeliveryID(w http.ResponseWriter, r *http.Request) (subscriptionID string, deliveryID string, ok bool) {
+	subscriptionID, ok = webhookSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID = chi.URLParam(r, "delivery_id")
+	_, err := uuid.Parse(deliveryID)
+	if err != nil {
+		WriteErrorResponse(w, r, model.InvalidFieldError("delivery_id", "uuid"))
+		return subscriptionID, "", false
+	}
+
+	return subscriptionID, deliveryID, true
+}
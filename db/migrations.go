@@ -0,0 +1,8 @@
+package db
+
+import "embed"
+
+// Migrations are the golang-migrate SQL files, embedded so the binary can migrate its own database.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_employees_is_active;
+
+ALTER TABLE employees
+  DROP CONSTRAINT IF EXISTS fk_employees_deactivated_by,
+  DROP COLUMN IF EXISTS deactivated_by,
+  DROP COLUMN IF EXISTS deactivated_at,
+  DROP COLUMN IF EXISTS is_active;
@@ -0,0 +1,8 @@
+-- employee activation status
+ALTER TABLE employees
+  ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  ADD COLUMN deactivated_at TIMESTAMP,
+  ADD COLUMN deactivated_by UUID,
+  ADD CONSTRAINT fk_employees_deactivated_by FOREIGN KEY (deactivated_by) REFERENCES employees(id);
+
+CREATE INDEX idx_employees_is_active ON employees(is_active);
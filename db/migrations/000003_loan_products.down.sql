@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_loans_product_id;
+
+ALTER TABLE loans
+  DROP CONSTRAINT IF EXISTS fk_loans_product,
+  DROP COLUMN IF EXISTS tenor_months,
+  DROP COLUMN IF EXISTS product_id;
+
+DROP TRIGGER IF EXISTS set_timestamp ON loan_products;
+DROP INDEX IF EXISTS idx_loan_products_is_active;
+DROP TABLE IF EXISTS loan_products;
@@ -0,0 +1,62 @@
+-- Loan products
+CREATE TABLE loan_products (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  code VARCHAR(50) NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  description TEXT,
+  min_principal_amount NUMERIC(20,2) NOT NULL,
+  max_principal_amount NUMERIC(20,2) NOT NULL,
+  tenor_months INTEGER[] NOT NULL,
+  min_interest_rate NUMERIC(5,2) NOT NULL,
+  max_interest_rate NUMERIC(5,2) NOT NULL,
+  min_roi_rate NUMERIC(5,2) NOT NULL,
+  max_roi_rate NUMERIC(5,2) NOT NULL,
+  fees JSONB NOT NULL DEFAULT '[]',
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_loan_products_principal CHECK (min_principal_amount > 0 AND min_principal_amount <= max_principal_amount),
+  CONSTRAINT chk_loan_products_interest CHECK (min_interest_rate >= 0 AND min_interest_rate <= max_interest_rate),
+  CONSTRAINT chk_loan_products_roi CHECK (min_roi_rate >= 0 AND min_roi_rate <= max_roi_rate)
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON loan_products
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_loan_products_is_active ON loan_products(is_active);
+
+-- loans reference a product
+ALTER TABLE loans
+  ADD COLUMN product_id UUID,
+  ADD COLUMN tenor_months INTEGER,
+  ADD CONSTRAINT fk_loans_product FOREIGN KEY (product_id) REFERENCES loan_products(id);
+
+CREATE INDEX idx_loans_product_id ON loans(product_id);
+
+-- default catalogue
+INSERT INTO loan_products (code, name, description, min_principal_amount, max_principal_amount, tenor_months, min_interest_rate, max_interest_rate, min_roi_rate, max_roi_rate, fees)
+VALUES
+  (
+    'PRODUCTIVE_SME',
+    'Productive SME',
+    'Working capital for micro and small businesses',
+    1000000, 500000000,
+    '{3,6,9,12}',
+    12.00, 24.00,
+    8.00, 16.00,
+    '[{"name": "admin_fee", "type": "percentage", "amount": 2.5}, {"name": "late_fee", "type": "percentage", "amount": 0.1}]'
+  ),
+  (
+    'INVOICE_FINANCING',
+    'Invoice Financing',
+    'Short term financing backed by outstanding invoices',
+    5000000, 2000000000,
+    '{1,2,3,6}',
+    10.00, 18.00,
+    7.00, 13.00,
+    '[{"name": "admin_fee", "type": "percentage", "amount": 1.5}, {"name": "stamp_duty", "type": "flat", "amount": 10000}]'
+  );
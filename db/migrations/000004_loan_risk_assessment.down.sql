@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_loans_risk_grade;
+
+ALTER TABLE loans
+  DROP CONSTRAINT IF EXISTS chk_loans_risk_grade,
+  DROP COLUMN IF EXISTS suggested_max_interest_rate,
+  DROP COLUMN IF EXISTS suggested_min_interest_rate,
+  DROP COLUMN IF EXISTS risk_assessed_at,
+  DROP COLUMN IF EXISTS risk_scorecard_version,
+  DROP COLUMN IF EXISTS risk_explanation,
+  DROP COLUMN IF EXISTS risk_grade,
+  DROP COLUMN IF EXISTS risk_score;
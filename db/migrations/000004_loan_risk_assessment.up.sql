@@ -0,0 +1,12 @@
+-- risk assessment computed at loan proposal
+ALTER TABLE loans
+  ADD COLUMN risk_score INTEGER,
+  ADD COLUMN risk_grade VARCHAR(1),
+  ADD COLUMN risk_explanation JSONB,
+  ADD COLUMN risk_scorecard_version VARCHAR(50),
+  ADD COLUMN risk_assessed_at TIMESTAMP,
+  ADD COLUMN suggested_min_interest_rate NUMERIC(5,2),
+  ADD COLUMN suggested_max_interest_rate NUMERIC(5,2),
+  ADD CONSTRAINT chk_loans_risk_grade CHECK (risk_grade IN ('A', 'B', 'C', 'D', 'E'));
+
+CREATE INDEX idx_loans_risk_grade ON loans(risk_grade);
@@ -0,0 +1,18 @@
+-- keep the system actor if loans still reference it
+DELETE FROM employees
+WHERE id = '00000000-0000-0000-0000-000000000001'
+  AND NOT EXISTS (SELECT 1 FROM loans WHERE canceled_by = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE investments
+  DROP CONSTRAINT IF EXISTS chk_investments_status,
+  DROP COLUMN IF EXISTS released_at,
+  DROP COLUMN IF EXISTS status;
+
+DROP INDEX IF EXISTS idx_loans_published_funding_deadline;
+
+ALTER TABLE loans
+  DROP COLUMN IF EXISTS funding_deadline;
+
+ALTER TABLE loan_products
+  DROP CONSTRAINT IF EXISTS chk_loan_products_funding_window,
+  DROP COLUMN IF EXISTS funding_window_days;
@@ -0,0 +1,21 @@
+-- funding window per product
+ALTER TABLE loan_products
+  ADD COLUMN funding_window_days INTEGER NOT NULL DEFAULT 14,
+  ADD CONSTRAINT chk_loan_products_funding_window CHECK (funding_window_days > 0);
+
+-- funding deadline set when the loan is published
+ALTER TABLE loans
+  ADD COLUMN funding_deadline TIMESTAMP;
+
+CREATE INDEX idx_loans_published_funding_deadline ON loans(funding_deadline) WHERE state = 'published';
+
+-- investor holds are released when the loan is canceled
+ALTER TABLE investments
+  ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active',
+  ADD COLUMN released_at TIMESTAMP,
+  ADD CONSTRAINT chk_investments_status CHECK (status IN ('active', 'released'));
+
+-- system actor used by background jobs
+INSERT INTO employees (id, name, employee_number)
+VALUES ('00000000-0000-0000-0000-000000000001', 'System', 'SYSTEM')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS set_timestamp ON recurring_jobs;
+DROP TABLE IF EXISTS recurring_jobs;
+
+DROP TRIGGER IF EXISTS set_timestamp ON jobs;
+DROP INDEX IF EXISTS idx_jobs_status;
+DROP INDEX IF EXISTS idx_jobs_running_locked_at;
+DROP INDEX IF EXISTS idx_jobs_pending_run_at;
+DROP TABLE IF EXISTS jobs;
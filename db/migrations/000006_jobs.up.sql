@@ -0,0 +1,48 @@
+-- background job queue
+CREATE TABLE jobs (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL DEFAULT 5,
+  run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_at TIMESTAMP,
+  locked_by TEXT,
+  last_error TEXT,
+  completed_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_jobs_status CHECK (status IN ('pending', 'running', 'succeeded', 'dead')),
+  CONSTRAINT chk_jobs_max_attempts CHECK (max_attempts > 0)
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON jobs
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_jobs_pending_run_at ON jobs(run_at) WHERE status = 'pending';
+CREATE INDEX idx_jobs_running_locked_at ON jobs(locked_at) WHERE status = 'running';
+CREATE INDEX idx_jobs_status ON jobs(status);
+
+-- cron style recurring jobs, enqueued into jobs when next_run_at is due
+CREATE TABLE recurring_jobs (
+  name VARCHAR(100) PRIMARY KEY NOT NULL,
+  schedule VARCHAR(100) NOT NULL,
+  job_type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  max_attempts INTEGER NOT NULL DEFAULT 5,
+  next_run_at TIMESTAMP NOT NULL,
+  last_run_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON recurring_jobs
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
@@ -0,0 +1,17 @@
+-- domain events written in the same transaction as the change that produced them
+CREATE TABLE outbox_events (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  event_type VARCHAR(100) NOT NULL,
+  aggregate_type VARCHAR(50) NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload JSONB NOT NULL,
+  occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  published_at TIMESTAMP,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_until TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
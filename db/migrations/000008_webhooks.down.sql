@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_webhook_delivery_attempts_delivery_id;
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+
+DROP TRIGGER IF EXISTS set_timestamp ON webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription_created_at;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP TRIGGER IF EXISTS set_timestamp ON webhook_subscriptions;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_event_types;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,62 @@
+CREATE TABLE webhook_subscriptions (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  url TEXT NOT NULL,
+  description TEXT,
+  event_types TEXT[] NOT NULL,
+  secret TEXT NOT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  consecutive_failures INTEGER NOT NULL DEFAULT 0,
+  disabled_at TIMESTAMP,
+  disabled_reason TEXT,
+  created_by UUID,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON webhook_subscriptions
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_webhook_subscriptions_event_types ON webhook_subscriptions USING GIN (event_types) WHERE is_active = TRUE;
+
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  event_id UUID NOT NULL,
+  event_type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_status_code INTEGER,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP,
+  delivered_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed')),
+  -- fan-out is retried by the at-least-once event relay
+  CONSTRAINT uq_webhook_deliveries_subscription_event UNIQUE (subscription_id, event_id)
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON webhook_deliveries
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_webhook_deliveries_subscription_created_at ON webhook_deliveries(subscription_id, created_at DESC);
+
+CREATE TABLE webhook_delivery_attempts (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+  attempt INTEGER NOT NULL,
+  status_code INTEGER,
+  error TEXT,
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  attempted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id);
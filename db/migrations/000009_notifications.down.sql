@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS set_timestamp ON email_notifications;
+DROP INDEX IF EXISTS idx_email_notifications_recipient;
+DROP TABLE IF EXISTS email_notifications;
+
+DROP TRIGGER IF EXISTS set_timestamp ON notification_preferences;
+DROP TABLE IF EXISTS notification_preferences;
+
+ALTER TABLE borrowers DROP COLUMN IF EXISTS email;
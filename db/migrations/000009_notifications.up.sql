@@ -0,0 +1,50 @@
+ALTER TABLE borrowers ADD COLUMN email VARCHAR(100);
+
+CREATE TABLE notification_preferences (
+  recipient_type VARCHAR(20) NOT NULL,
+  recipient_id UUID NOT NULL,
+  language VARCHAR(5) NOT NULL DEFAULT 'id',
+  email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  disabled_events TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (recipient_type, recipient_id),
+  CONSTRAINT chk_notification_preferences_recipient_type CHECK (recipient_type IN ('borrower', 'investor')),
+  CONSTRAINT chk_notification_preferences_language CHECK (language IN ('id', 'en'))
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON notification_preferences
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TABLE email_notifications (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  event VARCHAR(50) NOT NULL,
+  recipient_type VARCHAR(20) NOT NULL,
+  recipient_id UUID NOT NULL,
+  email VARCHAR(100) NOT NULL,
+  language VARCHAR(5) NOT NULL,
+  subject TEXT NOT NULL,
+  body TEXT NOT NULL,
+  -- one email per recipient and occurrence, triggers are retried by the at-least-once event relay
+  dedupe_key TEXT NOT NULL UNIQUE,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  sent_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_email_notifications_status CHECK (status IN ('pending', 'sent'))
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON email_notifications
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_email_notifications_recipient ON email_notifications(recipient_type, recipient_id, created_at DESC);
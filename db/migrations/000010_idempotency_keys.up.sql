@@ -0,0 +1,18 @@
+CREATE TABLE idempotency_keys (
+  user_id UUID NOT NULL,
+  idempotency_key VARCHAR(255) NOT NULL,
+  method VARCHAR(10) NOT NULL,
+  path TEXT NOT NULL,
+  request_hash VARCHAR(64) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'processing',
+  response_code INTEGER,
+  response_body BYTEA,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NOT NULL,
+  completed_at TIMESTAMP,
+
+  PRIMARY KEY (user_id, idempotency_key),
+  CONSTRAINT chk_idempotency_keys_status CHECK (status IN ('processing', 'completed'))
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
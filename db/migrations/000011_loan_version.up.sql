@@ -0,0 +1 @@
+ALTER TABLE loans ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
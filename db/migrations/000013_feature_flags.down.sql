@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS set_timestamp ON feature_flags;
+DROP TABLE IF EXISTS feature_flags;
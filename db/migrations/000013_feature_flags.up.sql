@@ -0,0 +1,25 @@
+CREATE TABLE feature_flags (
+  key VARCHAR(100) PRIMARY KEY NOT NULL,
+  description TEXT,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  rollout_percentage INTEGER NOT NULL DEFAULT 0,
+  rules JSONB NOT NULL DEFAULT '[]',
+  updated_by UUID,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_feature_flags_rollout_percentage CHECK (rollout_percentage BETWEEN 0 AND 100),
+  CONSTRAINT fk_feature_flags_updated_by FOREIGN KEY (updated_by) REFERENCES employees(id)
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON feature_flags
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- the expiry job already cancels every expired loan, the flag starts fully rolled out to keep it that way
+INSERT INTO feature_flags (key, description, enabled, rollout_percentage)
+VALUES
+  ('loan.auto_cancel_expired', 'Cancel published loans whose funding window expired', TRUE, 100),
+  ('scoring.candidate_scorecard', 'Score loan proposals with the candidate scorecard of SCORING_CANDIDATE_SCORECARD_PATH', FALSE, 0);
@@ -0,0 +1,39 @@
+-- append-only trail of every mutation, each entry carries the hash of the one before it so any change
+-- to the history breaks the chain
+CREATE TABLE audit_logs (
+  id BIGSERIAL PRIMARY KEY,
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id VARCHAR(255) NOT NULL,
+  action VARCHAR(20) NOT NULL,
+  actor_id VARCHAR(100),
+  source_ip VARCHAR(100),
+  request_id VARCHAR(255),
+  changes JSON NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  prev_hash CHAR(64) NOT NULL,
+  hash CHAR(64) NOT NULL,
+
+  CONSTRAINT uq_audit_logs_hash UNIQUE (hash),
+  CONSTRAINT uq_audit_logs_prev_hash UNIQUE (prev_hash)
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id, id);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id, id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+CREATE OR REPLACE FUNCTION trigger_audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only, % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW
+EXECUTE FUNCTION trigger_audit_logs_append_only();
+
+CREATE TRIGGER audit_logs_no_truncate
+BEFORE TRUNCATE ON audit_logs
+FOR EACH STATEMENT
+EXECUTE FUNCTION trigger_audit_logs_append_only();
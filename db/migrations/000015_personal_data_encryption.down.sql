@@ -0,0 +1,27 @@
+-- the columns are only narrowed back while they hold plaintext, values still encrypted fail the migration
+-- instead of being left unreadable
+ALTER TABLE investors
+  DROP CONSTRAINT IF EXISTS uq_investors_email_hash,
+  DROP CONSTRAINT IF EXISTS uq_investors_npwp_hash,
+  DROP CONSTRAINT IF EXISTS uq_investors_nik_hash,
+  DROP COLUMN IF EXISTS encryption_key_id,
+  DROP COLUMN IF EXISTS email_hash,
+  DROP COLUMN IF EXISTS npwp_hash,
+  DROP COLUMN IF EXISTS nik_hash,
+  ALTER COLUMN phone_number TYPE VARCHAR(15),
+  ALTER COLUMN email TYPE VARCHAR(100),
+  ALTER COLUMN npwp TYPE VARCHAR(16),
+  ALTER COLUMN nik TYPE VARCHAR(16),
+  ADD CONSTRAINT investors_nik_key UNIQUE (nik),
+  ADD CONSTRAINT investors_npwp_key UNIQUE (npwp),
+  ADD CONSTRAINT investors_email_key UNIQUE (email);
+CREATE INDEX idx_investors_email ON investors(email);
+
+ALTER TABLE borrowers
+  DROP CONSTRAINT IF EXISTS uq_borrowers_nik_hash,
+  DROP COLUMN IF EXISTS encryption_key_id,
+  DROP COLUMN IF EXISTS nik_hash,
+  ALTER COLUMN email TYPE VARCHAR(100),
+  ALTER COLUMN nik TYPE VARCHAR(16),
+  ADD CONSTRAINT borrowers_nik_key UNIQUE (nik);
+CREATE INDEX idx_borrowers_nik ON borrowers(nik);
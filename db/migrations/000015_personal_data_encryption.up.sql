@@ -0,0 +1,27 @@
+-- personal data is encrypted by the service, the plaintext values stay readable until the re-encryption job
+-- has encrypted them and filled the blind indexes, encryption_key_id is NULL until then
+ALTER TABLE borrowers DROP CONSTRAINT IF EXISTS borrowers_nik_key;
+DROP INDEX IF EXISTS idx_borrowers_nik;
+ALTER TABLE borrowers
+  ALTER COLUMN nik TYPE TEXT,
+  ALTER COLUMN email TYPE TEXT,
+  ADD COLUMN nik_hash CHAR(64),
+  ADD COLUMN encryption_key_id VARCHAR(64),
+  ADD CONSTRAINT uq_borrowers_nik_hash UNIQUE (nik_hash);
+
+ALTER TABLE investors DROP CONSTRAINT IF EXISTS investors_nik_key;
+ALTER TABLE investors DROP CONSTRAINT IF EXISTS investors_npwp_key;
+ALTER TABLE investors DROP CONSTRAINT IF EXISTS investors_email_key;
+DROP INDEX IF EXISTS idx_investors_email;
+ALTER TABLE investors
+  ALTER COLUMN nik TYPE TEXT,
+  ALTER COLUMN npwp TYPE TEXT,
+  ALTER COLUMN email TYPE TEXT,
+  ALTER COLUMN phone_number TYPE TEXT,
+  ADD COLUMN nik_hash CHAR(64),
+  ADD COLUMN npwp_hash CHAR(64),
+  ADD COLUMN email_hash CHAR(64),
+  ADD COLUMN encryption_key_id VARCHAR(64),
+  ADD CONSTRAINT uq_investors_nik_hash UNIQUE (nik_hash),
+  ADD CONSTRAINT uq_investors_npwp_hash UNIQUE (npwp_hash),
+  ADD CONSTRAINT uq_investors_email_hash UNIQUE (email_hash);
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_personal_data_erasure_requests_status;
+DROP INDEX IF EXISTS uq_personal_data_erasure_requests_pending;
+DROP TRIGGER IF EXISTS set_timestamp ON personal_data_erasure_requests;
+DROP TABLE IF EXISTS personal_data_erasure_requests;
+
+-- erased borrowers and investors have no personal data to restore, rolling back fails while there are any
+ALTER TABLE investors
+  DROP COLUMN IF EXISTS erased_at,
+  ALTER COLUMN email SET NOT NULL,
+  ALTER COLUMN npwp SET NOT NULL,
+  ALTER COLUMN nik SET NOT NULL,
+  ALTER COLUMN name SET NOT NULL;
+
+ALTER TABLE borrowers
+  DROP COLUMN IF EXISTS erased_at,
+  ALTER COLUMN dob SET NOT NULL,
+  ALTER COLUMN nik SET NOT NULL,
+  ALTER COLUMN occupation SET NOT NULL,
+  ALTER COLUMN address SET NOT NULL,
+  ALTER COLUMN name SET NOT NULL;
@@ -0,0 +1,43 @@
+-- erased borrowers and investors keep their row, so loans and investments still point to them, with every
+-- personal column cleared
+ALTER TABLE borrowers
+  ALTER COLUMN name DROP NOT NULL,
+  ALTER COLUMN address DROP NOT NULL,
+  ALTER COLUMN occupation DROP NOT NULL,
+  ALTER COLUMN nik DROP NOT NULL,
+  ALTER COLUMN dob DROP NOT NULL,
+  ADD COLUMN erased_at TIMESTAMPTZ;
+
+ALTER TABLE investors
+  ALTER COLUMN name DROP NOT NULL,
+  ALTER COLUMN nik DROP NOT NULL,
+  ALTER COLUMN npwp DROP NOT NULL,
+  ALTER COLUMN email DROP NOT NULL,
+  ADD COLUMN erased_at TIMESTAMPTZ;
+
+CREATE TABLE personal_data_erasure_requests (
+  id UUID PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4(),
+  subject_type VARCHAR(20) NOT NULL,
+  subject_id UUID NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  reason TEXT,
+  rejected_reason TEXT,
+  processed_at TIMESTAMPTZ,
+  processed_by UUID,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT chk_personal_data_erasure_requests_subject_type CHECK (subject_type IN ('borrower', 'investor')),
+  CONSTRAINT chk_personal_data_erasure_requests_status CHECK (status IN ('pending', 'completed', 'rejected')),
+  CONSTRAINT fk_personal_data_erasure_requests_processed_by FOREIGN KEY (processed_by) REFERENCES employees(id)
+);
+
+-- Triggers for updated_at
+CREATE TRIGGER set_timestamp
+BEFORE UPDATE ON personal_data_erasure_requests
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- a subject has at most one request waiting for review
+CREATE UNIQUE INDEX uq_personal_data_erasure_requests_pending ON personal_data_erasure_requests(subject_type, subject_id) WHERE status = 'pending';
+CREATE INDEX idx_personal_data_erasure_requests_status ON personal_data_erasure_requests(status, created_at);
@@ -0,0 +1,2 @@
+-- set when the business metrics counted the event, so redelivered and replayed events are not counted again
+ALTER TABLE outbox_events ADD COLUMN metrics_recorded_at TIMESTAMP;
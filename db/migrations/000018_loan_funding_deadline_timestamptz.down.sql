@@ -0,0 +1 @@
+ALTER TABLE loans ALTER COLUMN funding_deadline TYPE TIMESTAMP;
@@ -0,0 +1,3 @@
+-- the deadline is compared with the time of the app, keep it as an instant so the zones of the app and the
+-- database session cannot shift it; existing values are read in the zone of the migrating session
+ALTER TABLE loans ALTER COLUMN funding_deadline TYPE TIMESTAMPTZ;
@@ -0,0 +1,12 @@
+ALTER TABLE recurring_jobs
+  ALTER COLUMN next_run_at TYPE TIMESTAMP,
+  ALTER COLUMN last_run_at TYPE TIMESTAMP,
+  ALTER COLUMN created_at TYPE TIMESTAMP,
+  ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE jobs
+  ALTER COLUMN run_at TYPE TIMESTAMP,
+  ALTER COLUMN locked_at TYPE TIMESTAMP,
+  ALTER COLUMN completed_at TYPE TIMESTAMP,
+  ALTER COLUMN created_at TYPE TIMESTAMP,
+  ALTER COLUMN updated_at TYPE TIMESTAMP;
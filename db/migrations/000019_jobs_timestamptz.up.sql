@@ -0,0 +1,14 @@
+-- run times are computed by the app and compared with NOW(), keep them as instants so the zones of the app
+-- and the database session cannot shift them; existing values are read in the zone of the migrating session
+ALTER TABLE jobs
+  ALTER COLUMN run_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN locked_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN completed_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE recurring_jobs
+  ALTER COLUMN next_run_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN last_run_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+ALTER TABLE outbox_events
+  ALTER COLUMN occurred_at TYPE TIMESTAMP,
+  ALTER COLUMN published_at TYPE TIMESTAMP,
+  ALTER COLUMN next_attempt_at TYPE TIMESTAMP,
+  ALTER COLUMN locked_until TYPE TIMESTAMP;
@@ -0,0 +1,7 @@
+-- retry times are computed by the app and compared with NOW(), keep them as instants so the zones of the app
+-- and the database session cannot shift them; existing values are read in the zone of the migrating session
+ALTER TABLE outbox_events
+  ALTER COLUMN occurred_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN published_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN locked_until TYPE TIMESTAMPTZ;
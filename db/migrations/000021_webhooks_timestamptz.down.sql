@@ -0,0 +1,13 @@
+ALTER TABLE webhook_delivery_attempts
+  ALTER COLUMN attempted_at TYPE TIMESTAMP;
+
+ALTER TABLE webhook_deliveries
+  ALTER COLUMN next_attempt_at TYPE TIMESTAMP,
+  ALTER COLUMN delivered_at TYPE TIMESTAMP,
+  ALTER COLUMN created_at TYPE TIMESTAMP,
+  ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE webhook_subscriptions
+  ALTER COLUMN disabled_at TYPE TIMESTAMP,
+  ALTER COLUMN created_at TYPE TIMESTAMP,
+  ALTER COLUMN updated_at TYPE TIMESTAMP;
@@ -0,0 +1,15 @@
+-- retry times are computed by the app and compared with its clock, keep them as instants so the zones of the
+-- app and the database session cannot shift them; existing values are read in the zone of the migrating session
+ALTER TABLE webhook_subscriptions
+  ALTER COLUMN disabled_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE webhook_deliveries
+  ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN delivered_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+  ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE webhook_delivery_attempts
+  ALTER COLUMN attempted_at TYPE TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- headers of the stored response that describe its body, replayed with it; responses stored before have none
+ALTER TABLE idempotency_keys
+  ADD COLUMN response_headers JSONB NOT NULL DEFAULT '{}';
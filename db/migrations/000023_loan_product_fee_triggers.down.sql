@@ -0,0 +1,5 @@
+UPDATE loan_products
+SET fees = (
+  SELECT COALESCE(jsonb_agg(elements.fee - 'trigger' ORDER BY elements.position), '[]')
+  FROM jsonb_array_elements(fees) WITH ORDINALITY AS elements(fee, position)
+);
@@ -0,0 +1,9 @@
+-- fees are charged upfront unless triggered by a late repayment, the seeded late_fee is not charged at proposal
+UPDATE loan_products
+SET fees = (
+  SELECT COALESCE(jsonb_agg(
+    elements.fee || jsonb_build_object('trigger', CASE WHEN elements.fee->>'name' = 'late_fee' THEN 'late' ELSE 'upfront' END)
+    ORDER BY elements.position
+  ), '[]')
+  FROM jsonb_array_elements(fees) WITH ORDINALITY AS elements(fee, position)
+);
@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+type EventRelay struct {
+	EventService service.IEventService
+	Interval     time.Duration
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+// Close stops the relay after the batch in flight. Events of an interrupted batch are published again
+// once their lease expires.
+func (er *EventRelay) Close() {
+	slog.Info("shutting down event relay")
+	er.cancel()
+	<-er.done
+	slog.Info("event relay shut down is successful")
+}
+
+// RunEventRelay publishes outbox events to the configured sinks.
+func RunEventRelay(app *application.App) *EventRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	er := &EventRelay{
+		EventService: service.NewEventService(app),
+		Interval:     app.Config.Event.RelayInterval,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+
+	// in-process subscribers
+	er.EventService.Subscribe(model.EventTypeAll, service.NewWebhookService(app).FanOutEvent)
+	er.EventService.Subscribe(model.EventTypeAll, service.NewMetricsService(app).HandleEvent)
+
+	notificationService := service.NewNotificationService(app)
+	er.EventService.Subscribe(model.EventTypeLoanFullyFunded, notificationService.HandleEvent)
+	er.EventService.Subscribe(model.EventTypeLoanDisbursed, notificationService.HandleEvent)
+
+	go func(er *EventRelay) {
+		defer close(er.done)
+
+		ticker := time.NewTicker(er.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// drain the backlog before waiting for the next tick
+				for {
+					published, err := er.EventService.RelayEvents(ctx)
+					if err != nil {
+						slog.ErrorContext(ctx, "event relay RelayEvents error", "error", err)
+						break
+					}
+					if published == 0 || ctx.Err() != nil {
+						break
+					}
+				}
+			}
+		}
+	}(er)
+
+	slog.Info("event relay started", "interval", er.Interval.String())
+
+	return er
+}
@@ -3,13 +3,16 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/controller"
+	"github.com/frencius/loan-service/service"
 	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type HTTPServer struct {
@@ -22,12 +25,12 @@ func (hs *HTTPServer) Close() {
 		cancel()
 	}()
 
-	log.Println("shutting down http server")
+	slog.Info("shutting down http server")
 	err := hs.Server.Shutdown(ctxShutdown)
 	if err != nil {
-		log.Println("http server shut down error", err)
+		slog.Error("http server shut down error", "error", err)
 	}
-	log.Println("http server shut down is successful")
+	slog.Info("http server shut down is successful")
 }
 
 func RunHTTPServer(app *application.App) *HTTPServer {
@@ -35,33 +38,61 @@ func RunHTTPServer(app *application.App) *HTTPServer {
 
 	router := setupRouter(app)
 	hs.Server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", app.Config.AppHTTPPort),
-		Handler: router,
+		Addr:              fmt.Sprintf(":%d", app.Config.AppHTTPPort),
+		Handler:           router,
+		ReadHeaderTimeout: app.Config.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       app.Config.HTTP.ReadTimeout,
+		WriteTimeout:      app.Config.HTTP.WriteTimeout,
+		IdleTimeout:       app.Config.HTTP.IdleTimeout,
 	}
 
 	go func(hs *HTTPServer) {
 		err := hs.Server.ListenAndServe()
 		if err != nil {
-			log.Println("failed to start http server", err)
+			slog.Error("failed to start http server", "error", err)
 		}
 	}(hs)
 
-	log.Printf("http server started at port %v", hs.Server.Addr)
+	slog.Info("http server started", "addr", hs.Server.Addr)
 
 	return hs
 }
 
-func CORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// enable cors
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With, Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Session-Key")
+// CORS lets the allowed origins call the API from a browser, * allows any origin. Preflight requests are
+// answered here, with 204 for an allowed origin and 403 otherwise.
+func CORS(allowedOrigins []string) func(next http.Handler) http.Handler {
+	allowAny := slices.Contains(allowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			allowed := origin != "" && (allowAny || slices.Contains(allowedOrigins, origin))
+
+			w.Header().Add("Vary", "Origin")
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag, Retry-After, Idempotent-Replayed")
+			}
+
+			// preflight request
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if !allowed {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With, Accept, Accept-Language, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Session-Key, X-Request-ID, Idempotency-Key, If-Match, traceparent, tracestate")
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
 
-		if r.Method != http.MethodOptions {
 			next.ServeHTTP(w, r)
-		}
-	})
+		})
+	}
 }
 
 func setupRouter(app *application.App) *chi.Mux {
@@ -69,16 +100,73 @@ func setupRouter(app *application.App) *chi.Mux {
 
 	healthCheckController := controller.NewHealthCheckController(app)
 	loanController := controller.NewLoanController(app)
+	employeeController := controller.NewEmployeeController(app)
+	loanProductController := controller.NewLoanProductController(app)
+	jobController := controller.NewJobController(app)
+	webhookController := controller.NewWebhookController(app)
+	notificationController := controller.NewNotificationController(app)
+	featureFlagController := controller.NewFeatureFlagController(app)
+	auditController := controller.NewAuditController(app)
+	personalDataController := controller.NewPersonalDataController(app)
+	idempotency := IdempotencyMiddleware(service.NewIdempotencyService(app))
+	rateLimit := RateLimitMiddleware(app.Config.RateLimit, service.NewRateLimitService(app), router)
 
 	// middleware
-	router.Use(CORS)
-	router.Get("/health-checks", healthCheckController.Ping)
+	router.Use(RequestIDMiddleware)
+	router.Use(ClientIPMiddleware(app.Config.HTTP.ClientIPHeader, app.Config.HTTP.TrustedProxies))
+	router.Use(TracingMiddleware)
+	router.Use(AccessLogMiddleware)
+	router.Use(MetricsMiddleware(app.MetricsRegistry))
+	router.Use(CORS(app.Config.HTTP.AllowedOrigins()))
+	router.Use(BodyLimitMiddleware(app.Config.HTTP.MaxBodyBytes))
+	router.Get("/livez", healthCheckController.Live)
+	router.Get("/readyz", healthCheckController.Ready)
+	// kept for existing monitors, same as /readyz
+	router.Get("/health-checks", healthCheckController.Ready)
+	router.Handle("/metrics", promhttp.HandlerFor(app.MetricsRegistry, promhttp.HandlerOpts{}))
 	router.Route("/v1", func(r chi.Router) {
 		r.Use(AuthMiddleware)
-		r.Post("/loans", loanController.CreateLoan)
+		r.Use(rateLimit)
+		r.With(idempotency).Post("/loans", loanController.CreateLoan)
+		r.Get("/loans/published", loanController.ListPublishedLoans)
+		r.Get("/loans/{id}", loanController.GetLoan)
 		r.Patch("/loans/{id}", loanController.UpdateLoanState)
-		r.Post("/loans/{id}/investments", loanController.CreateLoanInvestment)
-
+		r.With(idempotency).Post("/loans/{id}/investments", loanController.CreateLoanInvestment)
+		r.Get("/loan-products", loanProductController.ListLoanProducts)
+		r.Get("/loan-products/{id}", loanProductController.GetLoanProduct)
+		r.Get("/notification-preferences/{recipient_type}/{id}", notificationController.GetNotificationPreference)
+		r.Put("/notification-preferences/{recipient_type}/{id}", notificationController.UpdateNotificationPreference)
+		r.Get("/personal-data/{recipient_type}/{id}/export", personalDataController.ExportPersonalData)
+		r.Post("/personal-data/{recipient_type}/{id}/erasure-requests", personalDataController.CreateErasureRequest)
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Post("/employees", employeeController.CreateEmployee)
+			r.Get("/employees", employeeController.ListEmployees)
+			r.Get("/employees/{id}", employeeController.GetEmployee)
+			r.Put("/employees/{id}", employeeController.UpdateEmployee)
+			r.Post("/employees/{id}/deactivate", employeeController.DeactivateEmployee)
+			r.Get("/loan-products", loanProductController.ListAllLoanProducts)
+			r.Post("/loan-products", loanProductController.CreateLoanProduct)
+			r.Put("/loan-products/{id}", loanProductController.UpdateLoanProduct)
+			r.Get("/jobs", jobController.ListJobs)
+			r.Post("/jobs/{id}/retry", jobController.RetryJob)
+			r.Post("/webhooks", webhookController.CreateWebhookSubscription)
+			r.Get("/webhooks", webhookController.ListWebhookSubscriptions)
+			r.Get("/webhooks/{id}", webhookController.GetWebhookSubscription)
+			r.Put("/webhooks/{id}", webhookController.UpdateWebhookSubscription)
+			r.Delete("/webhooks/{id}", webhookController.DeleteWebhookSubscription)
+			r.Get("/webhooks/{id}/deliveries", webhookController.ListWebhookDeliveries)
+			r.Get("/webhooks/{id}/deliveries/{delivery_id}", webhookController.GetWebhookDelivery)
+			r.Post("/webhooks/{id}/deliveries/{delivery_id}/redeliver", webhookController.RedeliverWebhook)
+			r.Get("/feature-flags", featureFlagController.ListFeatureFlags)
+			r.Get("/feature-flags/{key}", featureFlagController.GetFeatureFlag)
+			r.Put("/feature-flags/{key}", featureFlagController.UpdateFeatureFlag)
+			r.Get("/audit-logs", auditController.ListAuditLogs)
+			r.Get("/audit-logs/verify", auditController.VerifyAuditLogs)
+			r.Get("/erasure-requests", personalDataController.ListErasureRequests)
+			r.Post("/erasure-requests/{id}/complete", personalDataController.CompleteErasureRequest)
+			r.Post("/erasure-requests/{id}/reject", personalDataController.RejectErasureRequest)
+		})
 	})
 
 	return router
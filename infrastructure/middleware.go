@@ -1,16 +1,290 @@
 package infrastructure
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/controller"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
 )
 
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// TODO: auth checking
 
-		// return hardcoded user id
+		// return hardcoded user id and role, the role is matched by feature flag rules
 		ctx := context.WithValue(r.Context(), "userID", "f2c86f5c-6578-4d63-aa01-5bd4246c3bd8")
+		ctx = context.WithValue(ctx, "userRole", "employee")
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDMiddleware takes the X-Request-ID header of the request or assigns a new id, returns it in the
+// response and puts it in the request context so every log of the request carries it.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(model.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(model.RequestIDHeader, requestID)
+		ctx := application.WithRequestID(r.Context(), requestID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > model.RequestIDMaxLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AccessLogMiddleware logs every request with its status and latency.
+func AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		route := r.URL.Path
+		if routeContext := chi.RouteContext(r.Context()); routeContext != nil && routeContext.RoutePattern() != "" {
+			route = routeContext.RoutePattern()
+		}
+
+		level := slog.LevelInfo
+		if recorder.statusCode >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.Log(r.Context(), level, "http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route,
+			"status", recorder.statusCode,
+			"bytes", recorder.bytes,
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// BodyLimitMiddleware rejects request bodies larger than maxBytes with 413, a body without a
+// Content-Length is cut off once it reaches the limit.
+func BodyLimitMiddleware(maxBytes int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				controller.WriteErrorResponse(w, r, model.ErrorRequestBodyTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitMiddleware limits the requests of every client per route, a limited request is answered with
+// 429 and a Retry-After header. Routes are matched on the router so every loan id shares one bucket.
+func RateLimitMiddleware(config configuration.RateLimit, rateLimitService service.IRateLimitService, router chi.Routes) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !config.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// unknown paths share one bucket so they cannot grow the buckets without bound
+			route := "*"
+			routeContext := chi.NewRouteContext()
+			if router.Match(routeContext, r.Method, r.URL.Path) {
+				route = routeContext.RoutePattern()
+			}
+
+			allowed, retryAfter := rateLimitService.Allow(application.ClientIP(r.Context()), r.Method+" "+route)
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				controller.WriteErrorResponse(w, r, model.ErrorRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIPMiddleware puts the address of the client in the request context, for rate limiting and the
+// audit log.
+func ClientIPMiddleware(header string, trustedProxies int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := application.WithClientIP(r.Context(), clientIP(r, header, trustedProxies))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// clientIP is the address the trusted proxies put in the header, counted from the right since every proxy
+// appends the address it got the request from and the client can send any value on the left. The address of
+// the connection is used when the header is not set or does not hold a valid IP there.
+func clientIP(r *http.Request, header string, trustedProxies int) string {
+	if header != "" {
+		addresses := strings.Split(strings.Join(r.Header.Values(header), ","), ",")
+		if index := len(addresses) - trustedProxies; trustedProxies > 0 && index >= 0 {
+			if addr, err := netip.ParseAddr(strings.TrimSpace(addresses[index])); err == nil {
+				return addr.Unmap().WithZone("").String()
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// idempotentReplayHeaders are the response headers stored with an idempotent response and replayed with it,
+// they describe the body, e.g. an error is application/problem+json in the language of the first request.
+var idempotentReplayHeaders = []string{"Content-Type", model.ContentLanguageHeader, model.ETagHeader}
+
+// IdempotencyMiddleware replays the stored response when a request is retried with the same
+// Idempotency-Key header. Requests without the header are passed through.
+func IdempotencyMiddleware(idempotencyService service.IIdempotencyService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(model.IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				controller.WriteErrorResponse(w, r, controller.RequestBodyError(err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			userID, _ := r.Context().Value("userID").(string)
+			replay, err := idempotencyService.BeginRequest(r.Context(), &model.BeginIdempotentRequest{
+				UserID: userID,
+				Key:    key,
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Body:   body,
+			})
+			if err != nil {
+				controller.WriteErrorResponse(w, r, err)
+				return
+			}
+
+			if replay != nil {
+				// responses stored before their headers were kept are all JSON
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				for name, value := range replay.ResponseHeaders {
+					w.Header().Set(name, value)
+				}
+				w.Header().Set(model.IdempotentReplayedHeader, "true")
+				w.WriteHeader(replay.ResponseCode)
+				_, _ = w.Write(replay.ResponseBody)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+			defer func() {
+				// a panicking handler releases the key so the request can be retried
+				if !completed {
+					recorder.statusCode = http.StatusInternalServerError
+				}
+
+				responseHeaders := map[string]string{}
+				for _, name := range idempotentReplayHeaders {
+					if value := w.Header().Get(name); value != "" {
+						responseHeaders[name] = value
+					}
+				}
+
+				err := idempotencyService.CompleteRequest(context.WithoutCancel(r.Context()), &model.CompleteIdempotentRequest{
+					UserID:          userID,
+					Key:             key,
+					ResponseCode:    recorder.statusCode,
+					ResponseHeaders: responseHeaders,
+					ResponseBody:    recorder.body.Bytes(),
+				})
+				if err != nil {
+					slog.ErrorContext(r.Context(), "IdempotencyMiddleware CompleteRequest error", "error", err)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+			completed = true
+		})
+	}
+}
+
+// responseRecorder keeps a copy of the response written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// statusRecorder keeps the status and size of the response for the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	bytes       int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	if !sr.wroteHeader {
+		sr.statusCode = statusCode
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += n
+	return n, err
+}
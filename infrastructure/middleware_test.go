@@ -0,0 +1,144 @@
+package infrastructure_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/infrastructure"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("ClientIPMiddleware", func() {
+	DescribeTable("should put the client address in the request context",
+		func(header string, trustedProxies int, values []string, expected string) {
+			request := httptest.NewRequest(http.MethodGet, "/v1/loans", nil)
+			request.RemoteAddr = "10.0.0.2:41234"
+			for _, value := range values {
+				request.Header.Add("X-Forwarded-For", value)
+			}
+
+			var clientIP string
+			handler := infrastructure.ClientIPMiddleware(header, trustedProxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				clientIP = application.ClientIP(r.Context())
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			Expect(clientIP).To(Equal(expected))
+		},
+		Entry("without a header configured", "", 1, []string{"203.0.113.7"}, "10.0.0.2"),
+		Entry("without the header", "X-Forwarded-For", 1, nil, "10.0.0.2"),
+		Entry("a single address", "X-Forwarded-For", 1, []string{"203.0.113.7"}, "203.0.113.7"),
+		Entry("an address spoofed by the client", "X-Forwarded-For", 1, []string{"198.51.100.1, 203.0.113.7"}, "203.0.113.7"),
+		Entry("two trusted proxies", "X-Forwarded-For", 2, []string{"198.51.100.1, 203.0.113.7, 10.0.0.9"}, "203.0.113.7"),
+		Entry("addresses over several header lines", "X-Forwarded-For", 2, []string{"198.51.100.1, 203.0.113.7", "10.0.0.9"}, "203.0.113.7"),
+		Entry("fewer addresses than trusted proxies", "X-Forwarded-For", 2, []string{"203.0.113.7"}, "10.0.0.2"),
+		Entry("an IPv6 address", "X-Forwarded-For", 1, []string{"2001:db8::7"}, "2001:db8::7"),
+		Entry("an IPv4-mapped IPv6 address", "X-Forwarded-For", 1, []string{"::ffff:203.0.113.7"}, "203.0.113.7"),
+		Entry("an IPv6 address with a zone", "X-Forwarded-For", 1, []string{"fe80::1%" + strings.Repeat("eth0", 50)}, "fe80::1"),
+		Entry("a value that is not an IP", "X-Forwarded-For", 1, []string{"203.0.113.7, not-an-ip"}, "10.0.0.2"),
+		Entry("an oversized value", "X-Forwarded-For", 1, []string{strings.Repeat("203.0.113.7", 20)}, "10.0.0.2"),
+		Entry("an empty value", "X-Forwarded-For", 1, []string{""}, "10.0.0.2"),
+	)
+})
+
+var _ = Describe("IdempotencyMiddleware", func() {
+	var (
+		mockCtrl            *gomock.Controller
+		mockIdempotencyRepo *mock.MockIIdempotencyRepository
+		handler             http.Handler
+		handled             int
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIdempotencyRepo = mock.NewMockIIdempotencyRepository(mockCtrl)
+		handled = 0
+
+		idempotencyService := &service.IdempotencyService{
+			IdempotencyRepository: mockIdempotencyRepo,
+			Config:                configuration.Idempotency{KeyTTL: 24 * time.Hour, ProcessingLease: time.Minute},
+			Now:                   time.Now,
+		}
+		handler = infrastructure.IdempotencyMiddleware(idempotencyService)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			handled++
+			w.Header().Set("Content-Type", model.ProblemContentType)
+			w.Header().Set(model.ContentLanguageHeader, "id")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"code":"loan_state_invalid"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/v1/loans", strings.NewReader(`{"borrower_id":"borrower-1"}`))
+		request.Header.Set(model.IdempotencyKeyHeader, "key-1")
+
+		return request.WithContext(context.WithValue(request.Context(), "userID", "user-1"))
+	}
+
+	// firstRequest runs the request once and returns the key as it was stored
+	firstRequest := func() *model.IdempotencyKey {
+		stored := &model.IdempotencyKey{Status: model.IdempotencyKeyStatusCompleted}
+		mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, idempotencyKey *model.IdempotencyKey, _ time.Time, _ time.Time) (bool, error) {
+				stored.RequestHash = idempotencyKey.RequestHash
+				return true, nil
+			})
+		mockIdempotencyRepo.EXPECT().CompleteIdempotencyKey(gomock.Any(), "user-1", "key-1", http.StatusUnprocessableEntity, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ string, responseCode int, responseHeaders map[string]string, responseBody []byte) error {
+				stored.ResponseCode = responseCode
+				stored.ResponseHeaders = responseHeaders
+				stored.ResponseBody = responseBody
+				return nil
+			})
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		return stored
+	}
+
+	replay := func(stored *model.IdempotencyKey) *httptest.ResponseRecorder {
+		mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+		mockIdempotencyRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "user-1", "key-1").Return(stored, nil)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest())
+
+		return recorder
+	}
+
+	It("should replay a stored response with the headers it was sent with", func() {
+		stored := firstRequest()
+		Expect(stored.ResponseHeaders).To(Equal(map[string]string{"Content-Type": model.ProblemContentType, model.ContentLanguageHeader: "id"}))
+
+		recorder := replay(stored)
+		Expect(handled).To(Equal(1))
+		Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal(model.ProblemContentType))
+		Expect(recorder.Header().Get(model.ContentLanguageHeader)).To(Equal("id"))
+		Expect(recorder.Header().Get(model.IdempotentReplayedHeader)).To(Equal("true"))
+		Expect(recorder.Body.String()).To(Equal(`{"code":"loan_state_invalid"}`))
+	})
+
+	It("should replay a response stored without headers as JSON", func() {
+		stored := firstRequest()
+		stored.ResponseHeaders = map[string]string{}
+
+		recorder := replay(stored)
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json; charset=UTF-8"))
+		Expect(recorder.Header().Get(model.IdempotentReplayedHeader)).To(Equal("true"))
+	})
+})
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/db"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migrator applies the migrations embedded in the binary. golang-migrate holds a postgres advisory lock
+// while it migrates, so replicas migrating at the same time wait for each other instead of racing.
+type Migrator struct {
+	Migrate *migrate.Migrate
+}
+
+// MigrationStatus compares the schema version with the newest embedded migration.
+type MigrationStatus struct {
+	Version       uint
+	Dirty         bool
+	LatestVersion uint
+	Pending       int
+}
+
+func NewMigrator(ctx context.Context, app *application.App) (*Migrator, error) {
+	source, err := iofs.New(db.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	// a dedicated connection, the advisory lock is held by the session
+	conn, err := app.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{
+		DatabaseName: app.Config.Database.Name,
+		SchemaName:   app.Config.Database.Schema,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, app.Config.Database.Name, driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	m.LockTimeout = app.Config.Database.MigrationLockTimeout
+	m.Log = &migrationLogger{ctx: ctx}
+
+	return &Migrator{Migrate: m}, nil
+}
+
+// Close releases the migration connection, the app DB stays open.
+func (mg *Migrator) Close() {
+	sourceErr, databaseErr := mg.Migrate.Close()
+	if err := errors.Join(sourceErr, databaseErr); err != nil {
+		slog.Error("migrator close error", "error", err)
+	}
+}
+
+// Up applies every pending migration, it is a no-op when the schema is up to date.
+func (mg *Migrator) Up() error {
+	err := mg.Migrate.Up()
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+// Down reverts the given number of applied migrations.
+func (mg *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+
+	err := mg.Migrate.Steps(-steps)
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+func (mg *Migrator) Status() (status *MigrationStatus, err error) {
+	status = &MigrationStatus{}
+
+	status.Version, status.Dirty, err = mg.Migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+
+	versions, err := embeddedMigrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		if version > status.Version {
+			status.Pending++
+		}
+		if version > status.LatestVersion {
+			status.LatestVersion = version
+		}
+	}
+
+	return status, nil
+}
+
+// embeddedMigrationVersions lists the versions of the embedded up migrations.
+func embeddedMigrationVersions() (versions []uint, err error) {
+	entries, err := fs.ReadDir(db.Migrations, "migrations")
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+
+		var version uint
+		if _, err = fmt.Sscanf(entry.Name(), "%d_", &version); err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		versions = append(versions, version)
+	}
+
+	return
+}
+
+// migrationLogger writes golang-migrate progress to the service log.
+type migrationLogger struct {
+	ctx context.Context
+}
+
+func (ml *migrationLogger) Printf(format string, v ...any) {
+	slog.InfoContext(ml.ctx, "migration: "+strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (ml *migrationLogger) Verbose() bool {
+	return false
+}
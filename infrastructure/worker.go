@@ -0,0 +1,194 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/controller"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+type JobWorker struct {
+	JobService      service.IJobService
+	Handlers        map[model.JobType]controller.JobHandler
+	WorkerID        string
+	Concurrency     int
+	PollInterval    time.Duration
+	ShutdownTimeout time.Duration
+	slots           chan struct{}
+	running         sync.WaitGroup
+	cancelPolling   context.CancelFunc
+	cancelJobs      context.CancelFunc
+	done            chan struct{}
+}
+
+// Close stops claiming new jobs and waits for the running ones. Jobs still running after the
+// shutdown timeout are canceled, their lock expires and another worker picks them up again.
+func (jw *JobWorker) Close() {
+	slog.Info("shutting down job worker")
+	jw.cancelPolling()
+	<-jw.done
+
+	finished := make(chan struct{})
+	go func() {
+		jw.running.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(jw.ShutdownTimeout):
+		slog.Warn("job worker shut down timeout, canceling running jobs")
+		jw.cancelJobs()
+		<-finished
+	}
+	jw.cancelJobs()
+
+	slog.Info("job worker shut down is successful")
+}
+
+func RunJobWorker(app *application.App) *JobWorker {
+	pollingCtx, cancelPolling := context.WithCancel(context.Background())
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+
+	hostname, _ := os.Hostname()
+	jw := &JobWorker{
+		JobService:      service.NewJobService(app),
+		Handlers:        setupJobHandlers(app),
+		WorkerID:        fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		Concurrency:     max(app.Config.Job.WorkerConcurrency, 1),
+		PollInterval:    app.Config.Job.PollInterval,
+		ShutdownTimeout: app.Config.Job.ShutdownTimeout,
+		cancelPolling:   cancelPolling,
+		cancelJobs:      cancelJobs,
+		done:            make(chan struct{}),
+	}
+	jw.slots = make(chan struct{}, jw.Concurrency)
+
+	registerRecurringJobs(pollingCtx, app, jw.JobService)
+
+	go func(jw *JobWorker) {
+		defer close(jw.done)
+
+		ticker := time.NewTicker(jw.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pollingCtx.Done():
+				return
+			case <-ticker.C:
+				jw.poll(pollingCtx, jobsCtx)
+			}
+		}
+	}(jw)
+
+	slog.Info("job worker started", "worker_id", jw.WorkerID, "concurrency", jw.Concurrency)
+
+	return jw
+}
+
+func setupJobHandlers(app *application.App) map[model.JobType]controller.JobHandler {
+	jobController := controller.NewJobController(app)
+
+	return map[model.JobType]controller.JobHandler{
+		model.JobTypeCancelExpiredLoans:     jobController.CancelExpiredLoans,
+		model.JobTypeDeliverWebhook:         jobController.DeliverWebhook,
+		model.JobTypeSendEmailNotification:  jobController.SendEmailNotification,
+		model.JobTypeSendRepaymentReminders: jobController.SendRepaymentReminders,
+		model.JobTypePurgeIdempotencyKeys:   jobController.PurgeIdempotencyKeys,
+		model.JobTypeReencryptPersonalData:  jobController.ReencryptPersonalData,
+	}
+}
+
+func registerRecurringJobs(ctx context.Context, app *application.App, jobService service.IJobService) {
+	err := jobService.RegisterRecurringJob(ctx, "cancel-expired-loans", app.Config.Loan.ExpirySchedule, model.JobTypeCancelExpiredLoans)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to register recurring job cancel-expired-loans", "error", err)
+	}
+
+	err = jobService.RegisterRecurringJob(ctx, "send-repayment-reminders", app.Config.Notification.RepaymentReminderSchedule, model.JobTypeSendRepaymentReminders)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to register recurring job send-repayment-reminders", "error", err)
+	}
+
+	err = jobService.RegisterRecurringJob(ctx, "purge-idempotency-keys", app.Config.Idempotency.PurgeSchedule, model.JobTypePurgeIdempotencyKeys)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to register recurring job purge-idempotency-keys", "error", err)
+	}
+
+	err = jobService.RegisterRecurringJob(ctx, "reencrypt-personal-data", app.Config.Encryption.ReencryptSchedule, model.JobTypeReencryptPersonalData)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to register recurring job reencrypt-personal-data", "error", err)
+	}
+}
+
+func (jw *JobWorker) poll(pollingCtx context.Context, jobsCtx context.Context) {
+	_, err := jw.JobService.ScheduleRecurringJobs(pollingCtx)
+	if err != nil {
+		slog.ErrorContext(pollingCtx, "job worker ScheduleRecurringJobs error", "error", err)
+	}
+
+	free := cap(jw.slots) - len(jw.slots)
+	if free == 0 {
+		return
+	}
+
+	jobs, err := jw.JobService.ClaimJobs(pollingCtx, jw.WorkerID, free)
+	if err != nil {
+		slog.ErrorContext(pollingCtx, "job worker ClaimJobs error", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		jw.slots <- struct{}{}
+		jw.running.Add(1)
+		go func(job *model.Job) {
+			defer func() {
+				<-jw.slots
+				jw.running.Done()
+			}()
+			jw.run(jobsCtx, job)
+		}(job)
+	}
+}
+
+func (jw *JobWorker) run(ctx context.Context, job *model.Job) {
+	err := jw.handle(ctx, job)
+
+	// record the outcome even when the worker is shutting down
+	if err != nil {
+		slog.WarnContext(ctx, "job attempt failed", "job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts, "error", err)
+		err = jw.JobService.FailJob(context.Background(), job, err)
+		if err != nil {
+			slog.ErrorContext(ctx, "job worker FailJob error", "error", err)
+		}
+		return
+	}
+
+	err = jw.JobService.CompleteJob(context.Background(), job)
+	if err != nil {
+		slog.ErrorContext(ctx, "job worker CompleteJob error", "error", err)
+	}
+}
+
+func (jw *JobWorker) handle(ctx context.Context, job *model.Job) (err error) {
+	handler, ok := jw.Handlers[job.Type]
+	if !ok {
+		return model.ErrorJobHandlerNotFound
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"runtime"
@@ -23,6 +24,24 @@ func main() {
 	}
 	defer app.Close()
 
+	if len(os.Args) > 1 {
+		err = runCommand(ctx, app, os.Args[1:])
+		cancel()
+		if err != nil {
+			app.Close()
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	if app.Config.Database.AutoMigrate {
+		err = migrateOnBoot(ctx, app)
+		if err != nil {
+			app.Close()
+			log.Fatalf("failed to migrate database: %v", err)
+		}
+	}
+
 	terminateChannel := make(chan os.Signal, 1)
 	signal.Notify(terminateChannel,
 		os.Interrupt,
@@ -32,16 +51,40 @@ func main() {
 	)
 
 	go func() {
-		msgStr := fmt.Sprintf("system call: %+v", <-terminateChannel)
-		log.Println(msgStr)
+		sig := <-terminateChannel
+		slog.Info("system call", "signal", sig.String())
 		cancel()
 	}()
 
-	log.Println("service started")
+	slog.InfoContext(ctx, "effective configuration", "config", app.Config.Redacted())
+	slog.Info("service started")
 	hs := infrastructure.RunHTTPServer(app)
+	jw := infrastructure.RunJobWorker(app)
+	er := infrastructure.RunEventRelay(app)
 
 	defer hs.Close()
+	defer jw.Close()
+	defer er.Close()
 	<-ctx.Done()
 
-	log.Println("service finished")
+	slog.Info("service finished")
+}
+
+func runCommand(ctx context.Context, app *application.App, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrateCommand(ctx, app, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func migrateOnBoot(ctx context.Context, app *application.App) error {
+	migrator, err := infrastructure.NewMigrator(ctx, app)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/infrastructure"
+)
+
+const migrateUsage = "usage: loan-service migrate up|down [steps]|status|version"
+
+// runMigrateCommand runs `loan-service migrate <command>` against the configured database.
+func runMigrateCommand(ctx context.Context, app *application.App, args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := infrastructure.NewMigrator(ctx, app)
+	if err != nil {
+		return
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q: %w", args[1], err)
+			}
+		}
+		err = migrator.Down(steps)
+	case "status":
+		var status *infrastructure.MigrationStatus
+		status, err = migrator.Status()
+		if err != nil {
+			return
+		}
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\npending: %d\n", status.Version, status.Dirty, status.LatestVersion, status.Pending)
+	case "version":
+		var status *infrastructure.MigrationStatus
+		status, err = migrator.Status()
+		if err != nil {
+			return
+		}
+		fmt.Println(status.Version)
+	default:
+		err = errors.New(migrateUsage)
+	}
+
+	return
+}
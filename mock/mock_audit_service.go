@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/audit.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuditService is a mock of IAuditService interface.
+type MockIAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditServiceMockRecorder
+}
+
+// MockIAuditServiceMockRecorder is the mock recorder for MockIAuditService.
+type MockIAuditServiceMockRecorder struct {
+	mock *MockIAuditService
+}
+
+// NewMockIAuditService creates a new mock instance.
+func NewMockIAuditService(ctrl *gomock.Controller) *MockIAuditService {
+	mock := &MockIAuditService{ctrl: ctrl}
+	mock.recorder = &MockIAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditService) EXPECT() *MockIAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// ListAuditLogs mocks base method.
+func (m *MockIAuditService) ListAuditLogs(ctx context.Context, listAuditLogsRequest *model.ListAuditLogsRequest) ([]*model.AuditLogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", ctx, listAuditLogsRequest)
+	ret0, _ := ret[0].([]*model.AuditLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockIAuditServiceMockRecorder) ListAuditLogs(ctx, listAuditLogsRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockIAuditService)(nil).ListAuditLogs), ctx, listAuditLogsRequest)
+}
+
+// RecordOperatorCommand mocks base method.
+func (m *MockIAuditService) RecordOperatorCommand(ctx context.Context, operatorCommand *model.OperatorCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOperatorCommand", ctx, operatorCommand)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOperatorCommand indicates an expected call of RecordOperatorCommand.
+func (mr *MockIAuditServiceMockRecorder) RecordOperatorCommand(ctx, operatorCommand interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOperatorCommand", reflect.TypeOf((*MockIAuditService)(nil).RecordOperatorCommand), ctx, operatorCommand)
+}
+
+// VerifyAuditLogs mocks base method.
+func (m *MockIAuditService) VerifyAuditLogs(ctx context.Context) (*model.AuditVerificationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditLogs", ctx)
+	ret0, _ := ret[0].(*model.AuditVerificationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditLogs indicates an expected call of VerifyAuditLogs.
+func (mr *MockIAuditServiceMockRecorder) VerifyAuditLogs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditLogs", reflect.TypeOf((*MockIAuditService)(nil).VerifyAuditLogs), ctx)
+}
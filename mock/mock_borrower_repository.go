@@ -7,6 +7,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/frencius/loan-service/model"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,35 @@ func (m *MockIBorrowerRepository) EXPECT() *MockIBorrowerRepositoryMockRecorder
 	return m.recorder
 }
 
+// CreateBorrower mocks base method.
+func (m *MockIBorrowerRepository) CreateBorrower(ctx context.Context, borrower *model.Borrower) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBorrower", ctx, borrower)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBorrower indicates an expected call of CreateBorrower.
+func (mr *MockIBorrowerRepositoryMockRecorder) CreateBorrower(ctx, borrower interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBorrower", reflect.TypeOf((*MockIBorrowerRepository)(nil).CreateBorrower), ctx, borrower)
+}
+
+// EraseBorrower mocks base method.
+func (m *MockIBorrowerRepository) EraseBorrower(ctx context.Context, id string, erasedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseBorrower", ctx, id, erasedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EraseBorrower indicates an expected call of EraseBorrower.
+func (mr *MockIBorrowerRepositoryMockRecorder) EraseBorrower(ctx, id, erasedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseBorrower", reflect.TypeOf((*MockIBorrowerRepository)(nil).EraseBorrower), ctx, id, erasedAt)
+}
+
 // GetBorrowerByID mocks base method.
 func (m *MockIBorrowerRepository) GetBorrowerByID(ctx context.Context, id string) (*model.Borrower, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +79,47 @@ func (mr *MockIBorrowerRepositoryMockRecorder) GetBorrowerByID(ctx, id interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowerByID", reflect.TypeOf((*MockIBorrowerRepository)(nil).GetBorrowerByID), ctx, id)
 }
+
+// GetBorrowerByNIK mocks base method.
+func (m *MockIBorrowerRepository) GetBorrowerByNIK(ctx context.Context, nik string) (*model.Borrower, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBorrowerByNIK", ctx, nik)
+	ret0, _ := ret[0].(*model.Borrower)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBorrowerByNIK indicates an expected call of GetBorrowerByNIK.
+func (mr *MockIBorrowerRepositoryMockRecorder) GetBorrowerByNIK(ctx, nik interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowerByNIK", reflect.TypeOf((*MockIBorrowerRepository)(nil).GetBorrowerByNIK), ctx, nik)
+}
+
+// ListBorrowersToReencrypt mocks base method.
+func (m *MockIBorrowerRepository) ListBorrowersToReencrypt(ctx context.Context, afterID string, limit int) ([]*model.Borrower, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBorrowersToReencrypt", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*model.Borrower)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBorrowersToReencrypt indicates an expected call of ListBorrowersToReencrypt.
+func (mr *MockIBorrowerRepositoryMockRecorder) ListBorrowersToReencrypt(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBorrowersToReencrypt", reflect.TypeOf((*MockIBorrowerRepository)(nil).ListBorrowersToReencrypt), ctx, afterID, limit)
+}
+
+// ReencryptBorrower mocks base method.
+func (m *MockIBorrowerRepository) ReencryptBorrower(ctx context.Context, borrower *model.Borrower) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptBorrower", ctx, borrower)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReencryptBorrower indicates an expected call of ReencryptBorrower.
+func (mr *MockIBorrowerRepositoryMockRecorder) ReencryptBorrower(ctx, borrower interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptBorrower", reflect.TypeOf((*MockIBorrowerRepository)(nil).ReencryptBorrower), ctx, borrower)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/email_sender.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIEmailSender is a mock of IEmailSender interface.
+type MockIEmailSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmailSenderMockRecorder
+}
+
+// MockIEmailSenderMockRecorder is the mock recorder for MockIEmailSender.
+type MockIEmailSenderMockRecorder struct {
+	mock *MockIEmailSender
+}
+
+// NewMockIEmailSender creates a new mock instance.
+func NewMockIEmailSender(ctrl *gomock.Controller) *MockIEmailSender {
+	mock := &MockIEmailSender{ctrl: ctrl}
+	mock.recorder = &MockIEmailSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmailSender) EXPECT() *MockIEmailSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockIEmailSender) Send(ctx context.Context, message *model.EmailMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockIEmailSenderMockRecorder) Send(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockIEmailSender)(nil).Send), ctx, message)
+}
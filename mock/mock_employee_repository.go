@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/employee.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIEmployeeRepository is a mock of IEmployeeRepository interface.
+type MockIEmployeeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmployeeRepositoryMockRecorder
+}
+
+// MockIEmployeeRepositoryMockRecorder is the mock recorder for MockIEmployeeRepository.
+type MockIEmployeeRepositoryMockRecorder struct {
+	mock *MockIEmployeeRepository
+}
+
+// NewMockIEmployeeRepository creates a new mock instance.
+func NewMockIEmployeeRepository(ctrl *gomock.Controller) *MockIEmployeeRepository {
+	mock := &MockIEmployeeRepository{ctrl: ctrl}
+	mock.recorder = &MockIEmployeeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmployeeRepository) EXPECT() *MockIEmployeeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEmployee mocks base method.
+func (m *MockIEmployeeRepository) CreateEmployee(ctx context.Context, employee *model.Employee) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmployee", ctx, employee)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmployee indicates an expected call of CreateEmployee.
+func (mr *MockIEmployeeRepositoryMockRecorder) CreateEmployee(ctx, employee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmployee", reflect.TypeOf((*MockIEmployeeRepository)(nil).CreateEmployee), ctx, employee)
+}
+
+// DeactivateEmployee mocks base method.
+func (m *MockIEmployeeRepository) DeactivateEmployee(ctx context.Context, id, deactivatedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateEmployee", ctx, id, deactivatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateEmployee indicates an expected call of DeactivateEmployee.
+func (mr *MockIEmployeeRepositoryMockRecorder) DeactivateEmployee(ctx, id, deactivatedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateEmployee", reflect.TypeOf((*MockIEmployeeRepository)(nil).DeactivateEmployee), ctx, id, deactivatedBy)
+}
+
+// GetEmployeeByID mocks base method.
+func (m *MockIEmployeeRepository) GetEmployeeByID(ctx context.Context, id string) (*model.Employee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmployeeByID", ctx, id)
+	ret0, _ := ret[0].(*model.Employee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmployeeByID indicates an expected call of GetEmployeeByID.
+func (mr *MockIEmployeeRepositoryMockRecorder) GetEmployeeByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmployeeByID", reflect.TypeOf((*MockIEmployeeRepository)(nil).GetEmployeeByID), ctx, id)
+}
+
+// GetEmployeesByIDs mocks base method.
+func (m *MockIEmployeeRepository) GetEmployeesByIDs(ctx context.Context, ids []string) ([]*model.Employee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmployeesByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*model.Employee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmployeesByIDs indicates an expected call of GetEmployeesByIDs.
+func (mr *MockIEmployeeRepositoryMockRecorder) GetEmployeesByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmployeesByIDs", reflect.TypeOf((*MockIEmployeeRepository)(nil).GetEmployeesByIDs), ctx, ids)
+}
+
+// ListEmployees mocks base method.
+func (m *MockIEmployeeRepository) ListEmployees(ctx context.Context, activeOnly bool) ([]*model.Employee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmployees", ctx, activeOnly)
+	ret0, _ := ret[0].([]*model.Employee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmployees indicates an expected call of ListEmployees.
+func (mr *MockIEmployeeRepositoryMockRecorder) ListEmployees(ctx, activeOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmployees", reflect.TypeOf((*MockIEmployeeRepository)(nil).ListEmployees), ctx, activeOnly)
+}
+
+// UpdateEmployee mocks base method.
+func (m *MockIEmployeeRepository) UpdateEmployee(ctx context.Context, employee *model.Employee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmployee", ctx, employee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmployee indicates an expected call of UpdateEmployee.
+func (mr *MockIEmployeeRepositoryMockRecorder) UpdateEmployee(ctx, employee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmployee", reflect.TypeOf((*MockIEmployeeRepository)(nil).UpdateEmployee), ctx, employee)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/event_sink.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIEventSink is a mock of IEventSink interface.
+type MockIEventSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEventSinkMockRecorder
+}
+
+// MockIEventSinkMockRecorder is the mock recorder for MockIEventSink.
+type MockIEventSinkMockRecorder struct {
+	mock *MockIEventSink
+}
+
+// NewMockIEventSink creates a new mock instance.
+func NewMockIEventSink(ctrl *gomock.Controller) *MockIEventSink {
+	mock := &MockIEventSink{ctrl: ctrl}
+	mock.recorder = &MockIEventSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEventSink) EXPECT() *MockIEventSinkMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockIEventSink) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockIEventSinkMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockIEventSink)(nil).Name))
+}
+
+// Publish mocks base method.
+func (m *MockIEventSink) Publish(ctx context.Context, envelope *model.EventEnvelope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, envelope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockIEventSinkMockRecorder) Publish(ctx, envelope interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockIEventSink)(nil).Publish), ctx, envelope)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/feature_flag.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIFeatureFlagRepository is a mock of IFeatureFlagRepository interface.
+type MockIFeatureFlagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFeatureFlagRepositoryMockRecorder
+}
+
+// MockIFeatureFlagRepositoryMockRecorder is the mock recorder for MockIFeatureFlagRepository.
+type MockIFeatureFlagRepositoryMockRecorder struct {
+	mock *MockIFeatureFlagRepository
+}
+
+// NewMockIFeatureFlagRepository creates a new mock instance.
+func NewMockIFeatureFlagRepository(ctrl *gomock.Controller) *MockIFeatureFlagRepository {
+	mock := &MockIFeatureFlagRepository{ctrl: ctrl}
+	mock.recorder = &MockIFeatureFlagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFeatureFlagRepository) EXPECT() *MockIFeatureFlagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetFeatureFlagByKey mocks base method.
+func (m *MockIFeatureFlagRepository) GetFeatureFlagByKey(ctx context.Context, key string) (*model.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlagByKey", ctx, key)
+	ret0, _ := ret[0].(*model.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlagByKey indicates an expected call of GetFeatureFlagByKey.
+func (mr *MockIFeatureFlagRepositoryMockRecorder) GetFeatureFlagByKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlagByKey", reflect.TypeOf((*MockIFeatureFlagRepository)(nil).GetFeatureFlagByKey), ctx, key)
+}
+
+// ListFeatureFlags mocks base method.
+func (m *MockIFeatureFlagRepository) ListFeatureFlags(ctx context.Context) ([]*model.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeatureFlags", ctx)
+	ret0, _ := ret[0].([]*model.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeatureFlags indicates an expected call of ListFeatureFlags.
+func (mr *MockIFeatureFlagRepositoryMockRecorder) ListFeatureFlags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeatureFlags", reflect.TypeOf((*MockIFeatureFlagRepository)(nil).ListFeatureFlags), ctx)
+}
+
+// UpsertFeatureFlag mocks base method.
+func (m *MockIFeatureFlagRepository) UpsertFeatureFlag(ctx context.Context, featureFlag *model.FeatureFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFeatureFlag", ctx, featureFlag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertFeatureFlag indicates an expected call of UpsertFeatureFlag.
+func (mr *MockIFeatureFlagRepositoryMockRecorder) UpsertFeatureFlag(ctx, featureFlag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFeatureFlag", reflect.TypeOf((*MockIFeatureFlagRepository)(nil).UpsertFeatureFlag), ctx, featureFlag)
+}
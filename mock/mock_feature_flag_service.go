@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/feature_flag.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIFeatureFlagService is a mock of IFeatureFlagService interface.
+type MockIFeatureFlagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFeatureFlagServiceMockRecorder
+}
+
+// MockIFeatureFlagServiceMockRecorder is the mock recorder for MockIFeatureFlagService.
+type MockIFeatureFlagServiceMockRecorder struct {
+	mock *MockIFeatureFlagService
+}
+
+// NewMockIFeatureFlagService creates a new mock instance.
+func NewMockIFeatureFlagService(ctrl *gomock.Controller) *MockIFeatureFlagService {
+	mock := &MockIFeatureFlagService{ctrl: ctrl}
+	mock.recorder = &MockIFeatureFlagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFeatureFlagService) EXPECT() *MockIFeatureFlagServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFeatureFlag mocks base method.
+func (m *MockIFeatureFlagService) GetFeatureFlag(ctx context.Context, getFeatureFlagRequest *model.GetFeatureFlagRequest) (*model.FeatureFlagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlag", ctx, getFeatureFlagRequest)
+	ret0, _ := ret[0].(*model.FeatureFlagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlag indicates an expected call of GetFeatureFlag.
+func (mr *MockIFeatureFlagServiceMockRecorder) GetFeatureFlag(ctx, getFeatureFlagRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlag", reflect.TypeOf((*MockIFeatureFlagService)(nil).GetFeatureFlag), ctx, getFeatureFlagRequest)
+}
+
+// IsEnabled mocks base method.
+func (m *MockIFeatureFlagService) IsEnabled(ctx context.Context, key string, featureFlagContext *model.FeatureFlagContext) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, key, featureFlagContext)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockIFeatureFlagServiceMockRecorder) IsEnabled(ctx, key, featureFlagContext interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockIFeatureFlagService)(nil).IsEnabled), ctx, key, featureFlagContext)
+}
+
+// ListFeatureFlags mocks base method.
+func (m *MockIFeatureFlagService) ListFeatureFlags(ctx context.Context) ([]*model.FeatureFlagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeatureFlags", ctx)
+	ret0, _ := ret[0].([]*model.FeatureFlagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeatureFlags indicates an expected call of ListFeatureFlags.
+func (mr *MockIFeatureFlagServiceMockRecorder) ListFeatureFlags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeatureFlags", reflect.TypeOf((*MockIFeatureFlagService)(nil).ListFeatureFlags), ctx)
+}
+
+// UpdateFeatureFlag mocks base method.
+func (m *MockIFeatureFlagService) UpdateFeatureFlag(ctx context.Context, updateFeatureFlagRequest *model.UpdateFeatureFlagRequest) (*model.FeatureFlagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeatureFlag", ctx, updateFeatureFlagRequest)
+	ret0, _ := ret[0].(*model.FeatureFlagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeatureFlag indicates an expected call of UpdateFeatureFlag.
+func (mr *MockIFeatureFlagServiceMockRecorder) UpdateFeatureFlag(ctx, updateFeatureFlagRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeatureFlag", reflect.TypeOf((*MockIFeatureFlagService)(nil).UpdateFeatureFlag), ctx, updateFeatureFlagRequest)
+}
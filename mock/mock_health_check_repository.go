@@ -1,13 +1,26 @@
 package mock
 
 import (
+	"context"
+	"time"
+
 	"github.com/frencius/loan-service/model"
 )
 
 type MockHealthCheckRepository struct {
-	PingFunc func() (*model.Ping, error)
+	PingDatabaseFunc      func(ctx context.Context) error
+	GetMigrationStateFunc func(ctx context.Context) (*model.MigrationState, error)
+	GetJobQueueLagFunc    func(ctx context.Context) (time.Duration, error)
+}
+
+func (m *MockHealthCheckRepository) PingDatabase(ctx context.Context) error {
+	return m.PingDatabaseFunc(ctx)
+}
+
+func (m *MockHealthCheckRepository) GetMigrationState(ctx context.Context) (*model.MigrationState, error) {
+	return m.GetMigrationStateFunc(ctx)
 }
 
-func (m *MockHealthCheckRepository) Ping() (*model.Ping, error) {
-	return m.PingFunc()
+func (m *MockHealthCheckRepository) GetJobQueueLag(ctx context.Context) (time.Duration, error) {
+	return m.GetJobQueueLagFunc(ctx)
 }
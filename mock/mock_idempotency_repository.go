@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/idempotency.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdempotencyRepository is a mock of IIdempotencyRepository interface.
+type MockIIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyRepositoryMockRecorder
+}
+
+// MockIIdempotencyRepositoryMockRecorder is the mock recorder for MockIIdempotencyRepository.
+type MockIIdempotencyRepositoryMockRecorder struct {
+	mock *MockIIdempotencyRepository
+}
+
+// NewMockIIdempotencyRepository creates a new mock instance.
+func NewMockIIdempotencyRepository(ctrl *gomock.Controller) *MockIIdempotencyRepository {
+	mock := &MockIIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyRepository) EXPECT() *MockIIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockIIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, userID, key string, responseCode int, responseHeaders map[string]string, responseBody []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, userID, key, responseCode, responseHeaders, responseBody)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockIIdempotencyRepositoryMockRecorder) CompleteIdempotencyKey(ctx, userID, key, responseCode, responseHeaders, responseBody interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockIIdempotencyRepository)(nil).CompleteIdempotencyKey), ctx, userID, key, responseCode, responseHeaders, responseBody)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockIIdempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey *model.IdempotencyKey, now, leaseExpiredAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, idempotencyKey, now, leaseExpiredAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockIIdempotencyRepositoryMockRecorder) CreateIdempotencyKey(ctx, idempotencyKey, now, leaseExpiredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockIIdempotencyRepository)(nil).CreateIdempotencyKey), ctx, idempotencyKey, now, leaseExpiredAt)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockIIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockIIdempotencyRepositoryMockRecorder) DeleteExpiredIdempotencyKeys(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockIIdempotencyRepository)(nil).DeleteExpiredIdempotencyKeys), ctx, now)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, userID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIIdempotencyRepositoryMockRecorder) DeleteIdempotencyKey(ctx, userID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIIdempotencyRepository)(nil).DeleteIdempotencyKey), ctx, userID, key)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockIIdempotencyRepository) GetIdempotencyKey(ctx context.Context, userID, key string) (*model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(*model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockIIdempotencyRepositoryMockRecorder) GetIdempotencyKey(ctx, userID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockIIdempotencyRepository)(nil).GetIdempotencyKey), ctx, userID, key)
+}
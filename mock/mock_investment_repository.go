@@ -64,3 +64,48 @@ func (mr *MockIInvestmentRepositoryMockRecorder) GetInvestmentByInvestorID(ctx,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvestmentByInvestorID", reflect.TypeOf((*MockIInvestmentRepository)(nil).GetInvestmentByInvestorID), ctx, id)
 }
+
+// ListInvestmentsByInvestorID mocks base method.
+func (m *MockIInvestmentRepository) ListInvestmentsByInvestorID(ctx context.Context, investorID string) ([]*model.Investment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvestmentsByInvestorID", ctx, investorID)
+	ret0, _ := ret[0].([]*model.Investment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvestmentsByInvestorID indicates an expected call of ListInvestmentsByInvestorID.
+func (mr *MockIInvestmentRepositoryMockRecorder) ListInvestmentsByInvestorID(ctx, investorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvestmentsByInvestorID", reflect.TypeOf((*MockIInvestmentRepository)(nil).ListInvestmentsByInvestorID), ctx, investorID)
+}
+
+// ListInvestmentsByLoanID mocks base method.
+func (m *MockIInvestmentRepository) ListInvestmentsByLoanID(ctx context.Context, loanID string) ([]*model.Investment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvestmentsByLoanID", ctx, loanID)
+	ret0, _ := ret[0].([]*model.Investment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvestmentsByLoanID indicates an expected call of ListInvestmentsByLoanID.
+func (mr *MockIInvestmentRepositoryMockRecorder) ListInvestmentsByLoanID(ctx, loanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvestmentsByLoanID", reflect.TypeOf((*MockIInvestmentRepository)(nil).ListInvestmentsByLoanID), ctx, loanID)
+}
+
+// ReleaseInvestmentsByLoanID mocks base method.
+func (m *MockIInvestmentRepository) ReleaseInvestmentsByLoanID(ctx context.Context, loanID string) ([]*model.Investment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseInvestmentsByLoanID", ctx, loanID)
+	ret0, _ := ret[0].([]*model.Investment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseInvestmentsByLoanID indicates an expected call of ReleaseInvestmentsByLoanID.
+func (mr *MockIInvestmentRepositoryMockRecorder) ReleaseInvestmentsByLoanID(ctx, loanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseInvestmentsByLoanID", reflect.TypeOf((*MockIInvestmentRepository)(nil).ReleaseInvestmentsByLoanID), ctx, loanID)
+}
@@ -7,6 +7,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/frencius/loan-service/model"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,35 @@ func (m *MockIInvestorRepository) EXPECT() *MockIInvestorRepositoryMockRecorder
 	return m.recorder
 }
 
+// CreateInvestor mocks base method.
+func (m *MockIInvestorRepository) CreateInvestor(ctx context.Context, investor *model.Investor) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvestor", ctx, investor)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvestor indicates an expected call of CreateInvestor.
+func (mr *MockIInvestorRepositoryMockRecorder) CreateInvestor(ctx, investor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvestor", reflect.TypeOf((*MockIInvestorRepository)(nil).CreateInvestor), ctx, investor)
+}
+
+// EraseInvestor mocks base method.
+func (m *MockIInvestorRepository) EraseInvestor(ctx context.Context, id string, erasedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseInvestor", ctx, id, erasedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EraseInvestor indicates an expected call of EraseInvestor.
+func (mr *MockIInvestorRepositoryMockRecorder) EraseInvestor(ctx, id, erasedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseInvestor", reflect.TypeOf((*MockIInvestorRepository)(nil).EraseInvestor), ctx, id, erasedAt)
+}
+
 // GetInvestorByID mocks base method.
 func (m *MockIInvestorRepository) GetInvestorByID(ctx context.Context, id string) (*model.Investor, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +79,47 @@ func (mr *MockIInvestorRepositoryMockRecorder) GetInvestorByID(ctx, id interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvestorByID", reflect.TypeOf((*MockIInvestorRepository)(nil).GetInvestorByID), ctx, id)
 }
+
+// GetInvestorByNIK mocks base method.
+func (m *MockIInvestorRepository) GetInvestorByNIK(ctx context.Context, nik string) (*model.Investor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvestorByNIK", ctx, nik)
+	ret0, _ := ret[0].(*model.Investor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvestorByNIK indicates an expected call of GetInvestorByNIK.
+func (mr *MockIInvestorRepositoryMockRecorder) GetInvestorByNIK(ctx, nik interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvestorByNIK", reflect.TypeOf((*MockIInvestorRepository)(nil).GetInvestorByNIK), ctx, nik)
+}
+
+// ListInvestorsToReencrypt mocks base method.
+func (m *MockIInvestorRepository) ListInvestorsToReencrypt(ctx context.Context, afterID string, limit int) ([]*model.Investor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvestorsToReencrypt", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*model.Investor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvestorsToReencrypt indicates an expected call of ListInvestorsToReencrypt.
+func (mr *MockIInvestorRepositoryMockRecorder) ListInvestorsToReencrypt(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvestorsToReencrypt", reflect.TypeOf((*MockIInvestorRepository)(nil).ListInvestorsToReencrypt), ctx, afterID, limit)
+}
+
+// ReencryptInvestor mocks base method.
+func (m *MockIInvestorRepository) ReencryptInvestor(ctx context.Context, investor *model.Investor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptInvestor", ctx, investor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReencryptInvestor indicates an expected call of ReencryptInvestor.
+func (mr *MockIInvestorRepositoryMockRecorder) ReencryptInvestor(ctx, investor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptInvestor", reflect.TypeOf((*MockIInvestorRepository)(nil).ReencryptInvestor), ctx, investor)
+}
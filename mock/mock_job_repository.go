@@ -0,0 +1,198 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/job.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIJobRepository is a mock of IJobRepository interface.
+type MockIJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIJobRepositoryMockRecorder
+}
+
+// MockIJobRepositoryMockRecorder is the mock recorder for MockIJobRepository.
+type MockIJobRepositoryMockRecorder struct {
+	mock *MockIJobRepository
+}
+
+// NewMockIJobRepository creates a new mock instance.
+func NewMockIJobRepository(ctrl *gomock.Controller) *MockIJobRepository {
+	mock := &MockIJobRepository{ctrl: ctrl}
+	mock.recorder = &MockIJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIJobRepository) EXPECT() *MockIJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimJobs mocks base method.
+func (m *MockIJobRepository) ClaimJobs(ctx context.Context, workerID string, limit int, lockTimeout time.Duration) ([]*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJobs", ctx, workerID, limit, lockTimeout)
+	ret0, _ := ret[0].([]*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJobs indicates an expected call of ClaimJobs.
+func (mr *MockIJobRepositoryMockRecorder) ClaimJobs(ctx, workerID, limit, lockTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJobs", reflect.TypeOf((*MockIJobRepository)(nil).ClaimJobs), ctx, workerID, limit, lockTimeout)
+}
+
+// CompleteJob mocks base method.
+func (m *MockIJobRepository) CompleteJob(ctx context.Context, id, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", ctx, id, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockIJobRepositoryMockRecorder) CompleteJob(ctx, id, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockIJobRepository)(nil).CompleteJob), ctx, id, workerID)
+}
+
+// DeadLetterExpiredJobs mocks base method.
+func (m *MockIJobRepository) DeadLetterExpiredJobs(ctx context.Context, lockTimeout time.Duration) ([]*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterExpiredJobs", ctx, lockTimeout)
+	ret0, _ := ret[0].([]*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetterExpiredJobs indicates an expected call of DeadLetterExpiredJobs.
+func (mr *MockIJobRepositoryMockRecorder) DeadLetterExpiredJobs(ctx, lockTimeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterExpiredJobs", reflect.TypeOf((*MockIJobRepository)(nil).DeadLetterExpiredJobs), ctx, lockTimeout)
+}
+
+// EnqueueJob mocks base method.
+func (m *MockIJobRepository) EnqueueJob(ctx context.Context, job *model.Job) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueJob", ctx, job)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueJob indicates an expected call of EnqueueJob.
+func (mr *MockIJobRepositoryMockRecorder) EnqueueJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueJob", reflect.TypeOf((*MockIJobRepository)(nil).EnqueueJob), ctx, job)
+}
+
+// EnqueueRecurringJob mocks base method.
+func (m *MockIJobRepository) EnqueueRecurringJob(ctx context.Context, recurringJob *model.RecurringJob, nextRunAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueRecurringJob", ctx, recurringJob, nextRunAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueRecurringJob indicates an expected call of EnqueueRecurringJob.
+func (mr *MockIJobRepositoryMockRecorder) EnqueueRecurringJob(ctx, recurringJob, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueRecurringJob", reflect.TypeOf((*MockIJobRepository)(nil).EnqueueRecurringJob), ctx, recurringJob, nextRunAt)
+}
+
+// FailJob mocks base method.
+func (m *MockIJobRepository) FailJob(ctx context.Context, id, workerID, lastError string, nextRunAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailJob", ctx, id, workerID, lastError, nextRunAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailJob indicates an expected call of FailJob.
+func (mr *MockIJobRepositoryMockRecorder) FailJob(ctx, id, workerID, lastError, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailJob", reflect.TypeOf((*MockIJobRepository)(nil).FailJob), ctx, id, workerID, lastError, nextRunAt)
+}
+
+// GetJobByID mocks base method.
+func (m *MockIJobRepository) GetJobByID(ctx context.Context, id string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobByID", ctx, id)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobByID indicates an expected call of GetJobByID.
+func (mr *MockIJobRepositoryMockRecorder) GetJobByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobByID", reflect.TypeOf((*MockIJobRepository)(nil).GetJobByID), ctx, id)
+}
+
+// ListDueRecurringJobs mocks base method.
+func (m *MockIJobRepository) ListDueRecurringJobs(ctx context.Context, now time.Time) ([]*model.RecurringJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueRecurringJobs", ctx, now)
+	ret0, _ := ret[0].([]*model.RecurringJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueRecurringJobs indicates an expected call of ListDueRecurringJobs.
+func (mr *MockIJobRepositoryMockRecorder) ListDueRecurringJobs(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueRecurringJobs", reflect.TypeOf((*MockIJobRepository)(nil).ListDueRecurringJobs), ctx, now)
+}
+
+// ListJobs mocks base method.
+func (m *MockIJobRepository) ListJobs(ctx context.Context, status model.JobStatus, limit int) ([]*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, status, limit)
+	ret0, _ := ret[0].([]*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockIJobRepositoryMockRecorder) ListJobs(ctx, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockIJobRepository)(nil).ListJobs), ctx, status, limit)
+}
+
+// RetryJob mocks base method.
+func (m *MockIJobRepository) RetryJob(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryJob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryJob indicates an expected call of RetryJob.
+func (mr *MockIJobRepositoryMockRecorder) RetryJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockIJobRepository)(nil).RetryJob), ctx, id)
+}
+
+// UpsertRecurringJob mocks base method.
+func (m *MockIJobRepository) UpsertRecurringJob(ctx context.Context, recurringJob *model.RecurringJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRecurringJob", ctx, recurringJob)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRecurringJob indicates an expected call of UpsertRecurringJob.
+func (mr *MockIJobRepositoryMockRecorder) UpsertRecurringJob(ctx, recurringJob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRecurringJob", reflect.TypeOf((*MockIJobRepository)(nil).UpsertRecurringJob), ctx, recurringJob)
+}
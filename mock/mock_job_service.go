@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/job.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIJobService is a mock of IJobService interface.
+type MockIJobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIJobServiceMockRecorder
+}
+
+// MockIJobServiceMockRecorder is the mock recorder for MockIJobService.
+type MockIJobServiceMockRecorder struct {
+	mock *MockIJobService
+}
+
+// NewMockIJobService creates a new mock instance.
+func NewMockIJobService(ctrl *gomock.Controller) *MockIJobService {
+	mock := &MockIJobService{ctrl: ctrl}
+	mock.recorder = &MockIJobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIJobService) EXPECT() *MockIJobServiceMockRecorder {
+	return m.recorder
+}
+
+// ClaimJobs mocks base method.
+func (m *MockIJobService) ClaimJobs(ctx context.Context, workerID string, limit int) ([]*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJobs", ctx, workerID, limit)
+	ret0, _ := ret[0].([]*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJobs indicates an expected call of ClaimJobs.
+func (mr *MockIJobServiceMockRecorder) ClaimJobs(ctx, workerID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJobs", reflect.TypeOf((*MockIJobService)(nil).ClaimJobs), ctx, workerID, limit)
+}
+
+// CompleteJob mocks base method.
+func (m *MockIJobService) CompleteJob(ctx context.Context, job *model.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockIJobServiceMockRecorder) CompleteJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockIJobService)(nil).CompleteJob), ctx, job)
+}
+
+// EnqueueJob mocks base method.
+func (m *MockIJobService) EnqueueJob(ctx context.Context, jobType model.JobType, payload any, runAt *time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueJob", ctx, jobType, payload, runAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueJob indicates an expected call of EnqueueJob.
+func (mr *MockIJobServiceMockRecorder) EnqueueJob(ctx, jobType, payload, runAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueJob", reflect.TypeOf((*MockIJobService)(nil).EnqueueJob), ctx, jobType, payload, runAt)
+}
+
+// FailJob mocks base method.
+func (m *MockIJobService) FailJob(ctx context.Context, job *model.Job, jobErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailJob", ctx, job, jobErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailJob indicates an expected call of FailJob.
+func (mr *MockIJobServiceMockRecorder) FailJob(ctx, job, jobErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailJob", reflect.TypeOf((*MockIJobService)(nil).FailJob), ctx, job, jobErr)
+}
+
+// ListJobs mocks base method.
+func (m *MockIJobService) ListJobs(ctx context.Context, listJobsRequest *model.ListJobsRequest) ([]*model.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, listJobsRequest)
+	ret0, _ := ret[0].([]*model.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockIJobServiceMockRecorder) ListJobs(ctx, listJobsRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockIJobService)(nil).ListJobs), ctx, listJobsRequest)
+}
+
+// RegisterRecurringJob mocks base method.
+func (m *MockIJobService) RegisterRecurringJob(ctx context.Context, name, schedule string, jobType model.JobType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterRecurringJob", ctx, name, schedule, jobType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterRecurringJob indicates an expected call of RegisterRecurringJob.
+func (mr *MockIJobServiceMockRecorder) RegisterRecurringJob(ctx, name, schedule, jobType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRecurringJob", reflect.TypeOf((*MockIJobService)(nil).RegisterRecurringJob), ctx, name, schedule, jobType)
+}
+
+// RetryJob mocks base method.
+func (m *MockIJobService) RetryJob(ctx context.Context, retryJobRequest *model.RetryJobRequest) (*model.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryJob", ctx, retryJobRequest)
+	ret0, _ := ret[0].(*model.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryJob indicates an expected call of RetryJob.
+func (mr *MockIJobServiceMockRecorder) RetryJob(ctx, retryJobRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockIJobService)(nil).RetryJob), ctx, retryJobRequest)
+}
+
+// ScheduleRecurringJobs mocks base method.
+func (m *MockIJobService) ScheduleRecurringJobs(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleRecurringJobs", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleRecurringJobs indicates an expected call of ScheduleRecurringJobs.
+func (mr *MockIJobServiceMockRecorder) ScheduleRecurringJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleRecurringJobs", reflect.TypeOf((*MockIJobService)(nil).ScheduleRecurringJobs), ctx)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/loan_product.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILoanProductRepository is a mock of ILoanProductRepository interface.
+type MockILoanProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoanProductRepositoryMockRecorder
+}
+
+// MockILoanProductRepositoryMockRecorder is the mock recorder for MockILoanProductRepository.
+type MockILoanProductRepositoryMockRecorder struct {
+	mock *MockILoanProductRepository
+}
+
+// NewMockILoanProductRepository creates a new mock instance.
+func NewMockILoanProductRepository(ctrl *gomock.Controller) *MockILoanProductRepository {
+	mock := &MockILoanProductRepository{ctrl: ctrl}
+	mock.recorder = &MockILoanProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoanProductRepository) EXPECT() *MockILoanProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateLoanProduct mocks base method.
+func (m *MockILoanProductRepository) CreateLoanProduct(ctx context.Context, product *model.LoanProduct) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanProduct", ctx, product)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanProduct indicates an expected call of CreateLoanProduct.
+func (mr *MockILoanProductRepositoryMockRecorder) CreateLoanProduct(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanProduct", reflect.TypeOf((*MockILoanProductRepository)(nil).CreateLoanProduct), ctx, product)
+}
+
+// GetLoanProductByID mocks base method.
+func (m *MockILoanProductRepository) GetLoanProductByID(ctx context.Context, id string) (*model.LoanProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanProductByID", ctx, id)
+	ret0, _ := ret[0].(*model.LoanProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanProductByID indicates an expected call of GetLoanProductByID.
+func (mr *MockILoanProductRepositoryMockRecorder) GetLoanProductByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanProductByID", reflect.TypeOf((*MockILoanProductRepository)(nil).GetLoanProductByID), ctx, id)
+}
+
+// ListLoanProducts mocks base method.
+func (m *MockILoanProductRepository) ListLoanProducts(ctx context.Context, activeOnly bool) ([]*model.LoanProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoanProducts", ctx, activeOnly)
+	ret0, _ := ret[0].([]*model.LoanProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoanProducts indicates an expected call of ListLoanProducts.
+func (mr *MockILoanProductRepositoryMockRecorder) ListLoanProducts(ctx, activeOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoanProducts", reflect.TypeOf((*MockILoanProductRepository)(nil).ListLoanProducts), ctx, activeOnly)
+}
+
+// UpdateLoanProduct mocks base method.
+func (m *MockILoanProductRepository) UpdateLoanProduct(ctx context.Context, product *model.LoanProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoanProduct", ctx, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLoanProduct indicates an expected call of UpdateLoanProduct.
+func (mr *MockILoanProductRepositoryMockRecorder) UpdateLoanProduct(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoanProduct", reflect.TypeOf((*MockILoanProductRepository)(nil).UpdateLoanProduct), ctx, product)
+}
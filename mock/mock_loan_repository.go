@@ -7,6 +7,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/frencius/loan-service/model"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,21 @@ func (m *MockILoanRepository) EXPECT() *MockILoanRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountLoansByState mocks base method.
+func (m *MockILoanRepository) CountLoansByState(ctx context.Context) (map[model.LoanState]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLoansByState", ctx)
+	ret0, _ := ret[0].(map[model.LoanState]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLoansByState indicates an expected call of CountLoansByState.
+func (mr *MockILoanRepositoryMockRecorder) CountLoansByState(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLoansByState", reflect.TypeOf((*MockILoanRepository)(nil).CountLoansByState), ctx)
+}
+
 // CreateLoan mocks base method.
 func (m *MockILoanRepository) CreateLoan(ctx context.Context, loan *model.Loan) (string, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +66,21 @@ func (mr *MockILoanRepositoryMockRecorder) CreateLoan(ctx, loan interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoan", reflect.TypeOf((*MockILoanRepository)(nil).CreateLoan), ctx, loan)
 }
 
+// GetBorrowerLoanStats mocks base method.
+func (m *MockILoanRepository) GetBorrowerLoanStats(ctx context.Context, borrowerID string) (*model.BorrowerLoanStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBorrowerLoanStats", ctx, borrowerID)
+	ret0, _ := ret[0].(*model.BorrowerLoanStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBorrowerLoanStats indicates an expected call of GetBorrowerLoanStats.
+func (mr *MockILoanRepositoryMockRecorder) GetBorrowerLoanStats(ctx, borrowerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBorrowerLoanStats", reflect.TypeOf((*MockILoanRepository)(nil).GetBorrowerLoanStats), ctx, borrowerID)
+}
+
 // GetLoanByID mocks base method.
 func (m *MockILoanRepository) GetLoanByID(ctx context.Context, id string) (*model.Loan, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +96,51 @@ func (mr *MockILoanRepositoryMockRecorder) GetLoanByID(ctx, id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanByID", reflect.TypeOf((*MockILoanRepository)(nil).GetLoanByID), ctx, id)
 }
 
+// ListExpiredPublishedLoans mocks base method.
+func (m *MockILoanRepository) ListExpiredPublishedLoans(ctx context.Context, now time.Time) ([]*model.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredPublishedLoans", ctx, now)
+	ret0, _ := ret[0].([]*model.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredPublishedLoans indicates an expected call of ListExpiredPublishedLoans.
+func (mr *MockILoanRepositoryMockRecorder) ListExpiredPublishedLoans(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredPublishedLoans", reflect.TypeOf((*MockILoanRepository)(nil).ListExpiredPublishedLoans), ctx, now)
+}
+
+// ListLoansByBorrowerID mocks base method.
+func (m *MockILoanRepository) ListLoansByBorrowerID(ctx context.Context, borrowerID string) ([]*model.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoansByBorrowerID", ctx, borrowerID)
+	ret0, _ := ret[0].([]*model.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoansByBorrowerID indicates an expected call of ListLoansByBorrowerID.
+func (mr *MockILoanRepositoryMockRecorder) ListLoansByBorrowerID(ctx, borrowerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoansByBorrowerID", reflect.TypeOf((*MockILoanRepository)(nil).ListLoansByBorrowerID), ctx, borrowerID)
+}
+
+// ListLoansByState mocks base method.
+func (m *MockILoanRepository) ListLoansByState(ctx context.Context, state model.LoanState) ([]*model.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoansByState", ctx, state)
+	ret0, _ := ret[0].([]*model.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoansByState indicates an expected call of ListLoansByState.
+func (mr *MockILoanRepositoryMockRecorder) ListLoansByState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoansByState", reflect.TypeOf((*MockILoanRepository)(nil).ListLoansByState), ctx, state)
+}
+
 // UpdateLoanState mocks base method.
 func (m *MockILoanRepository) UpdateLoanState(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,152 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/notification.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINotificationRepository is a mock of INotificationRepository interface.
+type MockINotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotificationRepositoryMockRecorder
+}
+
+// MockINotificationRepositoryMockRecorder is the mock recorder for MockINotificationRepository.
+type MockINotificationRepositoryMockRecorder struct {
+	mock *MockINotificationRepository
+}
+
+// NewMockINotificationRepository creates a new mock instance.
+func NewMockINotificationRepository(ctrl *gomock.Controller) *MockINotificationRepository {
+	mock := &MockINotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockINotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotificationRepository) EXPECT() *MockINotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEmailNotification mocks base method.
+func (m *MockINotificationRepository) CreateEmailNotification(ctx context.Context, notification *model.EmailNotification) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailNotification", ctx, notification)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmailNotification indicates an expected call of CreateEmailNotification.
+func (mr *MockINotificationRepositoryMockRecorder) CreateEmailNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailNotification", reflect.TypeOf((*MockINotificationRepository)(nil).CreateEmailNotification), ctx, notification)
+}
+
+// DeleteNotificationPreference mocks base method.
+func (m *MockINotificationRepository) DeleteNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationPreference", ctx, recipientType, recipientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationPreference indicates an expected call of DeleteNotificationPreference.
+func (mr *MockINotificationRepositoryMockRecorder) DeleteNotificationPreference(ctx, recipientType, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationPreference", reflect.TypeOf((*MockINotificationRepository)(nil).DeleteNotificationPreference), ctx, recipientType, recipientID)
+}
+
+// GetEmailNotificationByID mocks base method.
+func (m *MockINotificationRepository) GetEmailNotificationByID(ctx context.Context, id string) (*model.EmailNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailNotificationByID", ctx, id)
+	ret0, _ := ret[0].(*model.EmailNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailNotificationByID indicates an expected call of GetEmailNotificationByID.
+func (mr *MockINotificationRepositoryMockRecorder) GetEmailNotificationByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailNotificationByID", reflect.TypeOf((*MockINotificationRepository)(nil).GetEmailNotificationByID), ctx, id)
+}
+
+// GetNotificationPreference mocks base method.
+func (m *MockINotificationRepository) GetNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (*model.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreference", ctx, recipientType, recipientID)
+	ret0, _ := ret[0].(*model.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreference indicates an expected call of GetNotificationPreference.
+func (mr *MockINotificationRepositoryMockRecorder) GetNotificationPreference(ctx, recipientType, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreference", reflect.TypeOf((*MockINotificationRepository)(nil).GetNotificationPreference), ctx, recipientType, recipientID)
+}
+
+// ListEmailNotificationsByRecipient mocks base method.
+func (m *MockINotificationRepository) ListEmailNotificationsByRecipient(ctx context.Context, recipientType model.RecipientType, recipientID string) ([]*model.EmailNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmailNotificationsByRecipient", ctx, recipientType, recipientID)
+	ret0, _ := ret[0].([]*model.EmailNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmailNotificationsByRecipient indicates an expected call of ListEmailNotificationsByRecipient.
+func (mr *MockINotificationRepositoryMockRecorder) ListEmailNotificationsByRecipient(ctx, recipientType, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmailNotificationsByRecipient", reflect.TypeOf((*MockINotificationRepository)(nil).ListEmailNotificationsByRecipient), ctx, recipientType, recipientID)
+}
+
+// RedactEmailNotifications mocks base method.
+func (m *MockINotificationRepository) RedactEmailNotifications(ctx context.Context, recipientType model.RecipientType, recipientID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedactEmailNotifications", ctx, recipientType, recipientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedactEmailNotifications indicates an expected call of RedactEmailNotifications.
+func (mr *MockINotificationRepositoryMockRecorder) RedactEmailNotifications(ctx, recipientType, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedactEmailNotifications", reflect.TypeOf((*MockINotificationRepository)(nil).RedactEmailNotifications), ctx, recipientType, recipientID)
+}
+
+// UpdateEmailNotification mocks base method.
+func (m *MockINotificationRepository) UpdateEmailNotification(ctx context.Context, notification *model.EmailNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailNotification", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmailNotification indicates an expected call of UpdateEmailNotification.
+func (mr *MockINotificationRepositoryMockRecorder) UpdateEmailNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailNotification", reflect.TypeOf((*MockINotificationRepository)(nil).UpdateEmailNotification), ctx, notification)
+}
+
+// UpsertNotificationPreference mocks base method.
+func (m *MockINotificationRepository) UpsertNotificationPreference(ctx context.Context, preference *model.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreference", ctx, preference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNotificationPreference indicates an expected call of UpsertNotificationPreference.
+func (mr *MockINotificationRepositoryMockRecorder) UpsertNotificationPreference(ctx, preference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreference", reflect.TypeOf((*MockINotificationRepository)(nil).UpsertNotificationPreference), ctx, preference)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/notifier.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockINotifier is a mock of INotifier interface.
+type MockINotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotifierMockRecorder
+}
+
+// MockINotifierMockRecorder is the mock recorder for MockINotifier.
+type MockINotifierMockRecorder struct {
+	mock *MockINotifier
+}
+
+// NewMockINotifier creates a new mock instance.
+func NewMockINotifier(ctrl *gomock.Controller) *MockINotifier {
+	mock := &MockINotifier{ctrl: ctrl}
+	mock.recorder = &MockINotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotifier) EXPECT() *MockINotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockINotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockINotifierMockRecorder) Notify(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockINotifier)(nil).Notify), ctx, notification)
+}
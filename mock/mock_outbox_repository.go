@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/outbox.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIOutboxRepository is a mock of IOutboxRepository interface.
+type MockIOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxRepositoryMockRecorder
+}
+
+// MockIOutboxRepositoryMockRecorder is the mock recorder for MockIOutboxRepository.
+type MockIOutboxRepositoryMockRecorder struct {
+	mock *MockIOutboxRepository
+}
+
+// NewMockIOutboxRepository creates a new mock instance.
+func NewMockIOutboxRepository(ctrl *gomock.Controller) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockIOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxRepository) EXPECT() *MockIOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimEvents mocks base method.
+func (m *MockIOutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]*model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEvents", ctx, limit, lease)
+	ret0, _ := ret[0].([]*model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEvents indicates an expected call of ClaimEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) ClaimEvents(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).ClaimEvents), ctx, limit, lease)
+}
+
+// CreateEvent mocks base method.
+func (m *MockIOutboxRepository) CreateEvent(ctx context.Context, event *model.Event) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, event)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockIOutboxRepositoryMockRecorder) CreateEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockIOutboxRepository)(nil).CreateEvent), ctx, event)
+}
+
+// MarkEventFailed mocks base method.
+func (m *MockIOutboxRepository) MarkEventFailed(ctx context.Context, id, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventFailed", ctx, id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventFailed indicates an expected call of MarkEventFailed.
+func (mr *MockIOutboxRepositoryMockRecorder) MarkEventFailed(ctx, id, lastError, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventFailed", reflect.TypeOf((*MockIOutboxRepository)(nil).MarkEventFailed), ctx, id, lastError, nextAttemptAt)
+}
+
+// MarkEventMetricsRecorded mocks base method.
+func (m *MockIOutboxRepository) MarkEventMetricsRecorded(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventMetricsRecorded", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEventMetricsRecorded indicates an expected call of MarkEventMetricsRecorded.
+func (mr *MockIOutboxRepositoryMockRecorder) MarkEventMetricsRecorded(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventMetricsRecorded", reflect.TypeOf((*MockIOutboxRepository)(nil).MarkEventMetricsRecorded), ctx, id)
+}
+
+// MarkEventPublished mocks base method.
+func (m *MockIOutboxRepository) MarkEventPublished(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventPublished", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventPublished indicates an expected call of MarkEventPublished.
+func (mr *MockIOutboxRepositoryMockRecorder) MarkEventPublished(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventPublished", reflect.TypeOf((*MockIOutboxRepository)(nil).MarkEventPublished), ctx, id)
+}
+
+// ReplayEvents mocks base method.
+func (m *MockIOutboxRepository) ReplayEvents(ctx context.Context, eventID, aggregateID string) ([]*model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayEvents", ctx, eventID, aggregateID)
+	ret0, _ := ret[0].([]*model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayEvents indicates an expected call of ReplayEvents.
+func (mr *MockIOutboxRepositoryMockRecorder) ReplayEvents(ctx, eventID, aggregateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayEvents", reflect.TypeOf((*MockIOutboxRepository)(nil).ReplayEvents), ctx, eventID, aggregateID)
+}
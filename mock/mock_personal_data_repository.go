@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/personal_data.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIPersonalDataRepository is a mock of IPersonalDataRepository interface.
+type MockIPersonalDataRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPersonalDataRepositoryMockRecorder
+}
+
+// MockIPersonalDataRepositoryMockRecorder is the mock recorder for MockIPersonalDataRepository.
+type MockIPersonalDataRepositoryMockRecorder struct {
+	mock *MockIPersonalDataRepository
+}
+
+// NewMockIPersonalDataRepository creates a new mock instance.
+func NewMockIPersonalDataRepository(ctrl *gomock.Controller) *MockIPersonalDataRepository {
+	mock := &MockIPersonalDataRepository{ctrl: ctrl}
+	mock.recorder = &MockIPersonalDataRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPersonalDataRepository) EXPECT() *MockIPersonalDataRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateErasureRequest mocks base method.
+func (m *MockIPersonalDataRepository) CreateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateErasureRequest", ctx, erasureRequest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateErasureRequest indicates an expected call of CreateErasureRequest.
+func (mr *MockIPersonalDataRepositoryMockRecorder) CreateErasureRequest(ctx, erasureRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateErasureRequest", reflect.TypeOf((*MockIPersonalDataRepository)(nil).CreateErasureRequest), ctx, erasureRequest)
+}
+
+// GetErasureRequestByID mocks base method.
+func (m *MockIPersonalDataRepository) GetErasureRequestByID(ctx context.Context, id string) (*model.PersonalDataErasureRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetErasureRequestByID", ctx, id)
+	ret0, _ := ret[0].(*model.PersonalDataErasureRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetErasureRequestByID indicates an expected call of GetErasureRequestByID.
+func (mr *MockIPersonalDataRepositoryMockRecorder) GetErasureRequestByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetErasureRequestByID", reflect.TypeOf((*MockIPersonalDataRepository)(nil).GetErasureRequestByID), ctx, id)
+}
+
+// ListErasureRequests mocks base method.
+func (m *MockIPersonalDataRepository) ListErasureRequests(ctx context.Context, status model.ErasureRequestStatus) ([]*model.PersonalDataErasureRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListErasureRequests", ctx, status)
+	ret0, _ := ret[0].([]*model.PersonalDataErasureRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListErasureRequests indicates an expected call of ListErasureRequests.
+func (mr *MockIPersonalDataRepositoryMockRecorder) ListErasureRequests(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListErasureRequests", reflect.TypeOf((*MockIPersonalDataRepository)(nil).ListErasureRequests), ctx, status)
+}
+
+// ListErasureRequestsBySubject mocks base method.
+func (m *MockIPersonalDataRepository) ListErasureRequestsBySubject(ctx context.Context, subjectType model.RecipientType, subjectID string) ([]*model.PersonalDataErasureRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListErasureRequestsBySubject", ctx, subjectType, subjectID)
+	ret0, _ := ret[0].([]*model.PersonalDataErasureRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListErasureRequestsBySubject indicates an expected call of ListErasureRequestsBySubject.
+func (mr *MockIPersonalDataRepositoryMockRecorder) ListErasureRequestsBySubject(ctx, subjectType, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListErasureRequestsBySubject", reflect.TypeOf((*MockIPersonalDataRepository)(nil).ListErasureRequestsBySubject), ctx, subjectType, subjectID)
+}
+
+// UpdateErasureRequest mocks base method.
+func (m *MockIPersonalDataRepository) UpdateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateErasureRequest", ctx, erasureRequest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateErasureRequest indicates an expected call of UpdateErasureRequest.
+func (mr *MockIPersonalDataRepositoryMockRecorder) UpdateErasureRequest(ctx, erasureRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateErasureRequest", reflect.TypeOf((*MockIPersonalDataRepository)(nil).UpdateErasureRequest), ctx, erasureRequest)
+}
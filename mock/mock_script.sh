@@ -1,4 +1,20 @@
 mockgen -source=./repository/loan.go -destination=./mock/mock_loan_repository.go -package=mock
 mockgen -source=./repository/borrower.go -destination=./mock/mock_borrower_repository.go -package=mock
 mockgen -source=./repository/investor.go -destination=./mock/mock_investor_repository.go -package=mock
-mockgen -source=./repository/investment.go -destination=./mock/mock_investment_repository.go -package=mock
\ No newline at end of file
+mockgen -source=./repository/investment.go -destination=./mock/mock_investment_repository.go -package=mock
+mockgen -source=./repository/employee.go -destination=./mock/mock_employee_repository.go -package=mock
+mockgen -source=./repository/loan_product.go -destination=./mock/mock_loan_product_repository.go -package=mock
+mockgen -source=./service/notifier.go -destination=./mock/mock_notifier.go -package=mock
+mockgen -source=./repository/job.go -destination=./mock/mock_job_repository.go -package=mock
+mockgen -source=./repository/outbox.go -destination=./mock/mock_outbox_repository.go -package=mock
+mockgen -source=./service/event_sink.go -destination=./mock/mock_event_sink.go -package=mock
+mockgen -source=./repository/webhook.go -destination=./mock/mock_webhook_repository.go -package=mock
+mockgen -source=./service/job.go -destination=./mock/mock_job_service.go -package=mock
+mockgen -source=./repository/notification.go -destination=./mock/mock_notification_repository.go -package=mock
+mockgen -source=./service/email_sender.go -destination=./mock/mock_email_sender.go -package=mock
+mockgen -source=./repository/idempotency.go -destination=./mock/mock_idempotency_repository.go -package=mock
+mockgen -source=./repository/feature_flag.go -destination=./mock/mock_feature_flag_repository.go -package=mock
+mockgen -source=./service/feature_flag.go -destination=./mock/mock_feature_flag_service.go -package=mock
+mockgen -source=./repository/audit.go -destination=./mock/mock_audit_repository.go -package=mock
+mockgen -source=./repository/personal_data.go -destination=./mock/mock_personal_data_repository.go -package=mock
+mockgen -source=./service/audit.go -destination=./mock/mock_audit_service.go -package=mock
@@ -0,0 +1,12 @@
+package mock
+
+import (
+	"context"
+)
+
+// MockTransactionManager runs the unit of work directly without a database transaction.
+type MockTransactionManager struct{}
+
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
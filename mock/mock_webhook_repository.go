@@ -0,0 +1,227 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repository/webhook.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/frencius/loan-service/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIWebhookRepository is a mock of IWebhookRepository interface.
+type MockIWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookRepositoryMockRecorder
+}
+
+// MockIWebhookRepositoryMockRecorder is the mock recorder for MockIWebhookRepository.
+type MockIWebhookRepositoryMockRecorder struct {
+	mock *MockIWebhookRepository
+}
+
+// NewMockIWebhookRepository creates a new mock instance.
+func NewMockIWebhookRepository(ctrl *gomock.Controller) *MockIWebhookRepository {
+	mock := &MockIWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockIWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebhookRepository) EXPECT() *MockIWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockIWebhookRepository) CreateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockIWebhookRepositoryMockRecorder) CreateWebhookDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockIWebhookRepository)(nil).CreateWebhookDelivery), ctx, delivery)
+}
+
+// CreateWebhookDeliveryAttempt mocks base method.
+func (m *MockIWebhookRepository) CreateWebhookDeliveryAttempt(ctx context.Context, attempt *model.WebhookDeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveryAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDeliveryAttempt indicates an expected call of CreateWebhookDeliveryAttempt.
+func (mr *MockIWebhookRepositoryMockRecorder) CreateWebhookDeliveryAttempt(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveryAttempt", reflect.TypeOf((*MockIWebhookRepository)(nil).CreateWebhookDeliveryAttempt), ctx, attempt)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockIWebhookRepository) CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) CreateWebhookSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).CreateWebhookSubscription), ctx, subscription)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockIWebhookRepository) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) DeleteWebhookSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).DeleteWebhookSubscription), ctx, id)
+}
+
+// GetWebhookDeliveryByID mocks base method.
+func (m *MockIWebhookRepository) GetWebhookDeliveryByID(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryByID indicates an expected call of GetWebhookDeliveryByID.
+func (mr *MockIWebhookRepositoryMockRecorder) GetWebhookDeliveryByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryByID", reflect.TypeOf((*MockIWebhookRepository)(nil).GetWebhookDeliveryByID), ctx, id)
+}
+
+// GetWebhookSubscriptionByID mocks base method.
+func (m *MockIWebhookRepository) GetWebhookSubscriptionByID(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscriptionByID", ctx, id)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscriptionByID indicates an expected call of GetWebhookSubscriptionByID.
+func (mr *MockIWebhookRepositoryMockRecorder) GetWebhookSubscriptionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscriptionByID", reflect.TypeOf((*MockIWebhookRepository)(nil).GetWebhookSubscriptionByID), ctx, id)
+}
+
+// ListActiveWebhookSubscriptionsByEventType mocks base method.
+func (m *MockIWebhookRepository) ListActiveWebhookSubscriptionsByEventType(ctx context.Context, eventType model.EventType) ([]*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveWebhookSubscriptionsByEventType", ctx, eventType)
+	ret0, _ := ret[0].([]*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveWebhookSubscriptionsByEventType indicates an expected call of ListActiveWebhookSubscriptionsByEventType.
+func (mr *MockIWebhookRepositoryMockRecorder) ListActiveWebhookSubscriptionsByEventType(ctx, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveWebhookSubscriptionsByEventType", reflect.TypeOf((*MockIWebhookRepository)(nil).ListActiveWebhookSubscriptionsByEventType), ctx, eventType)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockIWebhookRepository) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, subscriptionID, limit)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockIWebhookRepositoryMockRecorder) ListWebhookDeliveries(ctx, subscriptionID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockIWebhookRepository)(nil).ListWebhookDeliveries), ctx, subscriptionID, limit)
+}
+
+// ListWebhookDeliveryAttempts mocks base method.
+func (m *MockIWebhookRepository) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID string) ([]*model.WebhookDeliveryAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveryAttempts", ctx, deliveryID)
+	ret0, _ := ret[0].([]*model.WebhookDeliveryAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveryAttempts indicates an expected call of ListWebhookDeliveryAttempts.
+func (mr *MockIWebhookRepositoryMockRecorder) ListWebhookDeliveryAttempts(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveryAttempts", reflect.TypeOf((*MockIWebhookRepository)(nil).ListWebhookDeliveryAttempts), ctx, deliveryID)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockIWebhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockIWebhookRepositoryMockRecorder) ListWebhookSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockIWebhookRepository)(nil).ListWebhookSubscriptions), ctx)
+}
+
+// RecordWebhookSubscriptionResult mocks base method.
+func (m *MockIWebhookRepository) RecordWebhookSubscriptionResult(ctx context.Context, id string, succeeded bool, disableAfterFailures int) (*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookSubscriptionResult", ctx, id, succeeded, disableAfterFailures)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookSubscriptionResult indicates an expected call of RecordWebhookSubscriptionResult.
+func (mr *MockIWebhookRepositoryMockRecorder) RecordWebhookSubscriptionResult(ctx, id, succeeded, disableAfterFailures interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookSubscriptionResult", reflect.TypeOf((*MockIWebhookRepository)(nil).RecordWebhookSubscriptionResult), ctx, id, succeeded, disableAfterFailures)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockIWebhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateWebhookDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateWebhookDelivery), ctx, delivery)
+}
+
+// UpdateWebhookSubscription mocks base method.
+func (m *MockIWebhookRepository) UpdateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookSubscription indicates an expected call of UpdateWebhookSubscription.
+func (mr *MockIWebhookRepositoryMockRecorder) UpdateWebhookSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookSubscription", reflect.TypeOf((*MockIWebhookRepository)(nil).UpdateWebhookSubscription), ctx, subscription)
+}
@@ -0,0 +1,224 @@
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionExport records an access to personal data, nothing is changed
+	AuditActionExport AuditAction = "export"
+	// AuditActionRun records an operator command of loanctl with its outcome
+	AuditActionRun AuditAction = "run"
+)
+
+// audited entities
+const (
+	AuditEntityLoan                   = "loan"
+	AuditEntityInvestment             = "investment"
+	AuditEntityEmployee               = "employee"
+	AuditEntityLoanProduct            = "loan_product"
+	AuditEntityWebhookSubscription    = "webhook_subscription"
+	AuditEntityFeatureFlag            = "feature_flag"
+	AuditEntityNotificationPreference = "notification_preference"
+	AuditEntityBorrower               = "borrower"
+	AuditEntityInvestor               = "investor"
+	AuditEntityErasureRequest         = "personal_data_erasure_request"
+	AuditEntityOutboxEvent            = "outbox_event"
+	AuditEntityOperatorCommand        = "operator_command"
+)
+
+// outcomes of an operator command
+const (
+	OperatorCommandSucceeded = "succeeded"
+	OperatorCommandFailed    = "failed"
+)
+
+// AuditGenesisHash is the previous hash of the first entry of the chain.
+var AuditGenesisHash = strings.Repeat("0", 64)
+
+// auditIgnoredFields change on every write and are left out of the diff.
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// data model
+type (
+	// AuditLog is an entry of the append-only audit trail. Hash covers every other field and the hash of
+	// the entry before, so altering or removing an entry breaks the chain from there on.
+	AuditLog struct {
+		ID         int64
+		EntityType string
+		EntityID   string
+		Action     AuditAction
+		ActorID    string
+		SourceIP   string
+		RequestID  string
+		Changes    json.RawMessage
+		CreatedAt  time.Time
+		PrevHash   string
+		Hash       string
+	}
+
+	// AuditChange is a changed field, Before is absent for created entities and After for deleted ones.
+	AuditChange struct {
+		Before json.RawMessage `json:"before,omitempty"`
+		After  json.RawMessage `json:"after,omitempty"`
+	}
+
+	// OperatorCommand is a loanctl command as it is audited, Error is set when Outcome is failed.
+	OperatorCommand struct {
+		Command string   `json:"command"`
+		Args    []string `json:"args"`
+		Outcome string   `json:"outcome"`
+		Error   string   `json:"error,omitempty"`
+	}
+
+	AuditLogFilter struct {
+		EntityType string
+		EntityID   string
+		ActorID    string
+		From       *time.Time
+		To         *time.Time
+		// BeforeID pages back from the entry with the id, zero starts at the latest entry
+		BeforeID int64
+		Limit    int
+	}
+)
+
+// ComputeHash hashes the entry chained to PrevHash. Changes are hashed as stored, so they must be the exact
+// bytes written to the database.
+func (al *AuditLog) ComputeHash() string {
+	fields, _ := json.Marshal([]string{
+		al.PrevHash,
+		al.EntityType,
+		al.EntityID,
+		string(al.Action),
+		al.ActorID,
+		al.SourceIP,
+		al.RequestID,
+		al.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	hash := sha256.New()
+	hash.Write(fields)
+	hash.Write([]byte("\n"))
+	hash.Write(al.Changes)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ComposeAuditChanges diffs the JSON fields of before and after, either may be nil for a created or deleted
+// entity. Fields that did not change are left out.
+func ComposeAuditChanges(before any, after any) (changes map[string]AuditChange, err error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return
+	}
+
+	changes = map[string]AuditChange{}
+	for field, value := range beforeFields {
+		if !bytes.Equal(value, afterFields[field]) {
+			changes[field] = AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = AuditChange{After: value}
+		}
+	}
+
+	return
+}
+
+func auditFields(entity any) (fields map[string]json.RawMessage, err error) {
+	fields = map[string]json.RawMessage{}
+	if entity == nil {
+		return
+	}
+
+	entityBytes, err := json.Marshal(entity)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(entityBytes, &fields)
+	for field, value := range fields {
+		if auditIgnoredFields[field] || string(value) == "null" {
+			delete(fields, field)
+		}
+	}
+
+	return
+}
+
+// request response
+type (
+	ListAuditLogsRequest struct {
+		EntityType string
+		EntityID   string
+		ActorID    string
+		From       *time.Time
+		To         *time.Time
+		BeforeID   int64
+		Limit      int
+	}
+
+	AuditLogResponse struct {
+		ID         int64                  `json:"id"`
+		EntityType string                 `json:"entity_type"`
+		EntityID   string                 `json:"entity_id"`
+		Action     AuditAction            `json:"action"`
+		ActorID    string                 `json:"actor_id,omitempty"`
+		SourceIP   string                 `json:"source_ip,omitempty"`
+		RequestID  string                 `json:"request_id,omitempty"`
+		Changes    map[string]AuditChange `json:"changes"`
+		CreatedAt  time.Time              `json:"created_at"`
+		PrevHash   string                 `json:"prev_hash"`
+		Hash       string                 `json:"hash"`
+	}
+
+	// AuditVerificationResponse reports the first entry whose hash or link to the entry before does not
+	// match, the chain is intact when there is none.
+	AuditVerificationResponse struct {
+		Valid          bool   `json:"valid"`
+		CheckedEntries int64  `json:"checked_entries"`
+		LastHash       string `json:"last_hash,omitempty"`
+		FirstInvalidID int64  `json:"first_invalid_id,omitempty"`
+		Reason         string `json:"reason,omitempty"`
+	}
+)
+
+func ComposeAuditLogResponse(auditLog *AuditLog) *AuditLogResponse {
+	changes := map[string]AuditChange{}
+	_ = json.Unmarshal(auditLog.Changes, &changes)
+
+	return &AuditLogResponse{
+		ID:         auditLog.ID,
+		EntityType: auditLog.EntityType,
+		EntityID:   auditLog.EntityID,
+		Action:     auditLog.Action,
+		ActorID:    auditLog.ActorID,
+		SourceIP:   auditLog.SourceIP,
+		RequestID:  auditLog.RequestID,
+		Changes:    changes,
+		CreatedAt:  auditLog.CreatedAt,
+		PrevHash:   auditLog.PrevHash,
+		Hash:       auditLog.Hash,
+	}
+}
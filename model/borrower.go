@@ -9,4 +9,31 @@ type Borrower struct {
 	Occupation string
 	NIK        string
 	DOB        *time.Time
+	Email      string
+	// ErasedAt is set once the personal data is erased, the borrower is kept for its loans
+	ErasedAt *time.Time
+}
+
+type BorrowerResponse struct {
+	BorrowerID string     `json:"borrower_id"`
+	Name       string     `json:"name,omitempty"`
+	Address    string     `json:"address,omitempty"`
+	Occupation string     `json:"occupation,omitempty"`
+	NIK        string     `json:"nik,omitempty"`
+	DOB        *time.Time `json:"dob,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	ErasedAt   *time.Time `json:"erased_at,omitempty"`
+}
+
+func ComposeBorrowerResponse(borrower *Borrower) *BorrowerResponse {
+	return &BorrowerResponse{
+		BorrowerID: borrower.ID,
+		Name:       borrower.Name,
+		Address:    borrower.Address,
+		Occupation: borrower.Occupation,
+		NIK:        borrower.NIK,
+		DOB:        borrower.DOB,
+		Email:      borrower.Email,
+		ErasedAt:   borrower.ErasedAt,
+	}
 }
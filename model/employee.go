@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// data model
+type (
+	Employee struct {
+		ID             string
+		Name           string
+		EmployeeNumber string
+		IsActive       bool
+		DeactivatedAt  *time.Time
+		DeactivatedBy  string
+		CreatedAt      *time.Time
+		UpdatedAt      *time.Time
+	}
+)
+
+// request response
+type (
+	CreateEmployeeRequest struct {
+		Name           string `json:"name" validate:"required"`
+		EmployeeNumber string `json:"employee_number" validate:"required"`
+	}
+
+	UpdateEmployeeRequest struct {
+		EmployeeID string
+		Name       string `json:"name" validate:"required"`
+	}
+
+	GetEmployeeRequest struct {
+		EmployeeID string
+	}
+
+	ListEmployeesRequest struct {
+		ActiveOnly bool
+	}
+
+	DeactivateEmployeeRequest struct {
+		EmployeeID string
+	}
+
+	EmployeeResponse struct {
+		EmployeeID     string     `json:"employee_id"`
+		Name           string     `json:"name"`
+		EmployeeNumber string     `json:"employee_number"`
+		IsActive       bool       `json:"is_active"`
+		DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"`
+		CreatedAt      *time.Time `json:"created_at,omitempty"`
+		UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	}
+)
+
+func ComposeEmployeeResponse(employee *Employee) *EmployeeResponse {
+	return &EmployeeResponse{
+		EmployeeID:     employee.ID,
+		Name:           employee.Name,
+		EmployeeNumber: employee.EmployeeNumber,
+		IsActive:       employee.IsActive,
+		DeactivatedAt:  employee.DeactivatedAt,
+		CreatedAt:      employee.CreatedAt,
+		UpdatedAt:      employee.UpdatedAt,
+	}
+}
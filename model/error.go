@@ -1,16 +1,184 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode is the stable, machine-readable identifier clients can branch on.
+type ErrorCode string
+
+type (
+	// DomainError is a business error reported to clients with its code and HTTP status.
+	DomainError struct {
+		Code    ErrorCode
+		Status  int
+		Message string
+		Details map[string]any
+		Fields  []FieldError
+	}
+
+	// FieldError reports a request field that failed validation.
+	FieldError struct {
+		Field   string `json:"field"`
+		Rule    string `json:"rule"`
+		Param   string `json:"param,omitempty"`
+		Message string `json:"message,omitempty"`
+		// Translations holds the message in every supported language, the response carries one of them
+		Translations map[Language]string `json:"-"`
+	}
+)
+
+func NewDomainError(code ErrorCode, status int, message string) *DomainError {
+	return &DomainError{Code: code, Status: status, Message: message}
+}
+
+func (de *DomainError) Error() string {
+	return de.Message
+}
+
+// Is matches errors with the same code, so copies carrying details still match their sentinel.
+func (de *DomainError) Is(target error) bool {
+	other, ok := target.(*DomainError)
+	return ok && other.Code == de.Code
+}
+
+// WithDetail returns a copy of the error carrying an extra detail for the client.
+func (de *DomainError) WithDetail(key string, value any) *DomainError {
+	copied := *de
+	copied.Details = make(map[string]any, len(de.Details)+1)
+	for k, v := range de.Details {
+		copied.Details[k] = v
+	}
+	copied.Details[key] = value
+
+	return &copied
+}
+
+// WithFields returns a copy of the error reporting the given invalid fields.
+func (de *DomainError) WithFields(fields ...FieldError) *DomainError {
+	copied := *de
+	copied.Fields = append(append([]FieldError{}, de.Fields...), fields...)
+
+	return &copied
+}
+
+// LocalizedMessage returns the message in the given language, falling back to English.
+func (de *DomainError) LocalizedMessage(language Language) string {
+	if message, ok := errorMessages[language][de.Code]; ok {
+		return message
+	}
+
+	return de.Message
+}
+
+// LocalizedFields returns the field errors with their message in the given language, falling back to English.
+func (de *DomainError) LocalizedFields(language Language) []FieldError {
+	if len(de.Fields) == 0 {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(de.Fields))
+	for _, field := range de.Fields {
+		message, ok := field.Translations[language]
+		if !ok {
+			message = field.Translations[LanguageEnglish]
+		}
+		field.Message = message
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Wrap reports cause under the domain error, e.g. the decoder error of an invalid body.
+func (de *DomainError) Wrap(cause error) error {
+	return fmt.Errorf("%w: %v", de, cause)
+}
+
+// InvalidFieldError reports a single invalid field, e.g. a path param that is not a uuid.
+func InvalidFieldError(field string, rule string) *DomainError {
+	translations := make(map[Language]string, len(fieldRuleMessages))
+	for language, messages := range fieldRuleMessages {
+		if message, ok := messages[rule]; ok {
+			translations[language] = fmt.Sprintf(message, field)
+		}
+	}
+
+	return ErrorValidationFailed.WithFields(FieldError{Field: field, Rule: rule, Translations: translations})
+}
+
+// ErrorInternal is reported for every error that is not a DomainError.
+var ErrorInternal = NewDomainError("internal_error", http.StatusInternalServerError, "Something wrong in the system!")
+
+// errors reported to clients
+var (
+	ErrorRequestBodyInvalid                     = NewDomainError("request_body_invalid", http.StatusBadRequest, "request body invalid")
+	ErrorRequestBodyTooLarge                    = NewDomainError("request_body_too_large", http.StatusRequestEntityTooLarge, "request body is too large")
+	ErrorRateLimited                            = NewDomainError("rate_limited", http.StatusTooManyRequests, "too many requests, retry later")
+	ErrorValidationFailed                       = NewDomainError("validation_failed", http.StatusBadRequest, "request is invalid")
+	ErrorBorrowerNotFound                       = NewDomainError("borrower_not_found", http.StatusNotFound, "borrower is not found")
+	ErrorBorrowerExist                          = NewDomainError("borrower_exist", http.StatusBadRequest, "a borrower with the NIK already exists")
+	ErrorLoanNotFound                           = NewDomainError("loan_not_found", http.StatusNotFound, "loan is not found")
+	ErrorLoanStateInvalid                       = NewDomainError("loan_state_invalid", http.StatusBadRequest, "loan state invalid")
+	ErrorLoanStateTransitionNotAllowed          = NewDomainError("loan_state_transition_not_allowed", http.StatusBadRequest, "loan state transition is not allowed")
+	ErrorStateTransitionRequirementNotFulfilled = NewDomainError("state_transition_requirement_not_fulfilled", http.StatusBadRequest, "loan state transition requirement is not fulfilled")
+	ErrorTransitionToTheSameState               = NewDomainError("transition_to_the_same_state", http.StatusBadRequest, "loan state cannot transition to the same state")
+	ErrorInvestorNotFound                       = NewDomainError("investor_not_found", http.StatusNotFound, "investor is not found")
+	ErrorInvestorExist                          = NewDomainError("investor_exist", http.StatusBadRequest, "an investor with the NIK, NPWP or email already exists")
+	ErrorStateMustBePublished                   = NewDomainError("state_must_be_published", http.StatusBadRequest, "loan state must be published")
+	ErrorInvestmentExist                        = NewDomainError("investment_exist", http.StatusBadRequest, "investment already exists")
+	ErrorInvestmentNotFound                     = NewDomainError("investment_not_found", http.StatusNotFound, "investment is not found")
+	ErrorEmployeeNotFound                       = NewDomainError("employee_not_found", http.StatusNotFound, "employee is not found")
+	ErrorEmployeeNumberExist                    = NewDomainError("employee_number_exist", http.StatusBadRequest, "employee number already exists")
+	ErrorEmployeeInactive                       = NewDomainError("employee_inactive", http.StatusBadRequest, "employee is inactive")
+	ErrorLoanProductNotFound                    = NewDomainError("loan_product_not_found", http.StatusNotFound, "loan product is not found")
+	ErrorLoanProductInactive                    = NewDomainError("loan_product_inactive", http.StatusBadRequest, "loan product is inactive")
+	ErrorLoanProductCodeExist                   = NewDomainError("loan_product_code_exist", http.StatusBadRequest, "loan product code already exists")
+	ErrorLoanProductInvalid                     = NewDomainError("loan_product_invalid", http.StatusBadRequest, "loan product limits are invalid")
+	ErrorPrincipalAmountOutOfRange              = NewDomainError("principal_amount_out_of_range", http.StatusBadRequest, "principal amount is out of the product range")
+	ErrorTenorNotAllowed                        = NewDomainError("tenor_not_allowed", http.StatusBadRequest, "tenor is not allowed for the product")
+	ErrorInterestRateOutOfBand                  = NewDomainError("interest_rate_out_of_band", http.StatusBadRequest, "interest rate is out of the product band")
+	ErrorROIRateOutOfBand                       = NewDomainError("roi_rate_out_of_band", http.StatusBadRequest, "roi rate is out of the product band")
+	ErrorFundingWindowExpired                   = NewDomainError("funding_window_expired", http.StatusBadRequest, "loan funding window has expired")
+	ErrorJobNotFound                            = NewDomainError("job_not_found", http.StatusNotFound, "job is not found")
+	ErrorJobNotDead                             = NewDomainError("job_not_dead", http.StatusBadRequest, "only dead jobs can be retried")
+	ErrorJobStatusInvalid                       = NewDomainError("job_status_invalid", http.StatusBadRequest, "job status invalid")
+	ErrorWebhookSubscriptionNotFound            = NewDomainError("webhook_subscription_not_found", http.StatusNotFound, "webhook subscription is not found")
+	ErrorWebhookSubscriptionInactive            = NewDomainError("webhook_subscription_inactive", http.StatusBadRequest, "webhook subscription is inactive")
+	ErrorWebhookEventTypeInvalid                = NewDomainError("webhook_event_type_invalid", http.StatusBadRequest, "webhook event type invalid")
+	ErrorWebhookDeliveryNotFound                = NewDomainError("webhook_delivery_not_found", http.StatusNotFound, "webhook delivery is not found")
+	ErrorNotificationEventInvalid               = NewDomainError("notification_event_invalid", http.StatusBadRequest, "notification event invalid")
+	ErrorRecipientTypeInvalid                   = NewDomainError("recipient_type_invalid", http.StatusBadRequest, "recipient type invalid")
+	ErrorIdempotencyKeyInvalid                  = NewDomainError("idempotency_key_invalid", http.StatusBadRequest, "idempotency key invalid")
+	ErrorIdempotencyKeyMismatch                 = NewDomainError("idempotency_key_mismatch", http.StatusConflict, "idempotency key was used for a different request")
+	ErrorIdempotencyKeyInProgress               = NewDomainError("idempotency_key_in_progress", http.StatusConflict, "a request with the same idempotency key is in progress")
+	ErrorLoanIfMatchRequired                    = NewDomainError("loan_if_match_required", http.StatusPreconditionRequired, "If-Match header with the loan ETag is required")
+	ErrorLoanETagInvalid                        = NewDomainError("loan_etag_invalid", http.StatusBadRequest, "loan ETag invalid")
+	ErrorLoanVersionMismatch                    = NewDomainError("loan_version_mismatch", http.StatusPreconditionFailed, "loan was modified, reload it and retry")
+	ErrorLoanUpdateConflict                     = NewDomainError("loan_update_conflict", http.StatusConflict, "loan was updated concurrently, retry the request")
+	ErrorEventReplayFilterInvalid               = NewDomainError("event_replay_filter_invalid", http.StatusBadRequest, "replay either one event id or one aggregate id")
+	ErrorLoanAgreementNotReady                  = NewDomainError("loan_agreement_not_ready", http.StatusConflict, "loan agreement is only ready once the loan is fully funded")
+	ErrorFeatureFlagNotFound                    = NewDomainError("feature_flag_not_found", http.StatusNotFound, "feature flag is not found")
+	ErrorFeatureFlagKeyInvalid                  = NewDomainError("feature_flag_key_invalid", http.StatusBadRequest, "feature flag key must be lowercase letters, digits, dots and underscores")
+	ErrorErasureRequestNotFound                 = NewDomainError("erasure_request_not_found", http.StatusNotFound, "erasure request is not found")
+	ErrorErasureRequestExist                    = NewDomainError("erasure_request_exist", http.StatusConflict, "an erasure request is already pending")
+	ErrorErasureRequestNotPending               = NewDomainError("erasure_request_not_pending", http.StatusConflict, "erasure request is already processed")
+	ErrorErasureRequestStatusInvalid            = NewDomainError("erasure_request_status_invalid", http.StatusBadRequest, "erasure request status invalid")
+	ErrorPersonalDataErased                     = NewDomainError("personal_data_erased", http.StatusGone, "personal data is already erased")
+	ErrorPersonalDataErasureBlocked             = NewDomainError("personal_data_erasure_blocked", http.StatusConflict, "personal data cannot be erased while loans or investments are running")
+)
 
+// internal errors, reported as internal_error
 var (
-	ErrorBorrowerNotFound                       = errors.New("borrower is not found")
-	ErrorLoanNotFound                           = errors.New("loan is not found")
-	ErrorLoanStateInvalid                       = errors.New("loan state invalid")
-	ErrorLoanStateTransitionNotAllowed          = errors.New("loan state transition is not allowed")
-	ErrorStateTransitionRequirementNotFulfilled = errors.New("loan state transition requirement is not fulfilled")
-	ErrorTransitionToTheSameState               = errors.New("loan state transition could not be in the same sate")
-	ErrorInvestorNotFound                       = errors.New("investor is not found")
-	ErrorStateMustBePublished                   = errors.New("loan state must be publihsed")
-	ErrorInvestmentExist                        = errors.New("investment exist")
-	ErrorInvestmentNotFound                     = errors.New("investment is not found")
+	ErrorScorecardInvalid               = errors.New("scorecard is invalid")
+	ErrorJobHandlerNotFound             = errors.New("job handler is not found")
+	ErrorRecurringJobAlreadyScheduled   = errors.New("recurring job is already scheduled")
+	ErrorWebhookDeliveryExist           = errors.New("webhook delivery exist")
+	ErrorNotificationPreferenceNotFound = errors.New("notification preference is not found")
+	ErrorEmailNotificationNotFound      = errors.New("email notification is not found")
+	ErrorEmailNotificationExist         = errors.New("email notification exist")
+	ErrorEmailTemplateNotFound          = errors.New("email template is not found")
+	ErrorIdempotencyKeyNotFound         = errors.New("idempotency key is not found")
 )
@@ -0,0 +1,79 @@
+package model
+
+// errorMessages translates the messages of domain errors by error code. English messages live on the
+// errors themselves and are used when a translation is missing.
+var errorMessages = map[Language]map[ErrorCode]string{
+	LanguageIndonesian: {
+		ErrorInternal.Code:                               "terjadi kesalahan pada sistem",
+		ErrorRequestBodyInvalid.Code:                     "isi permintaan tidak valid",
+		ErrorRequestBodyTooLarge.Code:                    "isi permintaan terlalu besar",
+		ErrorRateLimited.Code:                            "terlalu banyak permintaan, coba lagi nanti",
+		ErrorValidationFailed.Code:                       "permintaan tidak valid",
+		ErrorBorrowerNotFound.Code:                       "peminjam tidak ditemukan",
+		ErrorBorrowerExist.Code:                          "peminjam dengan NIK tersebut sudah terdaftar",
+		ErrorLoanNotFound.Code:                           "pinjaman tidak ditemukan",
+		ErrorLoanStateInvalid.Code:                       "status pinjaman tidak valid",
+		ErrorLoanStateTransitionNotAllowed.Code:          "perubahan status pinjaman tidak diizinkan",
+		ErrorStateTransitionRequirementNotFulfilled.Code: "syarat perubahan status pinjaman belum terpenuhi",
+		ErrorTransitionToTheSameState.Code:               "status pinjaman tidak dapat diubah ke status yang sama",
+		ErrorInvestorNotFound.Code:                       "investor tidak ditemukan",
+		ErrorInvestorExist.Code:                          "investor dengan NIK, NPWP atau email tersebut sudah terdaftar",
+		ErrorStateMustBePublished.Code:                   "status pinjaman harus sudah dipublikasikan",
+		ErrorInvestmentExist.Code:                        "investasi sudah ada",
+		ErrorInvestmentNotFound.Code:                     "investasi tidak ditemukan",
+		ErrorEmployeeNotFound.Code:                       "karyawan tidak ditemukan",
+		ErrorEmployeeNumberExist.Code:                    "nomor karyawan sudah terdaftar",
+		ErrorEmployeeInactive.Code:                       "karyawan tidak aktif",
+		ErrorLoanProductNotFound.Code:                    "produk pinjaman tidak ditemukan",
+		ErrorLoanProductInactive.Code:                    "produk pinjaman tidak aktif",
+		ErrorLoanProductCodeExist.Code:                   "kode produk pinjaman sudah terdaftar",
+		ErrorLoanProductInvalid.Code:                     "batasan produk pinjaman tidak valid",
+		ErrorPrincipalAmountOutOfRange.Code:              "jumlah pokok pinjaman di luar batas produk",
+		ErrorTenorNotAllowed.Code:                        "tenor tidak diizinkan untuk produk ini",
+		ErrorInterestRateOutOfBand.Code:                  "suku bunga di luar rentang produk",
+		ErrorROIRateOutOfBand.Code:                       "tingkat imbal hasil di luar rentang produk",
+		ErrorFundingWindowExpired.Code:                   "masa pendanaan pinjaman telah berakhir",
+		ErrorJobNotFound.Code:                            "job tidak ditemukan",
+		ErrorJobNotDead.Code:                             "hanya job yang gagal permanen yang dapat diulang",
+		ErrorJobStatusInvalid.Code:                       "status job tidak valid",
+		ErrorWebhookSubscriptionNotFound.Code:            "langganan webhook tidak ditemukan",
+		ErrorWebhookSubscriptionInactive.Code:            "langganan webhook tidak aktif",
+		ErrorWebhookEventTypeInvalid.Code:                "jenis event webhook tidak valid",
+		ErrorWebhookDeliveryNotFound.Code:                "pengiriman webhook tidak ditemukan",
+		ErrorNotificationEventInvalid.Code:               "jenis notifikasi tidak valid",
+		ErrorRecipientTypeInvalid.Code:                   "jenis penerima tidak valid",
+		ErrorIdempotencyKeyInvalid.Code:                  "idempotency key tidak valid",
+		ErrorIdempotencyKeyMismatch.Code:                 "idempotency key sudah dipakai untuk permintaan lain",
+		ErrorIdempotencyKeyInProgress.Code:               "permintaan dengan idempotency key yang sama sedang diproses",
+		ErrorLoanIfMatchRequired.Code:                    "header If-Match dengan ETag pinjaman wajib diisi",
+		ErrorLoanETagInvalid.Code:                        "ETag pinjaman tidak valid",
+		ErrorLoanVersionMismatch.Code:                    "pinjaman telah diubah, muat ulang lalu coba lagi",
+		ErrorLoanUpdateConflict.Code:                     "pinjaman sedang diubah bersamaan, silakan coba lagi",
+		ErrorEventReplayFilterInvalid.Code:               "putar ulang satu event id atau satu aggregate id",
+		ErrorLoanAgreementNotReady.Code:                  "perjanjian pinjaman baru tersedia setelah pinjaman terdanai penuh",
+		ErrorFeatureFlagNotFound.Code:                    "feature flag tidak ditemukan",
+		ErrorFeatureFlagKeyInvalid.Code:                  "kunci feature flag hanya boleh berisi huruf kecil, angka, titik dan garis bawah",
+		ErrorErasureRequestNotFound.Code:                 "permintaan penghapusan tidak ditemukan",
+		ErrorErasureRequestExist.Code:                    "masih ada permintaan penghapusan yang menunggu diproses",
+		ErrorErasureRequestNotPending.Code:               "permintaan penghapusan sudah diproses",
+		ErrorErasureRequestStatusInvalid.Code:            "status permintaan penghapusan tidak valid",
+		ErrorPersonalDataErased.Code:                     "data pribadi sudah dihapus",
+		ErrorPersonalDataErasureBlocked.Code:             "data pribadi tidak dapat dihapus selama masih ada pinjaman atau investasi yang berjalan",
+	},
+}
+
+// fieldRuleMessages translates the rules of field errors reported outside the validator, %s is the field.
+var fieldRuleMessages = map[Language]map[string]string{
+	LanguageEnglish: {
+		"uuid":     "%s must be a valid UUID",
+		"boolean":  "%s must be a boolean",
+		"number":   "%s must be a number",
+		"datetime": "%s must be an RFC 3339 date time",
+	},
+	LanguageIndonesian: {
+		"uuid":     "%s harus berupa UUID yang valid",
+		"boolean":  "%s harus berupa boolean",
+		"number":   "%s harus berupa angka",
+		"datetime": "%s harus berupa tanggal dan waktu RFC 3339",
+	},
+}
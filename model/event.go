@@ -0,0 +1,158 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTypeLoanCreated         EventType = "loan.created"
+	EventTypeLoanProposed        EventType = "loan.proposed"
+	EventTypeLoanApproved        EventType = "loan.approved"
+	EventTypeLoanRejected        EventType = "loan.rejected"
+	EventTypeLoanCanceled        EventType = "loan.canceled"
+	EventTypeLoanPublished       EventType = "loan.published"
+	EventTypeLoanFullyFunded     EventType = "loan.fully_funded"
+	EventTypeLoanDisbursed       EventType = "loan.disbursed"
+	EventTypeInvestmentCreated   EventType = "investment.created"
+	EventTypeInvestmentsReleased EventType = "investment.released"
+
+	// EventTypeAll subscribes an in-process handler to every event type.
+	EventTypeAll EventType = "*"
+)
+
+var ValidEventTypes = map[EventType]bool{
+	EventTypeLoanCreated:         true,
+	EventTypeLoanProposed:        true,
+	EventTypeLoanApproved:        true,
+	EventTypeLoanRejected:        true,
+	EventTypeLoanCanceled:        true,
+	EventTypeLoanPublished:       true,
+	EventTypeLoanFullyFunded:     true,
+	EventTypeLoanDisbursed:       true,
+	EventTypeInvestmentCreated:   true,
+	EventTypeInvestmentsReleased: true,
+}
+
+// LoanStateEventTypes maps the target state of a transition to the event it emits.
+var LoanStateEventTypes = map[LoanState]EventType{
+	LoanStateProposed:  EventTypeLoanProposed,
+	LoanStateApproved:  EventTypeLoanApproved,
+	LoanStateRejected:  EventTypeLoanRejected,
+	LoanStateCanceled:  EventTypeLoanCanceled,
+	LoanStatePublished: EventTypeLoanPublished,
+	LoanStateInvested:  EventTypeLoanFullyFunded,
+	LoanStateDisbursed: EventTypeLoanDisbursed,
+}
+
+const (
+	AggregateTypeLoan       = "loan"
+	AggregateTypeInvestment = "investment"
+)
+
+// data model
+type (
+	// Event is a domain event stored in the outbox until the relay has published it.
+	Event struct {
+		ID            string
+		Type          EventType
+		AggregateType string
+		AggregateID   string
+		Payload       json.RawMessage
+		OccurredAt    *time.Time
+		PublishedAt   *time.Time
+		Attempts      int
+		LastError     string
+		NextAttemptAt *time.Time
+		// TraceParent is the W3C traceparent of the change that produced the event
+		TraceParent string
+	}
+
+	LoanEventPayload struct {
+		LoanID              string     `json:"loan_id"`
+		BorrowerID          string     `json:"borrower_id"`
+		ProductID           string     `json:"product_id,omitempty"`
+		State               LoanState  `json:"state"`
+		PreviousState       LoanState  `json:"previous_state,omitempty"`
+		PrincipalAmount     float64    `json:"principal_amount"`
+		TotalInvestedAmount float64    `json:"total_invested_amount"`
+		Reason              string     `json:"reason,omitempty"`
+		ActorID             string     `json:"actor_id,omitempty"`
+		PublishedAt         *time.Time `json:"published_at,omitempty"`
+		// Forced is set when an operator moved the loan outside the state machine
+		Forced bool `json:"forced,omitempty"`
+	}
+
+	InvestmentEventPayload struct {
+		InvestmentID        string   `json:"investment_id,omitempty"`
+		LoanID              string   `json:"loan_id"`
+		InvestorID          string   `json:"investor_id,omitempty"`
+		InvestorIDs         []string `json:"investor_ids,omitempty"`
+		InvestedAmount      float64  `json:"invested_amount,omitempty"`
+		TotalInvestedAmount float64  `json:"total_invested_amount"`
+	}
+
+	// ReplayEventsRequest selects the outbox events to publish again, one event or every event of an aggregate
+	ReplayEventsRequest struct {
+		EventID     string
+		AggregateID string
+	}
+
+	// EventDeliveryResponse is the delivery state of an outbox event, recorded in the audit log when an
+	// operator replays the event.
+	EventDeliveryResponse struct {
+		EventID     string     `json:"event_id"`
+		Type        EventType  `json:"type"`
+		PublishedAt *time.Time `json:"published_at"`
+		Attempts    int        `json:"attempts"`
+		LastError   string     `json:"last_error,omitempty"`
+	}
+
+	// EventEnvelope is the wire format handed to event sinks.
+	EventEnvelope struct {
+		EventID       string          `json:"event_id"`
+		Type          EventType       `json:"type"`
+		AggregateType string          `json:"aggregate_type"`
+		AggregateID   string          `json:"aggregate_id"`
+		OccurredAt    *time.Time      `json:"occurred_at"`
+		Data          json.RawMessage `json:"data"`
+	}
+)
+
+func ComposeLoanEventPayload(loan *Loan, previousState LoanState, reason string, actorID string) *LoanEventPayload {
+	return &LoanEventPayload{
+		LoanID:              loan.ID,
+		BorrowerID:          loan.BorrowerID,
+		ProductID:           loan.ProductID,
+		State:               loan.State,
+		PreviousState:       previousState,
+		PrincipalAmount:     loan.PrincipalAmount,
+		TotalInvestedAmount: loan.TotalInvestedAmount,
+		Reason:              reason,
+		ActorID:             actorID,
+		PublishedAt:         loan.PublishedAt,
+	}
+}
+
+func ComposeEventEnvelope(event *Event) *EventEnvelope {
+	return &EventEnvelope{
+		EventID:       event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.OccurredAt,
+		Data:          event.Payload,
+	}
+}
+
+func ComposeEventDeliveryResponse(event *Event) *EventDeliveryResponse {
+	return &EventDeliveryResponse{
+		EventID:     event.ID,
+		Type:        event.Type,
+		PublishedAt: event.PublishedAt,
+		Attempts:    event.Attempts,
+		LastError:   event.LastError,
+	}
+}
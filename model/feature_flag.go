@@ -0,0 +1,133 @@
+package model
+
+import (
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// feature flags checked by the services
+const (
+	// FeatureFlagAutoCancelExpiredLoans lets the expiry job cancel published loans past their funding deadline
+	FeatureFlagAutoCancelExpiredLoans = "loan.auto_cancel_expired"
+	// FeatureFlagCandidateScorecard scores loan proposals with the candidate scorecard instead of the current one
+	FeatureFlagCandidateScorecard = "scoring.candidate_scorecard"
+)
+
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z0-9_.]{1,100}$`)
+
+// IsValidFeatureFlagKey checks a flag key, e.g. loan.auto_cancel_expired.
+func IsValidFeatureFlagKey(key string) bool {
+	return featureFlagKeyPattern.MatchString(key)
+}
+
+// data model
+type (
+	FeatureFlag struct {
+		Key               string
+		Description       string
+		Enabled           bool
+		RolloutPercentage int
+		Rules             []FeatureFlagRule
+		UpdatedBy         string
+		CreatedAt         *time.Time
+		UpdatedAt         *time.Time
+	}
+
+	// FeatureFlagRule turns the flag on for the requests matching every criterion it sets.
+	FeatureFlagRule struct {
+		Roles      []string `json:"roles,omitempty"`
+		UserIDs    []string `json:"user_ids,omitempty" validate:"omitempty,dive,uuid"`
+		ProductIDs []string `json:"product_ids,omitempty" validate:"omitempty,dive,uuid"`
+	}
+
+	// FeatureFlagContext is what a flag is evaluated against.
+	FeatureFlagContext struct {
+		UserID    string
+		Role      string
+		ProductID string
+	}
+)
+
+// IsEnabledFor evaluates the flag. A disabled flag is off for everyone. An enabled flag is on when a rule
+// matches, otherwise for RolloutPercentage percent of the users, always the same users for the same flag.
+func (ff *FeatureFlag) IsEnabledFor(featureFlagContext *FeatureFlagContext) bool {
+	if !ff.Enabled {
+		return false
+	}
+
+	for _, rule := range ff.Rules {
+		if rule.Matches(featureFlagContext) {
+			return true
+		}
+	}
+
+	switch {
+	case ff.RolloutPercentage >= 100:
+		return true
+	case ff.RolloutPercentage <= 0:
+		return false
+	}
+
+	return rolloutBucket(ff.Key, featureFlagContext.UserID) < ff.RolloutPercentage
+}
+
+// Matches reports whether the context meets every criterion of the rule, a rule without criteria matches nothing.
+func (ffr *FeatureFlagRule) Matches(featureFlagContext *FeatureFlagContext) bool {
+	if len(ffr.Roles) == 0 && len(ffr.UserIDs) == 0 && len(ffr.ProductIDs) == 0 {
+		return false
+	}
+
+	return (len(ffr.Roles) == 0 || slices.Contains(ffr.Roles, featureFlagContext.Role)) &&
+		(len(ffr.UserIDs) == 0 || slices.Contains(ffr.UserIDs, featureFlagContext.UserID)) &&
+		(len(ffr.ProductIDs) == 0 || slices.Contains(ffr.ProductIDs, featureFlagContext.ProductID))
+}
+
+// rolloutBucket places the user in one of 100 buckets, hashed with the flag key so every flag rolls out to
+// a different share of users.
+func rolloutBucket(key string, userID string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key + ":" + userID))
+
+	return int(hash.Sum32() % 100)
+}
+
+// request response
+type (
+	UpdateFeatureFlagRequest struct {
+		Key               string
+		Description       string            `json:"description"`
+		Enabled           bool              `json:"enabled"`
+		RolloutPercentage int               `json:"rollout_percentage" validate:"gte=0,lte=100"`
+		Rules             []FeatureFlagRule `json:"rules" validate:"dive"`
+	}
+
+	GetFeatureFlagRequest struct {
+		Key string
+	}
+
+	FeatureFlagResponse struct {
+		Key               string            `json:"key"`
+		Description       string            `json:"description,omitempty"`
+		Enabled           bool              `json:"enabled"`
+		RolloutPercentage int               `json:"rollout_percentage"`
+		Rules             []FeatureFlagRule `json:"rules"`
+		UpdatedBy         string            `json:"updated_by,omitempty"`
+		CreatedAt         *time.Time        `json:"created_at,omitempty"`
+		UpdatedAt         *time.Time        `json:"updated_at,omitempty"`
+	}
+)
+
+func ComposeFeatureFlagResponse(featureFlag *FeatureFlag) *FeatureFlagResponse {
+	return &FeatureFlagResponse{
+		Key:               featureFlag.Key,
+		Description:       featureFlag.Description,
+		Enabled:           featureFlag.Enabled,
+		RolloutPercentage: featureFlag.RolloutPercentage,
+		Rules:             featureFlag.Rules,
+		UpdatedBy:         featureFlag.UpdatedBy,
+		CreatedAt:         featureFlag.CreatedAt,
+		UpdatedAt:         featureFlag.UpdatedAt,
+	}
+}
@@ -1,11 +1,30 @@
 package model
 
+type HealthStatus string
+
+const (
+	HealthStatusUp HealthStatus = "up"
+	// HealthStatusDegraded is reported when a non critical dependency fails, the service still takes traffic
+	HealthStatusDegraded HealthStatus = "degraded"
+	// HealthStatusDown is reported when a critical dependency fails, the service should not take traffic
+	HealthStatusDown HealthStatus = "down"
+)
+
 type HealthCheckResponse struct {
-	ServiceStatus  string `json:"service_status,omitempty"`
-	DatabaseStatus string `json:"database_status,omitempty"`
+	Status HealthStatus         `json:"status"`
+	Checks []*HealthCheckResult `json:"checks,omitempty"`
+}
+
+type HealthCheckResult struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	Critical  bool         `json:"critical"`
+	LatencyMS float64      `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
 }
 
-type Ping struct {
-	ServiceStatus  string
-	DatabaseStatus string
+// MigrationState is the golang-migrate version of the schema, dirty while a migration runs or after it failed.
+type MigrationState struct {
+	Version int64
+	Dirty   bool
 }
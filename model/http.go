@@ -2,6 +2,15 @@ package model
 
 import "net/http"
 
+const (
+	RequestIDHeader    = "X-Request-ID"
+	RequestIDMaxLength = 128
+
+	ProblemContentType = "application/problem+json"
+	// ProblemTypePrefix prefixes the error code to form the RFC 7807 problem type URI
+	ProblemTypePrefix = "urn:loan-service:error:"
+)
+
 type (
 	GenericResponse struct {
 		Code    int            `json:"code,omitempty"`
@@ -11,8 +20,22 @@ type (
 	}
 
 	ErrorResponse struct {
-		Detail  string `json:"detail,omitempty"`
-		Message string `json:"message,omitempty"`
+		Code    ErrorCode      `json:"code,omitempty"`
+		Detail  string         `json:"detail,omitempty"`
+		Message string         `json:"message,omitempty"`
+		Details map[string]any `json:"details,omitempty"`
+		Fields  []FieldError   `json:"fields,omitempty"`
+	}
+
+	// ProblemResponse is the RFC 7807 representation of an error, sent to clients accepting application/problem+json.
+	ProblemResponse struct {
+		Type    string         `json:"type"`
+		Title   string         `json:"title"`
+		Status  int            `json:"status"`
+		Detail  string         `json:"detail,omitempty"`
+		Code    ErrorCode      `json:"code"`
+		Details map[string]any `json:"details,omitempty"`
+		Fields  []FieldError   `json:"fields,omitempty"`
 	}
 )
 
@@ -34,3 +57,31 @@ func ComposeErrorResponse(code int, errDetail string, errMessage string) Generic
 		},
 	}
 }
+
+// ComposeDomainErrorResponse builds the error response of a domain error in the given language, errDetail
+// is the full error message including the wrapped causes.
+func ComposeDomainErrorResponse(domainErr *DomainError, errDetail string, language Language) GenericResponse {
+	return GenericResponse{
+		Code:    domainErr.Status,
+		Message: http.StatusText(domainErr.Status),
+		Error: &ErrorResponse{
+			Code:    domainErr.Code,
+			Detail:  errDetail,
+			Message: domainErr.LocalizedMessage(language),
+			Details: domainErr.Details,
+			Fields:  domainErr.LocalizedFields(language),
+		},
+	}
+}
+
+func ComposeProblemResponse(domainErr *DomainError, errDetail string, language Language) *ProblemResponse {
+	return &ProblemResponse{
+		Type:    ProblemTypePrefix + string(domainErr.Code),
+		Title:   domainErr.LocalizedMessage(language),
+		Status:  domainErr.Status,
+		Detail:  errDetail,
+		Code:    domainErr.Code,
+		Details: domainErr.Details,
+		Fields:  domainErr.LocalizedFields(language),
+	}
+}
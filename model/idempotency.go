@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const JobTypePurgeIdempotencyKeys JobType = "idempotency.purge_expired"
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	IdempotencyKeyMaxLength  = 255
+)
+
+type IdempotencyKeyStatus string
+
+const (
+	IdempotencyKeyStatusProcessing IdempotencyKeyStatus = "processing"
+	IdempotencyKeyStatusCompleted  IdempotencyKeyStatus = "completed"
+)
+
+// data model
+type (
+	// IdempotencyKey remembers the first request made with a key and the response it got.
+	IdempotencyKey struct {
+		Key          string
+		UserID       string
+		Method       string
+		Path         string
+		RequestHash  string
+		Status       IdempotencyKeyStatus
+		ResponseCode int
+		// ResponseHeaders are the headers replayed with the response, e.g. its Content-Type
+		ResponseHeaders map[string]string
+		ResponseBody    []byte
+		CreatedAt       *time.Time
+		ExpiresAt       *time.Time
+		CompletedAt     *time.Time
+	}
+)
+
+// request response
+type (
+	BeginIdempotentRequest struct {
+		UserID string
+		Key    string
+		Method string
+		Path   string
+		Body   []byte
+	}
+
+	CompleteIdempotentRequest struct {
+		UserID          string
+		Key             string
+		ResponseCode    int
+		ResponseHeaders map[string]string
+		ResponseBody    []byte
+	}
+)
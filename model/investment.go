@@ -2,6 +2,13 @@ package model
 
 import "time"
 
+type InvestmentStatus string
+
+const (
+	InvestmentStatusActive   InvestmentStatus = "active"
+	InvestmentStatusReleased InvestmentStatus = "released"
+)
+
 type Investment struct {
 	ID                           string
 	LoanID                       string
@@ -11,4 +18,34 @@ type Investment struct {
 	IsInvestmentAggrementSigned  bool
 	InvestmentAggrementSignedAt  *time.Time
 	TotalProfit                  float64
+	Status                       InvestmentStatus
+	ReleasedAt                   *time.Time
+}
+
+type InvestmentResponse struct {
+	InvestmentID                 string           `json:"investment_id"`
+	LoanID                       string           `json:"loan_id"`
+	InvestorID                   string           `json:"investor_id"`
+	InvestedAmount               float64          `json:"invested_amount"`
+	InvestmentAgreementLetterURL string           `json:"investment_agreement_letter_url,omitempty"`
+	IsInvestmentAggrementSigned  bool             `json:"is_investment_aggrement_signed"`
+	InvestmentAggrementSignedAt  *time.Time       `json:"investment_aggrement_signed_at,omitempty"`
+	TotalProfit                  float64          `json:"total_profit"`
+	Status                       InvestmentStatus `json:"status"`
+	ReleasedAt                   *time.Time       `json:"released_at,omitempty"`
+}
+
+func ComposeInvestmentResponse(investment *Investment) *InvestmentResponse {
+	return &InvestmentResponse{
+		InvestmentID:                 investment.ID,
+		LoanID:                       investment.LoanID,
+		InvestorID:                   investment.InvestorID,
+		InvestedAmount:               investment.InvestedAmount,
+		InvestmentAgreementLetterURL: investment.InvestmentAgreementLetterURL,
+		IsInvestmentAggrementSigned:  investment.IsInvestmentAggrementSigned,
+		InvestmentAggrementSignedAt:  investment.InvestmentAggrementSignedAt,
+		TotalProfit:                  investment.TotalProfit,
+		Status:                       investment.Status,
+		ReleasedAt:                   investment.ReleasedAt,
+	}
 }
@@ -11,4 +11,30 @@ type Investor struct {
 	PhoneNumber string
 	CreatedAt   *time.Time
 	UpdatedAt   *time.Time
+	// ErasedAt is set once the personal data is erased, the investor is kept for its investments
+	ErasedAt *time.Time
+}
+
+type InvestorResponse struct {
+	InvestorID  string     `json:"investor_id"`
+	Name        string     `json:"name,omitempty"`
+	NIK         string     `json:"nik,omitempty"`
+	NPWP        string     `json:"npwp,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	PhoneNumber string     `json:"phone_number,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	ErasedAt    *time.Time `json:"erased_at,omitempty"`
+}
+
+func ComposeInvestorResponse(investor *Investor) *InvestorResponse {
+	return &InvestorResponse{
+		InvestorID:  investor.ID,
+		Name:        investor.Name,
+		NIK:         investor.NIK,
+		NPWP:        investor.NPWP,
+		Email:       investor.Email,
+		PhoneNumber: investor.PhoneNumber,
+		CreatedAt:   investor.CreatedAt,
+		ErasedAt:    investor.ErasedAt,
+	}
 }
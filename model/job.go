@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobType string
+
+const (
+	JobTypeCancelExpiredLoans    JobType = "loan.cancel_expired"
+	JobTypeReencryptPersonalData JobType = "personal_data.reencrypt"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusDead      JobStatus = "dead"
+)
+
+var ValidJobStatus = map[JobStatus]bool{
+	JobStatusPending:   true,
+	JobStatusRunning:   true,
+	JobStatusSucceeded: true,
+	JobStatusDead:      true,
+}
+
+// data model
+type (
+	Job struct {
+		ID          string
+		Type        JobType
+		Payload     json.RawMessage
+		Status      JobStatus
+		Attempts    int
+		MaxAttempts int
+		RunAt       *time.Time
+		LockedAt    *time.Time
+		LockedBy    string
+		LastError   string
+		CompletedAt *time.Time
+		CreatedAt   *time.Time
+		UpdatedAt   *time.Time
+	}
+
+	RecurringJob struct {
+		Name        string
+		Schedule    string
+		JobType     JobType
+		Payload     json.RawMessage
+		MaxAttempts int
+		NextRunAt   *time.Time
+		LastRunAt   *time.Time
+	}
+)
+
+// request response
+type (
+	ListJobsRequest struct {
+		Status JobStatus
+		Limit  int
+	}
+
+	RetryJobRequest struct {
+		JobID string
+	}
+
+	JobResponse struct {
+		JobID       string          `json:"job_id"`
+		Type        string          `json:"type"`
+		Payload     json.RawMessage `json:"payload"`
+		Status      string          `json:"status"`
+		Attempts    int             `json:"attempts"`
+		MaxAttempts int             `json:"max_attempts"`
+		RunAt       *time.Time      `json:"run_at,omitempty"`
+		LastError   string          `json:"last_error,omitempty"`
+		CompletedAt *time.Time      `json:"completed_at,omitempty"`
+		CreatedAt   *time.Time      `json:"created_at,omitempty"`
+	}
+)
+
+func ComposeJobResponse(job *Job) *JobResponse {
+	return &JobResponse{
+		JobID:       job.ID,
+		Type:        string(job.Type),
+		Payload:     job.Payload,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+	}
+}
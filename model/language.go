@@ -0,0 +1,30 @@
+package model
+
+import "golang.org/x/text/language"
+
+const (
+	AcceptLanguageHeader  = "Accept-Language"
+	ContentLanguageHeader = "Content-Language"
+)
+
+type Language string
+
+const (
+	LanguageIndonesian Language = "id"
+	LanguageEnglish    Language = "en"
+)
+
+// apiLanguages are the languages of API messages in the order of apiLanguageTags, English is the fallback.
+var (
+	apiLanguages    = []Language{LanguageEnglish, LanguageIndonesian}
+	apiLanguageTags = []language.Tag{language.English, language.Indonesian}
+	languageMatcher = language.NewMatcher(apiLanguageTags)
+)
+
+// AcceptedLanguage picks the language of API messages from an Accept-Language header, English when
+// none of the requested languages is supported.
+func AcceptedLanguage(acceptLanguage string) Language {
+	_, index := language.MatchStrings(languageMatcher, acceptLanguage)
+
+	return apiLanguages[index]
+}
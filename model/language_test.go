@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/model"
+)
+
+var _ = Describe("AcceptedLanguage", func() {
+	DescribeTable("should pick the API language",
+		func(acceptLanguage string, expected model.Language) {
+			Expect(model.AcceptedLanguage(acceptLanguage)).To(Equal(expected))
+		},
+		Entry("no header", "", model.LanguageEnglish),
+		Entry("english", "en", model.LanguageEnglish),
+		Entry("indonesian", "id", model.LanguageIndonesian),
+		Entry("indonesian region tag", "id-ID", model.LanguageIndonesian),
+		Entry("english region tag", "en-GB", model.LanguageEnglish),
+		Entry("deprecated indonesian tag", "in", model.LanguageIndonesian),
+		Entry("higher q-value for english", "id;q=0.5, en;q=0.9", model.LanguageEnglish),
+		Entry("higher q-value for indonesian", "en;q=0.1, id-ID;q=0.8", model.LanguageIndonesian),
+		Entry("unsupported language", "fr-FR", model.LanguageEnglish),
+		Entry("unsupported language before a supported one", "ja, id;q=0.7", model.LanguageIndonesian),
+		Entry("wildcard", "*", model.LanguageEnglish),
+		Entry("malformed header", ";;q=abc", model.LanguageEnglish),
+	)
+})
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 type LoanState string
 
@@ -25,10 +29,11 @@ var ValidStateTransitions = map[LoanState][]LoanState{
 }
 
 var StateUpdates = map[LoanState][]string{
+	LoanStateProposed:  {"state"},
 	LoanStateApproved:  {"state", "approved_at", "approved_by"},
-	LoanStateRejected:  {"state", "rejected_at", "rejected_by"},
-	LoanStateCanceled:  {"state", "canceled_at", "canceled_by"},
-	LoanStatePublished: {"state", "published_at", "published_by"},
+	LoanStateRejected:  {"state", "rejected_at", "rejected_by", "rejected_reason"},
+	LoanStateCanceled:  {"state", "canceled_at", "canceled_by", "canceled_reason"},
+	LoanStatePublished: {"state", "published_at", "published_by", "funding_deadline"},
 	LoanStateInvested:  {"state", "invested_at"},
 	LoanStateDisbursed: {"state"},
 }
@@ -50,6 +55,8 @@ type (
 	Loan struct {
 		ID                     string
 		BorrowerID             string
+		ProductID              string
+		TenorMonths            int64
 		PrincipalAmount        float64
 		TotalInvestedAmount    float64
 		InterestRate           float64
@@ -73,10 +80,13 @@ type (
 		CanceledReason         string
 		PublishedAt            *time.Time
 		PublishedBy            string
+		FundingDeadline        *time.Time
 		InvestedAt             *time.Time
 		DisbursedAt            *time.Time
 		DisbursedBy            string
 		UpdatedAt              *time.Time
+		Risk                   *RiskAssessment
+		Version                int64
 	}
 )
 
@@ -84,24 +94,40 @@ type (
 type (
 	CreateLoanRequest struct {
 		BorrowerID      string  `json:"borrower_id" validate:"required"`
+		ProductID       string  `json:"product_id" validate:"required"`
+		TenorMonths     int64   `json:"tenor_months" validate:"required"`
 		PrincipalAmount float64 `json:"principal_amount" validate:"required"`
 		InterestRate    float64 `json:"interest_rate" validate:"required"`
 		ROIRate         float64 `json:"roi_rate" validate:"required"`
 	}
 
 	CreateLoanResponse struct {
-		LoanID string `json:"loan_id"`
-		State  string `json:"state"`
+		LoanID string            `json:"loan_id"`
+		State  string            `json:"state"`
+		Fees   []LoanFeeResponse `json:"fees,omitempty"`
+		Risk   *LoanRiskResponse `json:"risk,omitempty"`
 	}
 
 	UpdateLoanStateRequest struct {
 		LoanID string
-		State  string `json:"state" validate:"required"`
+		// ExpectedVersion is the loan version from the If-Match header, zero skips the check
+		ExpectedVersion int64
+		State           string `json:"state" validate:"required"`
+		Reason          string `json:"reason"`
 	}
 
 	UpdateLoanStateResponse struct {
-		LoanID string `json:"loan_id"`
-		State  string `json:"state"`
+		LoanID  string `json:"loan_id"`
+		State   string `json:"state"`
+		Version int64  `json:"version"`
+	}
+
+	// ForceLoanStateRequest moves a loan to any state, skipping the state machine and the state
+	// requirements. Operators use it to repair data, the reason is mandatory.
+	ForceLoanStateRequest struct {
+		LoanID string `json:"loan_id" validate:"required"`
+		State  string `json:"state" validate:"required"`
+		Reason string `json:"reason" validate:"required"`
 	}
 
 	CreateLoanInvestmentRequest struct {
@@ -113,4 +139,196 @@ type (
 	CreateLoanInvestmentResponse struct {
 		InvestmentID string `json:"investment_id"`
 	}
+
+	GetLoanRequest struct {
+		LoanID string
+	}
+
+	LoanActorResponse struct {
+		EmployeeID string `json:"employee_id"`
+		Name       string `json:"name"`
+	}
+
+	LoanResponse struct {
+		LoanID                 string             `json:"loan_id"`
+		BorrowerID             string             `json:"borrower_id"`
+		ProductID              string             `json:"product_id,omitempty"`
+		TenorMonths            int64              `json:"tenor_months,omitempty"`
+		PrincipalAmount        float64            `json:"principal_amount"`
+		TotalInvestedAmount    float64            `json:"total_invested_amount"`
+		InterestRate           float64            `json:"interest_rate"`
+		ROIRate                float64            `json:"roi_rate"`
+		State                  string             `json:"state"`
+		VisitProofURL          string             `json:"visit_proof_url,omitempty"`
+		ValidatedAt            *time.Time         `json:"validated_at,omitempty"`
+		ValidatedBy            *LoanActorResponse `json:"validated_by,omitempty"`
+		LoanAgreementLetterURL string             `json:"loan_agreement_letter_url,omitempty"`
+		IsLoanAggrementSigned  bool               `json:"is_loan_aggrement_signed"`
+		LoanAggrementSignedAt  *time.Time         `json:"loan_aggrement_signed_at,omitempty"`
+		CreatedAt              *time.Time         `json:"created_at,omitempty"`
+		CreatedBy              *LoanActorResponse `json:"created_by,omitempty"`
+		ApprovedAt             *time.Time         `json:"approved_at,omitempty"`
+		ApprovedBy             *LoanActorResponse `json:"approved_by,omitempty"`
+		RejectedAt             *time.Time         `json:"rejected_at,omitempty"`
+		RejectedBy             *LoanActorResponse `json:"rejected_by,omitempty"`
+		RejectedReason         string             `json:"rejected_reason,omitempty"`
+		CanceledAt             *time.Time         `json:"canceled_at,omitempty"`
+		CanceledBy             *LoanActorResponse `json:"canceled_by,omitempty"`
+		CanceledReason         string             `json:"canceled_reason,omitempty"`
+		PublishedAt            *time.Time         `json:"published_at,omitempty"`
+		PublishedBy            *LoanActorResponse `json:"published_by,omitempty"`
+		FundingDeadline        *time.Time         `json:"funding_deadline,omitempty"`
+		InvestedAt             *time.Time         `json:"invested_at,omitempty"`
+		DisbursedAt            *time.Time         `json:"disbursed_at,omitempty"`
+		DisbursedBy            *LoanActorResponse `json:"disbursed_by,omitempty"`
+		UpdatedAt              *time.Time         `json:"updated_at,omitempty"`
+		Risk                   *LoanRiskResponse  `json:"risk,omitempty"`
+		Version                int64              `json:"version"`
+	}
+
+	ListPublishedLoansRequest struct {
+		RiskGrade string
+	}
+
+	// PublishedLoanResponse is the investor facing view of a loan open for funding.
+	PublishedLoanResponse struct {
+		LoanID              string     `json:"loan_id"`
+		ProductID           string     `json:"product_id,omitempty"`
+		TenorMonths         int64      `json:"tenor_months,omitempty"`
+		PrincipalAmount     float64    `json:"principal_amount"`
+		TotalInvestedAmount float64    `json:"total_invested_amount"`
+		ROIRate             float64    `json:"roi_rate"`
+		RiskGrade           string     `json:"risk_grade,omitempty"`
+		PublishedAt         *time.Time `json:"published_at,omitempty"`
+		FundingDeadline     *time.Time `json:"funding_deadline,omitempty"`
+	}
 )
+
+// ReasonFundingWindowExpired is recorded on loans canceled because nobody fully funded them in time.
+const ReasonFundingWindowExpired = "funding window expired"
+
+// SystemEmployeeID is the actor recorded for changes made by background jobs.
+const SystemEmployeeID = "00000000-0000-0000-0000-000000000001"
+
+const (
+	ETagHeader    = "ETag"
+	IfMatchHeader = "If-Match"
+)
+
+// LoanETag formats the loan version as a strong entity tag.
+func LoanETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseLoanETag reads the loan version back from an If-Match header value.
+func ParseLoanETag(etag string) (version int64, err error) {
+	unquoted, err := strconv.Unquote(strings.TrimSpace(etag))
+	if err != nil {
+		return 0, ErrorLoanETagInvalid
+	}
+
+	version, err = strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, ErrorLoanETagInvalid
+	}
+
+	return
+}
+
+// NilUUID is returned by the loan repository for actor columns that are not set yet.
+const NilUUID = "00000000-0000-0000-0000-000000000000"
+
+// ActorIDs returns the distinct employee ids referenced by the loan audit columns.
+func (l *Loan) ActorIDs() []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, id := range []string{l.ValidatedBy, l.CreatedBy, l.ApprovedBy, l.RejectedBy, l.CanceledBy, l.PublishedBy, l.DisbursedBy} {
+		if id == "" || id == NilUUID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// ComposeLoanResponse builds the loan response, resolving actor ids into employee names.
+func ComposeLoanResponse(loan *Loan, employees map[string]*Employee) *LoanResponse {
+	actor := func(id string) *LoanActorResponse {
+		if id == "" || id == NilUUID {
+			return nil
+		}
+
+		resp := &LoanActorResponse{EmployeeID: id}
+		if employee, ok := employees[id]; ok {
+			resp.Name = employee.Name
+		}
+
+		return resp
+	}
+
+	productID := loan.ProductID
+	if productID == NilUUID {
+		productID = ""
+	}
+
+	return &LoanResponse{
+		LoanID:                 loan.ID,
+		BorrowerID:             loan.BorrowerID,
+		ProductID:              productID,
+		TenorMonths:            loan.TenorMonths,
+		PrincipalAmount:        loan.PrincipalAmount,
+		TotalInvestedAmount:    loan.TotalInvestedAmount,
+		InterestRate:           loan.InterestRate,
+		ROIRate:                loan.ROIRate,
+		State:                  string(loan.State),
+		VisitProofURL:          loan.VisitProofURL,
+		ValidatedAt:            loan.ValidatedAt,
+		ValidatedBy:            actor(loan.ValidatedBy),
+		LoanAgreementLetterURL: loan.LoanAgreementLetterURL,
+		IsLoanAggrementSigned:  loan.IsLoanAggrementSigned,
+		LoanAggrementSignedAt:  loan.LoanAggrementSignedAt,
+		CreatedAt:              loan.CreatedAt,
+		CreatedBy:              actor(loan.CreatedBy),
+		ApprovedAt:             loan.ApprovedAt,
+		ApprovedBy:             actor(loan.ApprovedBy),
+		RejectedAt:             loan.RejectedAt,
+		RejectedBy:             actor(loan.RejectedBy),
+		RejectedReason:         loan.RejectedReason,
+		CanceledAt:             loan.CanceledAt,
+		CanceledBy:             actor(loan.CanceledBy),
+		CanceledReason:         loan.CanceledReason,
+		PublishedAt:            loan.PublishedAt,
+		PublishedBy:            actor(loan.PublishedBy),
+		FundingDeadline:        loan.FundingDeadline,
+		InvestedAt:             loan.InvestedAt,
+		DisbursedAt:            loan.DisbursedAt,
+		DisbursedBy:            actor(loan.DisbursedBy),
+		UpdatedAt:              loan.UpdatedAt,
+		Risk:                   ComposeLoanRiskResponse(loan.Risk),
+		Version:                loan.Version,
+	}
+}
+
+func ComposePublishedLoanResponse(loan *Loan) *PublishedLoanResponse {
+	resp := &PublishedLoanResponse{
+		LoanID:              loan.ID,
+		TenorMonths:         loan.TenorMonths,
+		PrincipalAmount:     loan.PrincipalAmount,
+		TotalInvestedAmount: loan.TotalInvestedAmount,
+		ROIRate:             loan.ROIRate,
+		PublishedAt:         loan.PublishedAt,
+		FundingDeadline:     loan.FundingDeadline,
+	}
+
+	if loan.ProductID != NilUUID {
+		resp.ProductID = loan.ProductID
+	}
+
+	if loan.Risk != nil {
+		resp.RiskGrade = string(loan.Risk.Grade)
+	}
+
+	return resp
+}
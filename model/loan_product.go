@@ -0,0 +1,210 @@
+package model
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+type LoanProductFeeType string
+
+const (
+	LoanProductFeeTypeFlat       LoanProductFeeType = "flat"
+	LoanProductFeeTypePercentage LoanProductFeeType = "percentage"
+)
+
+// LoanProductFeeTrigger is when a fee is charged, upfront fees are charged on every loan, late fees only when a
+// repayment is late.
+type LoanProductFeeTrigger string
+
+const (
+	LoanProductFeeTriggerUpfront LoanProductFeeTrigger = "upfront"
+	LoanProductFeeTriggerLate    LoanProductFeeTrigger = "late"
+)
+
+// data model
+type (
+	LoanProduct struct {
+		ID                 string
+		Code               string
+		Name               string
+		Description        string
+		MinPrincipalAmount float64
+		MaxPrincipalAmount float64
+		TenorMonths        []int64
+		MinInterestRate    float64
+		MaxInterestRate    float64
+		MinROIRate         float64
+		MaxROIRate         float64
+		Fees               []LoanProductFee
+		FundingWindowDays  int64
+		IsActive           bool
+		CreatedAt          *time.Time
+		UpdatedAt          *time.Time
+	}
+
+	LoanProductFee struct {
+		Name   string             `json:"name" validate:"required"`
+		Type   LoanProductFeeType `json:"type" validate:"required,oneof=flat percentage"`
+		Amount float64            `json:"amount" validate:"gte=0"`
+		// Trigger is upfront when empty
+		Trigger LoanProductFeeTrigger `json:"trigger" validate:"omitempty,oneof=upfront late"`
+	}
+)
+
+// DefaultFundingWindowDays applies to products created without a funding window and to loans without a product.
+const DefaultFundingWindowDays = 14
+
+// FundingDeadline returns when a loan published at publishedAt stops accepting investments.
+func (lp *LoanProduct) FundingDeadline(publishedAt time.Time) time.Time {
+	days := lp.FundingWindowDays
+	if days <= 0 {
+		days = DefaultFundingWindowDays
+	}
+
+	return publishedAt.AddDate(0, 0, int(days))
+}
+
+// IsValid checks that every band of the product is consistent.
+func (lp *LoanProduct) IsValid() bool {
+	return lp.MinPrincipalAmount > 0 && lp.MinPrincipalAmount <= lp.MaxPrincipalAmount &&
+		lp.MinInterestRate >= 0 && lp.MinInterestRate <= lp.MaxInterestRate &&
+		lp.MinROIRate >= 0 && lp.MinROIRate <= lp.MaxROIRate &&
+		len(lp.TenorMonths) > 0
+}
+
+// ValidateLoan checks the requested loan terms against the product limits.
+func (lp *LoanProduct) ValidateLoan(principalAmount float64, tenorMonths int64, interestRate float64, roiRate float64) error {
+	if !lp.IsActive {
+		return ErrorLoanProductInactive
+	}
+
+	if principalAmount < lp.MinPrincipalAmount || principalAmount > lp.MaxPrincipalAmount {
+		return ErrorPrincipalAmountOutOfRange
+	}
+
+	if !slices.Contains(lp.TenorMonths, tenorMonths) {
+		return ErrorTenorNotAllowed
+	}
+
+	if interestRate < lp.MinInterestRate || interestRate > lp.MaxInterestRate {
+		return ErrorInterestRateOutOfBand
+	}
+
+	if roiRate < lp.MinROIRate || roiRate > lp.MaxROIRate {
+		return ErrorROIRateOutOfBand
+	}
+
+	return nil
+}
+
+// ComputeFees applies the upfront fees of the product fee schedule to a principal amount. Late fees are left
+// out, they are only charged when a repayment is late.
+func (lp *LoanProduct) ComputeFees(principalAmount float64) []LoanFeeResponse {
+	fees := make([]LoanFeeResponse, 0, len(lp.Fees))
+	for _, fee := range lp.Fees {
+		if fee.Trigger == LoanProductFeeTriggerLate {
+			continue
+		}
+
+		amount := fee.Amount
+		if fee.Type == LoanProductFeeTypePercentage {
+			amount = math.Round(principalAmount*fee.Amount) / 100
+		}
+
+		fees = append(fees, LoanFeeResponse{
+			Name:   fee.Name,
+			Amount: amount,
+		})
+	}
+
+	return fees
+}
+
+// request response
+type (
+	CreateLoanProductRequest struct {
+		Code               string           `json:"code" validate:"required"`
+		Name               string           `json:"name" validate:"required"`
+		Description        string           `json:"description"`
+		MinPrincipalAmount float64          `json:"min_principal_amount" validate:"required,gt=0"`
+		MaxPrincipalAmount float64          `json:"max_principal_amount" validate:"required,gtefield=MinPrincipalAmount"`
+		TenorMonths        []int64          `json:"tenor_months" validate:"required,min=1,dive,gt=0"`
+		MinInterestRate    float64          `json:"min_interest_rate" validate:"gte=0"`
+		MaxInterestRate    float64          `json:"max_interest_rate" validate:"required,gtefield=MinInterestRate"`
+		MinROIRate         float64          `json:"min_roi_rate" validate:"gte=0"`
+		MaxROIRate         float64          `json:"max_roi_rate" validate:"required,gtefield=MinROIRate"`
+		Fees               []LoanProductFee `json:"fees" validate:"dive"`
+		FundingWindowDays  int64            `json:"funding_window_days" validate:"omitempty,gt=0"`
+	}
+
+	UpdateLoanProductRequest struct {
+		ProductID          string
+		Name               string           `json:"name" validate:"required"`
+		Description        string           `json:"description"`
+		MinPrincipalAmount float64          `json:"min_principal_amount" validate:"required,gt=0"`
+		MaxPrincipalAmount float64          `json:"max_principal_amount" validate:"required,gtefield=MinPrincipalAmount"`
+		TenorMonths        []int64          `json:"tenor_months" validate:"required,min=1,dive,gt=0"`
+		MinInterestRate    float64          `json:"min_interest_rate" validate:"gte=0"`
+		MaxInterestRate    float64          `json:"max_interest_rate" validate:"required,gtefield=MinInterestRate"`
+		MinROIRate         float64          `json:"min_roi_rate" validate:"gte=0"`
+		MaxROIRate         float64          `json:"max_roi_rate" validate:"required,gtefield=MinROIRate"`
+		Fees               []LoanProductFee `json:"fees" validate:"dive"`
+		FundingWindowDays  int64            `json:"funding_window_days" validate:"omitempty,gt=0"`
+		IsActive           bool             `json:"is_active"`
+	}
+
+	GetLoanProductRequest struct {
+		ProductID string
+	}
+
+	ListLoanProductsRequest struct {
+		ActiveOnly bool
+	}
+
+	LoanProductResponse struct {
+		ProductID          string           `json:"product_id"`
+		Code               string           `json:"code"`
+		Name               string           `json:"name"`
+		Description        string           `json:"description,omitempty"`
+		MinPrincipalAmount float64          `json:"min_principal_amount"`
+		MaxPrincipalAmount float64          `json:"max_principal_amount"`
+		TenorMonths        []int64          `json:"tenor_months"`
+		MinInterestRate    float64          `json:"min_interest_rate"`
+		MaxInterestRate    float64          `json:"max_interest_rate"`
+		MinROIRate         float64          `json:"min_roi_rate"`
+		MaxROIRate         float64          `json:"max_roi_rate"`
+		Fees               []LoanProductFee `json:"fees"`
+		FundingWindowDays  int64            `json:"funding_window_days"`
+		IsActive           bool             `json:"is_active"`
+	}
+
+	LoanFeeResponse struct {
+		Name   string  `json:"name"`
+		Amount float64 `json:"amount"`
+	}
+)
+
+func ComposeLoanProductResponse(product *LoanProduct) *LoanProductResponse {
+	fees := product.Fees
+	if fees == nil {
+		fees = []LoanProductFee{}
+	}
+
+	return &LoanProductResponse{
+		ProductID:          product.ID,
+		Code:               product.Code,
+		Name:               product.Name,
+		Description:        product.Description,
+		MinPrincipalAmount: product.MinPrincipalAmount,
+		MaxPrincipalAmount: product.MaxPrincipalAmount,
+		TenorMonths:        product.TenorMonths,
+		MinInterestRate:    product.MinInterestRate,
+		MaxInterestRate:    product.MaxInterestRate,
+		MinROIRate:         product.MinROIRate,
+		MaxROIRate:         product.MaxROIRate,
+		Fees:               fees,
+		FundingWindowDays:  product.FundingWindowDays,
+		IsActive:           product.IsActive,
+	}
+}
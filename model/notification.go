@@ -0,0 +1,174 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	JobTypeSendEmailNotification  JobType = "notification.send_email"
+	JobTypeSendRepaymentReminders JobType = "notification.repayment_reminders"
+)
+
+type NotificationEvent string
+
+const (
+	NotificationEventLoanCanceled   NotificationEvent = "loan_canceled"
+	NotificationEventLoanFunded     NotificationEvent = "loan_funded"
+	NotificationEventAgreementReady NotificationEvent = "agreement_ready"
+	NotificationEventLoanDisbursed  NotificationEvent = "loan_disbursed"
+	NotificationEventRepaymentDue   NotificationEvent = "repayment_due"
+)
+
+var ValidNotificationEvents = map[NotificationEvent]bool{
+	NotificationEventLoanCanceled:   true,
+	NotificationEventLoanFunded:     true,
+	NotificationEventAgreementReady: true,
+	NotificationEventLoanDisbursed:  true,
+	NotificationEventRepaymentDue:   true,
+}
+
+type RecipientType string
+
+const (
+	RecipientTypeBorrower RecipientType = "borrower"
+	RecipientTypeInvestor RecipientType = "investor"
+)
+
+var ValidRecipientTypes = map[RecipientType]bool{
+	RecipientTypeBorrower: true,
+	RecipientTypeInvestor: true,
+}
+
+type EmailNotificationStatus string
+
+const (
+	EmailNotificationStatusPending EmailNotificationStatus = "pending"
+	EmailNotificationStatusSent    EmailNotificationStatus = "sent"
+)
+
+// data model
+type (
+	// Notification tells the borrower and investors of a loan that something happened to it.
+	Notification struct {
+		Event       NotificationEvent
+		LoanID      string
+		BorrowerID  string
+		InvestorIDs []string
+		Reason      string
+		// Key identifies the occurrence, a recipient gets one email per event and key. Defaults to the loan id.
+		Key         string
+		Installment *RepaymentInstallment
+	}
+
+	NotificationPreference struct {
+		RecipientType  RecipientType
+		RecipientID    string
+		Language       Language
+		EmailEnabled   bool
+		DisabledEvents []string
+		CreatedAt      *time.Time
+		UpdatedAt      *time.Time
+	}
+
+	// EmailNotification is a rendered email waiting for or done with delivery.
+	EmailNotification struct {
+		ID            string
+		Event         NotificationEvent
+		RecipientType RecipientType
+		RecipientID   string
+		Email         string
+		Language      Language
+		Subject       string
+		Body          string
+		DedupeKey     string
+		Status        EmailNotificationStatus
+		Attempts      int
+		LastError     string
+		SentAt        *time.Time
+		CreatedAt     *time.Time
+		UpdatedAt     *time.Time
+	}
+
+	EmailMessage struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	RepaymentInstallment struct {
+		Number  int64
+		DueDate time.Time
+		Amount  float64
+	}
+
+	SendEmailNotificationPayload struct {
+		NotificationID string `json:"notification_id"`
+	}
+)
+
+// request response
+type (
+	GetNotificationPreferenceRequest struct {
+		RecipientType RecipientType
+		RecipientID   string
+	}
+
+	UpdateNotificationPreferenceRequest struct {
+		RecipientType  RecipientType
+		RecipientID    string
+		Language       string   `json:"language" validate:"required,oneof=id en"`
+		EmailEnabled   bool     `json:"email_enabled"`
+		DisabledEvents []string `json:"disabled_events"`
+	}
+
+	NotificationPreferenceResponse struct {
+		RecipientType  string     `json:"recipient_type"`
+		RecipientID    string     `json:"recipient_id"`
+		Language       string     `json:"language"`
+		EmailEnabled   bool       `json:"email_enabled"`
+		DisabledEvents []string   `json:"disabled_events"`
+		UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	}
+)
+
+// Allows reports whether the recipient wants an email for the event.
+func (np *NotificationPreference) Allows(event NotificationEvent) bool {
+	return np.EmailEnabled && !slices.Contains(np.DisabledEvents, string(event))
+}
+
+// ComposeRepaymentSchedule splits the principal plus flat interest into equal monthly installments,
+// the first one due a month after disbursement.
+func ComposeRepaymentSchedule(loan *Loan) (installments []*RepaymentInstallment) {
+	if loan.DisbursedAt == nil || loan.TenorMonths <= 0 {
+		return
+	}
+
+	total := loan.PrincipalAmount * (1 + loan.InterestRate/100)
+	amount := total / float64(loan.TenorMonths)
+	for i := int64(1); i <= loan.TenorMonths; i++ {
+		installments = append(installments, &RepaymentInstallment{
+			Number:  i,
+			DueDate: loan.DisbursedAt.AddDate(0, int(i), 0),
+			Amount:  amount,
+		})
+	}
+
+	return
+}
+
+func ComposeNotificationPreferenceResponse(preference *NotificationPreference) *NotificationPreferenceResponse {
+	disabledEvents := preference.DisabledEvents
+	if disabledEvents == nil {
+		disabledEvents = []string{}
+	}
+
+	return &NotificationPreferenceResponse{
+		RecipientType:  string(preference.RecipientType),
+		RecipientID:    preference.RecipientID,
+		Language:       string(preference.Language),
+		EmailEnabled:   preference.EmailEnabled,
+		DisabledEvents: disabledEvents,
+		UpdatedAt:      preference.UpdatedAt,
+	}
+}
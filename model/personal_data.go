@@ -0,0 +1,195 @@
+package model
+
+import "time"
+
+type ErasureRequestStatus string
+
+const (
+	ErasureRequestStatusPending   ErasureRequestStatus = "pending"
+	ErasureRequestStatusCompleted ErasureRequestStatus = "completed"
+	ErasureRequestStatusRejected  ErasureRequestStatus = "rejected"
+)
+
+var ValidErasureRequestStatus = map[ErasureRequestStatus]bool{
+	ErasureRequestStatusPending:   true,
+	ErasureRequestStatusCompleted: true,
+	ErasureRequestStatusRejected:  true,
+}
+
+// ErasureBlockingLoanStates are the states of loans still running, their borrower cannot be erased before
+// the loan is settled or canceled. Disbursed loans count as running since repayment is not tracked.
+var ErasureBlockingLoanStates = map[LoanState]bool{
+	LoanStateProposed:  true,
+	LoanStateApproved:  true,
+	LoanStatePublished: true,
+	LoanStateInvested:  true,
+	LoanStateDisbursed: true,
+}
+
+// documents referenced by the data export
+const (
+	PersonalDataDocumentVisitProof          = "visit_proof"
+	PersonalDataDocumentLoanAgreement       = "loan_agreement"
+	PersonalDataDocumentInvestmentAgreement = "investment_agreement"
+)
+
+// data model
+type (
+	// PersonalDataErasureRequest asks to erase the personal data of a borrower or investor, an admin
+	// completes or rejects it.
+	PersonalDataErasureRequest struct {
+		ID             string
+		SubjectType    RecipientType
+		SubjectID      string
+		Status         ErasureRequestStatus
+		Reason         string
+		RejectedReason string
+		ProcessedAt    *time.Time
+		ProcessedBy    string
+		CreatedAt      *time.Time
+		UpdatedAt      *time.Time
+	}
+
+	// PersonalDataExport is the zip archive of everything stored about a borrower or investor.
+	PersonalDataExport struct {
+		FileName string
+		Content  []byte
+	}
+)
+
+// request response
+type (
+	ExportPersonalDataRequest struct {
+		SubjectType RecipientType
+		SubjectID   string
+	}
+
+	CreateErasureRequestRequest struct {
+		SubjectType RecipientType
+		SubjectID   string
+		Reason      string `json:"reason"`
+	}
+
+	ListErasureRequestsRequest struct {
+		Status ErasureRequestStatus
+	}
+
+	CompleteErasureRequestRequest struct {
+		ID string
+	}
+
+	RejectErasureRequestRequest struct {
+		ID     string
+		Reason string `json:"reason" validate:"required"`
+	}
+
+	ErasureRequestResponse struct {
+		ErasureRequestID string               `json:"erasure_request_id"`
+		SubjectType      string               `json:"subject_type"`
+		SubjectID        string               `json:"subject_id"`
+		Status           ErasureRequestStatus `json:"status"`
+		Reason           string               `json:"reason,omitempty"`
+		RejectedReason   string               `json:"rejected_reason,omitempty"`
+		ProcessedAt      *time.Time           `json:"processed_at,omitempty"`
+		ProcessedBy      string               `json:"processed_by,omitempty"`
+		CreatedAt        *time.Time           `json:"created_at,omitempty"`
+	}
+
+	// PersonalDataManifestResponse is the manifest.json of the data export.
+	PersonalDataManifestResponse struct {
+		SubjectType string    `json:"subject_type"`
+		SubjectID   string    `json:"subject_id"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Files       []string  `json:"files"`
+	}
+
+	// PersonalDataDocumentResponse references a stored document of the subject, the export links the
+	// documents rather than copying them.
+	PersonalDataDocumentResponse struct {
+		Type         string     `json:"type"`
+		LoanID       string     `json:"loan_id,omitempty"`
+		InvestmentID string     `json:"investment_id,omitempty"`
+		URL          string     `json:"url"`
+		SignedAt     *time.Time `json:"signed_at,omitempty"`
+	}
+
+	PersonalDataNotificationResponse struct {
+		NotificationID string                  `json:"notification_id"`
+		Event          NotificationEvent       `json:"event"`
+		Email          string                  `json:"email,omitempty"`
+		Subject        string                  `json:"subject,omitempty"`
+		Body           string                  `json:"body,omitempty"`
+		Status         EmailNotificationStatus `json:"status"`
+		SentAt         *time.Time              `json:"sent_at,omitempty"`
+		CreatedAt      *time.Time              `json:"created_at,omitempty"`
+	}
+
+	PersonalDataNotificationsResponse struct {
+		Preference    *NotificationPreferenceResponse     `json:"preference,omitempty"`
+		Notifications []*PersonalDataNotificationResponse `json:"notifications"`
+	}
+)
+
+func ComposeErasureRequestResponse(erasureRequest *PersonalDataErasureRequest) *ErasureRequestResponse {
+	return &ErasureRequestResponse{
+		ErasureRequestID: erasureRequest.ID,
+		SubjectType:      string(erasureRequest.SubjectType),
+		SubjectID:        erasureRequest.SubjectID,
+		Status:           erasureRequest.Status,
+		Reason:           erasureRequest.Reason,
+		RejectedReason:   erasureRequest.RejectedReason,
+		ProcessedAt:      erasureRequest.ProcessedAt,
+		ProcessedBy:      erasureRequest.ProcessedBy,
+		CreatedAt:        erasureRequest.CreatedAt,
+	}
+}
+
+func ComposePersonalDataNotificationResponse(notification *EmailNotification) *PersonalDataNotificationResponse {
+	return &PersonalDataNotificationResponse{
+		NotificationID: notification.ID,
+		Event:          notification.Event,
+		Email:          notification.Email,
+		Subject:        notification.Subject,
+		Body:           notification.Body,
+		Status:         notification.Status,
+		SentAt:         notification.SentAt,
+		CreatedAt:      notification.CreatedAt,
+	}
+}
+
+// ComposeLoanDocuments lists the documents stored for the loan.
+func ComposeLoanDocuments(loan *Loan) (documents []*PersonalDataDocumentResponse) {
+	if loan.VisitProofURL != "" {
+		documents = append(documents, &PersonalDataDocumentResponse{
+			Type:   PersonalDataDocumentVisitProof,
+			LoanID: loan.ID,
+			URL:    loan.VisitProofURL,
+		})
+	}
+
+	if loan.LoanAgreementLetterURL != "" {
+		documents = append(documents, &PersonalDataDocumentResponse{
+			Type:     PersonalDataDocumentLoanAgreement,
+			LoanID:   loan.ID,
+			URL:      loan.LoanAgreementLetterURL,
+			SignedAt: loan.LoanAggrementSignedAt,
+		})
+	}
+
+	return
+}
+
+// ComposeInvestmentDocuments lists the documents stored for the investment.
+func ComposeInvestmentDocuments(investment *Investment) (documents []*PersonalDataDocumentResponse) {
+	if investment.InvestmentAgreementLetterURL != "" {
+		documents = append(documents, &PersonalDataDocumentResponse{
+			Type:         PersonalDataDocumentInvestmentAgreement,
+			LoanID:       investment.LoanID,
+			InvestmentID: investment.ID,
+			URL:          investment.InvestmentAgreementLetterURL,
+			SignedAt:     investment.InvestmentAggrementSignedAt,
+		})
+	}
+
+	return
+}
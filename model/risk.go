@@ -0,0 +1,208 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+type RiskGrade string
+
+const (
+	RiskGradeA RiskGrade = "A"
+	RiskGradeB RiskGrade = "B"
+	RiskGradeC RiskGrade = "C"
+	RiskGradeD RiskGrade = "D"
+	RiskGradeE RiskGrade = "E"
+)
+
+// data model
+type (
+	// Scorecard holds the points awarded per borrower attribute and the score cut-off of every grade.
+	Scorecard struct {
+		Version                 string               `json:"version"`
+		BaseScore               int                  `json:"base_score"`
+		AgeBands                []ScorecardBand      `json:"age_bands"`
+		Occupations             map[string]int       `json:"occupations"`
+		DefaultOccupationPoints int                  `json:"default_occupation_points"`
+		DisbursedLoanPoints     int                  `json:"disbursed_loan_points"`
+		MaxDisbursedLoanPoints  int                  `json:"max_disbursed_loan_points"`
+		ActiveLoanPoints        int                  `json:"active_loan_points"`
+		RejectedLoanPoints      int                  `json:"rejected_loan_points"`
+		CanceledLoanPoints      int                  `json:"canceled_loan_points"`
+		PrincipalRatioBands     []ScorecardBand      `json:"principal_ratio_bands"`
+		Grades                  []ScorecardGradeBand `json:"grades"`
+	}
+
+	// ScorecardBand awards points to values in [Min, Max).
+	ScorecardBand struct {
+		Min    float64 `json:"min"`
+		Max    float64 `json:"max"`
+		Points int     `json:"points"`
+	}
+
+	// ScorecardGradeBand maps scores of at least MinScore to a grade and its suggested interest rate band.
+	ScorecardGradeBand struct {
+		Grade           RiskGrade `json:"grade"`
+		MinScore        int       `json:"min_score"`
+		MinInterestRate float64   `json:"min_interest_rate"`
+		MaxInterestRate float64   `json:"max_interest_rate"`
+	}
+
+	BorrowerLoanStats struct {
+		DisbursedLoans int
+		ActiveLoans    int
+		RejectedLoans  int
+		CanceledLoans  int
+	}
+
+	RiskScoringInput struct {
+		Borrower        *Borrower
+		LoanStats       *BorrowerLoanStats
+		Product         *LoanProduct
+		PrincipalAmount float64
+	}
+
+	RiskAssessment struct {
+		Score                    int
+		Grade                    RiskGrade
+		Explanation              []RiskFactor
+		ScorecardVersion         string
+		SuggestedMinInterestRate float64
+		SuggestedMaxInterestRate float64
+		AssessedAt               *time.Time
+	}
+
+	RiskFactor struct {
+		Factor string `json:"factor"`
+		Value  string `json:"value"`
+		Points int    `json:"points"`
+	}
+)
+
+// DefaultScorecard is used when no scorecard file is configured.
+func DefaultScorecard() *Scorecard {
+	return &Scorecard{
+		Version:   "default-v1",
+		BaseScore: 500,
+		AgeBands: []ScorecardBand{
+			{Min: 0, Max: 21, Points: -50},
+			{Min: 21, Max: 25, Points: 0},
+			{Min: 25, Max: 45, Points: 40},
+			{Min: 45, Max: 56, Points: 20},
+			{Min: 56, Max: 200, Points: -20},
+		},
+		Occupations: map[string]int{
+			"pns":           60,
+			"karyawan":      40,
+			"wiraswasta":    30,
+			"pedagang":      20,
+			"petani":        10,
+			"civil servant": 60,
+			"employee":      40,
+			"entrepreneur":  30,
+			"merchant":      20,
+			"farmer":        10,
+		},
+		DefaultOccupationPoints: 0,
+		DisbursedLoanPoints:     40,
+		MaxDisbursedLoanPoints:  120,
+		ActiveLoanPoints:        -30,
+		RejectedLoanPoints:      -60,
+		CanceledLoanPoints:      -20,
+		PrincipalRatioBands: []ScorecardBand{
+			{Min: 0, Max: 25, Points: 40},
+			{Min: 25, Max: 50, Points: 20},
+			{Min: 50, Max: 75, Points: 0},
+			{Min: 75, Max: 101, Points: -40},
+		},
+		Grades: []ScorecardGradeBand{
+			{Grade: RiskGradeA, MinScore: 620, MinInterestRate: 10, MaxInterestRate: 13},
+			{Grade: RiskGradeB, MinScore: 560, MinInterestRate: 13, MaxInterestRate: 16},
+			{Grade: RiskGradeC, MinScore: 500, MinInterestRate: 16, MaxInterestRate: 19},
+			{Grade: RiskGradeD, MinScore: 440, MinInterestRate: 19, MaxInterestRate: 22},
+			{Grade: RiskGradeE, MinScore: 0, MinInterestRate: 22, MaxInterestRate: 26},
+		},
+	}
+}
+
+// IsValid checks that the scorecard can grade every score.
+func (sc *Scorecard) IsValid() bool {
+	if len(sc.Grades) == 0 {
+		return false
+	}
+
+	for _, grade := range sc.Grades {
+		if grade.MinInterestRate > grade.MaxInterestRate {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LoadScorecard reads a JSON scorecard file.
+func LoadScorecard(path string) (scorecard *Scorecard, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	scorecard = &Scorecard{}
+	err = json.Unmarshal(content, scorecard)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scorecard.IsValid() {
+		return nil, ErrorScorecardInvalid
+	}
+
+	return
+}
+
+// BandPoints returns the points of the band containing value.
+func BandPoints(bands []ScorecardBand, value float64) int {
+	for _, band := range bands {
+		if value >= band.Min && value < band.Max {
+			return band.Points
+		}
+	}
+
+	return 0
+}
+
+// request response
+type (
+	LoanRiskResponse struct {
+		Score                 int                   `json:"score"`
+		Grade                 string                `json:"grade"`
+		Explanation           []RiskFactor          `json:"explanation"`
+		ScorecardVersion      string                `json:"scorecard_version"`
+		SuggestedInterestRate SuggestedInterestRate `json:"suggested_interest_rate"`
+		AssessedAt            *time.Time            `json:"assessed_at,omitempty"`
+	}
+
+	SuggestedInterestRate struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+)
+
+func ComposeLoanRiskResponse(risk *RiskAssessment) *LoanRiskResponse {
+	if risk == nil || risk.Grade == "" {
+		return nil
+	}
+
+	return &LoanRiskResponse{
+		Score:            risk.Score,
+		Grade:            string(risk.Grade),
+		Explanation:      risk.Explanation,
+		ScorecardVersion: risk.ScorecardVersion,
+		SuggestedInterestRate: SuggestedInterestRate{
+			Min: risk.SuggestedMinInterestRate,
+			Max: risk.SuggestedMaxInterestRate,
+		},
+		AssessedAt: risk.AssessedAt,
+	}
+}
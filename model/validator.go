@@ -2,30 +2,93 @@ package model
 
 import (
 	"errors"
-	"fmt"
 	"reflect"
 	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
 )
 
-func IsValid(i interface{}) (bool, error) {
-	errStrs := []string{}
-	val := reflect.ValueOf(i)
+var validate, validationTranslators = newValidator()
 
+// newValidator sets up the request validator with the translations of its messages in every API language.
+func newValidator() (*validator.Validate, map[Language]ut.Translator) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	universalTranslator := ut.New(en.New(), en.New(), id.New())
+	enTranslator, _ := universalTranslator.GetTranslator("en")
+	idTranslator, _ := universalTranslator.GetTranslator("id")
+
+	err := en_translations.RegisterDefaultTranslations(validate, enTranslator)
+	if err != nil {
+		panic("register en validation translations: " + err.Error())
+	}
+
+	err = id_translations.RegisterDefaultTranslations(validate, idTranslator)
+	if err != nil {
+		panic("register id validation translations: " + err.Error())
+	}
+
+	return validate, map[Language]ut.Translator{
+		LanguageEnglish:    enTranslator,
+		LanguageIndonesian: idTranslator,
+	}
+}
+
+// IsValid validates the request struct, the error is model.ErrorValidationFailed reporting every invalid field.
+func IsValid(i interface{}) (bool, error) {
+	fields := []FieldError{}
+
 	err := validate.Struct(i)
 	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			field, _ := val.Type().FieldByName(err.Field())
-			errStr := fmt.Sprintf("%s: %s", val.Type().Field(field.Index[0]).Tag.Get("json"), err.Tag())
-			errStrs = append(errStrs, errStr)
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return false, err
+		}
+
+		for _, err := range validationErrs {
+			translations := make(map[Language]string, len(validationTranslators))
+			for language, translator := range validationTranslators {
+				translations[language] = err.Translate(translator)
+			}
+
+			fields = append(fields, FieldError{
+				Field:        fieldPath(err),
+				Rule:         err.Tag(),
+				Param:        err.Param(),
+				Translations: translations,
+			})
 		}
 	}
 
-	if len(errStrs) > 0 {
-		return false, errors.New(strings.Join(errStrs, ", "))
+	if len(fields) > 0 {
+		return false, ErrorValidationFailed.WithFields(fields...)
+	}
+
+	return true, nil
+}
+
+// jsonFieldName reports struct fields by their json name so nested errors read like the request body.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" || name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// fieldPath strips the root struct name from the validator namespace, e.g. "fees[0].type".
+func fieldPath(err validator.FieldError) string {
+	parts := strings.SplitN(err.Namespace(), ".", 2)
+	if len(parts) < 2 {
+		return err.Field()
 	}
 
-	return true, err
+	return parts[1]
 }
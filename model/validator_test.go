@@ -0,0 +1,125 @@
+package model_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/model"
+)
+
+var _ = Describe("IsValid", func() {
+	It("should accept a valid request", func() {
+		valid, err := model.IsValid(model.RejectErasureRequestRequest{Reason: "active loan"})
+
+		Expect(err).To(BeNil())
+		Expect(valid).To(BeTrue())
+	})
+
+	Context("with an invalid request", func() {
+		var fields []model.FieldError
+
+		BeforeEach(func() {
+			valid, err := model.IsValid(model.CreateLoanProductRequest{
+				Code:               "PRODUCTIVE-12",
+				Name:               "Productive",
+				MinPrincipalAmount: 10000000,
+				MaxPrincipalAmount: 5000000,
+				TenorMonths:        []int64{0},
+				MaxInterestRate:    0.12,
+				MaxROIRate:         0.08,
+				Fees:               []model.LoanProductFee{{Name: "admin", Type: "fixed", Amount: -1}},
+			})
+			Expect(valid).To(BeFalse())
+
+			var domainErr *model.DomainError
+			Expect(errors.As(err, &domainErr)).To(BeTrue())
+			Expect(domainErr).To(MatchError(model.ErrorValidationFailed))
+			fields = domainErr.Fields
+		})
+
+		It("should report every invalid field by its json path", func() {
+			paths := []string{}
+			for _, field := range fields {
+				paths = append(paths, field.Field+":"+field.Rule)
+			}
+
+			Expect(paths).To(Equal([]string{
+				"max_principal_amount:gtefield",
+				"tenor_months[0]:gt",
+				"fees[0].type:oneof",
+				"fees[0].amount:gte",
+			}))
+		})
+
+		DescribeTable("should translate the message of each field",
+			func(path string, english string, indonesian string) {
+				var field *model.FieldError
+				for i := range fields {
+					if fields[i].Field == path {
+						field = &fields[i]
+					}
+				}
+				Expect(field).NotTo(BeNil())
+
+				Expect(field.Translations).To(Equal(map[model.Language]string{
+					model.LanguageEnglish:    english,
+					model.LanguageIndonesian: indonesian,
+				}))
+			},
+			Entry("gtefield", "max_principal_amount",
+				"max_principal_amount must be greater than or equal to MinPrincipalAmount",
+				"max_principal_amount harus lebih besar dari atau sama dengan MinPrincipalAmount"),
+			Entry("gt", "tenor_months[0]",
+				"tenor_months[0] must be greater than 0",
+				"tenor_months[0] harus lebih besar dari 0"),
+			Entry("oneof", "fees[0].type",
+				"type must be one of [flat percentage]",
+				"type harus berupa salah satu dari [flat percentage]"),
+			Entry("gte", "fees[0].amount",
+				"amount must be 0 or greater",
+				"amount harus 0 atau lebih besar"),
+		)
+	})
+
+	It("should translate a required field to Indonesian", func() {
+		_, err := model.IsValid(model.RejectErasureRequestRequest{})
+
+		fields := err.(*model.DomainError).LocalizedFields(model.LanguageIndonesian)
+		Expect(fields).To(HaveLen(1))
+		Expect(fields[0].Field).To(Equal("reason"))
+		Expect(fields[0].Message).To(Equal("reason wajib diisi"))
+	})
+})
+
+var _ = Describe("DomainError", func() {
+	It("should fall back to English for a language without translations", func() {
+		err := model.ErrorValidationFailed.WithFields(model.FieldError{
+			Field:        "loan_id",
+			Rule:         "uuid",
+			Translations: map[model.Language]string{model.LanguageEnglish: "loan_id must be a valid UUID"},
+		})
+
+		Expect(err.LocalizedFields(model.LanguageIndonesian)[0].Message).To(Equal("loan_id must be a valid UUID"))
+	})
+
+	It("should localize the message of a domain error", func() {
+		Expect(model.ErrorLoanNotFound.LocalizedMessage(model.LanguageIndonesian)).To(Equal("pinjaman tidak ditemukan"))
+		Expect(model.ErrorLoanNotFound.LocalizedMessage(model.LanguageEnglish)).To(Equal("loan is not found"))
+	})
+
+	It("should translate an invalid field error", func() {
+		fields := model.InvalidFieldError("loan_id", "uuid").LocalizedFields(model.LanguageIndonesian)
+
+		Expect(fields).To(Equal([]model.FieldError{{
+			Field:   "loan_id",
+			Rule:    "uuid",
+			Message: "loan_id harus berupa UUID yang valid",
+			Translations: map[model.Language]string{
+				model.LanguageEnglish:    "loan_id must be a valid UUID",
+				model.LanguageIndonesian: "loan_id harus berupa UUID yang valid",
+			},
+		}}))
+	})
+})
@@ -0,0 +1,195 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const JobTypeDeliverWebhook JobType = "webhook.deliver"
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// webhook request headers
+const (
+	WebhookHeaderDeliveryID = "X-Webhook-ID"
+	WebhookHeaderEvent      = "X-Webhook-Event"
+	WebhookHeaderTimestamp  = "X-Webhook-Timestamp"
+	WebhookHeaderSignature  = "X-Webhook-Signature"
+)
+
+// data model
+type (
+	WebhookSubscription struct {
+		ID                  string
+		URL                 string
+		Description         string
+		EventTypes          []string
+		Secret              string
+		IsActive            bool
+		ConsecutiveFailures int
+		DisabledAt          *time.Time
+		DisabledReason      string
+		CreatedBy           string
+		CreatedAt           *time.Time
+		UpdatedAt           *time.Time
+	}
+
+	WebhookDelivery struct {
+		ID             string
+		SubscriptionID string
+		EventID        string
+		EventType      EventType
+		Payload        json.RawMessage
+		Status         WebhookDeliveryStatus
+		Attempts       int
+		LastStatusCode int
+		LastError      string
+		NextAttemptAt  *time.Time
+		DeliveredAt    *time.Time
+		CreatedAt      *time.Time
+		UpdatedAt      *time.Time
+	}
+
+	WebhookDeliveryAttempt struct {
+		ID          string
+		DeliveryID  string
+		Attempt     int
+		StatusCode  int
+		Error       string
+		DurationMS  int64
+		AttemptedAt *time.Time
+	}
+
+	DeliverWebhookPayload struct {
+		DeliveryID string `json:"delivery_id"`
+	}
+)
+
+// request response
+type (
+	CreateWebhookSubscriptionRequest struct {
+		URL         string   `json:"url" validate:"required,url"`
+		Description string   `json:"description"`
+		EventTypes  []string `json:"event_types" validate:"required,min=1,dive,required"`
+		// Secret is generated when empty
+		Secret string `json:"secret" validate:"omitempty,min=16"`
+	}
+
+	UpdateWebhookSubscriptionRequest struct {
+		SubscriptionID string
+		URL            string   `json:"url" validate:"required,url"`
+		Description    string   `json:"description"`
+		EventTypes     []string `json:"event_types" validate:"required,min=1,dive,required"`
+		IsActive       bool     `json:"is_active"`
+	}
+
+	GetWebhookSubscriptionRequest struct {
+		SubscriptionID string
+	}
+
+	DeleteWebhookSubscriptionRequest struct {
+		SubscriptionID string
+	}
+
+	ListWebhookDeliveriesRequest struct {
+		SubscriptionID string
+		Limit          int
+	}
+
+	GetWebhookDeliveryRequest struct {
+		SubscriptionID string
+		DeliveryID     string
+	}
+
+	RedeliverWebhookRequest struct {
+		SubscriptionID string
+		DeliveryID     string
+	}
+
+	WebhookSubscriptionResponse struct {
+		SubscriptionID      string     `json:"subscription_id"`
+		URL                 string     `json:"url"`
+		Description         string     `json:"description,omitempty"`
+		EventTypes          []string   `json:"event_types"`
+		Secret              string     `json:"secret,omitempty"`
+		IsActive            bool       `json:"is_active"`
+		ConsecutiveFailures int        `json:"consecutive_failures"`
+		DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+		DisabledReason      string     `json:"disabled_reason,omitempty"`
+		CreatedAt           *time.Time `json:"created_at,omitempty"`
+		UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+	}
+
+	WebhookDeliveryResponse struct {
+		DeliveryID     string                            `json:"delivery_id"`
+		SubscriptionID string                            `json:"subscription_id"`
+		EventID        string                            `json:"event_id"`
+		EventType      string                            `json:"event_type"`
+		Status         string                            `json:"status"`
+		Attempts       int                               `json:"attempts"`
+		LastStatusCode int                               `json:"last_status_code,omitempty"`
+		LastError      string                            `json:"last_error,omitempty"`
+		NextAttemptAt  *time.Time                        `json:"next_attempt_at,omitempty"`
+		DeliveredAt    *time.Time                        `json:"delivered_at,omitempty"`
+		CreatedAt      *time.Time                        `json:"created_at,omitempty"`
+		AttemptLog     []*WebhookDeliveryAttemptResponse `json:"attempt_log,omitempty"`
+	}
+
+	WebhookDeliveryAttemptResponse struct {
+		Attempt     int        `json:"attempt"`
+		StatusCode  int        `json:"status_code,omitempty"`
+		Error       string     `json:"error,omitempty"`
+		DurationMS  int64      `json:"duration_ms"`
+		AttemptedAt *time.Time `json:"attempted_at,omitempty"`
+	}
+)
+
+// ComposeWebhookSubscriptionResponse never includes the secret, it is only returned once on creation.
+func ComposeWebhookSubscriptionResponse(subscription *WebhookSubscription) *WebhookSubscriptionResponse {
+	return &WebhookSubscriptionResponse{
+		SubscriptionID:      subscription.ID,
+		URL:                 subscription.URL,
+		Description:         subscription.Description,
+		EventTypes:          subscription.EventTypes,
+		IsActive:            subscription.IsActive,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		DisabledAt:          subscription.DisabledAt,
+		DisabledReason:      subscription.DisabledReason,
+		CreatedAt:           subscription.CreatedAt,
+		UpdatedAt:           subscription.UpdatedAt,
+	}
+}
+
+func ComposeWebhookDeliveryResponse(delivery *WebhookDelivery, attempts []*WebhookDeliveryAttempt) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	for _, attempt := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, &WebhookDeliveryAttemptResponse{
+			Attempt:     attempt.Attempt,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMS:  attempt.DurationMS,
+			AttemptedAt: attempt.AttemptedAt,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+type IAuditRepository interface {
+	CreateAuditLog(ctx context.Context, auditLog *model.AuditLog) (err error)
+	ListAuditLogs(ctx context.Context, filter *model.AuditLogFilter) (auditLogs []*model.AuditLog, err error)
+	ListAuditLogsAfter(ctx context.Context, afterID int64, limit int) (auditLogs []*model.AuditLog, err error)
+}
+
+type AuditRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(app *application.App) IAuditRepository {
+	return &AuditRepository{
+		DB: app.DB,
+	}
+}
+
+const auditLogColumns = `
+			id,
+			entity_type,
+			entity_id,
+			action,
+			COALESCE(actor_id, ''),
+			COALESCE(source_ip, ''),
+			COALESCE(request_id, ''),
+			changes,
+			created_at,
+			prev_hash,
+			hash
+`
+
+func scanAuditLog(scanner interface{ Scan(dest ...any) error }) (auditLog *model.AuditLog, err error) {
+	var changes []byte
+
+	auditLog = &model.AuditLog{}
+	err = scanner.Scan(
+		&auditLog.ID,
+		&auditLog.EntityType,
+		&auditLog.EntityID,
+		&auditLog.Action,
+		&auditLog.ActorID,
+		&auditLog.SourceIP,
+		&auditLog.RequestID,
+		&changes,
+		&auditLog.CreatedAt,
+		&auditLog.PrevHash,
+		&auditLog.Hash,
+	)
+	if err != nil {
+		return
+	}
+
+	auditLog.Changes = changes
+
+	return
+}
+
+// CreateAuditLog appends the entry to the chain, call it with the transaction of the change it records. The
+// chain is locked until the transaction ends so concurrent changes are appended one after another.
+func (ar *AuditRepository) CreateAuditLog(ctx context.Context, auditLog *model.AuditLog) (err error) {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); !ok {
+		err = errors.New("audit log must be written in the transaction of the change")
+		slog.ErrorContext(ctx, "CreateAuditLog", "error", err)
+		return
+	}
+
+	_, err = executor(ctx, ar.DB).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_logs'))`)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateAuditLog lock error", "error", err)
+		return
+	}
+
+	query := `
+		SELECT
+			hash
+		FROM
+			audit_logs
+		ORDER BY
+			id DESC
+		LIMIT 1
+	`
+
+	err = executor(ctx, ar.DB).QueryRowContext(ctx, query).Scan(&auditLog.PrevHash)
+	if err == sql.ErrNoRows {
+		auditLog.PrevHash = model.AuditGenesisHash
+		err = nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateAuditLog last hash error", "error", err)
+		return
+	}
+
+	auditLog.Hash = auditLog.ComputeHash()
+
+	query = `
+		INSERT INTO
+			audit_logs (
+				entity_type,
+				entity_id,
+				action,
+				actor_id,
+				source_ip,
+				request_id,
+				changes,
+				created_at,
+				prev_hash,
+				hash
+			)
+		VALUES
+			($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10)
+		RETURNING
+			id
+	`
+
+	err = executor(ctx, ar.DB).QueryRowContext(ctx, query,
+		auditLog.EntityType,
+		auditLog.EntityID,
+		auditLog.Action,
+		auditLog.ActorID,
+		auditLog.SourceIP,
+		auditLog.RequestID,
+		[]byte(auditLog.Changes),
+		auditLog.CreatedAt,
+		auditLog.PrevHash,
+		auditLog.Hash,
+	).Scan(&auditLog.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateAuditLog error", "error", err)
+		return
+	}
+
+	return
+}
+
+// ListAuditLogs returns the entries matching the filter, latest first.
+func (ar *AuditRepository) ListAuditLogs(ctx context.Context, filter *model.AuditLogFilter) (auditLogs []*model.AuditLog, err error) {
+	conditions := []string{}
+	args := []any{}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT`+auditLogColumns+`
+		FROM
+			audit_logs
+		%s
+		ORDER BY
+			id DESC
+		LIMIT $%d
+	`, where, len(args))
+
+	return ar.queryAuditLogs(ctx, "ListAuditLogs", query, args...)
+}
+
+// ListAuditLogsAfter returns the entries after afterID in chain order, for verifying the chain in batches.
+func (ar *AuditRepository) ListAuditLogsAfter(ctx context.Context, afterID int64, limit int) (auditLogs []*model.AuditLog, err error) {
+	query := `
+		SELECT` + auditLogColumns + `
+		FROM
+			audit_logs
+		WHERE
+			id > $1
+		ORDER BY
+			id
+		LIMIT $2
+	`
+
+	return ar.queryAuditLogs(ctx, "ListAuditLogsAfter", query, afterID, limit)
+}
+
+func (ar *AuditRepository) queryAuditLogs(ctx context.Context, method string, query string, args ...any) (auditLogs []*model.AuditLog, err error) {
+	auditLogs = []*model.AuditLog{}
+	rows, err := executor(ctx, ar.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, method+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var auditLog *model.AuditLog
+		auditLog, err = scanAuditLog(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, method+" Scan error", "error", err)
+			return
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, method+" rows error", "error", err)
+		return
+	}
+
+	return
+}
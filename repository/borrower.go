@@ -3,58 +3,289 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
 )
 
 type IBorrowerRepository interface {
+	CreateBorrower(ctx context.Context, borrower *model.Borrower) (ID string, err error)
 	GetBorrowerByID(ctx context.Context, id string) (borrower *model.Borrower, err error)
+	GetBorrowerByNIK(ctx context.Context, nik string) (borrower *model.Borrower, err error)
+	ListBorrowersToReencrypt(ctx context.Context, afterID string, limit int) (borrowers []*model.Borrower, err error)
+	ReencryptBorrower(ctx context.Context, borrower *model.Borrower) (err error)
+	EraseBorrower(ctx context.Context, id string, erasedAt time.Time) (err error)
 }
 
+// BorrowerRepository stores the NIK and email encrypted with FieldCipher, the NIK is looked up and kept
+// unique by its blind index.
 type BorrowerRepository struct {
-	DB *sql.DB
+	DB          *sql.DB
+	FieldCipher *application.FieldCipher
 }
 
 func NewBorrowerRepository(app *application.App) IBorrowerRepository {
 	return &BorrowerRepository{
-		DB: app.DB,
+		DB:          app.DB,
+		FieldCipher: app.FieldCipher,
 	}
 }
 
-func (acr *BorrowerRepository) GetBorrowerByID(ctx context.Context, id string) (borrower *model.Borrower, err error) {
-	query := `
-		SELECT
+const borrowerColumns = `
 			id,
-			name,
-			address,
-			occupation,
-			nik,
-			dob
-		FROM
-			borrowers
-		WHERE
-			id = $1
-	`
+			COALESCE(name, ''),
+			COALESCE(address, ''),
+			COALESCE(occupation, ''),
+			COALESCE(nik, ''),
+			dob,
+			COALESCE(email, ''),
+			erased_at,
+			COALESCE(encryption_key_id, '')
+`
+
+func (acr *BorrowerRepository) scanBorrower(scanner interface{ Scan(dest ...any) error }) (borrower *model.Borrower, err error) {
+	var keyID string
 
 	borrower = &model.Borrower{}
-	err = acr.DB.QueryRowContext(ctx, query, id).Scan(
+	err = scanner.Scan(
 		&borrower.ID,
 		&borrower.Name,
 		&borrower.Address,
 		&borrower.Occupation,
 		&borrower.NIK,
 		&borrower.DOB,
+		&borrower.Email,
+		&borrower.ErasedAt,
+		&keyID,
 	)
+	if err != nil {
+		return
+	}
+
+	err = decryptColumns(acr.FieldCipher, keyID, map[string]*string{
+		columnNIK:   &borrower.NIK,
+		columnEmail: &borrower.Email,
+	})
+
+	return
+}
+
+// CreateBorrower stores the borrower under its id, or a generated one when the id is empty.
+func (acr *BorrowerRepository) CreateBorrower(ctx context.Context, borrower *model.Borrower) (ID string, err error) {
+	ciphertexts, err := encryptColumns(acr.FieldCipher, map[string]string{
+		columnNIK:   borrower.NIK,
+		columnEmail: borrower.Email,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateBorrower encrypt error", "error", err)
+		return
+	}
+
+	query := `
+		INSERT INTO
+			borrowers (
+				id,
+				name,
+				address,
+				occupation,
+				nik,
+				nik_hash,
+				dob,
+				email,
+				encryption_key_id
+			)
+		VALUES
+			(COALESCE(NULLIF($1, '')::uuid, uuid_generate_v4()), $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING
+			id
+	`
+
+	err = executor(ctx, acr.DB).QueryRowContext(ctx, query,
+		borrower.ID,
+		borrower.Name,
+		borrower.Address,
+		borrower.Occupation,
+		ciphertexts[columnNIK],
+		acr.FieldCipher.BlindIndex(columnNIK, borrower.NIK),
+		borrower.DOB,
+		ciphertexts[columnEmail],
+		acr.FieldCipher.ActiveKeyID(),
+	).Scan(&ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.ErrorContext(ctx, "CreateBorrower", "error", err)
+			err = model.ErrorBorrowerExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateBorrower error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (acr *BorrowerRepository) GetBorrowerByID(ctx context.Context, id string) (borrower *model.Borrower, err error) {
+	query := `
+		SELECT` + borrowerColumns + `
+		FROM
+			borrowers
+		WHERE
+			id = $1
+	`
+
+	return acr.getBorrower(ctx, "GetBorrowerByID", query, id)
+}
+
+// GetBorrowerByNIK finds the borrower by the blind index of the NIK.
+func (acr *BorrowerRepository) GetBorrowerByNIK(ctx context.Context, nik string) (borrower *model.Borrower, err error) {
+	query := `
+		SELECT` + borrowerColumns + `
+		FROM
+			borrowers
+		WHERE
+			nik_hash = $1
+	`
+
+	return acr.getBorrower(ctx, "GetBorrowerByNIK", query, acr.FieldCipher.BlindIndex(columnNIK, nik))
+}
+
+func (acr *BorrowerRepository) getBorrower(ctx context.Context, method string, query string, arg any) (borrower *model.Borrower, err error) {
+	borrower, err = acr.scanBorrower(executor(ctx, acr.DB).QueryRowContext(ctx, query, arg))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("GetBorrowerByID ", err)
+			slog.DebugContext(ctx, method, "error", err)
 			err = model.ErrorBorrowerNotFound
 			return
 		}
 
-		log.Println("GetBorrowerByID ", err)
+		slog.ErrorContext(ctx, method, "error", err)
+		return
+	}
+
+	return
+}
+
+// ListBorrowersToReencrypt returns the borrowers after afterID, in id order, that are not encrypted with the
+// active key yet. Erased borrowers have nothing to encrypt.
+func (acr *BorrowerRepository) ListBorrowersToReencrypt(ctx context.Context, afterID string, limit int) (borrowers []*model.Borrower, err error) {
+	query := `
+		SELECT` + borrowerColumns + `
+		FROM
+			borrowers
+		WHERE
+			encryption_key_id IS DISTINCT FROM $1
+			AND erased_at IS NULL
+			AND id > $2
+		ORDER BY
+			id
+		LIMIT $3
+	`
+
+	borrowers = []*model.Borrower{}
+	rows, err := executor(ctx, acr.DB).QueryContext(ctx, query, acr.FieldCipher.ActiveKeyID(), afterID, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListBorrowersToReencrypt QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var borrower *model.Borrower
+		borrower, err = acr.scanBorrower(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListBorrowersToReencrypt Scan error", "error", err)
+			return
+		}
+		borrowers = append(borrowers, borrower)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListBorrowersToReencrypt rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// ReencryptBorrower encrypts the personal data of the borrower with the active key and refreshes its blind
+// index.
+func (acr *BorrowerRepository) ReencryptBorrower(ctx context.Context, borrower *model.Borrower) (err error) {
+	ciphertexts, err := encryptColumns(acr.FieldCipher, map[string]string{
+		columnNIK:   borrower.NIK,
+		columnEmail: borrower.Email,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "ReencryptBorrower encrypt error", "error", err)
+		return
+	}
+
+	query := `
+		UPDATE
+			borrowers
+		SET
+			nik = $2,
+			nik_hash = $3,
+			email = NULLIF($4, ''),
+			encryption_key_id = $5
+		WHERE
+			id = $1
+	`
+
+	_, err = executor(ctx, acr.DB).ExecContext(ctx, query,
+		borrower.ID,
+		ciphertexts[columnNIK],
+		acr.FieldCipher.BlindIndex(columnNIK, borrower.NIK),
+		ciphertexts[columnEmail],
+		acr.FieldCipher.ActiveKeyID(),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "ReencryptBorrower error", "error", err)
+		return
+	}
+
+	return
+}
+
+// EraseBorrower clears the personal data of the borrower and its NIK index, the row stays for the loans of
+// the borrower. An erased borrower cannot be erased again.
+func (acr *BorrowerRepository) EraseBorrower(ctx context.Context, id string, erasedAt time.Time) (err error) {
+	query := `
+		UPDATE
+			borrowers
+		SET
+			name = NULL,
+			address = NULL,
+			occupation = NULL,
+			nik = NULL,
+			nik_hash = NULL,
+			dob = NULL,
+			email = NULL,
+			encryption_key_id = NULL,
+			erased_at = $2
+		WHERE
+			id = $1
+			AND erased_at IS NULL
+	`
+
+	rows, err := executor(ctx, acr.DB).ExecContext(ctx, query, id, erasedAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "EraseBorrower ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "EraseBorrower RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorPersonalDataErased
+		slog.WarnContext(ctx, "EraseBorrower affected < 1 error", "error", err)
 		return
 	}
 
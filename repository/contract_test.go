@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/repository"
+	"github.com/frencius/loan-service/repository/repositorytest"
+)
+
+var _ = repositorytest.DescribeRepositories("postgres", func() (*repositorytest.Repositories, func()) {
+	if testDB == nil {
+		Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	truncate := func() {
+		_, err := testDB.ExecContext(ctx, `TRUNCATE borrowers, investors, loans, investments CASCADE`)
+		Expect(err).To(BeNil())
+	}
+	truncate()
+
+	testKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	nextKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("n", 32)))
+	blindIndexKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", 32)))
+
+	fieldCipher, err := application.NewFieldCipher([]byte(`{
+		"active_key_id": "test",
+		"keys": {"test": "` + testKey + `"},
+		"blind_index_key": "` + blindIndexKey + `"
+	}`))
+	Expect(err).To(BeNil())
+
+	// the keyfile after a rotation, the old key still decrypts the rows written before it
+	rotatedFieldCipher, err := application.NewFieldCipher([]byte(`{
+		"active_key_id": "next",
+		"keys": {"test": "` + testKey + `", "next": "` + nextKey + `"},
+		"blind_index_key": "` + blindIndexKey + `"
+	}`))
+	Expect(err).To(BeNil())
+
+	app := &application.App{
+		DB:          testDB,
+		FieldCipher: fieldCipher,
+	}
+
+	// the default catalogue of the loan products migration
+	products, err := repository.NewLoanProductRepository(app).ListLoanProducts(ctx, true)
+	Expect(err).To(BeNil())
+	Expect(products).NotTo(BeEmpty())
+
+	borrowerRepository := repository.NewBorrowerRepository(app).(*repository.BorrowerRepository)
+	investorRepository := repository.NewInvestorRepository(app).(*repository.InvestorRepository)
+
+	return &repositorytest.Repositories{
+		LoanRepository:       repository.NewLoanRepository(app),
+		BorrowerRepository:   borrowerRepository,
+		InvestorRepository:   investorRepository,
+		InvestmentRepository: repository.NewInvestmentRepository(app),
+		ProductID:            products[0].ID,
+		RotateKey: func() {
+			borrowerRepository.FieldCipher = rotatedFieldCipher
+			investorRepository.FieldCipher = rotatedFieldCipher
+		},
+	}, truncate
+})
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
+)
+
+const pqUniqueViolation = "23505"
+
+type IEmployeeRepository interface {
+	CreateEmployee(ctx context.Context, employee *model.Employee) (ID string, err error)
+	GetEmployeeByID(ctx context.Context, id string) (employee *model.Employee, err error)
+	GetEmployeesByIDs(ctx context.Context, ids []string) (employees []*model.Employee, err error)
+	ListEmployees(ctx context.Context, activeOnly bool) (employees []*model.Employee, err error)
+	UpdateEmployee(ctx context.Context, employee *model.Employee) (err error)
+	DeactivateEmployee(ctx context.Context, id string, deactivatedBy string) (err error)
+}
+
+type EmployeeRepository struct {
+	DB *sql.DB
+}
+
+func NewEmployeeRepository(app *application.App) IEmployeeRepository {
+	return &EmployeeRepository{
+		DB: app.DB,
+	}
+}
+
+const employeeColumns = `
+			id,
+			name,
+			employee_number,
+			is_active,
+			deactivated_at,
+			COALESCE(deactivated_by, '00000000-0000-0000-0000-000000000000'),
+			created_at,
+			updated_at
+`
+
+func scanEmployee(scanner interface{ Scan(dest ...any) error }) (employee *model.Employee, err error) {
+	employee = &model.Employee{}
+	err = scanner.Scan(
+		&employee.ID,
+		&employee.Name,
+		&employee.EmployeeNumber,
+		&employee.IsActive,
+		&employee.DeactivatedAt,
+		&employee.DeactivatedBy,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+	)
+
+	return
+}
+
+func (er *EmployeeRepository) CreateEmployee(ctx context.Context, employee *model.Employee) (ID string, err error) {
+	query := `
+		INSERT INTO
+			employees (
+				name,
+				employee_number
+			)
+		VALUES
+			($1, $2)
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, er.DB).QueryRowContext(ctx, query,
+		employee.Name,
+		employee.EmployeeNumber,
+	).Scan(&ID)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.ErrorContext(ctx, "CreateEmployee", "error", err)
+			err = model.ErrorEmployeeNumberExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateEmployee error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (er *EmployeeRepository) GetEmployeeByID(ctx context.Context, id string) (employee *model.Employee, err error) {
+	query := `
+		SELECT` + employeeColumns + `
+		FROM
+			employees
+		WHERE
+			id = $1
+	`
+
+	employee, err = scanEmployee(executor(ctx, er.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		employee = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetEmployeeByID", "error", err)
+			err = model.ErrorEmployeeNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetEmployeeByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (er *EmployeeRepository) GetEmployeesByIDs(ctx context.Context, ids []string) (employees []*model.Employee, err error) {
+	employees = []*model.Employee{}
+	if len(ids) == 0 {
+		return
+	}
+
+	query := `
+		SELECT` + employeeColumns + `
+		FROM
+			employees
+		WHERE
+			id = ANY($1)
+	`
+
+	return er.queryEmployees(ctx, "GetEmployeesByIDs", query, pq.Array(ids))
+}
+
+func (er *EmployeeRepository) ListEmployees(ctx context.Context, activeOnly bool) (employees []*model.Employee, err error) {
+	query := `
+		SELECT` + employeeColumns + `
+		FROM
+			employees
+		WHERE
+			($1 = false OR is_active = true)
+		ORDER BY
+			name
+	`
+
+	return er.queryEmployees(ctx, "ListEmployees", query, activeOnly)
+}
+
+func (er *EmployeeRepository) queryEmployees(ctx context.Context, caller string, query string, args ...any) (employees []*model.Employee, err error) {
+	employees = []*model.Employee{}
+
+	rows, err := executor(ctx, er.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, caller+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var employee *model.Employee
+		employee, err = scanEmployee(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, caller+" Scan error", "error", err)
+			return
+		}
+		employees = append(employees, employee)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, caller+" rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (er *EmployeeRepository) UpdateEmployee(ctx context.Context, employee *model.Employee) (err error) {
+	query := `
+		UPDATE
+			employees
+		SET
+			name = $2
+		WHERE
+			id = $1
+	`
+	rows, err := executor(ctx, er.DB).ExecContext(ctx, query, employee.ID, employee.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateEmployee ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateEmployee RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorEmployeeNotFound
+		slog.WarnContext(ctx, "UpdateEmployee affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (er *EmployeeRepository) DeactivateEmployee(ctx context.Context, id string, deactivatedBy string) (err error) {
+	query := `
+		UPDATE
+			employees
+		SET
+			is_active = false,
+			deactivated_at = NOW(),
+			deactivated_by = $2
+		WHERE
+			id = $1
+	`
+	rows, err := executor(ctx, er.DB).ExecContext(ctx, query, id, deactivatedBy)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeactivateEmployee ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "DeactivateEmployee RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorEmployeeNotFound
+		slog.WarnContext(ctx, "DeactivateEmployee affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/frencius/loan-service/application"
+)
+
+// personal data columns, also the field authenticated with their ciphertext and hashed into their blind index
+const (
+	columnNIK         = "nik"
+	columnNPWP        = "npwp"
+	columnEmail       = "email"
+	columnPhoneNumber = "phone_number"
+)
+
+// decryptColumns decrypts the columns of a row encrypted with keyID in place. Rows without a key id were
+// stored before encryption and still hold plaintext until the re-encryption job gets to them.
+func decryptColumns(fieldCipher *application.FieldCipher, keyID string, columns map[string]*string) (err error) {
+	if keyID == "" {
+		return
+	}
+
+	for column, value := range columns {
+		*value, err = fieldCipher.Decrypt(column, *value)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", column, err)
+		}
+	}
+
+	return
+}
+
+// encryptColumns encrypts the values with the active key, keyed like the columns.
+func encryptColumns(fieldCipher *application.FieldCipher, columns map[string]string) (ciphertexts map[string]string, err error) {
+	ciphertexts = make(map[string]string, len(columns))
+	for column, value := range columns {
+		ciphertexts[column], err = fieldCipher.Encrypt(column, value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt %s: %w", column, err)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+type IFeatureFlagRepository interface {
+	GetFeatureFlagByKey(ctx context.Context, key string) (featureFlag *model.FeatureFlag, err error)
+	ListFeatureFlags(ctx context.Context) (featureFlags []*model.FeatureFlag, err error)
+	UpsertFeatureFlag(ctx context.Context, featureFlag *model.FeatureFlag) (err error)
+}
+
+type FeatureFlagRepository struct {
+	DB *sql.DB
+}
+
+func NewFeatureFlagRepository(app *application.App) IFeatureFlagRepository {
+	return &FeatureFlagRepository{
+		DB: app.DB,
+	}
+}
+
+const featureFlagColumns = `
+			key,
+			COALESCE(description, ''),
+			enabled,
+			rollout_percentage,
+			rules,
+			COALESCE(updated_by::text, ''),
+			created_at,
+			updated_at
+`
+
+func scanFeatureFlag(scanner interface{ Scan(dest ...any) error }) (featureFlag *model.FeatureFlag, err error) {
+	var rules []byte
+
+	featureFlag = &model.FeatureFlag{}
+	err = scanner.Scan(
+		&featureFlag.Key,
+		&featureFlag.Description,
+		&featureFlag.Enabled,
+		&featureFlag.RolloutPercentage,
+		&rules,
+		&featureFlag.UpdatedBy,
+		&featureFlag.CreatedAt,
+		&featureFlag.UpdatedAt,
+	)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(rules, &featureFlag.Rules)
+
+	return
+}
+
+func (ffr *FeatureFlagRepository) GetFeatureFlagByKey(ctx context.Context, key string) (featureFlag *model.FeatureFlag, err error) {
+	query := `
+		SELECT` + featureFlagColumns + `
+		FROM
+			feature_flags
+		WHERE
+			key = $1
+	`
+
+	featureFlag, err = scanFeatureFlag(executor(ctx, ffr.DB).QueryRowContext(ctx, query, key))
+	if err != nil {
+		featureFlag = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetFeatureFlagByKey", "error", err)
+			err = model.ErrorFeatureFlagNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetFeatureFlagByKey", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ffr *FeatureFlagRepository) ListFeatureFlags(ctx context.Context) (featureFlags []*model.FeatureFlag, err error) {
+	query := `
+		SELECT` + featureFlagColumns + `
+		FROM
+			feature_flags
+		ORDER BY
+			key
+	`
+
+	featureFlags = []*model.FeatureFlag{}
+	rows, err := executor(ctx, ffr.DB).QueryContext(ctx, query)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListFeatureFlags QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var featureFlag *model.FeatureFlag
+		featureFlag, err = scanFeatureFlag(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListFeatureFlags Scan error", "error", err)
+			return
+		}
+		featureFlags = append(featureFlags, featureFlag)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListFeatureFlags rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// UpsertFeatureFlag creates the flag or replaces its settings, the created_at of an existing flag is kept.
+func (ffr *FeatureFlagRepository) UpsertFeatureFlag(ctx context.Context, featureFlag *model.FeatureFlag) (err error) {
+	query := `
+		INSERT INTO
+			feature_flags (
+				key,
+				description,
+				enabled,
+				rollout_percentage,
+				rules,
+				updated_by
+			)
+		VALUES
+			($1, $2, $3, $4, $5, NULLIF($6, '')::uuid)
+		ON CONFLICT (key) DO UPDATE SET
+			description = EXCLUDED.description,
+			enabled = EXCLUDED.enabled,
+			rollout_percentage = EXCLUDED.rollout_percentage,
+			rules = EXCLUDED.rules,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			created_at,
+			updated_at
+	`
+
+	rules, err := json.Marshal(featureFlag.Rules)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpsertFeatureFlag Marshal error", "error", err)
+		return
+	}
+
+	err = executor(ctx, ffr.DB).QueryRowContext(ctx, query,
+		featureFlag.Key,
+		featureFlag.Description,
+		featureFlag.Enabled,
+		featureFlag.RolloutPercentage,
+		rules,
+		featureFlag.UpdatedBy,
+	).Scan(&featureFlag.CreatedAt, &featureFlag.UpdatedAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpsertFeatureFlag error", "error", err)
+		return
+	}
+
+	return
+}
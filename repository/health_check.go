@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
 )
 
 type IHealthCheckRepository interface {
-	Ping() (ping *model.Ping, err error)
+	PingDatabase(ctx context.Context) (err error)
+	GetMigrationState(ctx context.Context) (migrationState *model.MigrationState, err error)
+	GetJobQueueLag(ctx context.Context) (lag time.Duration, err error)
 }
 
 type HealthCheckRepository struct {
@@ -22,15 +26,56 @@ func NewHealthCheckRepository(app *application.App) IHealthCheckRepository {
 	}
 }
 
-func (hcr *HealthCheckRepository) Ping() (ping *model.Ping, err error) {
-	if err = hcr.DB.Ping(); err != nil {
-		log.Println("Ping DB ERROR", err)
+func (hcr *HealthCheckRepository) PingDatabase(ctx context.Context) (err error) {
+	if err = hcr.DB.PingContext(ctx); err != nil {
+		slog.ErrorContext(ctx, "PingDatabase error", "error", err)
 		return
 	}
 
-	ping = &model.Ping{}
-	ping.DatabaseStatus = "OK"
-	ping.ServiceStatus = "OK"
+	return
+}
+
+// GetMigrationState reads the schema_migrations table maintained by golang-migrate.
+func (hcr *HealthCheckRepository) GetMigrationState(ctx context.Context) (migrationState *model.MigrationState, err error) {
+	query := `
+		SELECT
+			version,
+			dirty
+		FROM
+			schema_migrations
+		LIMIT 1
+		`
+
+	migrationState = &model.MigrationState{}
+	err = executor(ctx, hcr.DB).QueryRowContext(ctx, query).Scan(&migrationState.Version, &migrationState.Dirty)
+	if err != nil {
+		slog.ErrorContext(ctx, "GetMigrationState error", "error", err)
+		return nil, err
+	}
+
+	return
+}
+
+// GetJobQueueLag returns how long the oldest due job has been waiting, zero when the queue is drained.
+func (hcr *HealthCheckRepository) GetJobQueueLag(ctx context.Context) (lag time.Duration, err error) {
+	query := `
+		SELECT
+			COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(run_at)), 0)
+		FROM
+			jobs
+		WHERE
+			status = 'pending'
+			AND run_at <= NOW()
+		`
+
+	var lagSeconds float64
+	err = executor(ctx, hcr.DB).QueryRowContext(ctx, query).Scan(&lagSeconds)
+	if err != nil {
+		slog.ErrorContext(ctx, "GetJobQueueLag error", "error", err)
+		return
+	}
+
+	lag = time.Duration(lagSeconds * float64(time.Second))
 
 	return
 }
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+type IIdempotencyRepository interface {
+	CreateIdempotencyKey(ctx context.Context, idempotencyKey *model.IdempotencyKey, now time.Time, leaseExpiredAt time.Time) (created bool, err error)
+	GetIdempotencyKey(ctx context.Context, userID string, key string) (idempotencyKey *model.IdempotencyKey, err error)
+	CompleteIdempotencyKey(ctx context.Context, userID string, key string, responseCode int, responseHeaders map[string]string, responseBody []byte) (err error)
+	DeleteIdempotencyKey(ctx context.Context, userID string, key string) (err error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (deleted int64, err error)
+}
+
+type IdempotencyRepository struct {
+	DB *sql.DB
+}
+
+func NewIdempotencyRepository(app *application.App) IIdempotencyRepository {
+	return &IdempotencyRepository{
+		DB: app.DB,
+	}
+}
+
+// CreateIdempotencyKey claims the key for a new request. An expired key is taken over, so is a key still
+// processing since leaseExpiredAt or earlier, its request died without completing. created is false when
+// the key is still held by an earlier request.
+func (ir *IdempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey *model.IdempotencyKey, now time.Time, leaseExpiredAt time.Time) (created bool, err error) {
+	query := `
+		INSERT INTO
+			idempotency_keys (
+				user_id,
+				idempotency_key,
+				method,
+				path,
+				request_hash,
+				created_at,
+				expires_at
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE SET
+			method = EXCLUDED.method,
+			path = EXCLUDED.path,
+			request_hash = EXCLUDED.request_hash,
+			status = 'processing',
+			response_code = NULL,
+			response_headers = '{}',
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at,
+			completed_at = NULL
+		WHERE
+			idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.status = 'processing' AND idempotency_keys.created_at <= $8)
+		RETURNING
+			idempotency_key
+		`
+
+	var key string
+	err = executor(ctx, ir.DB).QueryRowContext(ctx, query,
+		idempotencyKey.UserID,
+		idempotencyKey.Key,
+		idempotencyKey.Method,
+		idempotencyKey.Path,
+		idempotencyKey.RequestHash,
+		now,
+		idempotencyKey.ExpiresAt,
+		leaseExpiredAt,
+	).Scan(&key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateIdempotencyKey error", "error", err)
+		return
+	}
+
+	created = true
+
+	return
+}
+
+func (ir *IdempotencyRepository) GetIdempotencyKey(ctx context.Context, userID string, key string) (idempotencyKey *model.IdempotencyKey, err error) {
+	query := `
+		SELECT
+			user_id,
+			idempotency_key,
+			method,
+			path,
+			request_hash,
+			status,
+			COALESCE(response_code, 0),
+			response_headers,
+			response_body,
+			created_at,
+			expires_at,
+			completed_at
+		FROM
+			idempotency_keys
+		WHERE
+			user_id = $1
+			AND idempotency_key = $2
+	`
+
+	var responseHeaders []byte
+
+	idempotencyKey = &model.IdempotencyKey{}
+	err = executor(ctx, ir.DB).QueryRowContext(ctx, query, userID, key).Scan(
+		&idempotencyKey.UserID,
+		&idempotencyKey.Key,
+		&idempotencyKey.Method,
+		&idempotencyKey.Path,
+		&idempotencyKey.RequestHash,
+		&idempotencyKey.Status,
+		&idempotencyKey.ResponseCode,
+		&responseHeaders,
+		&idempotencyKey.ResponseBody,
+		&idempotencyKey.CreatedAt,
+		&idempotencyKey.ExpiresAt,
+		&idempotencyKey.CompletedAt,
+	)
+	if err != nil {
+		idempotencyKey = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetIdempotencyKey", "error", err)
+			err = model.ErrorIdempotencyKeyNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetIdempotencyKey", "error", err)
+		return
+	}
+
+	err = json.Unmarshal(responseHeaders, &idempotencyKey.ResponseHeaders)
+	if err != nil {
+		idempotencyKey = nil
+		slog.ErrorContext(ctx, "GetIdempotencyKey Unmarshal error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ir *IdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, userID string, key string, responseCode int, responseHeaders map[string]string, responseBody []byte) (err error) {
+	query := `
+		UPDATE
+			idempotency_keys
+		SET
+			status = 'completed',
+			response_code = $3,
+			response_headers = $4,
+			response_body = $5,
+			completed_at = NOW()
+		WHERE
+			user_id = $1
+			AND idempotency_key = $2
+	`
+
+	headers, err := json.Marshal(responseHeaders)
+	if err != nil {
+		slog.ErrorContext(ctx, "CompleteIdempotencyKey Marshal error", "error", err)
+		return
+	}
+
+	rows, err := executor(ctx, ir.DB).ExecContext(ctx, query, userID, key, responseCode, headers, responseBody)
+	if err != nil {
+		slog.ErrorContext(ctx, "CompleteIdempotencyKey ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "CompleteIdempotencyKey RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorIdempotencyKeyNotFound
+		slog.WarnContext(ctx, "CompleteIdempotencyKey affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ir *IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, userID string, key string) (err error) {
+	query := `
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			user_id = $1
+			AND idempotency_key = $2
+	`
+
+	_, err = executor(ctx, ir.DB).ExecContext(ctx, query, userID, key)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteIdempotencyKey ExecContext error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ir *IdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (deleted int64, err error) {
+	query := `
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			expires_at <= $1
+	`
+
+	rows, err := executor(ctx, ir.DB).ExecContext(ctx, query, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteExpiredIdempotencyKeys ExecContext error", "error", err)
+		return
+	}
+
+	deleted, err = rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteExpiredIdempotencyKeys RowsAffected error", "error", err)
+		return
+	}
+
+	return
+}
@@ -3,7 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"log"
+	"log/slog"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
@@ -12,6 +12,9 @@ import (
 type IInvestmentRepository interface {
 	CreateInvestment(ctx context.Context, investment *model.Investment) (ID string, err error)
 	GetInvestmentByInvestorID(ctx context.Context, id string) (investment *model.Investment, err error)
+	ReleaseInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error)
+	ListInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error)
+	ListInvestmentsByInvestorID(ctx context.Context, investorID string) (investments []*model.Investment, err error)
 }
 
 type InvestmentRepository struct {
@@ -38,14 +41,14 @@ func (ir *InvestmentRepository) CreateInvestment(ctx context.Context, investment
 			id
 		`
 
-	err = ir.DB.QueryRowContext(ctx, query,
+	err = executor(ctx, ir.DB).QueryRowContext(ctx, query,
 		investment.LoanID,
 		investment.InvestorID,
 		investment.InvestedAmount,
 	).Scan(&ID)
 
 	if err != nil {
-		log.Println("CreateInvestment error ", err)
+		slog.ErrorContext(ctx, "CreateInvestment error", "error", err)
 		return
 	}
 
@@ -62,7 +65,9 @@ func (ir *InvestmentRepository) GetInvestmentByInvestorID(ctx context.Context, i
 			COALESCE(investment_agreement_letter_url, ''),
 			COALESCE(is_investment_aggrement_signed, false),
 			COALESCE(investment_aggrement_signed_at, null),
-			COALESCE(total_profit, 0)
+			COALESCE(total_profit, 0),
+			status,
+			released_at
 		FROM
 			investments
 		WHERE
@@ -70,7 +75,7 @@ func (ir *InvestmentRepository) GetInvestmentByInvestorID(ctx context.Context, i
 	`
 
 	investment = &model.Investment{}
-	err = ir.DB.QueryRowContext(ctx, query, id).Scan(
+	err = executor(ctx, ir.DB).QueryRowContext(ctx, query, id).Scan(
 		&investment.ID,
 		&investment.InvestorID,
 		&investment.LoanID,
@@ -79,15 +84,148 @@ func (ir *InvestmentRepository) GetInvestmentByInvestorID(ctx context.Context, i
 		&investment.IsInvestmentAggrementSigned,
 		&investment.InvestmentAggrementSignedAt,
 		&investment.TotalProfit,
+		&investment.Status,
+		&investment.ReleasedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("GetInvestmentByID ", err)
+			slog.DebugContext(ctx, "GetInvestmentByID", "error", err)
 			err = model.ErrorInvestmentNotFound
 			return
 		}
 
-		log.Println("GetInvestmentByID ", err)
+		slog.ErrorContext(ctx, "GetInvestmentByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ir *InvestmentRepository) ReleaseInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error) {
+	query := `
+		UPDATE
+			investments
+		SET
+			status = 'released',
+			released_at = NOW()
+		WHERE
+			loan_id = $1
+			AND status = 'active'
+		RETURNING
+			id,
+			investor_id,
+			loan_id,
+			invested_amount,
+			status,
+			released_at
+	`
+
+	investments = []*model.Investment{}
+	rows, err := executor(ctx, ir.DB).QueryContext(ctx, query, loanID)
+	if err != nil {
+		slog.ErrorContext(ctx, "ReleaseInvestmentsByLoanID QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		investment := &model.Investment{}
+		err = rows.Scan(
+			&investment.ID,
+			&investment.InvestorID,
+			&investment.LoanID,
+			&investment.InvestedAmount,
+			&investment.Status,
+			&investment.ReleasedAt,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "ReleaseInvestmentsByLoanID Scan error", "error", err)
+			return
+		}
+		investments = append(investments, investment)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ReleaseInvestmentsByLoanID rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+const investmentColumns = `
+			id,
+			investor_id,
+			loan_id,
+			invested_amount,
+			COALESCE(investment_agreement_letter_url, ''),
+			COALESCE(is_investment_aggrement_signed, false),
+			COALESCE(investment_aggrement_signed_at, null),
+			COALESCE(total_profit, 0),
+			status,
+			released_at
+`
+
+func (ir *InvestmentRepository) ListInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error) {
+	query := `
+		SELECT` + investmentColumns + `
+		FROM
+			investments
+		WHERE
+			loan_id = $1
+		ORDER BY
+			created_at
+	`
+
+	return ir.queryInvestments(ctx, "ListInvestmentsByLoanID", query, loanID)
+}
+
+func (ir *InvestmentRepository) ListInvestmentsByInvestorID(ctx context.Context, investorID string) (investments []*model.Investment, err error) {
+	query := `
+		SELECT` + investmentColumns + `
+		FROM
+			investments
+		WHERE
+			investor_id = $1
+		ORDER BY
+			created_at
+	`
+
+	return ir.queryInvestments(ctx, "ListInvestmentsByInvestorID", query, investorID)
+}
+
+func (ir *InvestmentRepository) queryInvestments(ctx context.Context, caller string, query string, args ...any) (investments []*model.Investment, err error) {
+	investments = []*model.Investment{}
+	rows, err := executor(ctx, ir.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, caller+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		investment := &model.Investment{}
+		err = rows.Scan(
+			&investment.ID,
+			&investment.InvestorID,
+			&investment.LoanID,
+			&investment.InvestedAmount,
+			&investment.InvestmentAgreementLetterURL,
+			&investment.IsInvestmentAggrementSigned,
+			&investment.InvestmentAggrementSignedAt,
+			&investment.TotalProfit,
+			&investment.Status,
+			&investment.ReleasedAt,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, caller+" Scan error", "error", err)
+			return
+		}
+		investments = append(investments, investment)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, caller+" rows error", "error", err)
 		return
 	}
 
@@ -3,45 +3,55 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
 )
 
 type IInvestorRepository interface {
+	CreateInvestor(ctx context.Context, investor *model.Investor) (ID string, err error)
 	GetInvestorByID(ctx context.Context, id string) (investor *model.Investor, err error)
+	GetInvestorByNIK(ctx context.Context, nik string) (investor *model.Investor, err error)
+	ListInvestorsToReencrypt(ctx context.Context, afterID string, limit int) (investors []*model.Investor, err error)
+	ReencryptInvestor(ctx context.Context, investor *model.Investor) (err error)
+	EraseInvestor(ctx context.Context, id string, erasedAt time.Time) (err error)
 }
 
+// InvestorRepository stores the NIK, NPWP, email and phone number encrypted with FieldCipher, the NIK, NPWP
+// and email are kept unique by their blind indexes.
 type InvestorRepository struct {
-	DB *sql.DB
+	DB          *sql.DB
+	FieldCipher *application.FieldCipher
 }
 
 func NewInvestorRepository(app *application.App) IInvestorRepository {
 	return &InvestorRepository{
-		DB: app.DB,
+		DB:          app.DB,
+		FieldCipher: app.FieldCipher,
 	}
 }
 
-func (ir *InvestorRepository) GetInvestorByID(ctx context.Context, id string) (investor *model.Investor, err error) {
-	query := `
-		SELECT
+const investorColumns = `
 			id,
-			name,
-			nik,
-			npwp,
-			email,
-			phone_number,
+			COALESCE(name, ''),
+			COALESCE(nik, ''),
+			COALESCE(npwp, ''),
+			COALESCE(email, ''),
+			COALESCE(phone_number, ''),
 			created_at,
-			updated_at
-		FROM
-			investors
-		WHERE
-			id = $1
-	`
+			updated_at,
+			erased_at,
+			COALESCE(encryption_key_id, '')
+`
+
+func (ir *InvestorRepository) scanInvestor(scanner interface{ Scan(dest ...any) error }) (investor *model.Investor, err error) {
+	var keyID string
 
 	investor = &model.Investor{}
-	err = ir.DB.QueryRowContext(ctx, query, id).Scan(
+	err = scanner.Scan(
 		&investor.ID,
 		&investor.Name,
 		&investor.NIK,
@@ -50,15 +60,256 @@ func (ir *InvestorRepository) GetInvestorByID(ctx context.Context, id string) (i
 		&investor.PhoneNumber,
 		&investor.CreatedAt,
 		&investor.UpdatedAt,
+		&investor.ErasedAt,
+		&keyID,
 	)
+	if err != nil {
+		return
+	}
+
+	err = decryptColumns(ir.FieldCipher, keyID, map[string]*string{
+		columnNIK:         &investor.NIK,
+		columnNPWP:        &investor.NPWP,
+		columnEmail:       &investor.Email,
+		columnPhoneNumber: &investor.PhoneNumber,
+	})
+
+	return
+}
+
+func (ir *InvestorRepository) encryptInvestor(investor *model.Investor) (ciphertexts map[string]string, err error) {
+	return encryptColumns(ir.FieldCipher, map[string]string{
+		columnNIK:         investor.NIK,
+		columnNPWP:        investor.NPWP,
+		columnEmail:       investor.Email,
+		columnPhoneNumber: investor.PhoneNumber,
+	})
+}
+
+// CreateInvestor stores the investor under its id, or a generated one when the id is empty.
+func (ir *InvestorRepository) CreateInvestor(ctx context.Context, investor *model.Investor) (ID string, err error) {
+	ciphertexts, err := ir.encryptInvestor(investor)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateInvestor encrypt error", "error", err)
+		return
+	}
+
+	query := `
+		INSERT INTO
+			investors (
+				id,
+				name,
+				nik,
+				nik_hash,
+				npwp,
+				npwp_hash,
+				email,
+				email_hash,
+				phone_number,
+				encryption_key_id
+			)
+		VALUES
+			(COALESCE(NULLIF($1, '')::uuid, uuid_generate_v4()), $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING
+			id
+	`
+
+	err = executor(ctx, ir.DB).QueryRowContext(ctx, query,
+		investor.ID,
+		investor.Name,
+		ciphertexts[columnNIK],
+		ir.FieldCipher.BlindIndex(columnNIK, investor.NIK),
+		ciphertexts[columnNPWP],
+		ir.FieldCipher.BlindIndex(columnNPWP, investor.NPWP),
+		ciphertexts[columnEmail],
+		ir.FieldCipher.BlindIndex(columnEmail, investor.Email),
+		ciphertexts[columnPhoneNumber],
+		ir.FieldCipher.ActiveKeyID(),
+	).Scan(&ID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.ErrorContext(ctx, "CreateInvestor", "error", err)
+			err = model.ErrorInvestorExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateInvestor error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (ir *InvestorRepository) GetInvestorByID(ctx context.Context, id string) (investor *model.Investor, err error) {
+	query := `
+		SELECT` + investorColumns + `
+		FROM
+			investors
+		WHERE
+			id = $1
+	`
+
+	return ir.getInvestor(ctx, "GetInvestorByID", query, id)
+}
+
+// GetInvestorByNIK finds the investor by the blind index of the NIK.
+func (ir *InvestorRepository) GetInvestorByNIK(ctx context.Context, nik string) (investor *model.Investor, err error) {
+	query := `
+		SELECT` + investorColumns + `
+		FROM
+			investors
+		WHERE
+			nik_hash = $1
+	`
+
+	return ir.getInvestor(ctx, "GetInvestorByNIK", query, ir.FieldCipher.BlindIndex(columnNIK, nik))
+}
+
+func (ir *InvestorRepository) getInvestor(ctx context.Context, method string, query string, arg any) (investor *model.Investor, err error) {
+	investor, err = ir.scanInvestor(executor(ctx, ir.DB).QueryRowContext(ctx, query, arg))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("GetInvestorByID ", err)
+			slog.DebugContext(ctx, method, "error", err)
 			err = model.ErrorInvestorNotFound
 			return
 		}
 
-		log.Println("GetInvestorByID ", err)
+		slog.ErrorContext(ctx, method, "error", err)
+		return
+	}
+
+	return
+}
+
+// ListInvestorsToReencrypt returns the investors after afterID, in id order, that are not encrypted with the
+// active key yet. Erased investors have nothing to encrypt.
+func (ir *InvestorRepository) ListInvestorsToReencrypt(ctx context.Context, afterID string, limit int) (investors []*model.Investor, err error) {
+	query := `
+		SELECT` + investorColumns + `
+		FROM
+			investors
+		WHERE
+			encryption_key_id IS DISTINCT FROM $1
+			AND erased_at IS NULL
+			AND id > $2
+		ORDER BY
+			id
+		LIMIT $3
+	`
+
+	investors = []*model.Investor{}
+	rows, err := executor(ctx, ir.DB).QueryContext(ctx, query, ir.FieldCipher.ActiveKeyID(), afterID, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListInvestorsToReencrypt QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var investor *model.Investor
+		investor, err = ir.scanInvestor(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListInvestorsToReencrypt Scan error", "error", err)
+			return
+		}
+		investors = append(investors, investor)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListInvestorsToReencrypt rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// ReencryptInvestor encrypts the personal data of the investor with the active key and refreshes its blind
+// indexes.
+func (ir *InvestorRepository) ReencryptInvestor(ctx context.Context, investor *model.Investor) (err error) {
+	ciphertexts, err := ir.encryptInvestor(investor)
+	if err != nil {
+		slog.ErrorContext(ctx, "ReencryptInvestor encrypt error", "error", err)
+		return
+	}
+
+	query := `
+		UPDATE
+			investors
+		SET
+			nik = $2,
+			nik_hash = $3,
+			npwp = $4,
+			npwp_hash = $5,
+			email = $6,
+			email_hash = $7,
+			phone_number = NULLIF($8, ''),
+			encryption_key_id = $9
+		WHERE
+			id = $1
+	`
+
+	_, err = executor(ctx, ir.DB).ExecContext(ctx, query,
+		investor.ID,
+		ciphertexts[columnNIK],
+		ir.FieldCipher.BlindIndex(columnNIK, investor.NIK),
+		ciphertexts[columnNPWP],
+		ir.FieldCipher.BlindIndex(columnNPWP, investor.NPWP),
+		ciphertexts[columnEmail],
+		ir.FieldCipher.BlindIndex(columnEmail, investor.Email),
+		ciphertexts[columnPhoneNumber],
+		ir.FieldCipher.ActiveKeyID(),
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.ErrorContext(ctx, "ReencryptInvestor", "error", err)
+			err = model.ErrorInvestorExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "ReencryptInvestor error", "error", err)
+		return
+	}
+
+	return
+}
+
+// EraseInvestor clears the personal data of the investor and its blind indexes, the row stays for the
+// investments of the investor. An erased investor cannot be erased again.
+func (ir *InvestorRepository) EraseInvestor(ctx context.Context, id string, erasedAt time.Time) (err error) {
+	query := `
+		UPDATE
+			investors
+		SET
+			name = NULL,
+			nik = NULL,
+			nik_hash = NULL,
+			npwp = NULL,
+			npwp_hash = NULL,
+			email = NULL,
+			email_hash = NULL,
+			phone_number = NULL,
+			encryption_key_id = NULL,
+			erased_at = $2
+		WHERE
+			id = $1
+			AND erased_at IS NULL
+	`
+
+	rows, err := executor(ctx, ir.DB).ExecContext(ctx, query, id, erasedAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "EraseInvestor ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "EraseInvestor RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorPersonalDataErased
+		slog.WarnContext(ctx, "EraseInvestor affected < 1 error", "error", err)
 		return
 	}
 
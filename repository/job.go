@@ -0,0 +1,467 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+type IJobRepository interface {
+	EnqueueJob(ctx context.Context, job *model.Job) (ID string, err error)
+	GetJobByID(ctx context.Context, id string) (job *model.Job, err error)
+	ListJobs(ctx context.Context, status model.JobStatus, limit int) (jobs []*model.Job, err error)
+	ClaimJobs(ctx context.Context, workerID string, limit int, lockTimeout time.Duration) (jobs []*model.Job, err error)
+	DeadLetterExpiredJobs(ctx context.Context, lockTimeout time.Duration) (jobs []*model.Job, err error)
+	CompleteJob(ctx context.Context, id string, workerID string) (err error)
+	FailJob(ctx context.Context, id string, workerID string, lastError string, nextRunAt *time.Time) (err error)
+	RetryJob(ctx context.Context, id string) (err error)
+	UpsertRecurringJob(ctx context.Context, recurringJob *model.RecurringJob) (err error)
+	ListDueRecurringJobs(ctx context.Context, now time.Time) (recurringJobs []*model.RecurringJob, err error)
+	EnqueueRecurringJob(ctx context.Context, recurringJob *model.RecurringJob, nextRunAt time.Time) (ID string, err error)
+}
+
+type JobRepository struct {
+	DB *sql.DB
+}
+
+func NewJobRepository(app *application.App) IJobRepository {
+	return &JobRepository{
+		DB: app.DB,
+	}
+}
+
+const jobColumns = `
+			id,
+			type,
+			payload,
+			status,
+			attempts,
+			max_attempts,
+			run_at,
+			locked_at,
+			COALESCE(locked_by, ''),
+			COALESCE(last_error, ''),
+			completed_at,
+			created_at,
+			updated_at
+`
+
+func scanJob(scanner interface{ Scan(dest ...any) error }) (job *model.Job, err error) {
+	var payload []byte
+
+	job = &model.Job{}
+	err = scanner.Scan(
+		&job.ID,
+		&job.Type,
+		&payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.LockedBy,
+		&job.LastError,
+		&job.CompletedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return
+	}
+
+	job.Payload = payload
+
+	return
+}
+
+func (jr *JobRepository) queryJobs(ctx context.Context, method string, query string, args ...any) (jobs []*model.Job, err error) {
+	jobs = []*model.Job{}
+	rows, err := executor(ctx, jr.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, method+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job *model.Job
+		job, err = scanJob(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, method+" Scan error", "error", err)
+			return
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, method+" rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (jr *JobRepository) EnqueueJob(ctx context.Context, job *model.Job) (ID string, err error) {
+	query := `
+		INSERT INTO
+			jobs (
+				type,
+				payload,
+				max_attempts,
+				run_at
+			)
+		VALUES
+			($1, $2, $3, COALESCE($4::timestamptz, NOW()))
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, jr.DB).QueryRowContext(ctx, query,
+		job.Type,
+		[]byte(job.Payload),
+		job.MaxAttempts,
+		job.RunAt,
+	).Scan(&ID)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "EnqueueJob error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (jr *JobRepository) GetJobByID(ctx context.Context, id string) (job *model.Job, err error) {
+	query := `
+		SELECT` + jobColumns + `
+		FROM
+			jobs
+		WHERE
+			id = $1
+	`
+
+	job, err = scanJob(executor(ctx, jr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		job = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetJobByID", "error", err)
+			err = model.ErrorJobNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetJobByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (jr *JobRepository) ListJobs(ctx context.Context, status model.JobStatus, limit int) (jobs []*model.Job, err error) {
+	query := `
+		SELECT` + jobColumns + `
+		FROM
+			jobs
+		WHERE
+			($1 = '' OR status = $1)
+		ORDER BY
+			created_at DESC
+		LIMIT $2
+	`
+
+	return jr.queryJobs(ctx, "ListJobs", query, status, limit)
+}
+
+// ClaimJobs locks up to limit runnable jobs for workerID. Pending jobs are due once run_at has passed,
+// running jobs are reclaimed when their lock is older than lockTimeout, e.g. after a worker crashed, as long
+// as they have attempts left. SKIP LOCKED lets several instances poll the same table without handing out a
+// job twice.
+func (jr *JobRepository) ClaimJobs(ctx context.Context, workerID string, limit int, lockTimeout time.Duration) (jobs []*model.Job, err error) {
+	query := `
+		UPDATE
+			jobs
+		SET
+			status = 'running',
+			attempts = attempts + 1,
+			locked_at = NOW(),
+			locked_by = $1
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					jobs
+				WHERE
+					(status = 'pending' AND run_at <= NOW())
+					OR (
+						status = 'running'
+						AND locked_at < NOW() - $3::float8 * INTERVAL '1 second'
+						AND attempts < max_attempts
+					)
+				ORDER BY
+					run_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING` + jobColumns
+
+	return jr.queryJobs(ctx, "ClaimJobs", query, workerID, limit, lockTimeout.Seconds())
+}
+
+// DeadLetterExpiredJobs dead-letters the running jobs whose lock expired on their last attempt, a job that
+// keeps crashing its worker is not reclaimed forever.
+func (jr *JobRepository) DeadLetterExpiredJobs(ctx context.Context, lockTimeout time.Duration) (jobs []*model.Job, err error) {
+	query := `
+		UPDATE
+			jobs
+		SET
+			status = 'dead',
+			last_error = 'lock expired on the last attempt, the worker stopped while running the job',
+			locked_at = NULL,
+			locked_by = NULL
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					jobs
+				WHERE
+					status = 'running'
+					AND locked_at < NOW() - $1::float8 * INTERVAL '1 second'
+					AND attempts >= max_attempts
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING` + jobColumns
+
+	return jr.queryJobs(ctx, "DeadLetterExpiredJobs", query, lockTimeout.Seconds())
+}
+
+func (jr *JobRepository) CompleteJob(ctx context.Context, id string, workerID string) (err error) {
+	query := `
+		UPDATE
+			jobs
+		SET
+			status = 'succeeded',
+			completed_at = NOW(),
+			locked_at = NULL,
+			locked_by = NULL,
+			last_error = NULL
+		WHERE
+			id = $1
+			AND status = 'running'
+			AND locked_by = $2
+	`
+
+	return jr.execJobUpdate(ctx, "CompleteJob", query, id, workerID)
+}
+
+// FailJob records a failed attempt. The job goes back to pending at nextRunAt, or is dead-lettered when nextRunAt is nil.
+func (jr *JobRepository) FailJob(ctx context.Context, id string, workerID string, lastError string, nextRunAt *time.Time) (err error) {
+	query := `
+		UPDATE
+			jobs
+		SET
+			status = CASE WHEN $4::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+			run_at = COALESCE($4::timestamptz, run_at),
+			last_error = $3,
+			locked_at = NULL,
+			locked_by = NULL
+		WHERE
+			id = $1
+			AND status = 'running'
+			AND locked_by = $2
+	`
+
+	return jr.execJobUpdate(ctx, "FailJob", query, id, workerID, lastError, nextRunAt)
+}
+
+// RetryJob puts a dead job back in the queue with a fresh set of attempts.
+func (jr *JobRepository) RetryJob(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE
+			jobs
+		SET
+			status = 'pending',
+			attempts = 0,
+			run_at = NOW()
+		WHERE
+			id = $1
+			AND status = 'dead'
+	`
+
+	return jr.execJobUpdate(ctx, "RetryJob", query, id)
+}
+
+func (jr *JobRepository) execJobUpdate(ctx context.Context, method string, query string, args ...any) (err error) {
+	rows, err := executor(ctx, jr.DB).ExecContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, method+" ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, method+" RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorJobNotFound
+		slog.WarnContext(ctx, method+" affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+// UpsertRecurringJob registers a recurring job, next_run_at is only moved when the schedule changes.
+func (jr *JobRepository) UpsertRecurringJob(ctx context.Context, recurringJob *model.RecurringJob) (err error) {
+	query := `
+		INSERT INTO
+			recurring_jobs (
+				name,
+				schedule,
+				job_type,
+				payload,
+				max_attempts,
+				next_run_at
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			job_type = EXCLUDED.job_type,
+			payload = EXCLUDED.payload,
+			max_attempts = EXCLUDED.max_attempts,
+			next_run_at = CASE
+				WHEN recurring_jobs.schedule = EXCLUDED.schedule THEN recurring_jobs.next_run_at
+				ELSE EXCLUDED.next_run_at
+			END,
+			schedule = EXCLUDED.schedule
+	`
+
+	_, err = executor(ctx, jr.DB).ExecContext(ctx, query,
+		recurringJob.Name,
+		recurringJob.Schedule,
+		recurringJob.JobType,
+		[]byte(recurringJob.Payload),
+		recurringJob.MaxAttempts,
+		recurringJob.NextRunAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpsertRecurringJob ExecContext error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (jr *JobRepository) ListDueRecurringJobs(ctx context.Context, now time.Time) (recurringJobs []*model.RecurringJob, err error) {
+	query := `
+		SELECT
+			name,
+			schedule,
+			job_type,
+			payload,
+			max_attempts,
+			next_run_at,
+			last_run_at
+		FROM
+			recurring_jobs
+		WHERE
+			next_run_at <= $1
+		ORDER BY
+			next_run_at
+	`
+
+	recurringJobs = []*model.RecurringJob{}
+	rows, err := executor(ctx, jr.DB).QueryContext(ctx, query, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListDueRecurringJobs QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payload []byte
+		recurringJob := &model.RecurringJob{}
+		err = rows.Scan(
+			&recurringJob.Name,
+			&recurringJob.Schedule,
+			&recurringJob.JobType,
+			&payload,
+			&recurringJob.MaxAttempts,
+			&recurringJob.NextRunAt,
+			&recurringJob.LastRunAt,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListDueRecurringJobs Scan error", "error", err)
+			return
+		}
+		recurringJob.Payload = payload
+		recurringJobs = append(recurringJobs, recurringJob)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListDueRecurringJobs rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// EnqueueRecurringJob advances the recurring job to nextRunAt and enqueues one run in a single statement.
+// The update only matches while next_run_at is unchanged, so when several instances see the same due
+// recurring job only one of them enqueues it; the others get model.ErrorRecurringJobAlreadyScheduled.
+func (jr *JobRepository) EnqueueRecurringJob(ctx context.Context, recurringJob *model.RecurringJob, nextRunAt time.Time) (ID string, err error) {
+	query := `
+		WITH scheduled AS (
+			UPDATE
+				recurring_jobs
+			SET
+				next_run_at = $3,
+				last_run_at = NOW()
+			WHERE
+				name = $1
+				AND next_run_at = $2
+			RETURNING
+				job_type,
+				payload,
+				max_attempts
+		)
+		INSERT INTO
+			jobs (
+				type,
+				payload,
+				max_attempts
+			)
+		SELECT
+			job_type,
+			payload,
+			max_attempts
+		FROM
+			scheduled
+		RETURNING
+			id
+	`
+
+	err = executor(ctx, jr.DB).QueryRowContext(ctx, query,
+		recurringJob.Name,
+		recurringJob.NextRunAt,
+		nextRunAt,
+	).Scan(&ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrorRecurringJobAlreadyScheduled
+			return
+		}
+
+		slog.ErrorContext(ctx, "EnqueueRecurringJob error", "error", err)
+		return
+	}
+
+	return
+}
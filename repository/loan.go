@@ -3,8 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -17,6 +18,11 @@ type ILoanRepository interface {
 	GetLoanByID(ctx context.Context, id string) (loan *model.Loan, err error)
 	UpdateLoanState(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) (err error)
 	UpdateLoanTotalInvestedAmount(ctx context.Context, loan *model.Loan) (err error)
+	ListLoansByState(ctx context.Context, state model.LoanState) (loans []*model.Loan, err error)
+	ListExpiredPublishedLoans(ctx context.Context, now time.Time) (loans []*model.Loan, err error)
+	ListLoansByBorrowerID(ctx context.Context, borrowerID string) (loans []*model.Loan, err error)
+	GetBorrowerLoanStats(ctx context.Context, borrowerID string) (stats *model.BorrowerLoanStats, err error)
+	CountLoansByState(ctx context.Context) (counts map[model.LoanState]int64, err error)
 }
 
 type LoanRepository struct {
@@ -34,40 +40,60 @@ func (lr *LoanRepository) CreateLoan(ctx context.Context, loan *model.Loan) (ID
 		INSERT INTO
 			loans (
 				borrower_id,
+				product_id,
+				tenor_months,
 				principal_amount,
 				interest_rate,
 				roi_rate,
 				state,
-				created_by
+				created_by,
+				risk_score,
+				risk_grade,
+				risk_explanation,
+				risk_scorecard_version,
+				risk_assessed_at,
+				suggested_min_interest_rate,
+				suggested_max_interest_rate
 			)
 		VALUES 
-			($1, $2, $3, $4, $5, $6)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING
 			id
 		`
 
-	err = lr.DB.QueryRowContext(ctx, query,
+	riskArgs, err := riskAssessmentArgs(loan.Risk)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateLoan riskAssessmentArgs error", "error", err)
+		return
+	}
+
+	args := []any{
 		loan.BorrowerID,
+		loan.ProductID,
+		loan.TenorMonths,
 		loan.PrincipalAmount,
 		loan.InterestRate,
 		loan.ROIRate,
 		loan.State,
 		loan.CreatedBy,
-	).Scan(&ID)
+	}
+	args = append(args, riskArgs...)
+
+	err = executor(ctx, lr.DB).QueryRowContext(ctx, query, args...).Scan(&ID)
 
 	if err != nil {
-		log.Println("CreateLoan error ", err)
+		slog.ErrorContext(ctx, "CreateLoan error", "error", err)
 		return
 	}
 
 	return
 }
 
-func (lr *LoanRepository) GetLoanByID(ctx context.Context, id string) (loan *model.Loan, err error) {
-	query := `
-		SELECT
+const loanColumns = `
 			id,
 			borrower_id,
+			COALESCE(product_id, '00000000-0000-0000-0000-000000000000'),
+			COALESCE(tenor_months, 0),
 			principal_amount,
 			interest_rate,
 			roi_rate,
@@ -91,20 +117,31 @@ func (lr *LoanRepository) GetLoanByID(ctx context.Context, id string) (loan *mod
 			COALESCE(canceled_reason, ''),
 			COALESCE(published_at, null),
 			COALESCE(published_by, '00000000-0000-0000-0000-000000000000'),
+			COALESCE(funding_deadline, null),
 			COALESCE(invested_at, null),
 			COALESCE(disbursed_at, null),
 			COALESCE(disbursed_by, '00000000-0000-0000-0000-000000000000'),
-			COALESCE(updated_at, null)
-		FROM
-			loans 
-		WHERE
-			id = $1
-		`
+			COALESCE(updated_at, null),
+			COALESCE(risk_score, 0),
+			COALESCE(risk_grade, ''),
+			COALESCE(risk_explanation, '[]'),
+			COALESCE(risk_scorecard_version, ''),
+			COALESCE(risk_assessed_at, null),
+			COALESCE(suggested_min_interest_rate, 0),
+			COALESCE(suggested_max_interest_rate, 0),
+			version
+`
+
+func scanLoan(scanner interface{ Scan(dest ...any) error }) (loan *model.Loan, err error) {
+	var riskExplanation []byte
 
 	loan = &model.Loan{}
-	err = lr.DB.QueryRowContext(ctx, query, id).Scan(
+	risk := &model.RiskAssessment{}
+	err = scanner.Scan(
 		&loan.ID,
 		&loan.BorrowerID,
+		&loan.ProductID,
+		&loan.TenorMonths,
 		&loan.PrincipalAmount,
 		&loan.InterestRate,
 		&loan.ROIRate,
@@ -128,55 +165,247 @@ func (lr *LoanRepository) GetLoanByID(ctx context.Context, id string) (loan *mod
 		&loan.CanceledReason,
 		&loan.PublishedAt,
 		&loan.PublishedBy,
+		&loan.FundingDeadline,
 		&loan.InvestedAt,
 		&loan.DisbursedAt,
 		&loan.DisbursedBy,
 		&loan.UpdatedAt,
+		&risk.Score,
+		&risk.Grade,
+		&riskExplanation,
+		&risk.ScorecardVersion,
+		&risk.AssessedAt,
+		&risk.SuggestedMinInterestRate,
+		&risk.SuggestedMaxInterestRate,
+		&loan.Version,
 	)
+	if err != nil {
+		return
+	}
+
+	if risk.Grade != "" {
+		err = json.Unmarshal(riskExplanation, &risk.Explanation)
+		if err != nil {
+			return
+		}
+		loan.Risk = risk
+	}
+
+	return
+}
+
+// riskAssessmentArgs returns the risk column values, all NULL when the loan was not assessed.
+func riskAssessmentArgs(risk *model.RiskAssessment) (args []any, err error) {
+	if risk == nil || risk.Grade == "" {
+		return []any{nil, nil, nil, nil, nil, nil, nil}, nil
+	}
 
+	explanation, err := json.Marshal(risk.Explanation)
 	if err != nil {
+		return
+	}
+
+	args = []any{
+		risk.Score,
+		risk.Grade,
+		explanation,
+		risk.ScorecardVersion,
+		risk.AssessedAt,
+		risk.SuggestedMinInterestRate,
+		risk.SuggestedMaxInterestRate,
+	}
+
+	return
+}
+
+func (lr *LoanRepository) GetLoanByID(ctx context.Context, id string) (loan *model.Loan, err error) {
+	query := `
+		SELECT` + loanColumns + `
+		FROM
+			loans 
+		WHERE
+			id = $1
+		`
+
+	loan, err = scanLoan(executor(ctx, lr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		loan = nil
 		if err == sql.ErrNoRows {
 			err = model.ErrorLoanNotFound
-			log.Println("GetLoanByID ", err)
+			slog.DebugContext(ctx, "GetLoanByID", "error", err)
+			return
+		}
+		slog.ErrorContext(ctx, "GetLoanByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lr *LoanRepository) ListLoansByState(ctx context.Context, state model.LoanState) (loans []*model.Loan, err error) {
+	query := `
+		SELECT` + loanColumns + `
+		FROM
+			loans
+		WHERE
+			state = $1
+		ORDER BY
+			created_at DESC
+		`
+
+	return lr.queryLoans(ctx, "ListLoansByState", query, state)
+}
+
+func (lr *LoanRepository) ListExpiredPublishedLoans(ctx context.Context, now time.Time) (loans []*model.Loan, err error) {
+	query := `
+		SELECT` + loanColumns + `
+		FROM
+			loans
+		WHERE
+			state = 'published'
+			AND funding_deadline < $1
+		ORDER BY
+			funding_deadline
+		`
+
+	return lr.queryLoans(ctx, "ListExpiredPublishedLoans", query, now)
+}
+
+func (lr *LoanRepository) ListLoansByBorrowerID(ctx context.Context, borrowerID string) (loans []*model.Loan, err error) {
+	query := `
+		SELECT` + loanColumns + `
+		FROM
+			loans
+		WHERE
+			borrower_id = $1
+		ORDER BY
+			created_at
+		`
+
+	return lr.queryLoans(ctx, "ListLoansByBorrowerID", query, borrowerID)
+}
+
+func (lr *LoanRepository) queryLoans(ctx context.Context, caller string, query string, args ...any) (loans []*model.Loan, err error) {
+	loans = []*model.Loan{}
+
+	rows, err := executor(ctx, lr.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, caller+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var loan *model.Loan
+		loan, err = scanLoan(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, caller+" Scan error", "error", err)
 			return
 		}
-		log.Println("GetLoanByID ", err)
+		loans = append(loans, loan)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, caller+" rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lr *LoanRepository) GetBorrowerLoanStats(ctx context.Context, borrowerID string) (stats *model.BorrowerLoanStats, err error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE state = 'disbursed'),
+			COUNT(*) FILTER (WHERE state IN ('proposed', 'approved', 'published', 'invested')),
+			COUNT(*) FILTER (WHERE state = 'rejected'),
+			COUNT(*) FILTER (WHERE state = 'canceled')
+		FROM
+			loans
+		WHERE
+			borrower_id = $1
+		`
+
+	stats = &model.BorrowerLoanStats{}
+	err = executor(ctx, lr.DB).QueryRowContext(ctx, query, borrowerID).Scan(
+		&stats.DisbursedLoans,
+		&stats.ActiveLoans,
+		&stats.RejectedLoans,
+		&stats.CanceledLoans,
+	)
+	if err != nil {
+		stats = nil
+		slog.ErrorContext(ctx, "GetBorrowerLoanStats", "error", err)
 		return
 	}
 
 	return
 }
 
+// UpdateLoanState moves the loan to the new state only while it still has the version and state it was read
+// with, otherwise it returns model.ErrorLoanUpdateConflict when the state changed or
+// model.ErrorLoanVersionMismatch when the loan was modified otherwise.
 func (lr *LoanRepository) UpdateLoanState(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) (err error) {
 
-	query, args, err := lr.buildLoanUpdateQuery(loan, newLoanState, ctx.Value("userID").(string))
+	userID := ctx.Value("userID").(string)
+	now := time.Now()
+	query, args, err := lr.buildLoanUpdateQuery(loan, newLoanState, userID, now)
 	if err != nil {
-		log.Println("CreateLoan buildLoanUpdateQuery error ", err)
+		slog.ErrorContext(ctx, "UpdateLoanState buildLoanUpdateQuery error", "error", err)
 		return
 	}
 
-	rows, err := lr.DB.ExecContext(ctx, query, args...)
+	rows, err := executor(ctx, lr.DB).ExecContext(ctx, query, args...)
 	if err != nil {
-		log.Println("CreateLoan ExecContext error ", err)
+		slog.ErrorContext(ctx, "UpdateLoanState ExecContext error", "error", err)
 		return
 	}
 
 	affected, err := rows.RowsAffected()
 	if err != nil {
-		log.Println("CreateLoan RowsAffected error ", err)
+		slog.ErrorContext(ctx, "UpdateLoanState RowsAffected error", "error", err)
 		return
 	}
 
 	if affected < 1 {
-		err = model.ErrorLoanNotFound
-		log.Println("CreateLoan affected < 1 error ", err)
+		err = lr.getLoanUpdateConflict(ctx, loan)
+		slog.WarnContext(ctx, "UpdateLoanState affected < 1 error", "error", err)
 		return
 	}
 
+	SetLoanStateFields(loan, newLoanState, userID, now)
+
 	return
 }
 
-func (lr *LoanRepository) buildLoanUpdateQuery(loan *model.Loan, newLoanState model.LoanState, userID string) (query string, args []any, err error) {
+// SetLoanStateFields sets the actor and time columns written by the state update on the loan, the in-memory
+// repository uses it too.
+func SetLoanStateFields(loan *model.Loan, newLoanState model.LoanState, userID string, now time.Time) {
+	for _, field := range model.StateUpdates[newLoanState] {
+		switch field {
+		case "approved_at":
+			loan.ApprovedAt = &now
+		case "approved_by":
+			loan.ApprovedBy = userID
+		case "rejected_at":
+			loan.RejectedAt = &now
+		case "rejected_by":
+			loan.RejectedBy = userID
+		case "canceled_at":
+			loan.CanceledAt = &now
+		case "canceled_by":
+			loan.CanceledBy = userID
+		case "published_at":
+			loan.PublishedAt = &now
+		case "published_by":
+			loan.PublishedBy = userID
+		case "invested_at":
+			loan.InvestedAt = &now
+		}
+	}
+}
+
+func (lr *LoanRepository) buildLoanUpdateQuery(loan *model.Loan, newLoanState model.LoanState, userID string, now time.Time) (query string, args []any, err error) {
 	setParts := []string{}
 	idx := 1
 
@@ -193,45 +422,132 @@ func (lr *LoanRepository) buildLoanUpdateQuery(loan *model.Loan, newLoanState mo
 		case "approved_by", "rejected_by", "canceled_by", "published_by", "disbursed_by":
 			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, idx))
 			args = append(args, userID)
+		case "rejected_reason":
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, idx))
+			args = append(args, loan.RejectedReason)
+		case "canceled_reason":
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, idx))
+			args = append(args, loan.CanceledReason)
+		case "funding_deadline":
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, idx))
+			args = append(args, loan.FundingDeadline)
 		default:
 			// timestamp field
 			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, idx))
-			args = append(args, time.Now())
+			args = append(args, now)
 		}
 		idx++
 	}
+	setParts = append(setParts, "version = version + 1")
 
-	// WHERE clause
-	query = fmt.Sprintf("UPDATE loans SET %s WHERE id = $%d", strings.Join(setParts, ", "), idx)
-	args = append(args, loan.ID)
+	// WHERE clause, the loan must still be the one the transition was validated against
+	query = fmt.Sprintf("UPDATE loans SET %s WHERE id = $%d AND version = $%d AND state = $%d",
+		strings.Join(setParts, ", "), idx, idx+1, idx+2)
+	args = append(args, loan.ID, loan.Version, loan.State)
 
 	return
 }
 
+// getLoanUpdateConflict explains why a conditional loan update matched no row.
+func (lr *LoanRepository) getLoanUpdateConflict(ctx context.Context, loan *model.Loan) (err error) {
+	query := `
+		SELECT
+			state,
+			version
+		FROM
+			loans
+		WHERE
+			id = $1
+		`
+
+	var (
+		state   model.LoanState
+		version int64
+	)
+	err = executor(ctx, lr.DB).QueryRowContext(ctx, query, loan.ID).Scan(&state, &version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrorLoanNotFound
+			return
+		}
+		slog.ErrorContext(ctx, "getLoanUpdateConflict", "error", err)
+		return
+	}
+
+	if state != loan.State {
+		return model.ErrorLoanUpdateConflict
+	}
+
+	return model.ErrorLoanVersionMismatch
+}
+
 func (lr *LoanRepository) UpdateLoanTotalInvestedAmount(ctx context.Context, loan *model.Loan) (err error) {
 	query := `
 		UPDATE
 			loans
 		SET
-			total_invested_amount = $2
+			total_invested_amount = $2,
+			version = version + 1
 		WHERE
 			id = $1
+			AND version = $3
 	`
-	rows, err := lr.DB.ExecContext(ctx, query, loan.ID, loan.TotalInvestedAmount)
+	rows, err := executor(ctx, lr.DB).ExecContext(ctx, query, loan.ID, loan.TotalInvestedAmount, loan.Version)
 	if err != nil {
-		log.Println("UpdateLoanTotalInvestedAmount ExecContext error ", err)
+		slog.ErrorContext(ctx, "UpdateLoanTotalInvestedAmount ExecContext error", "error", err)
 		return
 	}
 
 	affected, err := rows.RowsAffected()
 	if err != nil {
-		log.Println("UpdateLoanTotalInvestedAmount RowsAffected error ", err)
+		slog.ErrorContext(ctx, "UpdateLoanTotalInvestedAmount RowsAffected error", "error", err)
 		return
 	}
 
+	// another investment moved the loan on since it was read
 	if affected < 1 {
-		err = model.ErrorLoanNotFound
-		log.Println("UpdateLoanTotalInvestedAmount affected < 1 error ", err)
+		err = model.ErrorLoanUpdateConflict
+		slog.WarnContext(ctx, "UpdateLoanTotalInvestedAmount affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lr *LoanRepository) CountLoansByState(ctx context.Context) (counts map[model.LoanState]int64, err error) {
+	query := `
+		SELECT
+			state,
+			COUNT(*)
+		FROM
+			loans
+		GROUP BY
+			state
+		`
+
+	rows, err := executor(ctx, lr.DB).QueryContext(ctx, query)
+	if err != nil {
+		slog.ErrorContext(ctx, "CountLoansByState QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	counts = map[model.LoanState]int64{}
+	for rows.Next() {
+		var (
+			state model.LoanState
+			count int64
+		)
+		err = rows.Scan(&state, &count)
+		if err != nil {
+			slog.ErrorContext(ctx, "CountLoansByState Scan error", "error", err)
+			return
+		}
+		counts[state] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "CountLoansByState rows error", "error", err)
 		return
 	}
 
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
+)
+
+type ILoanProductRepository interface {
+	CreateLoanProduct(ctx context.Context, product *model.LoanProduct) (ID string, err error)
+	GetLoanProductByID(ctx context.Context, id string) (product *model.LoanProduct, err error)
+	ListLoanProducts(ctx context.Context, activeOnly bool) (products []*model.LoanProduct, err error)
+	UpdateLoanProduct(ctx context.Context, product *model.LoanProduct) (err error)
+}
+
+type LoanProductRepository struct {
+	DB *sql.DB
+}
+
+func NewLoanProductRepository(app *application.App) ILoanProductRepository {
+	return &LoanProductRepository{
+		DB: app.DB,
+	}
+}
+
+const loanProductColumns = `
+			id,
+			code,
+			name,
+			COALESCE(description, ''),
+			min_principal_amount,
+			max_principal_amount,
+			tenor_months,
+			min_interest_rate,
+			max_interest_rate,
+			min_roi_rate,
+			max_roi_rate,
+			fees,
+			funding_window_days,
+			is_active,
+			created_at,
+			updated_at
+`
+
+func scanLoanProduct(scanner interface{ Scan(dest ...any) error }) (product *model.LoanProduct, err error) {
+	var fees []byte
+
+	product = &model.LoanProduct{}
+	err = scanner.Scan(
+		&product.ID,
+		&product.Code,
+		&product.Name,
+		&product.Description,
+		&product.MinPrincipalAmount,
+		&product.MaxPrincipalAmount,
+		pq.Array(&product.TenorMonths),
+		&product.MinInterestRate,
+		&product.MaxInterestRate,
+		&product.MinROIRate,
+		&product.MaxROIRate,
+		&fees,
+		&product.FundingWindowDays,
+		&product.IsActive,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(fees, &product.Fees)
+
+	return
+}
+
+func (lpr *LoanProductRepository) CreateLoanProduct(ctx context.Context, product *model.LoanProduct) (ID string, err error) {
+	query := `
+		INSERT INTO
+			loan_products (
+				code,
+				name,
+				description,
+				min_principal_amount,
+				max_principal_amount,
+				tenor_months,
+				min_interest_rate,
+				max_interest_rate,
+				min_roi_rate,
+				max_roi_rate,
+				fees,
+				funding_window_days
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING
+			id
+		`
+
+	fees, err := json.Marshal(product.Fees)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateLoanProduct Marshal error", "error", err)
+		return
+	}
+
+	err = executor(ctx, lpr.DB).QueryRowContext(ctx, query,
+		product.Code,
+		product.Name,
+		product.Description,
+		product.MinPrincipalAmount,
+		product.MaxPrincipalAmount,
+		pq.Array(product.TenorMonths),
+		product.MinInterestRate,
+		product.MaxInterestRate,
+		product.MinROIRate,
+		product.MaxROIRate,
+		fees,
+		product.FundingWindowDays,
+	).Scan(&ID)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.ErrorContext(ctx, "CreateLoanProduct", "error", err)
+			err = model.ErrorLoanProductCodeExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateLoanProduct error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lpr *LoanProductRepository) GetLoanProductByID(ctx context.Context, id string) (product *model.LoanProduct, err error) {
+	query := `
+		SELECT` + loanProductColumns + `
+		FROM
+			loan_products
+		WHERE
+			id = $1
+	`
+
+	product, err = scanLoanProduct(executor(ctx, lpr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		product = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetLoanProductByID", "error", err)
+			err = model.ErrorLoanProductNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetLoanProductByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lpr *LoanProductRepository) ListLoanProducts(ctx context.Context, activeOnly bool) (products []*model.LoanProduct, err error) {
+	query := `
+		SELECT` + loanProductColumns + `
+		FROM
+			loan_products
+		WHERE
+			($1 = false OR is_active = true)
+		ORDER BY
+			name
+	`
+
+	products = []*model.LoanProduct{}
+	rows, err := executor(ctx, lpr.DB).QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListLoanProducts QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product *model.LoanProduct
+		product, err = scanLoanProduct(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListLoanProducts Scan error", "error", err)
+			return
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListLoanProducts rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (lpr *LoanProductRepository) UpdateLoanProduct(ctx context.Context, product *model.LoanProduct) (err error) {
+	query := `
+		UPDATE
+			loan_products
+		SET
+			name = $2,
+			description = $3,
+			min_principal_amount = $4,
+			max_principal_amount = $5,
+			tenor_months = $6,
+			min_interest_rate = $7,
+			max_interest_rate = $8,
+			min_roi_rate = $9,
+			max_roi_rate = $10,
+			fees = $11,
+			funding_window_days = $12,
+			is_active = $13
+		WHERE
+			id = $1
+	`
+
+	fees, err := json.Marshal(product.Fees)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateLoanProduct Marshal error", "error", err)
+		return
+	}
+
+	rows, err := executor(ctx, lpr.DB).ExecContext(ctx, query,
+		product.ID,
+		product.Name,
+		product.Description,
+		product.MinPrincipalAmount,
+		product.MaxPrincipalAmount,
+		pq.Array(product.TenorMonths),
+		product.MinInterestRate,
+		product.MaxInterestRate,
+		product.MinROIRate,
+		product.MaxROIRate,
+		fees,
+		product.FundingWindowDays,
+		product.IsActive,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateLoanProduct ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateLoanProduct RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorLoanProductNotFound
+		slog.WarnContext(ctx, "UpdateLoanProduct affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
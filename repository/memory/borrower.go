@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+// BorrowerRepository keeps borrowers in plaintext. The NIK is unique and looked up case and space
+// insensitive, the way its blind index is in Postgres. Each borrower records the id of the key it was written
+// with, so the borrowers written before RotateKey are listed for re-encryption.
+type BorrowerRepository struct {
+	mu        sync.Mutex
+	borrowers map[string]*model.Borrower
+	// keyIDs holds the key each borrower was last written with, the encryption_key_id column
+	keyIDs      map[string]string
+	activeKeyID string
+}
+
+func NewBorrowerRepository() repository.IBorrowerRepository {
+	return &BorrowerRepository{
+		borrowers: map[string]*model.Borrower{},
+		keyIDs:    map[string]string{},
+	}
+}
+
+// CreateBorrower stores the borrower under its id, or a generated one when the id is empty.
+func (br *BorrowerRepository) CreateBorrower(ctx context.Context, borrower *model.Borrower) (ID string, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	ID = borrower.ID
+	if ID == "" {
+		ID = uuid.NewString()
+	}
+
+	if _, ok := br.borrowers[ID]; ok {
+		return "", model.ErrorBorrowerExist
+	}
+
+	if br.findByNIK(borrower.NIK) != nil {
+		return "", model.ErrorBorrowerExist
+	}
+
+	br.borrowers[ID] = &model.Borrower{
+		ID:         ID,
+		Name:       borrower.Name,
+		Address:    borrower.Address,
+		Occupation: borrower.Occupation,
+		NIK:        borrower.NIK,
+		DOB:        truncateDate(borrower.DOB),
+		Email:      borrower.Email,
+	}
+	br.keyIDs[ID] = br.activeKeyID
+
+	return
+}
+
+// RotateKey makes keyID the key borrowers are written with, like a keyfile with a new active_key_id.
+func (br *BorrowerRepository) RotateKey(keyID string) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	br.activeKeyID = keyID
+}
+
+func (br *BorrowerRepository) GetBorrowerByID(ctx context.Context, id string) (borrower *model.Borrower, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	stored, ok := br.borrowers[id]
+	if !ok {
+		return nil, model.ErrorBorrowerNotFound
+	}
+
+	return copyBorrower(stored), nil
+}
+
+func (br *BorrowerRepository) GetBorrowerByNIK(ctx context.Context, nik string) (borrower *model.Borrower, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	stored := br.findByNIK(nik)
+	if stored == nil {
+		return nil, model.ErrorBorrowerNotFound
+	}
+
+	return copyBorrower(stored), nil
+}
+
+// findByNIK skips erased borrowers, their NIK index is cleared.
+func (br *BorrowerRepository) findByNIK(nik string) *model.Borrower {
+	for _, stored := range br.borrowers {
+		if stored.ErasedAt == nil && normalize(stored.NIK) == normalize(nik) {
+			return stored
+		}
+	}
+
+	return nil
+}
+
+// ListBorrowersToReencrypt returns up to limit borrowers that are not erased and were written with another key
+// than the active one, in id order after afterID.
+func (br *BorrowerRepository) ListBorrowersToReencrypt(ctx context.Context, afterID string, limit int) (borrowers []*model.Borrower, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	borrowers = []*model.Borrower{}
+	for id, stored := range br.borrowers {
+		if stored.ErasedAt == nil && br.keyIDs[id] != br.activeKeyID && id > afterID {
+			borrowers = append(borrowers, copyBorrower(stored))
+		}
+	}
+	slices.SortFunc(borrowers, func(a, b *model.Borrower) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return borrowers[:min(limit, len(borrowers))], nil
+}
+
+func (br *BorrowerRepository) ReencryptBorrower(ctx context.Context, borrower *model.Borrower) (err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	if stored, ok := br.borrowers[borrower.ID]; ok {
+		stored.NIK = borrower.NIK
+		stored.Email = borrower.Email
+		br.keyIDs[borrower.ID] = br.activeKeyID
+	}
+
+	return
+}
+
+// EraseBorrower clears the personal data of the borrower. An erased borrower cannot be erased again.
+func (br *BorrowerRepository) EraseBorrower(ctx context.Context, id string, erasedAt time.Time) (err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	stored, ok := br.borrowers[id]
+	if !ok || stored.ErasedAt != nil {
+		return model.ErrorPersonalDataErased
+	}
+
+	br.borrowers[id] = &model.Borrower{
+		ID:       id,
+		ErasedAt: &erasedAt,
+	}
+
+	return
+}
+
+func copyBorrower(stored *model.Borrower) *model.Borrower {
+	borrower := *stored
+	borrower.DOB = copyTime(stored.DOB)
+	borrower.ErasedAt = copyTime(stored.ErasedAt)
+
+	return &borrower
+}
+
+// normalize matches values the way application.FieldCipher.BlindIndex does.
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// truncateDate keeps the date only, like the date column.
+func truncateDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	year, month, day := t.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
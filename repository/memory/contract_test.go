@@ -0,0 +1,24 @@
+package memory_test
+
+import (
+	"github.com/frencius/loan-service/repository/memory"
+	"github.com/frencius/loan-service/repository/repositorytest"
+	"github.com/google/uuid"
+)
+
+var _ = repositorytest.DescribeRepositories("memory", func() (*repositorytest.Repositories, func()) {
+	borrowerRepository := memory.NewBorrowerRepository().(*memory.BorrowerRepository)
+	investorRepository := memory.NewInvestorRepository().(*memory.InvestorRepository)
+
+	return &repositorytest.Repositories{
+		LoanRepository:       memory.NewLoanRepository(),
+		BorrowerRepository:   borrowerRepository,
+		InvestorRepository:   investorRepository,
+		InvestmentRepository: memory.NewInvestmentRepository(),
+		ProductID:            uuid.NewString(),
+		RotateKey: func() {
+			borrowerRepository.RotateKey("next")
+			investorRepository.RotateKey("next")
+		},
+	}, nil
+})
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+// InvestmentRepository keeps investments in the order they were created, which is the created_at order the
+// lists of repository.InvestmentRepository use.
+type InvestmentRepository struct {
+	mu          sync.Mutex
+	investments []*model.Investment
+}
+
+func NewInvestmentRepository() repository.IInvestmentRepository {
+	return &InvestmentRepository{}
+}
+
+func (ir *InvestmentRepository) CreateInvestment(ctx context.Context, investment *model.Investment) (ID string, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	ID = uuid.NewString()
+	ir.investments = append(ir.investments, &model.Investment{
+		ID:             ID,
+		LoanID:         investment.LoanID,
+		InvestorID:     investment.InvestorID,
+		InvestedAmount: investment.InvestedAmount,
+		Status:         model.InvestmentStatusActive,
+	})
+
+	return
+}
+
+func (ir *InvestmentRepository) GetInvestmentByInvestorID(ctx context.Context, id string) (investment *model.Investment, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	for _, stored := range ir.investments {
+		if stored.InvestorID == id {
+			return copyInvestment(stored), nil
+		}
+	}
+
+	return nil, model.ErrorInvestmentNotFound
+}
+
+// ReleaseInvestmentsByLoanID returns the columns the UPDATE of repository.InvestmentRepository returns.
+func (ir *InvestmentRepository) ReleaseInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	now := time.Now()
+	investments = []*model.Investment{}
+	for _, stored := range ir.investments {
+		if stored.LoanID != loanID || stored.Status != model.InvestmentStatusActive {
+			continue
+		}
+
+		stored.Status = model.InvestmentStatusReleased
+		stored.ReleasedAt = &now
+		investments = append(investments, &model.Investment{
+			ID:             stored.ID,
+			InvestorID:     stored.InvestorID,
+			LoanID:         stored.LoanID,
+			InvestedAmount: stored.InvestedAmount,
+			Status:         stored.Status,
+			ReleasedAt:     copyTime(stored.ReleasedAt),
+		})
+	}
+
+	return
+}
+
+func (ir *InvestmentRepository) ListInvestmentsByLoanID(ctx context.Context, loanID string) (investments []*model.Investment, err error) {
+	return ir.listInvestments(func(investment *model.Investment) bool {
+		return investment.LoanID == loanID
+	}), nil
+}
+
+func (ir *InvestmentRepository) ListInvestmentsByInvestorID(ctx context.Context, investorID string) (investments []*model.Investment, err error) {
+	return ir.listInvestments(func(investment *model.Investment) bool {
+		return investment.InvestorID == investorID
+	}), nil
+}
+
+func (ir *InvestmentRepository) listInvestments(match func(investment *model.Investment) bool) (investments []*model.Investment) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	investments = []*model.Investment{}
+	for _, stored := range ir.investments {
+		if match(stored) {
+			investments = append(investments, copyInvestment(stored))
+		}
+	}
+
+	return
+}
+
+func copyInvestment(stored *model.Investment) *model.Investment {
+	investment := *stored
+	investment.InvestmentAggrementSignedAt = copyTime(stored.InvestmentAggrementSignedAt)
+	investment.ReleasedAt = copyTime(stored.ReleasedAt)
+
+	return &investment
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+// InvestorRepository keeps investors in plaintext. The NIK, NPWP and email are each unique and compared
+// case and space insensitive, the way their blind indexes are in Postgres. Each investor records the id of the
+// key it was written with, so the investors written before RotateKey are listed for re-encryption.
+type InvestorRepository struct {
+	mu        sync.Mutex
+	investors map[string]*model.Investor
+	// keyIDs holds the key each investor was last written with, the encryption_key_id column
+	keyIDs      map[string]string
+	activeKeyID string
+}
+
+func NewInvestorRepository() repository.IInvestorRepository {
+	return &InvestorRepository{
+		investors: map[string]*model.Investor{},
+		keyIDs:    map[string]string{},
+	}
+}
+
+// CreateInvestor stores the investor under its id, or a generated one when the id is empty.
+func (ir *InvestorRepository) CreateInvestor(ctx context.Context, investor *model.Investor) (ID string, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	ID = investor.ID
+	if ID == "" {
+		ID = uuid.NewString()
+	}
+
+	if _, ok := ir.investors[ID]; ok || ir.conflicts(investor) {
+		return "", model.ErrorInvestorExist
+	}
+
+	now := time.Now()
+	ir.investors[ID] = &model.Investor{
+		ID:          ID,
+		Name:        investor.Name,
+		NIK:         investor.NIK,
+		NPWP:        investor.NPWP,
+		Email:       investor.Email,
+		PhoneNumber: investor.PhoneNumber,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+	ir.keyIDs[ID] = ir.activeKeyID
+
+	return
+}
+
+// RotateKey makes keyID the key investors are written with, like a keyfile with a new active_key_id.
+func (ir *InvestorRepository) RotateKey(keyID string) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	ir.activeKeyID = keyID
+}
+
+// conflicts reports whether another investor that is not erased has the NIK, NPWP or email of the investor.
+func (ir *InvestorRepository) conflicts(investor *model.Investor) bool {
+	for _, stored := range ir.investors {
+		if stored.ID == investor.ID || stored.ErasedAt != nil {
+			continue
+		}
+
+		if normalize(stored.NIK) == normalize(investor.NIK) ||
+			normalize(stored.NPWP) == normalize(investor.NPWP) ||
+			normalize(stored.Email) == normalize(investor.Email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ir *InvestorRepository) GetInvestorByID(ctx context.Context, id string) (investor *model.Investor, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	stored, ok := ir.investors[id]
+	if !ok {
+		return nil, model.ErrorInvestorNotFound
+	}
+
+	return copyInvestor(stored), nil
+}
+
+func (ir *InvestorRepository) GetInvestorByNIK(ctx context.Context, nik string) (investor *model.Investor, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	for _, stored := range ir.investors {
+		if stored.ErasedAt == nil && normalize(stored.NIK) == normalize(nik) {
+			return copyInvestor(stored), nil
+		}
+	}
+
+	return nil, model.ErrorInvestorNotFound
+}
+
+// ListInvestorsToReencrypt returns up to limit investors that are not erased and were written with another key
+// than the active one, in id order after afterID.
+func (ir *InvestorRepository) ListInvestorsToReencrypt(ctx context.Context, afterID string, limit int) (investors []*model.Investor, err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	investors = []*model.Investor{}
+	for id, stored := range ir.investors {
+		if stored.ErasedAt == nil && ir.keyIDs[id] != ir.activeKeyID && id > afterID {
+			investors = append(investors, copyInvestor(stored))
+		}
+	}
+	slices.SortFunc(investors, func(a, b *model.Investor) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return investors[:min(limit, len(investors))], nil
+}
+
+func (ir *InvestorRepository) ReencryptInvestor(ctx context.Context, investor *model.Investor) (err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	stored, ok := ir.investors[investor.ID]
+	if !ok {
+		return
+	}
+
+	if ir.conflicts(investor) {
+		return model.ErrorInvestorExist
+	}
+
+	stored.NIK = investor.NIK
+	stored.NPWP = investor.NPWP
+	stored.Email = investor.Email
+	stored.PhoneNumber = investor.PhoneNumber
+	ir.keyIDs[investor.ID] = ir.activeKeyID
+
+	return
+}
+
+// EraseInvestor clears the personal data of the investor. An erased investor cannot be erased again.
+func (ir *InvestorRepository) EraseInvestor(ctx context.Context, id string, erasedAt time.Time) (err error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	stored, ok := ir.investors[id]
+	if !ok || stored.ErasedAt != nil {
+		return model.ErrorPersonalDataErased
+	}
+
+	now := time.Now()
+	ir.investors[id] = &model.Investor{
+		ID:        id,
+		CreatedAt: stored.CreatedAt,
+		UpdatedAt: &now,
+		ErasedAt:  &erasedAt,
+	}
+
+	return
+}
+
+func copyInvestor(stored *model.Investor) *model.Investor {
+	investor := *stored
+	investor.CreatedAt = copyTime(stored.CreatedAt)
+	investor.UpdatedAt = copyTime(stored.UpdatedAt)
+	investor.ErasedAt = copyTime(stored.ErasedAt)
+
+	return &investor
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+// LoanRepository keeps loans in a map and their ids in the order they were created, the created_at order of
+// repository.LoanRepository. It stores what repository.LoanRepository inserts and returns unset product and
+// actor ids as model.NilUUID, the way the columns are read from Postgres.
+type LoanRepository struct {
+	mu    sync.Mutex
+	loans map[string]*model.Loan
+	order []string
+}
+
+func NewLoanRepository() repository.ILoanRepository {
+	return &LoanRepository{
+		loans: map[string]*model.Loan{},
+	}
+}
+
+// CreateLoan stores the proposal columns only, like the INSERT of repository.LoanRepository.
+func (lr *LoanRepository) CreateLoan(ctx context.Context, loan *model.Loan) (ID string, err error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	now := time.Now()
+	stored := &model.Loan{
+		ID:              uuid.NewString(),
+		BorrowerID:      loan.BorrowerID,
+		ProductID:       loan.ProductID,
+		TenorMonths:     loan.TenorMonths,
+		PrincipalAmount: loan.PrincipalAmount,
+		InterestRate:    loan.InterestRate,
+		ROIRate:         loan.ROIRate,
+		State:           loan.State,
+		CreatedBy:       loan.CreatedBy,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
+		Version:         1,
+	}
+	if loan.Risk != nil && loan.Risk.Grade != "" {
+		stored.Risk = copyRiskAssessment(loan.Risk)
+	}
+	lr.loans[stored.ID] = stored
+	lr.order = append(lr.order, stored.ID)
+
+	return stored.ID, nil
+}
+
+func (lr *LoanRepository) GetLoanByID(ctx context.Context, id string) (loan *model.Loan, err error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	stored, ok := lr.loans[id]
+	if !ok {
+		return nil, model.ErrorLoanNotFound
+	}
+
+	return copyLoan(stored), nil
+}
+
+// UpdateLoanState writes the columns of model.StateUpdates while the loan still has the version and state it
+// was read with, and reports a conflict like repository.LoanRepository otherwise.
+func (lr *LoanRepository) UpdateLoanState(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) (err error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	stored, ok := lr.loans[loan.ID]
+	if !ok {
+		return model.ErrorLoanNotFound
+	}
+
+	if stored.State != loan.State {
+		return model.ErrorLoanUpdateConflict
+	}
+
+	if stored.Version != loan.Version {
+		return model.ErrorLoanVersionMismatch
+	}
+
+	fields, ok := model.StateUpdates[newLoanState]
+	if !ok {
+		return fmt.Errorf("loan state %q has no columns to update", newLoanState)
+	}
+
+	for _, field := range fields {
+		switch field {
+		case "rejected_reason":
+			stored.RejectedReason = loan.RejectedReason
+		case "canceled_reason":
+			stored.CanceledReason = loan.CanceledReason
+		case "funding_deadline":
+			stored.FundingDeadline = copyTime(loan.FundingDeadline)
+		}
+	}
+
+	userID := ctx.Value("userID").(string)
+	now := time.Now()
+	repository.SetLoanStateFields(stored, newLoanState, userID, now)
+	repository.SetLoanStateFields(loan, newLoanState, userID, now)
+	stored.State = newLoanState
+	stored.Version++
+	stored.UpdatedAt = &now
+
+	return
+}
+
+func (lr *LoanRepository) UpdateLoanTotalInvestedAmount(ctx context.Context, loan *model.Loan) (err error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	stored, ok := lr.loans[loan.ID]
+	if !ok || stored.Version != loan.Version {
+		return model.ErrorLoanUpdateConflict
+	}
+
+	now := time.Now()
+	stored.TotalInvestedAmount = loan.TotalInvestedAmount
+	stored.Version++
+	stored.UpdatedAt = &now
+
+	return
+}
+
+func (lr *LoanRepository) ListLoansByState(ctx context.Context, state model.LoanState) (loans []*model.Loan, err error) {
+	loans = lr.listLoans(func(loan *model.Loan) bool {
+		return loan.State == state
+	})
+	slices.Reverse(loans)
+
+	return
+}
+
+func (lr *LoanRepository) ListExpiredPublishedLoans(ctx context.Context, now time.Time) (loans []*model.Loan, err error) {
+	loans = lr.listLoans(func(loan *model.Loan) bool {
+		return loan.State == model.LoanStatePublished && loan.FundingDeadline != nil && loan.FundingDeadline.Before(now)
+	})
+	slices.SortStableFunc(loans, func(a, b *model.Loan) int {
+		return a.FundingDeadline.Compare(*b.FundingDeadline)
+	})
+
+	return
+}
+
+func (lr *LoanRepository) ListLoansByBorrowerID(ctx context.Context, borrowerID string) (loans []*model.Loan, err error) {
+	loans = lr.listLoans(func(loan *model.Loan) bool {
+		return loan.BorrowerID == borrowerID
+	})
+
+	return
+}
+
+// listLoans returns copies of the matching loans, oldest first.
+func (lr *LoanRepository) listLoans(match func(loan *model.Loan) bool) (loans []*model.Loan) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	loans = []*model.Loan{}
+	for _, id := range lr.order {
+		if loan := lr.loans[id]; match(loan) {
+			loans = append(loans, copyLoan(loan))
+		}
+	}
+
+	return
+}
+
+func (lr *LoanRepository) GetBorrowerLoanStats(ctx context.Context, borrowerID string) (stats *model.BorrowerLoanStats, err error) {
+	stats = &model.BorrowerLoanStats{}
+	for _, loan := range lr.listLoans(func(loan *model.Loan) bool { return loan.BorrowerID == borrowerID }) {
+		switch loan.State {
+		case model.LoanStateDisbursed:
+			stats.DisbursedLoans++
+		case model.LoanStateProposed, model.LoanStateApproved, model.LoanStatePublished, model.LoanStateInvested:
+			stats.ActiveLoans++
+		case model.LoanStateRejected:
+			stats.RejectedLoans++
+		case model.LoanStateCanceled:
+			stats.CanceledLoans++
+		}
+	}
+
+	return
+}
+
+func (lr *LoanRepository) CountLoansByState(ctx context.Context) (counts map[model.LoanState]int64, err error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	counts = map[model.LoanState]int64{}
+	for _, loan := range lr.loans {
+		counts[loan.State]++
+	}
+
+	return
+}
+
+// copyLoan copies the stored loan for a caller, unset ids read as model.NilUUID.
+func copyLoan(stored *model.Loan) *model.Loan {
+	loan := *stored
+	for _, id := range []*string{&loan.ProductID, &loan.ValidatedBy, &loan.CreatedBy, &loan.ApprovedBy, &loan.RejectedBy, &loan.CanceledBy, &loan.PublishedBy, &loan.DisbursedBy} {
+		if *id == "" {
+			*id = model.NilUUID
+		}
+	}
+	if stored.Risk != nil {
+		loan.Risk = copyRiskAssessment(stored.Risk)
+	}
+
+	return &loan
+}
+
+func copyRiskAssessment(risk *model.RiskAssessment) *model.RiskAssessment {
+	copied := *risk
+	copied.Explanation = slices.Clone(risk.Explanation)
+
+	return &copied
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	copied := *t
+	return &copied
+}
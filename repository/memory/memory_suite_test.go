@@ -0,0 +1,13 @@
+package memory_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MemoryRepository Suite")
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
+)
+
+type INotificationRepository interface {
+	GetNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (preference *model.NotificationPreference, err error)
+	UpsertNotificationPreference(ctx context.Context, preference *model.NotificationPreference) (err error)
+	CreateEmailNotification(ctx context.Context, notification *model.EmailNotification) (ID string, err error)
+	GetEmailNotificationByID(ctx context.Context, id string) (notification *model.EmailNotification, err error)
+	UpdateEmailNotification(ctx context.Context, notification *model.EmailNotification) (err error)
+	ListEmailNotificationsByRecipient(ctx context.Context, recipientType model.RecipientType, recipientID string) (notifications []*model.EmailNotification, err error)
+	RedactEmailNotifications(ctx context.Context, recipientType model.RecipientType, recipientID string) (err error)
+	DeleteNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (err error)
+}
+
+type NotificationRepository struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepository(app *application.App) INotificationRepository {
+	return &NotificationRepository{
+		DB: app.DB,
+	}
+}
+
+func (nr *NotificationRepository) GetNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (preference *model.NotificationPreference, err error) {
+	query := `
+		SELECT
+			recipient_type,
+			recipient_id,
+			language,
+			email_enabled,
+			disabled_events,
+			created_at,
+			updated_at
+		FROM
+			notification_preferences
+		WHERE
+			recipient_type = $1
+			AND recipient_id = $2
+	`
+
+	preference = &model.NotificationPreference{}
+	err = executor(ctx, nr.DB).QueryRowContext(ctx, query, recipientType, recipientID).Scan(
+		&preference.RecipientType,
+		&preference.RecipientID,
+		&preference.Language,
+		&preference.EmailEnabled,
+		pq.Array(&preference.DisabledEvents),
+		&preference.CreatedAt,
+		&preference.UpdatedAt,
+	)
+	if err != nil {
+		preference = nil
+		if err == sql.ErrNoRows {
+			err = model.ErrorNotificationPreferenceNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetNotificationPreference", "error", err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) UpsertNotificationPreference(ctx context.Context, preference *model.NotificationPreference) (err error) {
+	query := `
+		INSERT INTO
+			notification_preferences (
+				recipient_type,
+				recipient_id,
+				language,
+				email_enabled,
+				disabled_events
+			)
+		VALUES
+			($1, $2, $3, $4, $5)
+		ON CONFLICT (recipient_type, recipient_id) DO UPDATE SET
+			language = EXCLUDED.language,
+			email_enabled = EXCLUDED.email_enabled,
+			disabled_events = EXCLUDED.disabled_events
+		RETURNING
+			created_at,
+			updated_at
+	`
+
+	disabledEvents := preference.DisabledEvents
+	if disabledEvents == nil {
+		disabledEvents = []string{}
+	}
+
+	err = executor(ctx, nr.DB).QueryRowContext(ctx, query,
+		preference.RecipientType,
+		preference.RecipientID,
+		preference.Language,
+		preference.EmailEnabled,
+		pq.Array(disabledEvents),
+	).Scan(&preference.CreatedAt, &preference.UpdatedAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpsertNotificationPreference error", "error", err)
+		return
+	}
+
+	return
+}
+
+// CreateEmailNotification returns model.ErrorEmailNotificationExist when an email with the same dedupe key was already created.
+func (nr *NotificationRepository) CreateEmailNotification(ctx context.Context, notification *model.EmailNotification) (ID string, err error) {
+	query := `
+		INSERT INTO
+			email_notifications (
+				event,
+				recipient_type,
+				recipient_id,
+				email,
+				language,
+				subject,
+				body,
+				dedupe_key
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (dedupe_key) DO NOTHING
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, nr.DB).QueryRowContext(ctx, query,
+		notification.Event,
+		notification.RecipientType,
+		notification.RecipientID,
+		notification.Email,
+		notification.Language,
+		notification.Subject,
+		notification.Body,
+		notification.DedupeKey,
+	).Scan(&ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrorEmailNotificationExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateEmailNotification error", "error", err)
+		return
+	}
+
+	return
+}
+
+const emailNotificationColumns = `
+			id,
+			event,
+			recipient_type,
+			recipient_id,
+			email,
+			language,
+			subject,
+			body,
+			dedupe_key,
+			status,
+			attempts,
+			COALESCE(last_error, ''),
+			sent_at,
+			created_at,
+			updated_at
+`
+
+func scanEmailNotification(scanner interface{ Scan(dest ...any) error }) (notification *model.EmailNotification, err error) {
+	notification = &model.EmailNotification{}
+	err = scanner.Scan(
+		&notification.ID,
+		&notification.Event,
+		&notification.RecipientType,
+		&notification.RecipientID,
+		&notification.Email,
+		&notification.Language,
+		&notification.Subject,
+		&notification.Body,
+		&notification.DedupeKey,
+		&notification.Status,
+		&notification.Attempts,
+		&notification.LastError,
+		&notification.SentAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if err != nil {
+		notification = nil
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) GetEmailNotificationByID(ctx context.Context, id string) (notification *model.EmailNotification, err error) {
+	query := `
+		SELECT` + emailNotificationColumns + `
+		FROM
+			email_notifications
+		WHERE
+			id = $1
+	`
+
+	notification, err = scanEmailNotification(executor(ctx, nr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetEmailNotificationByID", "error", err)
+			err = model.ErrorEmailNotificationNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetEmailNotificationByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) UpdateEmailNotification(ctx context.Context, notification *model.EmailNotification) (err error) {
+	query := `
+		UPDATE
+			email_notifications
+		SET
+			status = $2,
+			attempts = $3,
+			last_error = NULLIF($4, ''),
+			sent_at = $5
+		WHERE
+			id = $1
+	`
+
+	rows, err := executor(ctx, nr.DB).ExecContext(ctx, query,
+		notification.ID,
+		notification.Status,
+		notification.Attempts,
+		notification.LastError,
+		notification.SentAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateEmailNotification ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateEmailNotification RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorEmailNotificationNotFound
+		slog.WarnContext(ctx, "UpdateEmailNotification affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) ListEmailNotificationsByRecipient(ctx context.Context, recipientType model.RecipientType, recipientID string) (notifications []*model.EmailNotification, err error) {
+	query := `
+		SELECT` + emailNotificationColumns + `
+		FROM
+			email_notifications
+		WHERE
+			recipient_type = $1
+			AND recipient_id = $2
+		ORDER BY
+			created_at DESC
+	`
+
+	notifications = []*model.EmailNotification{}
+	rows, err := executor(ctx, nr.DB).QueryContext(ctx, query, recipientType, recipientID)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListEmailNotificationsByRecipient QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var notification *model.EmailNotification
+		notification, err = scanEmailNotification(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListEmailNotificationsByRecipient Scan error", "error", err)
+			return
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListEmailNotificationsByRecipient rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// RedactEmailNotifications clears the address and the rendered content of every email of the recipient, the
+// rows stay as the record that the emails were sent.
+func (nr *NotificationRepository) RedactEmailNotifications(ctx context.Context, recipientType model.RecipientType, recipientID string) (err error) {
+	query := `
+		UPDATE
+			email_notifications
+		SET
+			email = '',
+			subject = '',
+			body = ''
+		WHERE
+			recipient_type = $1
+			AND recipient_id = $2
+	`
+
+	_, err = executor(ctx, nr.DB).ExecContext(ctx, query, recipientType, recipientID)
+	if err != nil {
+		slog.ErrorContext(ctx, "RedactEmailNotifications error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (nr *NotificationRepository) DeleteNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (err error) {
+	query := `
+		DELETE FROM
+			notification_preferences
+		WHERE
+			recipient_type = $1
+			AND recipient_id = $2
+	`
+
+	_, err = executor(ctx, nr.DB).ExecContext(ctx, query, recipientType, recipientID)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteNotificationPreference error", "error", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+type IOutboxRepository interface {
+	CreateEvent(ctx context.Context, event *model.Event) (ID string, err error)
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) (events []*model.Event, err error)
+	MarkEventPublished(ctx context.Context, id string) (err error)
+	MarkEventFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) (err error)
+	ReplayEvents(ctx context.Context, eventID string, aggregateID string) (events []*model.Event, err error)
+	MarkEventMetricsRecorded(ctx context.Context, id string) (marked bool, err error)
+}
+
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewOutboxRepository(app *application.App) IOutboxRepository {
+	return &OutboxRepository{
+		DB: app.DB,
+	}
+}
+
+const eventColumns = `
+			id,
+			event_type,
+			aggregate_type,
+			aggregate_id,
+			payload,
+			occurred_at,
+			published_at,
+			attempts,
+			COALESCE(last_error, ''),
+			next_attempt_at,
+			trace_parent
+`
+
+func scanEvent(scanner interface{ Scan(dest ...any) error }) (event *model.Event, err error) {
+	var payload []byte
+
+	event = &model.Event{}
+	err = scanner.Scan(
+		&event.ID,
+		&event.Type,
+		&event.AggregateType,
+		&event.AggregateID,
+		&payload,
+		&event.OccurredAt,
+		&event.PublishedAt,
+		&event.Attempts,
+		&event.LastError,
+		&event.NextAttemptAt,
+		&event.TraceParent,
+	)
+	if err != nil {
+		return
+	}
+
+	event.Payload = payload
+
+	return
+}
+
+// CreateEvent writes the event to the outbox, call it with the transaction of the change that produced the event.
+func (or *OutboxRepository) CreateEvent(ctx context.Context, event *model.Event) (ID string, err error) {
+	query := `
+		INSERT INTO
+			outbox_events (
+				event_type,
+				aggregate_type,
+				aggregate_id,
+				payload,
+				trace_parent
+			)
+		VALUES
+			($1, $2, $3, $4, $5)
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, or.DB).QueryRowContext(ctx, query,
+		event.Type,
+		event.AggregateType,
+		event.AggregateID,
+		[]byte(event.Payload),
+		event.TraceParent,
+	).Scan(&ID)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateEvent error", "error", err)
+		return
+	}
+
+	return
+}
+
+// ClaimEvents leases up to limit unpublished events that are due, oldest first. A lease that is not
+// followed by MarkEventPublished or MarkEventFailed expires, so a crashed relay cannot lose events.
+func (or *OutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) (events []*model.Event, err error) {
+	query := `
+		UPDATE
+			outbox_events
+		SET
+			locked_until = NOW() + $2::float8 * INTERVAL '1 second'
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					outbox_events
+				WHERE
+					published_at IS NULL
+					AND next_attempt_at <= NOW()
+					AND (locked_until IS NULL OR locked_until < NOW())
+				ORDER BY
+					occurred_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING` + eventColumns
+
+	events = []*model.Event{}
+	rows, err := executor(ctx, or.DB).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		slog.ErrorContext(ctx, "ClaimEvents QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event *model.Event
+		event, err = scanEvent(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ClaimEvents Scan error", "error", err)
+			return
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ClaimEvents rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (or *OutboxRepository) MarkEventPublished(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE
+			outbox_events
+		SET
+			published_at = NOW(),
+			attempts = attempts + 1,
+			last_error = NULL,
+			locked_until = NULL
+		WHERE
+			id = $1
+	`
+
+	_, err = executor(ctx, or.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "MarkEventPublished ExecContext error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (or *OutboxRepository) MarkEventFailed(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) (err error) {
+	query := `
+		UPDATE
+			outbox_events
+		SET
+			attempts = attempts + 1,
+			last_error = $2,
+			next_attempt_at = $3,
+			locked_until = NULL
+		WHERE
+			id = $1
+	`
+
+	_, err = executor(ctx, or.DB).ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "MarkEventFailed ExecContext error", "error", err)
+		return
+	}
+
+	return
+}
+
+// ReplayEvents makes the event, or every event of the aggregate, pending again so the relay publishes it
+// once more. Events leased by a relay right now are left alone. The replayed events are returned with their
+// delivery state from before the replay.
+func (or *OutboxRepository) ReplayEvents(ctx context.Context, eventID string, aggregateID string) (events []*model.Event, err error) {
+	query := `
+		UPDATE
+			outbox_events
+		SET
+			published_at = NULL,
+			attempts = 0,
+			last_error = NULL,
+			next_attempt_at = NOW()
+		FROM (
+			SELECT
+				id,
+				published_at,
+				attempts,
+				last_error
+			FROM
+				outbox_events
+			WHERE
+				(id = NULLIF($1, '')::uuid OR aggregate_id = NULLIF($2, '')::uuid)
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY
+				occurred_at
+			FOR UPDATE
+		) AS replayed
+		WHERE
+			outbox_events.id = replayed.id
+		RETURNING
+			outbox_events.id,
+			outbox_events.event_type,
+			replayed.published_at,
+			replayed.attempts,
+			COALESCE(replayed.last_error, '')
+	`
+
+	events = []*model.Event{}
+	rows, err := executor(ctx, or.DB).QueryContext(ctx, query, eventID, aggregateID)
+	if err != nil {
+		slog.ErrorContext(ctx, "ReplayEvents QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := &model.Event{}
+		err = rows.Scan(&event.ID, &event.Type, &event.PublishedAt, &event.Attempts, &event.LastError)
+		if err != nil {
+			slog.ErrorContext(ctx, "ReplayEvents Scan error", "error", err)
+			return
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ReplayEvents rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// MarkEventMetricsRecorded claims the event for the business metrics, marked is false when they already
+// counted it on an earlier delivery. A replay leaves the mark in place.
+func (or *OutboxRepository) MarkEventMetricsRecorded(ctx context.Context, id string) (marked bool, err error) {
+	query := `
+		UPDATE
+			outbox_events
+		SET
+			metrics_recorded_at = NOW()
+		WHERE
+			id = $1
+			AND metrics_recorded_at IS NULL
+	`
+
+	result, err := executor(ctx, or.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "MarkEventMetricsRecorded ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "MarkEventMetricsRecorded RowsAffected error", "error", err)
+		return
+	}
+
+	marked = affected > 0
+
+	return
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
+)
+
+type IPersonalDataRepository interface {
+	CreateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) (err error)
+	GetErasureRequestByID(ctx context.Context, id string) (erasureRequest *model.PersonalDataErasureRequest, err error)
+	ListErasureRequests(ctx context.Context, status model.ErasureRequestStatus) (erasureRequests []*model.PersonalDataErasureRequest, err error)
+	ListErasureRequestsBySubject(ctx context.Context, subjectType model.RecipientType, subjectID string) (erasureRequests []*model.PersonalDataErasureRequest, err error)
+	UpdateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) (err error)
+}
+
+type PersonalDataRepository struct {
+	DB *sql.DB
+}
+
+func NewPersonalDataRepository(app *application.App) IPersonalDataRepository {
+	return &PersonalDataRepository{
+		DB: app.DB,
+	}
+}
+
+const erasureRequestColumns = `
+			id,
+			subject_type,
+			subject_id,
+			status,
+			COALESCE(reason, ''),
+			COALESCE(rejected_reason, ''),
+			processed_at,
+			COALESCE(processed_by::text, ''),
+			created_at,
+			updated_at
+`
+
+func scanErasureRequest(scanner interface{ Scan(dest ...any) error }) (erasureRequest *model.PersonalDataErasureRequest, err error) {
+	erasureRequest = &model.PersonalDataErasureRequest{}
+	err = scanner.Scan(
+		&erasureRequest.ID,
+		&erasureRequest.SubjectType,
+		&erasureRequest.SubjectID,
+		&erasureRequest.Status,
+		&erasureRequest.Reason,
+		&erasureRequest.RejectedReason,
+		&erasureRequest.ProcessedAt,
+		&erasureRequest.ProcessedBy,
+		&erasureRequest.CreatedAt,
+		&erasureRequest.UpdatedAt,
+	)
+	if err != nil {
+		erasureRequest = nil
+	}
+
+	return
+}
+
+// CreateErasureRequest returns model.ErrorErasureRequestExist when the subject has a pending request already.
+func (pdr *PersonalDataRepository) CreateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) (err error) {
+	query := `
+		INSERT INTO
+			personal_data_erasure_requests (
+				subject_type,
+				subject_id,
+				status,
+				reason
+			)
+		VALUES
+			($1, $2, $3, NULLIF($4, ''))
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err = executor(ctx, pdr.DB).QueryRowContext(ctx, query,
+		erasureRequest.SubjectType,
+		erasureRequest.SubjectID,
+		erasureRequest.Status,
+		erasureRequest.Reason,
+	).Scan(&erasureRequest.ID, &erasureRequest.CreatedAt, &erasureRequest.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqUniqueViolation {
+			slog.WarnContext(ctx, "CreateErasureRequest", "error", err)
+			err = model.ErrorErasureRequestExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateErasureRequest error", "error", err)
+		return
+	}
+
+	return
+}
+
+// GetErasureRequestByID locks the request until the end of the transaction, so it is processed once.
+func (pdr *PersonalDataRepository) GetErasureRequestByID(ctx context.Context, id string) (erasureRequest *model.PersonalDataErasureRequest, err error) {
+	query := `
+		SELECT` + erasureRequestColumns + `
+		FROM
+			personal_data_erasure_requests
+		WHERE
+			id = $1
+		FOR UPDATE
+	`
+
+	erasureRequest, err = scanErasureRequest(executor(ctx, pdr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetErasureRequestByID", "error", err)
+			err = model.ErrorErasureRequestNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetErasureRequestByID", "error", err)
+		return
+	}
+
+	return
+}
+
+// ListErasureRequests returns the requests with the status, or every request when the status is empty,
+// oldest first.
+func (pdr *PersonalDataRepository) ListErasureRequests(ctx context.Context, status model.ErasureRequestStatus) (erasureRequests []*model.PersonalDataErasureRequest, err error) {
+	query := `
+		SELECT` + erasureRequestColumns + `
+		FROM
+			personal_data_erasure_requests
+		WHERE
+			($1 = '' OR status = $1)
+		ORDER BY
+			created_at
+	`
+
+	return pdr.queryErasureRequests(ctx, "ListErasureRequests", query, status)
+}
+
+func (pdr *PersonalDataRepository) ListErasureRequestsBySubject(ctx context.Context, subjectType model.RecipientType, subjectID string) (erasureRequests []*model.PersonalDataErasureRequest, err error) {
+	query := `
+		SELECT` + erasureRequestColumns + `
+		FROM
+			personal_data_erasure_requests
+		WHERE
+			subject_type = $1
+			AND subject_id = $2
+		ORDER BY
+			created_at
+	`
+
+	return pdr.queryErasureRequests(ctx, "ListErasureRequestsBySubject", query, subjectType, subjectID)
+}
+
+func (pdr *PersonalDataRepository) queryErasureRequests(ctx context.Context, caller string, query string, args ...any) (erasureRequests []*model.PersonalDataErasureRequest, err error) {
+	erasureRequests = []*model.PersonalDataErasureRequest{}
+	rows, err := executor(ctx, pdr.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, caller+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var erasureRequest *model.PersonalDataErasureRequest
+		erasureRequest, err = scanErasureRequest(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, caller+" Scan error", "error", err)
+			return
+		}
+		erasureRequests = append(erasureRequests, erasureRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, caller+" rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+// UpdateErasureRequest records the outcome of a pending request, a request that is not pending anymore
+// returns model.ErrorErasureRequestNotPending.
+func (pdr *PersonalDataRepository) UpdateErasureRequest(ctx context.Context, erasureRequest *model.PersonalDataErasureRequest) (err error) {
+	query := `
+		UPDATE
+			personal_data_erasure_requests
+		SET
+			status = $2,
+			rejected_reason = NULLIF($3, ''),
+			processed_at = $4,
+			processed_by = NULLIF($5, '')::uuid
+		WHERE
+			id = $1
+			AND status = 'pending'
+	`
+
+	rows, err := executor(ctx, pdr.DB).ExecContext(ctx, query,
+		erasureRequest.ID,
+		erasureRequest.Status,
+		erasureRequest.RejectedReason,
+		erasureRequest.ProcessedAt,
+		erasureRequest.ProcessedBy,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateErasureRequest ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateErasureRequest RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorErasureRequestNotPending
+		slog.WarnContext(ctx, "UpdateErasureRequest affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/infrastructure"
+)
+
+// testDB is the database of TEST_DATABASE_URL, the Postgres specs are skipped when it is not set.
+var testDB *sql.DB
+
+func TestRepository(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repository Suite")
+}
+
+var _ = BeforeSuite(func() {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		return
+	}
+
+	var err error
+	testDB, err = sql.Open("postgres", databaseURL)
+	Expect(err).To(BeNil())
+	Expect(testDB.Ping()).To(Succeed())
+
+	ctx := context.Background()
+	migrator, err := infrastructure.NewMigrator(ctx, &application.App{
+		DB: testDB,
+		Config: &configuration.Configuration{
+			Database: configuration.Database{MigrationLockTimeout: time.Minute},
+		},
+	})
+	Expect(err).To(BeNil())
+	defer migrator.Close()
+	Expect(migrator.Up()).To(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	if testDB != nil {
+		Expect(testDB.Close()).To(Succeed())
+	}
+})
@@ -0,0 +1,669 @@
+// Package repositorytest is the contract suite of the loan, borrower, investor and investment repositories.
+// It runs against the Postgres repositories and their in-memory versions in repository/memory, so both
+// behave the same to the services.
+package repositorytest
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+// Repositories are the implementations under test. ProductID is a loan product the loans can reference and
+// RotateKey switches the borrower and investor repositories to a new active encryption key.
+type Repositories struct {
+	LoanRepository       repository.ILoanRepository
+	BorrowerRepository   repository.IBorrowerRepository
+	InvestorRepository   repository.IInvestorRepository
+	InvestmentRepository repository.IInvestmentRepository
+	ProductID            string
+	RotateKey            func()
+}
+
+// DescribeRepositories registers the contract specs. setup runs before every spec and returns empty
+// repositories and a cleanup, which runs after the spec.
+func DescribeRepositories(implementation string, setup func() (repositories *Repositories, cleanup func())) bool {
+	return Describe(implementation+" repositories", func() {
+		var (
+			ctx          context.Context
+			repositories *Repositories
+			cleanup      func()
+			dob          time.Time
+		)
+
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), "userID", model.SystemEmployeeID)
+			repositories, cleanup = setup()
+			dob = time.Date(1990, time.March, 14, 0, 0, 0, 0, time.UTC)
+		})
+
+		AfterEach(func() {
+			if cleanup != nil {
+				cleanup()
+			}
+		})
+
+		createBorrower := func(nik string) string {
+			borrowerID, err := repositories.BorrowerRepository.CreateBorrower(ctx, &model.Borrower{
+				Name:       "Budi",
+				Address:    "Jakarta",
+				Occupation: "Teacher",
+				NIK:        nik,
+				DOB:        &dob,
+				Email:      nik + "@example.com",
+			})
+			Expect(err).To(BeNil())
+
+			return borrowerID
+		}
+
+		createInvestor := func(nik string) string {
+			investorID, err := repositories.InvestorRepository.CreateInvestor(ctx, &model.Investor{
+				Name:        "Sari",
+				NIK:         nik,
+				NPWP:        "npwp-" + nik,
+				Email:       nik + "@example.com",
+				PhoneNumber: "08123456789",
+			})
+			Expect(err).To(BeNil())
+
+			return investorID
+		}
+
+		createLoan := func(borrowerID string) *model.Loan {
+			loanID, err := repositories.LoanRepository.CreateLoan(ctx, &model.Loan{
+				BorrowerID:      borrowerID,
+				ProductID:       repositories.ProductID,
+				TenorMonths:     12,
+				PrincipalAmount: 10000000,
+				InterestRate:    12,
+				ROIRate:         8,
+				State:           model.LoanStateProposed,
+				CreatedBy:       model.SystemEmployeeID,
+			})
+			Expect(err).To(BeNil())
+
+			loan, err := repositories.LoanRepository.GetLoanByID(ctx, loanID)
+			Expect(err).To(BeNil())
+
+			return loan
+		}
+
+		updateLoanState := func(loan *model.Loan, newLoanState model.LoanState) *model.Loan {
+			Expect(repositories.LoanRepository.UpdateLoanState(ctx, loan, newLoanState)).To(Succeed())
+
+			loan, err := repositories.LoanRepository.GetLoanByID(ctx, loan.ID)
+			Expect(err).To(BeNil())
+
+			return loan
+		}
+
+		borrowerIDs := func(borrowers []*model.Borrower) (ids []string) {
+			for _, borrower := range borrowers {
+				ids = append(ids, borrower.ID)
+			}
+
+			return
+		}
+
+		investorIDs := func(investors []*model.Investor) (ids []string) {
+			for _, investor := range investors {
+				ids = append(ids, investor.ID)
+			}
+
+			return
+		}
+
+		loanIDs := func(loans []*model.Loan) (ids []string) {
+			for _, loan := range loans {
+				ids = append(ids, loan.ID)
+			}
+
+			return
+		}
+
+		Describe("LoanRepository", func() {
+			It("creates a proposed loan with the unset columns read as empty", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+
+				Expect(loan.ID).NotTo(BeEmpty())
+				Expect(loan.ProductID).To(Equal(repositories.ProductID))
+				Expect(loan.TenorMonths).To(Equal(int64(12)))
+				Expect(loan.PrincipalAmount).To(Equal(float64(10000000)))
+				Expect(loan.InterestRate).To(Equal(float64(12)))
+				Expect(loan.ROIRate).To(Equal(float64(8)))
+				Expect(loan.State).To(Equal(model.LoanStateProposed))
+				Expect(loan.CreatedBy).To(Equal(model.SystemEmployeeID))
+				Expect(loan.CreatedAt).NotTo(BeNil())
+				Expect(loan.Version).To(Equal(int64(1)))
+				Expect(loan.TotalInvestedAmount).To(BeZero())
+				Expect(loan.ApprovedBy).To(Equal(model.NilUUID))
+				Expect(loan.ApprovedAt).To(BeNil())
+				Expect(loan.VisitProofURL).To(BeEmpty())
+				Expect(loan.FundingDeadline).To(BeNil())
+				Expect(loan.Risk).To(BeNil())
+			})
+
+			It("stores the risk assessment of the loan", func() {
+				assessedAt := time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC)
+				loanID, err := repositories.LoanRepository.CreateLoan(ctx, &model.Loan{
+					BorrowerID:      createBorrower("3171000000000001"),
+					ProductID:       repositories.ProductID,
+					TenorMonths:     6,
+					PrincipalAmount: 5000000,
+					InterestRate:    10,
+					ROIRate:         7,
+					State:           model.LoanStateProposed,
+					CreatedBy:       model.SystemEmployeeID,
+					Risk: &model.RiskAssessment{
+						Score:                    640,
+						Grade:                    model.RiskGradeB,
+						Explanation:              []model.RiskFactor{{Factor: "age", Value: "35", Points: 20}},
+						ScorecardVersion:         "v1",
+						SuggestedMinInterestRate: 9,
+						SuggestedMaxInterestRate: 12,
+						AssessedAt:               &assessedAt,
+					},
+				})
+				Expect(err).To(BeNil())
+
+				loan, err := repositories.LoanRepository.GetLoanByID(ctx, loanID)
+				Expect(err).To(BeNil())
+				Expect(loan.Risk).NotTo(BeNil())
+				Expect(loan.Risk.Score).To(Equal(640))
+				Expect(loan.Risk.Grade).To(Equal(model.RiskGradeB))
+				Expect(loan.Risk.Explanation).To(Equal([]model.RiskFactor{{Factor: "age", Value: "35", Points: 20}}))
+				Expect(loan.Risk.ScorecardVersion).To(Equal("v1"))
+				Expect(loan.Risk.SuggestedMinInterestRate).To(Equal(float64(9)))
+				Expect(loan.Risk.SuggestedMaxInterestRate).To(Equal(float64(12)))
+				Expect(*loan.Risk.AssessedAt).To(BeTemporally("==", assessedAt))
+			})
+
+			It("returns ErrorLoanNotFound for an unknown loan", func() {
+				_, err := repositories.LoanRepository.GetLoanByID(ctx, uuid.NewString())
+				Expect(err).To(Equal(model.ErrorLoanNotFound))
+			})
+
+			It("updates the state and its actor columns and bumps the version", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+
+				Expect(repositories.LoanRepository.UpdateLoanState(ctx, loan, model.LoanStateApproved)).To(Succeed())
+				Expect(loan.ApprovedBy).To(Equal(model.SystemEmployeeID))
+				Expect(loan.ApprovedAt).NotTo(BeNil())
+
+				approved, err := repositories.LoanRepository.GetLoanByID(ctx, loan.ID)
+				Expect(err).To(BeNil())
+				Expect(approved.State).To(Equal(model.LoanStateApproved))
+				Expect(approved.ApprovedBy).To(Equal(model.SystemEmployeeID))
+				Expect(approved.ApprovedAt).NotTo(BeNil())
+				Expect(approved.Version).To(Equal(int64(2)))
+			})
+
+			It("stores the reason of a rejected loan", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				loan.RejectedReason = "incomplete documents"
+
+				rejected := updateLoanState(loan, model.LoanStateRejected)
+				Expect(rejected.State).To(Equal(model.LoanStateRejected))
+				Expect(rejected.RejectedReason).To(Equal("incomplete documents"))
+				Expect(rejected.RejectedBy).To(Equal(model.SystemEmployeeID))
+				Expect(rejected.RejectedAt).NotTo(BeNil())
+			})
+
+			It("stores the reason of a canceled loan", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				loan.CanceledReason = "no longer needed"
+
+				canceled := updateLoanState(loan, model.LoanStateCanceled)
+				Expect(canceled.State).To(Equal(model.LoanStateCanceled))
+				Expect(canceled.CanceledReason).To(Equal("no longer needed"))
+				Expect(canceled.CanceledBy).To(Equal(model.SystemEmployeeID))
+			})
+
+			It("writes only the columns of the new state", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				loan.RejectedReason = "not written"
+
+				approved := updateLoanState(loan, model.LoanStateApproved)
+				Expect(approved.RejectedReason).To(BeEmpty())
+				Expect(approved.RejectedBy).To(Equal(model.NilUUID))
+			})
+
+			It("stores the funding deadline of a published loan", func() {
+				loan := updateLoanState(createLoan(createBorrower("3171000000000001")), model.LoanStateApproved)
+				deadline := time.Now().Add(14 * 24 * time.Hour).Truncate(time.Second)
+				loan.FundingDeadline = &deadline
+
+				published := updateLoanState(loan, model.LoanStatePublished)
+				Expect(published.State).To(Equal(model.LoanStatePublished))
+				Expect(published.PublishedBy).To(Equal(model.SystemEmployeeID))
+				Expect(published.PublishedAt).NotTo(BeNil())
+				Expect(*published.FundingDeadline).To(BeTemporally("==", deadline))
+				Expect(published.Version).To(Equal(int64(3)))
+			})
+
+			It("returns ErrorLoanUpdateConflict when the state changed since the loan was read", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				stale := *loan
+
+				Expect(repositories.LoanRepository.UpdateLoanState(ctx, loan, model.LoanStateApproved)).To(Succeed())
+				Expect(repositories.LoanRepository.UpdateLoanState(ctx, &stale, model.LoanStateRejected)).To(Equal(model.ErrorLoanUpdateConflict))
+			})
+
+			It("returns ErrorLoanVersionMismatch when only the version changed since the loan was read", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				stale := *loan
+
+				loan.TotalInvestedAmount = 1000000
+				Expect(repositories.LoanRepository.UpdateLoanTotalInvestedAmount(ctx, loan)).To(Succeed())
+				Expect(repositories.LoanRepository.UpdateLoanState(ctx, &stale, model.LoanStateApproved)).To(Equal(model.ErrorLoanVersionMismatch))
+			})
+
+			It("updates the total invested amount while the version matches", func() {
+				loan := createLoan(createBorrower("3171000000000001"))
+				stale := *loan
+
+				loan.TotalInvestedAmount = 4000000
+				Expect(repositories.LoanRepository.UpdateLoanTotalInvestedAmount(ctx, loan)).To(Succeed())
+
+				updated, err := repositories.LoanRepository.GetLoanByID(ctx, loan.ID)
+				Expect(err).To(BeNil())
+				Expect(updated.TotalInvestedAmount).To(Equal(float64(4000000)))
+				Expect(updated.Version).To(Equal(int64(2)))
+
+				stale.TotalInvestedAmount = 2000000
+				Expect(repositories.LoanRepository.UpdateLoanTotalInvestedAmount(ctx, &stale)).To(Equal(model.ErrorLoanUpdateConflict))
+			})
+
+			It("lists the loans of a state newest first", func() {
+				borrowerID := createBorrower("3171000000000001")
+				first := createLoan(borrowerID)
+				second := createLoan(borrowerID)
+				updateLoanState(createLoan(borrowerID), model.LoanStateApproved)
+
+				loans, err := repositories.LoanRepository.ListLoansByState(ctx, model.LoanStateProposed)
+				Expect(err).To(BeNil())
+				Expect(loanIDs(loans)).To(Equal([]string{second.ID, first.ID}))
+
+				loans, err = repositories.LoanRepository.ListLoansByState(ctx, model.LoanStateDisbursed)
+				Expect(err).To(BeNil())
+				Expect(loans).To(BeEmpty())
+			})
+
+			It("lists the published loans past their funding deadline, earliest deadline first", func() {
+				borrowerID := createBorrower("3171000000000001")
+				now := time.Now()
+				publish := func(deadline time.Time) *model.Loan {
+					loan := updateLoanState(createLoan(borrowerID), model.LoanStateApproved)
+					loan.FundingDeadline = &deadline
+
+					return updateLoanState(loan, model.LoanStatePublished)
+				}
+
+				late := publish(now.Add(-time.Hour))
+				later := publish(now.Add(-2 * time.Hour))
+				publish(now.Add(time.Hour))
+
+				loans, err := repositories.LoanRepository.ListExpiredPublishedLoans(ctx, now)
+				Expect(err).To(BeNil())
+				Expect(loanIDs(loans)).To(Equal([]string{later.ID, late.ID}))
+			})
+
+			It("lists the loans of a borrower oldest first", func() {
+				borrowerID := createBorrower("3171000000000001")
+				first := createLoan(borrowerID)
+				second := createLoan(borrowerID)
+				createLoan(createBorrower("3171000000000002"))
+
+				loans, err := repositories.LoanRepository.ListLoansByBorrowerID(ctx, borrowerID)
+				Expect(err).To(BeNil())
+				Expect(loanIDs(loans)).To(Equal([]string{first.ID, second.ID}))
+
+				loans, err = repositories.LoanRepository.ListLoansByBorrowerID(ctx, uuid.NewString())
+				Expect(err).To(BeNil())
+				Expect(loans).To(BeEmpty())
+			})
+
+			It("counts the loans of a borrower by outcome and all loans by state", func() {
+				borrowerID := createBorrower("3171000000000001")
+				createLoan(borrowerID)
+				updateLoanState(createLoan(borrowerID), model.LoanStateApproved)
+				updateLoanState(createLoan(borrowerID), model.LoanStateRejected)
+				updateLoanState(createLoan(borrowerID), model.LoanStateCanceled)
+				createLoan(createBorrower("3171000000000002"))
+
+				stats, err := repositories.LoanRepository.GetBorrowerLoanStats(ctx, borrowerID)
+				Expect(err).To(BeNil())
+				Expect(stats).To(Equal(&model.BorrowerLoanStats{ActiveLoans: 2, RejectedLoans: 1, CanceledLoans: 1}))
+
+				counts, err := repositories.LoanRepository.CountLoansByState(ctx)
+				Expect(err).To(BeNil())
+				Expect(counts).To(Equal(map[model.LoanState]int64{
+					model.LoanStateProposed: 2,
+					model.LoanStateApproved: 1,
+					model.LoanStateRejected: 1,
+					model.LoanStateCanceled: 1,
+				}))
+			})
+		})
+
+		Describe("BorrowerRepository", func() {
+			It("creates a borrower and finds it by id and by NIK", func() {
+				borrowerID := createBorrower("3171000000000001")
+
+				borrower, err := repositories.BorrowerRepository.GetBorrowerByID(ctx, borrowerID)
+				Expect(err).To(BeNil())
+				Expect(borrower.ID).To(Equal(borrowerID))
+				Expect(borrower.Name).To(Equal("Budi"))
+				Expect(borrower.Address).To(Equal("Jakarta"))
+				Expect(borrower.Occupation).To(Equal("Teacher"))
+				Expect(borrower.NIK).To(Equal("3171000000000001"))
+				Expect(borrower.DOB.Format(time.DateOnly)).To(Equal("1990-03-14"))
+				Expect(borrower.Email).To(Equal("3171000000000001@example.com"))
+				Expect(borrower.ErasedAt).To(BeNil())
+
+				byNIK, err := repositories.BorrowerRepository.GetBorrowerByNIK(ctx, " 3171000000000001 ")
+				Expect(err).To(BeNil())
+				Expect(byNIK.ID).To(Equal(borrowerID))
+			})
+
+			It("keeps the given id", func() {
+				id := uuid.NewString()
+				borrowerID, err := repositories.BorrowerRepository.CreateBorrower(ctx, &model.Borrower{
+					ID:   id,
+					Name: "Budi",
+					NIK:  "3171000000000001",
+					DOB:  &dob,
+				})
+				Expect(err).To(BeNil())
+				Expect(borrowerID).To(Equal(id))
+
+				_, err = repositories.BorrowerRepository.CreateBorrower(ctx, &model.Borrower{
+					ID:   id,
+					Name: "Budi",
+					NIK:  "3171000000000002",
+					DOB:  &dob,
+				})
+				Expect(err).To(Equal(model.ErrorBorrowerExist))
+			})
+
+			It("returns ErrorBorrowerExist for a NIK that is taken", func() {
+				createBorrower("3171000000000001")
+
+				_, err := repositories.BorrowerRepository.CreateBorrower(ctx, &model.Borrower{
+					Name: "Budi",
+					NIK:  "3171000000000001",
+					DOB:  &dob,
+				})
+				Expect(err).To(Equal(model.ErrorBorrowerExist))
+			})
+
+			It("returns ErrorBorrowerNotFound for an unknown borrower", func() {
+				_, err := repositories.BorrowerRepository.GetBorrowerByID(ctx, uuid.NewString())
+				Expect(err).To(Equal(model.ErrorBorrowerNotFound))
+
+				_, err = repositories.BorrowerRepository.GetBorrowerByNIK(ctx, "3171000000000009")
+				Expect(err).To(Equal(model.ErrorBorrowerNotFound))
+			})
+
+			It("keeps the borrower readable after it is re-encrypted", func() {
+				borrowerID := createBorrower("3171000000000001")
+
+				borrower, err := repositories.BorrowerRepository.GetBorrowerByID(ctx, borrowerID)
+				Expect(err).To(BeNil())
+				Expect(repositories.BorrowerRepository.ReencryptBorrower(ctx, borrower)).To(Succeed())
+
+				reencrypted, err := repositories.BorrowerRepository.GetBorrowerByNIK(ctx, "3171000000000001")
+				Expect(err).To(BeNil())
+				Expect(reencrypted).To(Equal(borrower))
+
+				borrowers, err := repositories.BorrowerRepository.ListBorrowersToReencrypt(ctx, model.NilUUID, 10)
+				Expect(err).To(BeNil())
+				Expect(borrowers).To(BeEmpty())
+			})
+
+			It("lists the borrowers written with an older key in id order, in batches after an id", func() {
+				createdIDs := []string{createBorrower("3171000000000001"), createBorrower("3171000000000002"), createBorrower("3171000000000003")}
+				erasedID := createBorrower("3171000000000004")
+				Expect(repositories.BorrowerRepository.EraseBorrower(ctx, erasedID, time.Now())).To(Succeed())
+				slices.Sort(createdIDs)
+
+				repositories.RotateKey()
+
+				borrowers, err := repositories.BorrowerRepository.ListBorrowersToReencrypt(ctx, model.NilUUID, 2)
+				Expect(err).To(BeNil())
+				Expect(borrowerIDs(borrowers)).To(Equal(createdIDs[:2]))
+				Expect(borrowers[0].NIK).NotTo(BeEmpty())
+
+				borrowers, err = repositories.BorrowerRepository.ListBorrowersToReencrypt(ctx, createdIDs[1], 2)
+				Expect(err).To(BeNil())
+				Expect(borrowerIDs(borrowers)).To(Equal(createdIDs[2:]))
+
+				Expect(repositories.BorrowerRepository.ReencryptBorrower(ctx, borrowers[0])).To(Succeed())
+
+				borrowers, err = repositories.BorrowerRepository.ListBorrowersToReencrypt(ctx, model.NilUUID, 10)
+				Expect(err).To(BeNil())
+				Expect(borrowerIDs(borrowers)).To(Equal(createdIDs[:2]))
+			})
+
+			It("erases the personal data of a borrower once and frees its NIK", func() {
+				borrowerID := createBorrower("3171000000000001")
+				erasedAt := time.Date(2025, time.May, 1, 8, 0, 0, 0, time.UTC)
+
+				Expect(repositories.BorrowerRepository.EraseBorrower(ctx, borrowerID, erasedAt)).To(Succeed())
+
+				borrower, err := repositories.BorrowerRepository.GetBorrowerByID(ctx, borrowerID)
+				Expect(err).To(BeNil())
+				Expect(borrower.Name).To(BeEmpty())
+				Expect(borrower.Address).To(BeEmpty())
+				Expect(borrower.Occupation).To(BeEmpty())
+				Expect(borrower.NIK).To(BeEmpty())
+				Expect(borrower.DOB).To(BeNil())
+				Expect(borrower.Email).To(BeEmpty())
+				Expect(*borrower.ErasedAt).To(BeTemporally("==", erasedAt))
+
+				Expect(repositories.BorrowerRepository.EraseBorrower(ctx, borrowerID, erasedAt)).To(Equal(model.ErrorPersonalDataErased))
+				Expect(repositories.BorrowerRepository.EraseBorrower(ctx, uuid.NewString(), erasedAt)).To(Equal(model.ErrorPersonalDataErased))
+
+				_, err = repositories.BorrowerRepository.GetBorrowerByNIK(ctx, "3171000000000001")
+				Expect(err).To(Equal(model.ErrorBorrowerNotFound))
+				createBorrower("3171000000000001")
+			})
+		})
+
+		Describe("InvestorRepository", func() {
+			It("creates an investor and finds it by id and by NIK", func() {
+				investorID := createInvestor("3171000000000001")
+
+				investor, err := repositories.InvestorRepository.GetInvestorByID(ctx, investorID)
+				Expect(err).To(BeNil())
+				Expect(investor.ID).To(Equal(investorID))
+				Expect(investor.Name).To(Equal("Sari"))
+				Expect(investor.NIK).To(Equal("3171000000000001"))
+				Expect(investor.NPWP).To(Equal("npwp-3171000000000001"))
+				Expect(investor.Email).To(Equal("3171000000000001@example.com"))
+				Expect(investor.PhoneNumber).To(Equal("08123456789"))
+				Expect(investor.CreatedAt).NotTo(BeNil())
+				Expect(investor.ErasedAt).To(BeNil())
+
+				byNIK, err := repositories.InvestorRepository.GetInvestorByNIK(ctx, "3171000000000001")
+				Expect(err).To(BeNil())
+				Expect(byNIK.ID).To(Equal(investorID))
+			})
+
+			It("returns ErrorInvestorExist for a NIK, NPWP or email that is taken", func() {
+				createInvestor("3171000000000001")
+
+				for _, investor := range []*model.Investor{
+					{Name: "Sari", NIK: "3171000000000001", NPWP: "npwp-2", Email: "2@example.com"},
+					{Name: "Sari", NIK: "3171000000000002", NPWP: "NPWP-3171000000000001", Email: "2@example.com"},
+					{Name: "Sari", NIK: "3171000000000002", NPWP: "npwp-2", Email: " 3171000000000001@EXAMPLE.com"},
+				} {
+					_, err := repositories.InvestorRepository.CreateInvestor(ctx, investor)
+					Expect(err).To(Equal(model.ErrorInvestorExist))
+				}
+			})
+
+			It("returns ErrorInvestorNotFound for an unknown investor", func() {
+				_, err := repositories.InvestorRepository.GetInvestorByID(ctx, uuid.NewString())
+				Expect(err).To(Equal(model.ErrorInvestorNotFound))
+
+				_, err = repositories.InvestorRepository.GetInvestorByNIK(ctx, "3171000000000009")
+				Expect(err).To(Equal(model.ErrorInvestorNotFound))
+			})
+
+			It("keeps the investor readable after it is re-encrypted", func() {
+				investorID := createInvestor("3171000000000001")
+
+				investor, err := repositories.InvestorRepository.GetInvestorByID(ctx, investorID)
+				Expect(err).To(BeNil())
+				Expect(repositories.InvestorRepository.ReencryptInvestor(ctx, investor)).To(Succeed())
+
+				reencrypted, err := repositories.InvestorRepository.GetInvestorByNIK(ctx, "3171000000000001")
+				Expect(err).To(BeNil())
+				Expect(reencrypted.NIK).To(Equal(investor.NIK))
+				Expect(reencrypted.NPWP).To(Equal(investor.NPWP))
+				Expect(reencrypted.Email).To(Equal(investor.Email))
+				Expect(reencrypted.PhoneNumber).To(Equal(investor.PhoneNumber))
+
+				investors, err := repositories.InvestorRepository.ListInvestorsToReencrypt(ctx, model.NilUUID, 10)
+				Expect(err).To(BeNil())
+				Expect(investors).To(BeEmpty())
+			})
+
+			It("lists the investors written with an older key in id order, in batches after an id", func() {
+				createdIDs := []string{createInvestor("3171000000000001"), createInvestor("3171000000000002"), createInvestor("3171000000000003")}
+				erasedID := createInvestor("3171000000000004")
+				Expect(repositories.InvestorRepository.EraseInvestor(ctx, erasedID, time.Now())).To(Succeed())
+				slices.Sort(createdIDs)
+
+				repositories.RotateKey()
+
+				investors, err := repositories.InvestorRepository.ListInvestorsToReencrypt(ctx, model.NilUUID, 2)
+				Expect(err).To(BeNil())
+				Expect(investorIDs(investors)).To(Equal(createdIDs[:2]))
+				Expect(investors[0].NIK).NotTo(BeEmpty())
+
+				investors, err = repositories.InvestorRepository.ListInvestorsToReencrypt(ctx, createdIDs[1], 2)
+				Expect(err).To(BeNil())
+				Expect(investorIDs(investors)).To(Equal(createdIDs[2:]))
+
+				Expect(repositories.InvestorRepository.ReencryptInvestor(ctx, investors[0])).To(Succeed())
+
+				investors, err = repositories.InvestorRepository.ListInvestorsToReencrypt(ctx, model.NilUUID, 10)
+				Expect(err).To(BeNil())
+				Expect(investorIDs(investors)).To(Equal(createdIDs[:2]))
+			})
+
+			It("erases the personal data of an investor once and frees its NIK, NPWP and email", func() {
+				investorID := createInvestor("3171000000000001")
+				erasedAt := time.Date(2025, time.May, 1, 8, 0, 0, 0, time.UTC)
+
+				Expect(repositories.InvestorRepository.EraseInvestor(ctx, investorID, erasedAt)).To(Succeed())
+
+				investor, err := repositories.InvestorRepository.GetInvestorByID(ctx, investorID)
+				Expect(err).To(BeNil())
+				Expect(investor.Name).To(BeEmpty())
+				Expect(investor.NIK).To(BeEmpty())
+				Expect(investor.NPWP).To(BeEmpty())
+				Expect(investor.Email).To(BeEmpty())
+				Expect(investor.PhoneNumber).To(BeEmpty())
+				Expect(*investor.ErasedAt).To(BeTemporally("==", erasedAt))
+
+				Expect(repositories.InvestorRepository.EraseInvestor(ctx, investorID, erasedAt)).To(Equal(model.ErrorPersonalDataErased))
+
+				_, err = repositories.InvestorRepository.GetInvestorByNIK(ctx, "3171000000000001")
+				Expect(err).To(Equal(model.ErrorInvestorNotFound))
+				createInvestor("3171000000000001")
+			})
+		})
+
+		Describe("InvestmentRepository", func() {
+			var (
+				loanID     string
+				investorID string
+			)
+
+			BeforeEach(func() {
+				loanID = createLoan(createBorrower("3171000000000001")).ID
+				investorID = createInvestor("3171000000000001")
+			})
+
+			createInvestment := func(loanID string, investorID string, amount float64) string {
+				investmentID, err := repositories.InvestmentRepository.CreateInvestment(ctx, &model.Investment{
+					LoanID:         loanID,
+					InvestorID:     investorID,
+					InvestedAmount: amount,
+				})
+				Expect(err).To(BeNil())
+
+				return investmentID
+			}
+
+			It("creates an active investment and finds it by investor", func() {
+				investmentID := createInvestment(loanID, investorID, 2500000)
+
+				investment, err := repositories.InvestmentRepository.GetInvestmentByInvestorID(ctx, investorID)
+				Expect(err).To(BeNil())
+				Expect(investment).To(Equal(&model.Investment{
+					ID:             investmentID,
+					LoanID:         loanID,
+					InvestorID:     investorID,
+					InvestedAmount: 2500000,
+					Status:         model.InvestmentStatusActive,
+				}))
+
+				_, err = repositories.InvestmentRepository.GetInvestmentByInvestorID(ctx, uuid.NewString())
+				Expect(err).To(Equal(model.ErrorInvestmentNotFound))
+			})
+
+			It("lists the investments of a loan and of an investor oldest first", func() {
+				otherInvestorID := createInvestor("3171000000000002")
+				otherLoanID := createLoan(createBorrower("3171000000000002")).ID
+				first := createInvestment(loanID, investorID, 1000000)
+				second := createInvestment(loanID, otherInvestorID, 2000000)
+				third := createInvestment(otherLoanID, investorID, 3000000)
+
+				investmentIDs := func(investments []*model.Investment, err error) (ids []string) {
+					Expect(err).To(BeNil())
+					for _, investment := range investments {
+						ids = append(ids, investment.ID)
+					}
+
+					return
+				}
+
+				Expect(investmentIDs(repositories.InvestmentRepository.ListInvestmentsByLoanID(ctx, loanID))).To(Equal([]string{first, second}))
+				Expect(investmentIDs(repositories.InvestmentRepository.ListInvestmentsByInvestorID(ctx, investorID))).To(Equal([]string{first, third}))
+				Expect(investmentIDs(repositories.InvestmentRepository.ListInvestmentsByLoanID(ctx, uuid.NewString()))).To(BeEmpty())
+			})
+
+			It("releases the active investments of a loan once", func() {
+				investmentID := createInvestment(loanID, investorID, 1000000)
+
+				released, err := repositories.InvestmentRepository.ReleaseInvestmentsByLoanID(ctx, loanID)
+				Expect(err).To(BeNil())
+				Expect(released).To(HaveLen(1))
+				Expect(released[0].ID).To(Equal(investmentID))
+				Expect(released[0].InvestedAmount).To(Equal(float64(1000000)))
+				Expect(released[0].Status).To(Equal(model.InvestmentStatusReleased))
+				Expect(released[0].ReleasedAt).NotTo(BeNil())
+
+				investments, err := repositories.InvestmentRepository.ListInvestmentsByLoanID(ctx, loanID)
+				Expect(err).To(BeNil())
+				Expect(investments[0].Status).To(Equal(model.InvestmentStatusReleased))
+
+				released, err = repositories.InvestmentRepository.ReleaseInvestmentsByLoanID(ctx, loanID)
+				Expect(err).To(BeNil())
+				Expect(released).To(BeEmpty())
+			})
+		})
+	})
+}
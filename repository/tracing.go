@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/frencius/loan-service/repository")
+
+// tracedExecutor starts a client span per SQL statement. Only the query text is recorded, never its
+// arguments, and a query span ends when the rows are returned rather than when they are read.
+type tracedExecutor struct {
+	executor dbExecutor
+}
+
+func (te *tracedExecutor) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	ctx, span := startStatementSpan(ctx, query)
+	defer func() { endStatementSpan(span, err) }()
+
+	return te.executor.ExecContext(ctx, query, args...)
+}
+
+func (te *tracedExecutor) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	ctx, span := startStatementSpan(ctx, query)
+	defer func() { endStatementSpan(span, err) }()
+
+	return te.executor.QueryContext(ctx, query, args...)
+}
+
+func (te *tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startStatementSpan(ctx, query)
+	row := te.executor.QueryRowContext(ctx, query, args...)
+	endStatementSpan(span, row.Err())
+
+	return row
+}
+
+// startStatementSpan names the span after the SQL operation, e.g. SELECT or UPDATE.
+func startStatementSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+func endStatementSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
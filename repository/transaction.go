@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+)
+
+// ITransactionManager runs a unit of work in a single database transaction. Repositories called with
+// the ctx passed to fn join that transaction.
+type ITransactionManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error)
+}
+
+type TransactionManager struct {
+	DB *sql.DB
+}
+
+func NewTransactionManager(app *application.App) ITransactionManager {
+	return &TransactionManager{
+		DB: app.DB,
+	}
+}
+
+type txContextKey struct{}
+
+// dbExecutor is implemented by both *sql.DB and *sql.Tx.
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executor returns the transaction carried by ctx, or db when there is none, traced per statement.
+func executor(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return &tracedExecutor{executor: tx}
+	}
+
+	return &tracedExecutor{executor: db}
+}
+
+// WithTransaction commits when fn returns nil and rolls back otherwise. A nested call joins the outer transaction.
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := tm.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "WithTransaction BeginTx error", "error", err)
+		return
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txContextKey{}, tx))
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			slog.ErrorContext(ctx, "WithTransaction Rollback", "error", rollbackErr)
+		}
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		slog.ErrorContext(ctx, "WithTransaction Commit error", "error", err)
+		return
+	}
+
+	return
+}
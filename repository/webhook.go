@@ -0,0 +1,559 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/lib/pq"
+)
+
+type IWebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) (ID string, err error)
+	GetWebhookSubscriptionByID(ctx context.Context, id string) (subscription *model.WebhookSubscription, err error)
+	ListWebhookSubscriptions(ctx context.Context) (subscriptions []*model.WebhookSubscription, err error)
+	ListActiveWebhookSubscriptionsByEventType(ctx context.Context, eventType model.EventType) (subscriptions []*model.WebhookSubscription, err error)
+	UpdateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) (err error)
+	DeleteWebhookSubscription(ctx context.Context, id string) (err error)
+	RecordWebhookSubscriptionResult(ctx context.Context, id string, succeeded bool, disableAfterFailures int) (subscription *model.WebhookSubscription, err error)
+	CreateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (ID string, err error)
+	GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *model.WebhookDelivery, err error)
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) (deliveries []*model.WebhookDelivery, err error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (err error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, attempt *model.WebhookDeliveryAttempt) (err error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID string) (attempts []*model.WebhookDeliveryAttempt, err error)
+}
+
+type WebhookRepository struct {
+	DB *sql.DB
+}
+
+func NewWebhookRepository(app *application.App) IWebhookRepository {
+	return &WebhookRepository{
+		DB: app.DB,
+	}
+}
+
+const webhookSubscriptionColumns = `
+			id,
+			url,
+			COALESCE(description, ''),
+			event_types,
+			secret,
+			is_active,
+			consecutive_failures,
+			disabled_at,
+			COALESCE(disabled_reason, ''),
+			COALESCE(created_by::text, ''),
+			created_at,
+			updated_at
+`
+
+func scanWebhookSubscription(scanner interface{ Scan(dest ...any) error }) (subscription *model.WebhookSubscription, err error) {
+	subscription = &model.WebhookSubscription{}
+	err = scanner.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Description,
+		pq.Array(&subscription.EventTypes),
+		&subscription.Secret,
+		&subscription.IsActive,
+		&subscription.ConsecutiveFailures,
+		&subscription.DisabledAt,
+		&subscription.DisabledReason,
+		&subscription.CreatedBy,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+
+	return
+}
+
+const webhookDeliveryColumns = `
+			id,
+			subscription_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempts,
+			COALESCE(last_status_code, 0),
+			COALESCE(last_error, ''),
+			next_attempt_at,
+			delivered_at,
+			created_at,
+			updated_at
+`
+
+func scanWebhookDelivery(scanner interface{ Scan(dest ...any) error }) (delivery *model.WebhookDelivery, err error) {
+	var payload []byte
+
+	delivery = &model.WebhookDelivery{}
+	err = scanner.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return
+	}
+
+	delivery.Payload = payload
+
+	return
+}
+
+func (wr *WebhookRepository) CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) (ID string, err error) {
+	query := `
+		INSERT INTO
+			webhook_subscriptions (
+				url,
+				description,
+				event_types,
+				secret,
+				created_by
+			)
+		VALUES
+			($1, $2, $3, $4, NULLIF($5, '')::uuid)
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, wr.DB).QueryRowContext(ctx, query,
+		subscription.URL,
+		subscription.Description,
+		pq.Array(subscription.EventTypes),
+		subscription.Secret,
+		subscription.CreatedBy,
+	).Scan(&ID)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateWebhookSubscription error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) GetWebhookSubscriptionByID(ctx context.Context, id string) (subscription *model.WebhookSubscription, err error) {
+	query := `
+		SELECT` + webhookSubscriptionColumns + `
+		FROM
+			webhook_subscriptions
+		WHERE
+			id = $1
+	`
+
+	subscription, err = scanWebhookSubscription(executor(ctx, wr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		subscription = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetWebhookSubscriptionByID", "error", err)
+			err = model.ErrorWebhookSubscriptionNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetWebhookSubscriptionByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) ListWebhookSubscriptions(ctx context.Context) (subscriptions []*model.WebhookSubscription, err error) {
+	query := `
+		SELECT` + webhookSubscriptionColumns + `
+		FROM
+			webhook_subscriptions
+		ORDER BY
+			created_at
+	`
+
+	return wr.queryWebhookSubscriptions(ctx, "ListWebhookSubscriptions", query)
+}
+
+func (wr *WebhookRepository) ListActiveWebhookSubscriptionsByEventType(ctx context.Context, eventType model.EventType) (subscriptions []*model.WebhookSubscription, err error) {
+	query := `
+		SELECT` + webhookSubscriptionColumns + `
+		FROM
+			webhook_subscriptions
+		WHERE
+			is_active = TRUE
+			AND event_types @> ARRAY[$1]::text[]
+	`
+
+	return wr.queryWebhookSubscriptions(ctx, "ListActiveWebhookSubscriptionsByEventType", query, eventType)
+}
+
+func (wr *WebhookRepository) queryWebhookSubscriptions(ctx context.Context, method string, query string, args ...any) (subscriptions []*model.WebhookSubscription, err error) {
+	subscriptions = []*model.WebhookSubscription{}
+	rows, err := executor(ctx, wr.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, method+" QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subscription *model.WebhookSubscription
+		subscription, err = scanWebhookSubscription(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, method+" Scan error", "error", err)
+			return
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, method+" rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) UpdateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) (err error) {
+	query := `
+		UPDATE
+			webhook_subscriptions
+		SET
+			url = $2,
+			description = $3,
+			event_types = $4,
+			is_active = $5,
+			consecutive_failures = $6,
+			disabled_at = $7,
+			disabled_reason = NULLIF($8, '')
+		WHERE
+			id = $1
+	`
+
+	rows, err := executor(ctx, wr.DB).ExecContext(ctx, query,
+		subscription.ID,
+		subscription.URL,
+		subscription.Description,
+		pq.Array(subscription.EventTypes),
+		subscription.IsActive,
+		subscription.ConsecutiveFailures,
+		subscription.DisabledAt,
+		subscription.DisabledReason,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateWebhookSubscription ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateWebhookSubscription RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorWebhookSubscriptionNotFound
+		slog.WarnContext(ctx, "UpdateWebhookSubscription affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) DeleteWebhookSubscription(ctx context.Context, id string) (err error) {
+	query := `
+		DELETE FROM
+			webhook_subscriptions
+		WHERE
+			id = $1
+	`
+
+	rows, err := executor(ctx, wr.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteWebhookSubscription ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteWebhookSubscription RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorWebhookSubscriptionNotFound
+		slog.WarnContext(ctx, "DeleteWebhookSubscription affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+// RecordWebhookSubscriptionResult resets the failure streak on success, or extends it on failure and
+// disables the subscription once the streak reaches disableAfterFailures. The counter is updated in
+// place so concurrent deliveries to the same endpoint are all counted.
+func (wr *WebhookRepository) RecordWebhookSubscriptionResult(ctx context.Context, id string, succeeded bool, disableAfterFailures int) (subscription *model.WebhookSubscription, err error) {
+	query := `
+		UPDATE
+			webhook_subscriptions
+		SET
+			consecutive_failures = CASE WHEN $2 THEN 0 ELSE consecutive_failures + 1 END,
+			is_active = CASE WHEN NOT $2 AND consecutive_failures + 1 >= $3 THEN FALSE ELSE is_active END,
+			disabled_at = CASE WHEN NOT $2 AND is_active AND consecutive_failures + 1 >= $3 THEN NOW() ELSE disabled_at END,
+			disabled_reason = CASE WHEN NOT $2 AND is_active AND consecutive_failures + 1 >= $3 THEN 'too many consecutive failures' ELSE disabled_reason END
+		WHERE
+			id = $1
+		RETURNING` + webhookSubscriptionColumns
+
+	subscription, err = scanWebhookSubscription(executor(ctx, wr.DB).QueryRowContext(ctx, query, id, succeeded, disableAfterFailures))
+	if err != nil {
+		subscription = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "RecordWebhookSubscriptionResult", "error", err)
+			err = model.ErrorWebhookSubscriptionNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "RecordWebhookSubscriptionResult", "error", err)
+		return
+	}
+
+	return
+}
+
+// CreateWebhookDelivery returns model.ErrorWebhookDeliveryExist when the event was already fanned out to the subscription.
+func (wr *WebhookRepository) CreateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (ID string, err error) {
+	query := `
+		INSERT INTO
+			webhook_deliveries (
+				subscription_id,
+				event_id,
+				event_type,
+				payload,
+				next_attempt_at
+			)
+		VALUES
+			($1, $2, $3, $4, $5)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING
+			id
+		`
+
+	err = executor(ctx, wr.DB).QueryRowContext(ctx, query,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		[]byte(delivery.Payload),
+		delivery.NextAttemptAt,
+	).Scan(&ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrorWebhookDeliveryExist
+			return
+		}
+
+		slog.ErrorContext(ctx, "CreateWebhookDelivery error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *model.WebhookDelivery, err error) {
+	query := `
+		SELECT` + webhookDeliveryColumns + `
+		FROM
+			webhook_deliveries
+		WHERE
+			id = $1
+	`
+
+	delivery, err = scanWebhookDelivery(executor(ctx, wr.DB).QueryRowContext(ctx, query, id))
+	if err != nil {
+		delivery = nil
+		if err == sql.ErrNoRows {
+			slog.DebugContext(ctx, "GetWebhookDeliveryByID", "error", err)
+			err = model.ErrorWebhookDeliveryNotFound
+			return
+		}
+
+		slog.ErrorContext(ctx, "GetWebhookDeliveryByID", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) (deliveries []*model.WebhookDelivery, err error) {
+	query := `
+		SELECT` + webhookDeliveryColumns + `
+		FROM
+			webhook_deliveries
+		WHERE
+			subscription_id = $1
+		ORDER BY
+			created_at DESC
+		LIMIT $2
+	`
+
+	deliveries = []*model.WebhookDelivery{}
+	rows, err := executor(ctx, wr.DB).QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListWebhookDeliveries QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery *model.WebhookDelivery
+		delivery, err = scanWebhookDelivery(rows)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListWebhookDeliveries Scan error", "error", err)
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListWebhookDeliveries rows error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (err error) {
+	query := `
+		UPDATE
+			webhook_deliveries
+		SET
+			status = $2,
+			attempts = $3,
+			last_status_code = NULLIF($4, 0),
+			last_error = NULLIF($5, ''),
+			next_attempt_at = $6,
+			delivered_at = $7
+		WHERE
+			id = $1
+	`
+
+	rows, err := executor(ctx, wr.DB).ExecContext(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateWebhookDelivery ExecContext error", "error", err)
+		return
+	}
+
+	affected, err := rows.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateWebhookDelivery RowsAffected error", "error", err)
+		return
+	}
+
+	if affected < 1 {
+		err = model.ErrorWebhookDeliveryNotFound
+		slog.WarnContext(ctx, "UpdateWebhookDelivery affected < 1 error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) CreateWebhookDeliveryAttempt(ctx context.Context, attempt *model.WebhookDeliveryAttempt) (err error) {
+	query := `
+		INSERT INTO
+			webhook_delivery_attempts (
+				delivery_id,
+				attempt,
+				status_code,
+				error,
+				duration_ms
+			)
+		VALUES
+			($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5)
+		`
+
+	_, err = executor(ctx, wr.DB).ExecContext(ctx, query,
+		attempt.DeliveryID,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.DurationMS,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "CreateWebhookDeliveryAttempt ExecContext error", "error", err)
+		return
+	}
+
+	return
+}
+
+func (wr *WebhookRepository) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID string) (attempts []*model.WebhookDeliveryAttempt, err error) {
+	query := `
+		SELECT
+			id,
+			delivery_id,
+			attempt,
+			COALESCE(status_code, 0),
+			COALESCE(error, ''),
+			duration_ms,
+			attempted_at
+		FROM
+			webhook_delivery_attempts
+		WHERE
+			delivery_id = $1
+		ORDER BY
+			attempt
+	`
+
+	attempts = []*model.WebhookDeliveryAttempt{}
+	rows, err := executor(ctx, wr.DB).QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		slog.ErrorContext(ctx, "ListWebhookDeliveryAttempts QueryContext error", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attempt := &model.WebhookDeliveryAttempt{}
+		err = rows.Scan(
+			&attempt.ID,
+			&attempt.DeliveryID,
+			&attempt.Attempt,
+			&attempt.StatusCode,
+			&attempt.Error,
+			&attempt.DurationMS,
+			&attempt.AttemptedAt,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "ListWebhookDeliveryAttempts Scan error", "error", err)
+			return
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.ErrorContext(ctx, "ListWebhookDeliveryAttempts rows error", "error", err)
+		return
+	}
+
+	return
+}
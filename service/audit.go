@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+const (
+	defaultListAuditLogsLimit = 100
+	maxListAuditLogsLimit     = 1000
+	verifyAuditLogsBatchSize  = 1000
+)
+
+type IAuditService interface {
+	ListAuditLogs(ctx context.Context, listAuditLogsRequest *model.ListAuditLogsRequest) (auditLogResponses []*model.AuditLogResponse, err error)
+	VerifyAuditLogs(ctx context.Context) (auditVerificationResponse *model.AuditVerificationResponse, err error)
+	RecordOperatorCommand(ctx context.Context, operatorCommand *model.OperatorCommand) (err error)
+}
+
+type AuditService struct {
+	AuditRepository    repository.IAuditRepository
+	TransactionManager repository.ITransactionManager
+}
+
+func NewAuditService(app *application.App) IAuditService {
+	return &AuditService{
+		AuditRepository:    repository.NewAuditRepository(app),
+		TransactionManager: repository.NewTransactionManager(app),
+	}
+}
+
+// recordAudit appends the change of the entity to the audit log, before is nil for a created entity and
+// after for a deleted one. Call it with the transaction ctx of the change so the entry is only kept when
+// the change is committed.
+func recordAudit(ctx context.Context, auditRepository repository.IAuditRepository, entityType string, entityID string, action model.AuditAction, before any, after any) (err error) {
+	changes, err := model.ComposeAuditChanges(before, after)
+	if err != nil {
+		slog.ErrorContext(ctx, "recordAudit ComposeAuditChanges error", "error", err)
+		return
+	}
+
+	changesBytes, err := json.Marshal(changes)
+	if err != nil {
+		slog.ErrorContext(ctx, "recordAudit Marshal error", "error", err)
+		return
+	}
+
+	actorID, _ := ctx.Value("userID").(string)
+
+	return auditRepository.CreateAuditLog(ctx, &model.AuditLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorID:    actorID,
+		SourceIP:   auditSourceIP(ctx),
+		RequestID:  application.RequestID(ctx),
+		Changes:    changesBytes,
+		// the database keeps microseconds, the hash must be computed on what is stored
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	})
+}
+
+// auditSourceIP is the client address of the request as a canonical IP, it is left out when the address is not
+// an IP so a malformed address can never fail the change being audited.
+func auditSourceIP(ctx context.Context) string {
+	addr, err := netip.ParseAddr(application.ClientIP(ctx))
+	if err != nil {
+		return ""
+	}
+
+	return addr.Unmap().WithZone("").String()
+}
+
+func (as *AuditService) ListAuditLogs(ctx context.Context, listAuditLogsRequest *model.ListAuditLogsRequest) (auditLogResponses []*model.AuditLogResponse, err error) {
+	limit := listAuditLogsRequest.Limit
+	if limit <= 0 {
+		limit = defaultListAuditLogsLimit
+	}
+	limit = min(limit, maxListAuditLogsLimit)
+
+	auditLogs, err := as.AuditRepository.ListAuditLogs(ctx, &model.AuditLogFilter{
+		EntityType: listAuditLogsRequest.EntityType,
+		EntityID:   listAuditLogsRequest.EntityID,
+		ActorID:    listAuditLogsRequest.ActorID,
+		From:       listAuditLogsRequest.From,
+		To:         listAuditLogsRequest.To,
+		BeforeID:   listAuditLogsRequest.BeforeID,
+		Limit:      limit,
+	})
+	if err != nil {
+		return
+	}
+
+	auditLogResponses = make([]*model.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogResponses = append(auditLogResponses, model.ComposeAuditLogResponse(auditLog))
+	}
+
+	return
+}
+
+// RecordOperatorCommand appends a loanctl command and its outcome to the audit log under the operator of ctx,
+// keyed by the request id of the run. The changes the command made are audited by the services it called.
+func (as *AuditService) RecordOperatorCommand(ctx context.Context, operatorCommand *model.OperatorCommand) (err error) {
+	return as.TransactionManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return recordAudit(ctx, as.AuditRepository, model.AuditEntityOperatorCommand, application.RequestID(ctx), model.AuditActionRun, nil, operatorCommand)
+	})
+}
+
+// VerifyAuditLogs walks the whole chain and recomputes every hash. An altered entry fails its own hash, a
+// removed or reordered one fails the link of the entry after it.
+func (as *AuditService) VerifyAuditLogs(ctx context.Context) (auditVerificationResponse *model.AuditVerificationResponse, err error) {
+	auditVerificationResponse = &model.AuditVerificationResponse{Valid: true}
+	prevHash := model.AuditGenesisHash
+	var afterID int64
+
+	for {
+		var auditLogs []*model.AuditLog
+		auditLogs, err = as.AuditRepository.ListAuditLogsAfter(ctx, afterID, verifyAuditLogsBatchSize)
+		if err != nil {
+			return
+		}
+
+		for _, auditLog := range auditLogs {
+			switch {
+			case auditLog.PrevHash != prevHash:
+				auditVerificationResponse.Reason = fmt.Sprintf("entry %d does not follow the entry before it", auditLog.ID)
+			case auditLog.ComputeHash() != auditLog.Hash:
+				auditVerificationResponse.Reason = fmt.Sprintf("entry %d does not match its hash", auditLog.ID)
+			}
+
+			if auditVerificationResponse.Reason != "" {
+				auditVerificationResponse.Valid = false
+				auditVerificationResponse.FirstInvalidID = auditLog.ID
+				slog.WarnContext(ctx, "VerifyAuditLogs chain is broken", "id", auditLog.ID, "reason", auditVerificationResponse.Reason)
+				return
+			}
+
+			auditVerificationResponse.CheckedEntries++
+			auditVerificationResponse.LastHash = auditLog.Hash
+			prevHash = auditLog.Hash
+			afterID = auditLog.ID
+		}
+
+		if len(auditLogs) < verifyAuditLogsBatchSize {
+			return
+		}
+	}
+}
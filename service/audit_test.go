@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("AuditService", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockAuditRepo *mock.MockIAuditRepository
+		auditSvc      *service.AuditService
+		chain         []*model.AuditLog
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		auditSvc = &service.AuditService{
+			AuditRepository:    mockAuditRepo,
+			TransactionManager: &mock.MockTransactionManager{},
+		}
+
+		createdAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		prevHash := model.AuditGenesisHash
+		chain = []*model.AuditLog{}
+		for i, state := range []string{"proposed", "approved", "published"} {
+			auditLog := &model.AuditLog{
+				ID:         int64(i + 1),
+				EntityType: model.AuditEntityLoan,
+				EntityID:   "loan-1",
+				Action:     model.AuditActionUpdate,
+				ActorID:    "employee-1",
+				Changes:    json.RawMessage(`{"state":{"after":"` + state + `"}}`),
+				CreatedAt:  createdAt.Add(time.Duration(i) * time.Minute),
+				PrevHash:   prevHash,
+			}
+			auditLog.Hash = auditLog.ComputeHash()
+			prevHash = auditLog.Hash
+			chain = append(chain, auditLog)
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("ListAuditLogs", func() {
+		It("should pass the filter through with the default limit", func() {
+			ctx := context.Background()
+			from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogs(ctx, &model.AuditLogFilter{EntityType: model.AuditEntityLoan, EntityID: "loan-1", From: &from, Limit: 100}).
+				Return([]*model.AuditLog{chain[2]}, nil)
+
+			resp, err := auditSvc.ListAuditLogs(ctx, &model.ListAuditLogsRequest{EntityType: model.AuditEntityLoan, EntityID: "loan-1", From: &from})
+			Expect(err).To(BeNil())
+			Expect(resp).To(HaveLen(1))
+			Expect(resp[0].Hash).To(Equal(chain[2].Hash))
+			Expect(resp[0].Changes).To(Equal(map[string]model.AuditChange{"state": {After: json.RawMessage(`"published"`)}}))
+		})
+
+		It("should cap the limit", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogs(ctx, &model.AuditLogFilter{BeforeID: 50, Limit: 1000}).
+				Return([]*model.AuditLog{}, nil)
+
+			resp, err := auditSvc.ListAuditLogs(ctx, &model.ListAuditLogsRequest{BeforeID: 50, Limit: 5000})
+			Expect(err).To(BeNil())
+			Expect(resp).To(BeEmpty())
+		})
+	})
+
+	Context("VerifyAuditLogs", func() {
+		It("should report an intact chain", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogsAfter(ctx, int64(0), 1000).
+				Return(chain, nil)
+
+			resp, err := auditSvc.VerifyAuditLogs(ctx)
+			Expect(err).To(BeNil())
+			Expect(resp).To(Equal(&model.AuditVerificationResponse{Valid: true, CheckedEntries: 3, LastHash: chain[2].Hash}))
+		})
+
+		It("should report an empty chain as intact", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogsAfter(ctx, int64(0), 1000).
+				Return([]*model.AuditLog{}, nil)
+
+			resp, err := auditSvc.VerifyAuditLogs(ctx)
+			Expect(err).To(BeNil())
+			Expect(resp.Valid).To(BeTrue())
+			Expect(resp.CheckedEntries).To(BeZero())
+		})
+
+		It("should report an altered entry", func() {
+			ctx := context.Background()
+			chain[1].Changes = json.RawMessage(`{"state":{"after":"rejected"}}`)
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogsAfter(ctx, int64(0), 1000).
+				Return(chain, nil)
+
+			resp, err := auditSvc.VerifyAuditLogs(ctx)
+			Expect(err).To(BeNil())
+			Expect(resp.Valid).To(BeFalse())
+			Expect(resp.CheckedEntries).To(Equal(int64(1)))
+			Expect(resp.FirstInvalidID).To(Equal(int64(2)))
+			Expect(resp.Reason).To(ContainSubstring("does not match its hash"))
+		})
+
+		It("should report a removed entry", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogsAfter(ctx, int64(0), 1000).
+				Return([]*model.AuditLog{chain[0], chain[2]}, nil)
+
+			resp, err := auditSvc.VerifyAuditLogs(ctx)
+			Expect(err).To(BeNil())
+			Expect(resp.Valid).To(BeFalse())
+			Expect(resp.FirstInvalidID).To(Equal(int64(3)))
+			Expect(resp.Reason).To(ContainSubstring("does not follow the entry before it"))
+		})
+
+		It("should return error if the chain cannot be read", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				ListAuditLogsAfter(ctx, int64(0), 1000).
+				Return(nil, errors.New("db down"))
+
+			_, err := auditSvc.VerifyAuditLogs(ctx)
+			Expect(err).To(MatchError("db down"))
+		})
+	})
+
+	Context("RecordOperatorCommand", func() {
+		It("should audit the command under the operator keyed by the request id", func() {
+			ctx := application.WithRequestID(context.WithValue(context.Background(), "userID", "employee-1"), "loanctl-1")
+
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityOperatorCommand))
+					Expect(auditLog.EntityID).To(Equal("loanctl-1"))
+					Expect(auditLog.Action).To(Equal(model.AuditActionRun))
+					Expect(auditLog.ActorID).To(Equal("employee-1"))
+					Expect(string(auditLog.Changes)).To(ContainSubstring(`"command":{"after":"loan force-state"}`))
+					Expect(string(auditLog.Changes)).To(ContainSubstring(`"error":{"after":"invalid state"}`))
+					return nil
+				})
+
+			err := auditSvc.RecordOperatorCommand(ctx, &model.OperatorCommand{
+				Command: "loan force-state",
+				Args:    []string{"loan", "force-state", "-reason", "typo", "loan-1", "unknown"},
+				Outcome: model.OperatorCommandFailed,
+				Error:   "invalid state",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return error if the audit log cannot be written", func() {
+			ctx := context.Background()
+
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				Return(errors.New("db down"))
+
+			err := auditSvc.RecordOperatorCommand(ctx, &model.OperatorCommand{Command: "report summary", Outcome: model.OperatorCommandSucceeded})
+			Expect(err).To(MatchError("db down"))
+		})
+	})
+})
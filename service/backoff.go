@@ -0,0 +1,16 @@
+package service
+
+import "time"
+
+// exponentialBackoff doubles the delay for every attempt, starting at base and capped at maxDelay.
+func exponentialBackoff(attempts int, base time.Duration, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+
+	return min(delay, maxDelay)
+}
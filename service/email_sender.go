@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+)
+
+// IEmailSender hands an email over to the mail transport.
+type IEmailSender interface {
+	Send(ctx context.Context, message *model.EmailMessage) (err error)
+}
+
+func NewEmailSender(config configuration.SMTP) IEmailSender {
+	if config.Host == "" {
+		return &LogEmailSender{}
+	}
+
+	return &SMTPEmailSender{Config: config}
+}
+
+// LogEmailSender only writes emails to the service log, it is used when no SMTP host is configured.
+type LogEmailSender struct{}
+
+func (les *LogEmailSender) Send(ctx context.Context, message *model.EmailMessage) (err error) {
+	slog.InfoContext(ctx, "email", "email", message.To, "subject", message.Subject)
+
+	return
+}
+
+// SMTPEmailSender delivers plain text emails through an SMTP relay. A local sink such as Mailpit
+// (SMTP_HOST=localhost, SMTP_PORT=1025, SMTP_STARTTLS=false) captures them during development.
+type SMTPEmailSender struct {
+	Config configuration.SMTP
+}
+
+func (ses *SMTPEmailSender) Send(ctx context.Context, message *model.EmailMessage) (err error) {
+	from, err := mail.ParseAddress(ses.Config.From)
+	if err != nil {
+		return
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return
+	}
+
+	body, err := composeEmail(from, to, message)
+	if err != nil {
+		return
+	}
+
+	dialer := &net.Dialer{Timeout: ses.Config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ses.Config.Host, strconv.Itoa(ses.Config.Port)))
+	if err != nil {
+		return
+	}
+	if ses.Config.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(ses.Config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, ses.Config.Host)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && ses.Config.StartTLS {
+		err = client.StartTLS(&tls.Config{ServerName: ses.Config.Host})
+		if err != nil {
+			return
+		}
+	}
+
+	if ses.Config.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", ses.Config.Username, ses.Config.Password, ses.Config.Host))
+		if err != nil {
+			return
+		}
+	}
+
+	err = client.Mail(from.Address)
+	if err != nil {
+		return
+	}
+
+	err = client.Rcpt(to.Address)
+	if err != nil {
+		return
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return
+	}
+
+	_, err = writer.Write(body)
+	if err != nil {
+		return
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+
+	return client.Quit()
+}
+
+func composeEmail(from *mail.Address, to *mail.Address, message *model.EmailMessage) (email []byte, err error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(buf, "Content-Transfer-Encoding: quoted-printable\r\n")
+	fmt.Fprintf(buf, "\r\n")
+
+	writer := quotedprintable.NewWriter(buf)
+	_, err = writer.Write([]byte(message.Body))
+	if err != nil {
+		return
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+
+	email = buf.Bytes()
+
+	return
+}
@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+// smtpSink is a minimal SMTP server that keeps the received messages, like a local mail catcher.
+type smtpSink struct {
+	listener net.Listener
+	messages chan string
+}
+
+func newSMTPSink() *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	sink := &smtpSink{listener: listener, messages: make(chan string, 1)}
+	go sink.serve()
+
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 sink ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 sink")
+		case command == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			data := strings.Builder{}
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.messages <- data.String()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+var _ = Describe("SMTPEmailSender", func() {
+	It("should deliver the email to the SMTP server", func() {
+		sink := newSMTPSink()
+		defer sink.listener.Close()
+
+		sender := &service.SMTPEmailSender{Config: configuration.SMTP{
+			Host:    "127.0.0.1",
+			Port:    sink.port(),
+			From:    "Loan Service <no-reply@loan-service.local>",
+			Timeout: 5 * time.Second,
+		}}
+
+		err := sender.Send(context.Background(), &model.EmailMessage{
+			To:      "budi@example.com",
+			Subject: "Pinjaman Anda telah dicairkan",
+			Body:    "Halo Budi,\n",
+		})
+		Expect(err).To(BeNil())
+
+		var raw string
+		Eventually(sink.messages).Should(Receive(&raw))
+
+		message, err := mail.ReadMessage(strings.NewReader(raw))
+		Expect(err).To(BeNil())
+		Expect(message.Header.Get("To")).To(Equal("<budi@example.com>"))
+		Expect(message.Header.Get("From")).To(ContainSubstring("no-reply@loan-service.local"))
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+		Expect(err).To(BeNil())
+		Expect(subject).To(Equal("Pinjaman Anda telah dicairkan"))
+	})
+
+	It("should fail when the SMTP server is unreachable", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		sender := &service.SMTPEmailSender{Config: configuration.SMTP{
+			Host:    "127.0.0.1",
+			Port:    port,
+			From:    "no-reply@loan-service.local",
+			Timeout: time.Second,
+		}}
+
+		err = sender.Send(context.Background(), &model.EmailMessage{To: "budi@example.com", Subject: "subject", Body: "body"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(strconv.Itoa(port)))
+	})
+})
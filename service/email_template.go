@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/frencius/loan-service/model"
+)
+
+//go:embed templates/email
+var emailTemplateFS embed.FS
+
+// emailTemplates holds one template per language and notification event, keyed "<language>/<event>".
+// Each template defines a "subject" and a "body".
+var emailTemplates = parseEmailTemplates()
+
+// EmailTemplateData is available to every email template.
+type EmailTemplateData struct {
+	RecipientName      string
+	LoanID             string
+	PrincipalAmount    float64
+	TenorMonths        int64
+	InvestedAmount     float64
+	AgreementLetterURL string
+	Reason             string
+	Installment        *model.RepaymentInstallment
+}
+
+var emailTemplateFuncs = template.FuncMap{
+	"rupiah": formatRupiah,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+}
+
+func parseEmailTemplates() map[string]*template.Template {
+	templates := map[string]*template.Template{}
+	for _, language := range []model.Language{model.LanguageIndonesian, model.LanguageEnglish} {
+		for event := range model.ValidNotificationEvents {
+			name := path.Join(string(language), string(event))
+			templates[name] = template.Must(template.New(name).Funcs(emailTemplateFuncs).
+				ParseFS(emailTemplateFS, path.Join("templates/email", name+".tmpl")))
+		}
+	}
+
+	return templates
+}
+
+func renderEmail(language model.Language, event model.NotificationEvent, data *EmailTemplateData) (subject string, body string, err error) {
+	tmpl, ok := emailTemplates[path.Join(string(language), string(event))]
+	if !ok {
+		err = model.ErrorEmailTemplateNotFound
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.ExecuteTemplate(buf, "subject", data)
+	if err != nil {
+		return
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	err = tmpl.ExecuteTemplate(buf, "body", data)
+	if err != nil {
+		return
+	}
+	body = strings.TrimSpace(buf.String()) + "\n"
+
+	return
+}
+
+// formatRupiah formats an amount as "Rp1.500.000" or "Rp1.500.000,50".
+func formatRupiah(amount float64) string {
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	digits := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	if cents%100 != 0 {
+		return fmt.Sprintf("%sRp%s,%02d", sign, grouped.String(), cents%100)
+	}
+
+	return fmt.Sprintf("%sRp%s", sign, grouped.String())
+}
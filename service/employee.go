@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type IEmployeeService interface {
+	CreateEmployee(ctx context.Context, createEmployeeRequest *model.CreateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error)
+	GetEmployee(ctx context.Context, getEmployeeRequest *model.GetEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error)
+	ListEmployees(ctx context.Context, listEmployeesRequest *model.ListEmployeesRequest) (employeeResponses []*model.EmployeeResponse, err error)
+	UpdateEmployee(ctx context.Context, updateEmployeeRequest *model.UpdateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error)
+	DeactivateEmployee(ctx context.Context, deactivateEmployeeRequest *model.DeactivateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error)
+}
+
+type EmployeeService struct {
+	EmployeeRepository repository.IEmployeeRepository
+	TransactionManager repository.ITransactionManager
+	AuditRepository    repository.IAuditRepository
+}
+
+func NewEmployeeService(app *application.App) IEmployeeService {
+	return &EmployeeService{
+		EmployeeRepository: repository.NewEmployeeRepository(app),
+		TransactionManager: repository.NewTransactionManager(app),
+		AuditRepository:    repository.NewAuditRepository(app),
+	}
+}
+
+func (es *EmployeeService) CreateEmployee(ctx context.Context, createEmployeeRequest *model.CreateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error) {
+	employee := &model.Employee{
+		Name:           createEmployeeRequest.Name,
+		EmployeeNumber: createEmployeeRequest.EmployeeNumber,
+	}
+
+	err = es.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		employeeID, err := es.EmployeeRepository.CreateEmployee(ctx, employee)
+		if err != nil {
+			return
+		}
+
+		employeeResponse, err = es.GetEmployee(ctx, &model.GetEmployeeRequest{EmployeeID: employeeID})
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, es.AuditRepository, model.AuditEntityEmployee, employeeID, model.AuditActionCreate, nil, employeeResponse)
+	})
+	if err != nil {
+		employeeResponse = nil
+	}
+
+	return
+}
+
+func (es *EmployeeService) GetEmployee(ctx context.Context, getEmployeeRequest *model.GetEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error) {
+	employee, err := es.EmployeeRepository.GetEmployeeByID(ctx, getEmployeeRequest.EmployeeID)
+	if err != nil {
+		return
+	}
+
+	employeeResponse = model.ComposeEmployeeResponse(employee)
+
+	return
+}
+
+func (es *EmployeeService) ListEmployees(ctx context.Context, listEmployeesRequest *model.ListEmployeesRequest) (employeeResponses []*model.EmployeeResponse, err error) {
+	employees, err := es.EmployeeRepository.ListEmployees(ctx, listEmployeesRequest.ActiveOnly)
+	if err != nil {
+		return
+	}
+
+	employeeResponses = make([]*model.EmployeeResponse, 0, len(employees))
+	for _, employee := range employees {
+		employeeResponses = append(employeeResponses, model.ComposeEmployeeResponse(employee))
+	}
+
+	return
+}
+
+func (es *EmployeeService) UpdateEmployee(ctx context.Context, updateEmployeeRequest *model.UpdateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error) {
+	employee, err := es.EmployeeRepository.GetEmployeeByID(ctx, updateEmployeeRequest.EmployeeID)
+	if err != nil {
+		return
+	}
+
+	before := model.ComposeEmployeeResponse(employee)
+	employee.Name = updateEmployeeRequest.Name
+	employeeResponse = model.ComposeEmployeeResponse(employee)
+
+	err = es.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = es.EmployeeRepository.UpdateEmployee(ctx, employee)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, es.AuditRepository, model.AuditEntityEmployee, employee.ID, model.AuditActionUpdate, before, employeeResponse)
+	})
+	if err != nil {
+		employeeResponse = nil
+	}
+
+	return
+}
+
+func (es *EmployeeService) DeactivateEmployee(ctx context.Context, deactivateEmployeeRequest *model.DeactivateEmployeeRequest) (employeeResponse *model.EmployeeResponse, err error) {
+	employee, err := es.EmployeeRepository.GetEmployeeByID(ctx, deactivateEmployeeRequest.EmployeeID)
+	if err != nil {
+		return
+	}
+
+	if !employee.IsActive {
+		err = model.ErrorEmployeeInactive
+		return
+	}
+
+	before := model.ComposeEmployeeResponse(employee)
+
+	err = es.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = es.EmployeeRepository.DeactivateEmployee(ctx, employee.ID, ctx.Value("userID").(string))
+		if err != nil {
+			return
+		}
+
+		employeeResponse, err = es.GetEmployee(ctx, &model.GetEmployeeRequest{EmployeeID: employee.ID})
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, es.AuditRepository, model.AuditEntityEmployee, employee.ID, model.AuditActionUpdate, before, employeeResponse)
+	})
+	if err != nil {
+		employeeResponse = nil
+	}
+
+	return
+}
@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("EmployeeService", func() {
+	var (
+		mockCtrl         *gomock.Controller
+		mockEmployeeRepo *mock.MockIEmployeeRepository
+		mockAuditRepo    *mock.MockIAuditRepository
+		employeeSvc      service.IEmployeeService
+		expectAudit      func(action model.AuditAction) *gomock.Call
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEmployeeRepo = mock.NewMockIEmployeeRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+
+		employeeSvc = &service.EmployeeService{
+			EmployeeRepository: mockEmployeeRepo,
+			TransactionManager: &mock.MockTransactionManager{},
+			AuditRepository:    mockAuditRepo,
+		}
+
+		expectAudit = func(action model.AuditAction) *gomock.Call {
+			return mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityEmployee))
+					Expect(auditLog.EntityID).To(Equal("emp-1"))
+					Expect(auditLog.Action).To(Equal(action))
+					return nil
+				})
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("CreateEmployee", func() {
+		It("should create an employee and return it", func() {
+			ctx := context.Background()
+			createReq := &model.CreateEmployeeRequest{
+				Name:           "Budi",
+				EmployeeNumber: "EMP-001",
+			}
+
+			mockEmployeeRepo.EXPECT().
+				CreateEmployee(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, employee *model.Employee) (string, error) {
+					Expect(employee.Name).To(Equal("Budi"))
+					Expect(employee.EmployeeNumber).To(Equal("EMP-001"))
+					return "emp-1", nil
+				})
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(&model.Employee{ID: "emp-1", Name: "Budi", EmployeeNumber: "EMP-001", IsActive: true}, nil)
+			expectAudit(model.AuditActionCreate)
+
+			resp, err := employeeSvc.CreateEmployee(ctx, createReq)
+			Expect(err).To(BeNil())
+			Expect(resp.EmployeeID).To(Equal("emp-1"))
+			Expect(resp.IsActive).To(BeTrue())
+		})
+
+		It("should return error if employee number exists", func() {
+			ctx := context.Background()
+			createReq := &model.CreateEmployeeRequest{
+				Name:           "Budi",
+				EmployeeNumber: "EMP-001",
+			}
+
+			mockEmployeeRepo.EXPECT().
+				CreateEmployee(ctx, gomock.Any()).
+				Return("", model.ErrorEmployeeNumberExist)
+
+			resp, err := employeeSvc.CreateEmployee(ctx, createReq)
+			Expect(err).To(Equal(model.ErrorEmployeeNumberExist))
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Context("ListEmployees", func() {
+		It("should list employees", func() {
+			ctx := context.Background()
+
+			mockEmployeeRepo.EXPECT().
+				ListEmployees(ctx, true).
+				Return([]*model.Employee{{ID: "emp-1"}, {ID: "emp-2"}}, nil)
+
+			resp, err := employeeSvc.ListEmployees(ctx, &model.ListEmployeesRequest{ActiveOnly: true})
+			Expect(err).To(BeNil())
+			Expect(resp).To(HaveLen(2))
+		})
+	})
+
+	Context("UpdateEmployee", func() {
+		It("should update employee name", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+			employee := &model.Employee{ID: "emp-1", Name: "Budi"}
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(employee, nil)
+			mockEmployeeRepo.EXPECT().
+				UpdateEmployee(ctx, employee).
+				Return(nil)
+			expectAudit(model.AuditActionUpdate).
+				Do(func(_ context.Context, auditLog *model.AuditLog) {
+					Expect(auditLog.ActorID).To(Equal("admin-1"))
+					Expect(auditLog.Changes).To(MatchJSON(`{"name": {"before": "Budi", "after": "Budi Santoso"}}`))
+				})
+
+			resp, err := employeeSvc.UpdateEmployee(ctx, &model.UpdateEmployeeRequest{EmployeeID: "emp-1", Name: "Budi Santoso"})
+			Expect(err).To(BeNil())
+			Expect(resp.Name).To(Equal("Budi Santoso"))
+		})
+
+		It("should return error if employee not found", func() {
+			ctx := context.Background()
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-404").
+				Return(nil, model.ErrorEmployeeNotFound)
+
+			resp, err := employeeSvc.UpdateEmployee(ctx, &model.UpdateEmployeeRequest{EmployeeID: "emp-404", Name: "Budi"})
+			Expect(err).To(Equal(model.ErrorEmployeeNotFound))
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Context("DeactivateEmployee", func() {
+		It("should deactivate an active employee", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(&model.Employee{ID: "emp-1", IsActive: true}, nil)
+			mockEmployeeRepo.EXPECT().
+				DeactivateEmployee(ctx, "emp-1", "admin-1").
+				Return(nil)
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(&model.Employee{ID: "emp-1", IsActive: false}, nil)
+			expectAudit(model.AuditActionUpdate)
+
+			resp, err := employeeSvc.DeactivateEmployee(ctx, &model.DeactivateEmployeeRequest{EmployeeID: "emp-1"})
+			Expect(err).To(BeNil())
+			Expect(resp.IsActive).To(BeFalse())
+		})
+
+		It("should return error if employee is already inactive", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(&model.Employee{ID: "emp-1", IsActive: false}, nil)
+
+			resp, err := employeeSvc.DeactivateEmployee(ctx, &model.DeactivateEmployeeRequest{EmployeeID: "emp-1"})
+			Expect(err).To(Equal(model.ErrorEmployeeInactive))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should return error if DeactivateEmployee repo fails", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeeByID(ctx, "emp-1").
+				Return(&model.Employee{ID: "emp-1", IsActive: true}, nil)
+			mockEmployeeRepo.EXPECT().
+				DeactivateEmployee(ctx, "emp-1", "admin-1").
+				Return(errors.New("update failed"))
+
+			resp, err := employeeSvc.DeactivateEmployee(ctx, &model.DeactivateEmployeeRequest{EmployeeID: "emp-1"})
+			Expect(err).To(MatchError("update failed"))
+			Expect(resp).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type IEventService interface {
+	Subscribe(eventType model.EventType, handler EventHandler)
+	RelayEvents(ctx context.Context) (published int, err error)
+	ReplayEvents(ctx context.Context, replayEventsRequest *model.ReplayEventsRequest) (replayed int64, err error)
+}
+
+type EventService struct {
+	OutboxRepository   repository.IOutboxRepository
+	AuditRepository    repository.IAuditRepository
+	TransactionManager repository.ITransactionManager
+	Sinks              []IEventSink
+	Bus                *EventBus
+	Config             configuration.Event
+	Now                func() time.Time
+}
+
+func NewEventService(app *application.App) IEventService {
+	bus := NewEventBus()
+	sinks := []IEventSink{bus}
+	if app.Config.Event.LogSink {
+		sinks = append(sinks, &LogEventSink{})
+	}
+	if app.Config.Event.WebhookURL != "" {
+		sinks = append(sinks, &WebhookEventSink{
+			URL:    app.Config.Event.WebhookURL,
+			Client: &http.Client{Timeout: app.Config.Event.WebhookTimeout, Transport: tracedTransport},
+		})
+	}
+
+	return &EventService{
+		OutboxRepository:   repository.NewOutboxRepository(app),
+		AuditRepository:    repository.NewAuditRepository(app),
+		TransactionManager: repository.NewTransactionManager(app),
+		Sinks:              sinks,
+		Bus:                bus,
+		Config:             app.Config.Event,
+		Now:                time.Now,
+	}
+}
+
+// recordEvent stores a domain event in the outbox. Call it with the transaction ctx of the change
+// so the event is only published when the change is committed.
+func recordEvent(ctx context.Context, outboxRepository repository.IOutboxRepository, eventType model.EventType, aggregateType string, aggregateID string, payload any) (err error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "recordEvent Marshal error", "error", err)
+		return
+	}
+
+	_, err = outboxRepository.CreateEvent(ctx, &model.Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       payloadBytes,
+		TraceParent:   traceParent(ctx),
+	})
+
+	return
+}
+
+func (es *EventService) Subscribe(eventType model.EventType, handler EventHandler) {
+	es.Bus.Subscribe(eventType, handler)
+}
+
+// RelayEvents publishes a batch of pending outbox events to every sink. An event is marked published
+// only when all sinks accepted it, otherwise it is retried with exponential backoff.
+func (es *EventService) RelayEvents(ctx context.Context) (published int, err error) {
+	events, err := es.OutboxRepository.ClaimEvents(ctx, es.Config.RelayBatchSize, es.Config.RelayLease)
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		publishErr := es.publish(ctx, event)
+		if publishErr != nil {
+			slog.WarnContext(ctx, "event publish failed", "event_id", event.ID, "event_type", event.Type, "attempt", event.Attempts+1, "error", publishErr)
+			nextAttemptAt := es.Now().Add(exponentialBackoff(event.Attempts+1, es.Config.BackoffBase, es.Config.BackoffMax))
+			err = es.OutboxRepository.MarkEventFailed(ctx, event.ID, publishErr.Error(), nextAttemptAt)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		err = es.OutboxRepository.MarkEventPublished(ctx, event.ID)
+		if err != nil {
+			return
+		}
+		published++
+	}
+
+	return
+}
+
+// ReplayEvents queues already published events for the relay again. Every sink and subscriber receives them
+// a second time, subscribers deduplicating by event id, such as notifications, skip them. Each replayed event
+// is recorded in the audit log with the operator.
+func (es *EventService) ReplayEvents(ctx context.Context, replayEventsRequest *model.ReplayEventsRequest) (replayed int64, err error) {
+	if (replayEventsRequest.EventID == "") == (replayEventsRequest.AggregateID == "") {
+		err = model.ErrorEventReplayFilterInvalid
+		return
+	}
+
+	err = es.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		events, err := es.OutboxRepository.ReplayEvents(ctx, replayEventsRequest.EventID, replayEventsRequest.AggregateID)
+		if err != nil {
+			return
+		}
+
+		for _, event := range events {
+			after := &model.EventDeliveryResponse{EventID: event.ID, Type: event.Type}
+			err = recordAudit(ctx, es.AuditRepository, model.AuditEntityOutboxEvent, event.ID, model.AuditActionUpdate, model.ComposeEventDeliveryResponse(event), after)
+			if err != nil {
+				return
+			}
+		}
+
+		replayed = int64(len(events))
+
+		return
+	})
+
+	return
+}
+
+// publish runs in a consumer span of the trace that recorded the event, so the sinks and subscribers
+// show up under the request that made the change.
+func (es *EventService) publish(ctx context.Context, event *model.Event) (err error) {
+	ctx = withTraceParent(ctx, event.TraceParent)
+	ctx, span := tracer.Start(ctx, "EventService.publish "+string(event.Type), trace.WithSpanKind(trace.SpanKindConsumer))
+	defer func() { endSpan(span, err) }()
+
+	envelope := model.ComposeEventEnvelope(event)
+
+	errs := []error{}
+	for _, sink := range es.Sinks {
+		if sinkErr := sink.Publish(ctx, envelope); sinkErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), sinkErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
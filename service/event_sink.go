@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/frencius/loan-service/model"
+)
+
+// IEventSink delivers relayed events to one destination. Delivery is at-least-once, so a sink
+// may see the same event again after a failure and consumers should deduplicate on the event id.
+type IEventSink interface {
+	Name() string
+	Publish(ctx context.Context, envelope *model.EventEnvelope) (err error)
+}
+
+// LogEventSink writes events to the service log.
+type LogEventSink struct{}
+
+func (les *LogEventSink) Name() string {
+	return "log"
+}
+
+func (les *LogEventSink) Publish(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	slog.InfoContext(ctx, "event", "event_id", envelope.EventID, "event_type", envelope.Type, "aggregate_type", envelope.AggregateType, "aggregate_id", envelope.AggregateID, "data", string(envelope.Data))
+
+	return
+}
+
+// WebhookEventSink posts every event as JSON to a single URL.
+type WebhookEventSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (wes *WebhookEventSink) Name() string {
+	return "webhook"
+}
+
+func (wes *WebhookEventSink) Publish(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wes.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", envelope.EventID)
+	req.Header.Set("X-Event-Type", string(envelope.Type))
+
+	resp, err := wes.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+		return
+	}
+
+	return
+}
+
+// EventHandler reacts to an event inside the service.
+type EventHandler func(ctx context.Context, envelope *model.EventEnvelope) error
+
+// EventBus dispatches events to in-process subscribers.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[model.EventType][]EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: map[model.EventType][]EventHandler{},
+	}
+}
+
+func (eb *EventBus) Name() string {
+	return "in-process"
+}
+
+func (eb *EventBus) Subscribe(eventType model.EventType, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.subscribers[eventType] = append(eb.subscribers[eventType], handler)
+}
+
+// Publish runs every subscriber of the event type and of model.EventTypeAll, a failing subscriber does not stop the others.
+func (eb *EventBus) Publish(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	eb.mu.RLock()
+	handlers := append(slices.Clone(eb.subscribers[envelope.Type]), eb.subscribers[model.EventTypeAll]...)
+	eb.mu.RUnlock()
+
+	errs := []error{}
+	for _, handler := range handlers {
+		if handlerErr := handler(ctx, envelope); handlerErr != nil {
+			errs = append(errs, handlerErr)
+		}
+	}
+
+	return errors.Join(errs...)
+}
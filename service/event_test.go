@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("EventService", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		mockOutboxRepo *mock.MockIOutboxRepository
+		mockAuditRepo  *mock.MockIAuditRepository
+		mockSink       *mock.MockIEventSink
+		bus            *service.EventBus
+		eventSvc       service.IEventService
+		now            time.Time
+		event          *model.Event
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockOutboxRepo = mock.NewMockIOutboxRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		mockSink = mock.NewMockIEventSink(mockCtrl)
+		mockSink.EXPECT().Name().Return("mock").AnyTimes()
+		bus = service.NewEventBus()
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		event = &model.Event{
+			ID:            "event-1",
+			Type:          model.EventTypeLoanApproved,
+			AggregateType: model.AggregateTypeLoan,
+			AggregateID:   "loan-1",
+			Payload:       []byte(`{"loan_id":"loan-1"}`),
+			Attempts:      1,
+		}
+
+		eventSvc = &service.EventService{
+			OutboxRepository:   mockOutboxRepo,
+			AuditRepository:    mockAuditRepo,
+			TransactionManager: &mock.MockTransactionManager{},
+			Sinks:              []service.IEventSink{bus, mockSink},
+			Bus:                bus,
+			Config: configuration.Event{
+				RelayBatchSize: 10,
+				RelayLease:     time.Minute,
+				BackoffBase:    5 * time.Second,
+				BackoffMax:     time.Minute,
+			},
+			Now: func() time.Time { return now },
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("RelayEvents", func() {
+		It("should publish to every sink and mark the event published", func() {
+			ctx := context.Background()
+			received := []string{}
+			eventSvc.Subscribe(model.EventTypeLoanApproved, func(_ context.Context, envelope *model.EventEnvelope) error {
+				received = append(received, envelope.EventID)
+				return nil
+			})
+
+			mockOutboxRepo.EXPECT().ClaimEvents(derivedContext(ctx), 10, time.Minute).Return([]*model.Event{event}, nil)
+			mockSink.EXPECT().
+				Publish(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, envelope *model.EventEnvelope) error {
+					Expect(envelope.Type).To(Equal(model.EventTypeLoanApproved))
+					Expect(envelope.AggregateID).To(Equal("loan-1"))
+					return nil
+				})
+			mockOutboxRepo.EXPECT().MarkEventPublished(derivedContext(ctx), "event-1").Return(nil)
+
+			published, err := eventSvc.RelayEvents(ctx)
+			Expect(err).To(BeNil())
+			Expect(published).To(Equal(1))
+			Expect(received).To(Equal([]string{"event-1"}))
+		})
+
+		It("should retry with backoff when a sink fails", func() {
+			ctx := context.Background()
+
+			mockOutboxRepo.EXPECT().ClaimEvents(derivedContext(ctx), 10, time.Minute).Return([]*model.Event{event}, nil)
+			mockSink.EXPECT().Publish(derivedContext(ctx), gomock.Any()).Return(errors.New("unavailable"))
+			// second attempt: 5s doubled once
+			mockOutboxRepo.EXPECT().MarkEventFailed(derivedContext(ctx), "event-1", "mock: unavailable", now.Add(10*time.Second)).Return(nil)
+
+			published, err := eventSvc.RelayEvents(ctx)
+			Expect(err).To(BeNil())
+			Expect(published).To(Equal(0))
+		})
+
+		It("should not publish anything when claiming fails", func() {
+			ctx := context.Background()
+
+			mockOutboxRepo.EXPECT().ClaimEvents(derivedContext(ctx), 10, time.Minute).Return(nil, errors.New("db down"))
+
+			published, err := eventSvc.RelayEvents(ctx)
+			Expect(err).To(MatchError("db down"))
+			Expect(published).To(Equal(0))
+		})
+	})
+
+	Context("ReplayEvents", func() {
+		It("should queue the events of an aggregate again and audit each of them", func() {
+			ctx := context.WithValue(context.Background(), "userID", "operator-1")
+			publishedAt := now.Add(-time.Hour)
+			replayedEvents := []*model.Event{
+				{ID: "event-1", Type: model.EventTypeLoanApproved, PublishedAt: &publishedAt, Attempts: 1},
+				{ID: "event-2", Type: model.EventTypeLoanPublished, Attempts: 3, LastError: "webhook: 503"},
+			}
+
+			auditLogs := []*model.AuditLog{}
+			mockOutboxRepo.EXPECT().ReplayEvents(derivedContext(ctx), "", "loan-1").Return(replayedEvents, nil)
+			mockAuditRepo.EXPECT().CreateAuditLog(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, auditLog *model.AuditLog) error {
+					auditLogs = append(auditLogs, auditLog)
+					return nil
+				}).Times(2)
+
+			replayed, err := eventSvc.ReplayEvents(ctx, &model.ReplayEventsRequest{AggregateID: "loan-1"})
+			Expect(err).To(BeNil())
+			Expect(replayed).To(Equal(int64(2)))
+
+			Expect(auditLogs[0].EntityType).To(Equal(model.AuditEntityOutboxEvent))
+			Expect(auditLogs[0].EntityID).To(Equal("event-1"))
+			Expect(auditLogs[0].Action).To(Equal(model.AuditActionUpdate))
+			Expect(auditLogs[0].ActorID).To(Equal("operator-1"))
+			Expect(auditLogs[0].Changes).To(MatchJSON(`{
+				"published_at": {"before": "2025-06-01T09:00:00Z"},
+				"attempts": {"before": 1, "after": 0}
+			}`))
+			Expect(auditLogs[1].EntityID).To(Equal("event-2"))
+			Expect(auditLogs[1].Changes).To(MatchJSON(`{
+				"attempts": {"before": 3, "after": 0},
+				"last_error": {"before": "webhook: 503"}
+			}`))
+		})
+
+		It("should fail the replay when it cannot be audited", func() {
+			ctx := context.Background()
+
+			mockOutboxRepo.EXPECT().ReplayEvents(derivedContext(ctx), "event-1", "").
+				Return([]*model.Event{{ID: "event-1", Type: model.EventTypeLoanApproved, Attempts: 1}}, nil)
+			mockAuditRepo.EXPECT().CreateAuditLog(derivedContext(ctx), gomock.Any()).Return(errors.New("db down"))
+
+			replayed, err := eventSvc.ReplayEvents(ctx, &model.ReplayEventsRequest{EventID: "event-1"})
+			Expect(err).To(MatchError("db down"))
+			Expect(replayed).To(Equal(int64(0)))
+		})
+
+		DescribeTable("should require exactly one filter",
+			func(replayEventsRequest *model.ReplayEventsRequest) {
+				_, err := eventSvc.ReplayEvents(context.Background(), replayEventsRequest)
+				Expect(err).To(Equal(model.ErrorEventReplayFilterInvalid))
+			},
+			Entry("without a filter", &model.ReplayEventsRequest{}),
+			Entry("with both filters", &model.ReplayEventsRequest{EventID: "event-1", AggregateID: "loan-1"}),
+		)
+	})
+})
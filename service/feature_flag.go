@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type IFeatureFlagService interface {
+	IsEnabled(ctx context.Context, key string, featureFlagContext *model.FeatureFlagContext) (enabled bool)
+	GetFeatureFlag(ctx context.Context, getFeatureFlagRequest *model.GetFeatureFlagRequest) (featureFlagResponse *model.FeatureFlagResponse, err error)
+	ListFeatureFlags(ctx context.Context) (featureFlagResponses []*model.FeatureFlagResponse, err error)
+	UpdateFeatureFlag(ctx context.Context, updateFeatureFlagRequest *model.UpdateFeatureFlagRequest) (featureFlagResponse *model.FeatureFlagResponse, err error)
+}
+
+// FeatureFlagService evaluates the flags stored in the database. Flags are cached for CacheTTL, so a change
+// made on another instance takes effect here within the TTL.
+type FeatureFlagService struct {
+	FeatureFlagRepository repository.IFeatureFlagRepository
+	TransactionManager    repository.ITransactionManager
+	AuditRepository       repository.IAuditRepository
+	CacheTTL              time.Duration
+	Now                   func() time.Time
+
+	mu       sync.Mutex
+	flags    map[string]*model.FeatureFlag
+	loadedAt time.Time
+}
+
+func NewFeatureFlagService(app *application.App) IFeatureFlagService {
+	return &FeatureFlagService{
+		FeatureFlagRepository: repository.NewFeatureFlagRepository(app),
+		TransactionManager:    repository.NewTransactionManager(app),
+		AuditRepository:       repository.NewAuditRepository(app),
+		CacheTTL:              app.Config.FeatureFlag.CacheTTL,
+		Now:                   time.Now,
+	}
+}
+
+// IsEnabled evaluates the flag for the context. An unknown flag is off, and so is every flag while the
+// flags cannot be loaded and none are cached yet.
+func (ffs *FeatureFlagService) IsEnabled(ctx context.Context, key string, featureFlagContext *model.FeatureFlagContext) (enabled bool) {
+	featureFlag, ok := ffs.cachedFlags(ctx)[key]
+	if !ok {
+		slog.DebugContext(ctx, "IsEnabled unknown feature flag", "key", key)
+		return false
+	}
+
+	return featureFlag.IsEnabledFor(featureFlagContext)
+}
+
+// cachedFlags reloads every flag once the cache is older than the TTL. A failed reload keeps serving the
+// flags loaded before.
+func (ffs *FeatureFlagService) cachedFlags(ctx context.Context) map[string]*model.FeatureFlag {
+	ffs.mu.Lock()
+	defer ffs.mu.Unlock()
+
+	now := ffs.Now()
+	if ffs.flags != nil && now.Sub(ffs.loadedAt) < ffs.CacheTTL {
+		return ffs.flags
+	}
+
+	featureFlags, err := ffs.FeatureFlagRepository.ListFeatureFlags(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "cachedFlags ListFeatureFlags error", "error", err)
+		if ffs.flags != nil {
+			ffs.loadedAt = now
+		}
+		return ffs.flags
+	}
+
+	ffs.flags = make(map[string]*model.FeatureFlag, len(featureFlags))
+	for _, featureFlag := range featureFlags {
+		ffs.flags[featureFlag.Key] = featureFlag
+	}
+	ffs.loadedAt = now
+
+	return ffs.flags
+}
+
+func (ffs *FeatureFlagService) GetFeatureFlag(ctx context.Context, getFeatureFlagRequest *model.GetFeatureFlagRequest) (featureFlagResponse *model.FeatureFlagResponse, err error) {
+	featureFlag, err := ffs.FeatureFlagRepository.GetFeatureFlagByKey(ctx, getFeatureFlagRequest.Key)
+	if err != nil {
+		return
+	}
+
+	featureFlagResponse = model.ComposeFeatureFlagResponse(featureFlag)
+
+	return
+}
+
+func (ffs *FeatureFlagService) ListFeatureFlags(ctx context.Context) (featureFlagResponses []*model.FeatureFlagResponse, err error) {
+	featureFlags, err := ffs.FeatureFlagRepository.ListFeatureFlags(ctx)
+	if err != nil {
+		return
+	}
+
+	featureFlagResponses = make([]*model.FeatureFlagResponse, 0, len(featureFlags))
+	for _, featureFlag := range featureFlags {
+		featureFlagResponses = append(featureFlagResponses, model.ComposeFeatureFlagResponse(featureFlag))
+	}
+
+	return
+}
+
+// UpdateFeatureFlag creates the flag or replaces its settings.
+func (ffs *FeatureFlagService) UpdateFeatureFlag(ctx context.Context, updateFeatureFlagRequest *model.UpdateFeatureFlagRequest) (featureFlagResponse *model.FeatureFlagResponse, err error) {
+	if !model.IsValidFeatureFlagKey(updateFeatureFlagRequest.Key) {
+		err = model.ErrorFeatureFlagKeyInvalid
+		return
+	}
+
+	featureFlag := &model.FeatureFlag{
+		Key:               updateFeatureFlagRequest.Key,
+		Description:       updateFeatureFlagRequest.Description,
+		Enabled:           updateFeatureFlagRequest.Enabled,
+		RolloutPercentage: updateFeatureFlagRequest.RolloutPercentage,
+		Rules:             updateFeatureFlagRequest.Rules,
+	}
+	featureFlag.UpdatedBy, _ = ctx.Value("userID").(string)
+
+	if featureFlag.Rules == nil {
+		featureFlag.Rules = []model.FeatureFlagRule{}
+	}
+
+	err = ffs.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		action := model.AuditActionUpdate
+		var before *model.FeatureFlagResponse
+		current, err := ffs.FeatureFlagRepository.GetFeatureFlagByKey(ctx, featureFlag.Key)
+		switch err {
+		case nil:
+			before = model.ComposeFeatureFlagResponse(current)
+		case model.ErrorFeatureFlagNotFound:
+			action = model.AuditActionCreate
+		default:
+			return
+		}
+
+		err = ffs.FeatureFlagRepository.UpsertFeatureFlag(ctx, featureFlag)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ffs.AuditRepository, model.AuditEntityFeatureFlag, featureFlag.Key, action, before, model.ComposeFeatureFlagResponse(featureFlag))
+	})
+	if err != nil {
+		return
+	}
+
+	featureFlagResponse = model.ComposeFeatureFlagResponse(featureFlag)
+
+	return
+}
+
+// requestFeatureFlagContext is the flag context of the user of the request, background jobs run as the
+// system employee.
+func requestFeatureFlagContext(ctx context.Context, productID string) *model.FeatureFlagContext {
+	userID, _ := ctx.Value("userID").(string)
+	role, _ := ctx.Value("userRole").(string)
+
+	return &model.FeatureFlagContext{
+		UserID:    userID,
+		Role:      role,
+		ProductID: productID,
+	}
+}
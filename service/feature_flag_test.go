@@ -0,0 +1,219 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("FeatureFlagService", func() {
+	var (
+		mockCtrl            *gomock.Controller
+		mockFeatureFlagRepo *mock.MockIFeatureFlagRepository
+		mockAuditRepo       *mock.MockIAuditRepository
+		featureFlagSvc      *service.FeatureFlagService
+		now                 time.Time
+		flags               []*model.FeatureFlag
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockFeatureFlagRepo = mock.NewMockIFeatureFlagRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		flags = []*model.FeatureFlag{
+			{
+				Key:     "loan.auto_cancel_expired",
+				Enabled: true,
+				Rules: []model.FeatureFlagRule{
+					{ProductIDs: []string{"product-1"}},
+					{Roles: []string{"employee"}, UserIDs: []string{"user-1"}},
+				},
+			},
+			{Key: "scoring.candidate_scorecard", Enabled: false, RolloutPercentage: 100},
+			{Key: "loan.half_rollout", Enabled: true, RolloutPercentage: 50},
+		}
+
+		featureFlagSvc = &service.FeatureFlagService{
+			FeatureFlagRepository: mockFeatureFlagRepo,
+			TransactionManager:    &mock.MockTransactionManager{},
+			AuditRepository:       mockAuditRepo,
+			CacheTTL:              30 * time.Second,
+			Now:                   func() time.Time { return now },
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("IsEnabled", func() {
+		BeforeEach(func() {
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return(flags, nil)
+		})
+
+		DescribeTable("should evaluate the rules of the flag",
+			func(key string, featureFlagContext *model.FeatureFlagContext, enabled bool) {
+				Expect(featureFlagSvc.IsEnabled(context.Background(), key, featureFlagContext)).To(Equal(enabled))
+			},
+			Entry("product rule", "loan.auto_cancel_expired", &model.FeatureFlagContext{ProductID: "product-1"}, true),
+			Entry("role and user rule", "loan.auto_cancel_expired", &model.FeatureFlagContext{UserID: "user-1", Role: "employee"}, true),
+			Entry("role without the user", "loan.auto_cancel_expired", &model.FeatureFlagContext{UserID: "user-2", Role: "employee"}, false),
+			Entry("no rule matching", "loan.auto_cancel_expired", &model.FeatureFlagContext{ProductID: "product-2"}, false),
+			Entry("disabled flag", "scoring.candidate_scorecard", &model.FeatureFlagContext{UserID: "user-1"}, false),
+			Entry("unknown flag", "loan.unknown", &model.FeatureFlagContext{UserID: "user-1"}, false),
+		)
+
+		It("should roll out to the same share of users every time", func() {
+			ctx := context.Background()
+
+			enabled := 0
+			for i := range 1000 {
+				featureFlagContext := &model.FeatureFlagContext{UserID: fmt.Sprintf("user-%d", i)}
+				first := featureFlagSvc.IsEnabled(ctx, "loan.half_rollout", featureFlagContext)
+				Expect(featureFlagSvc.IsEnabled(ctx, "loan.half_rollout", featureFlagContext)).To(Equal(first))
+				if first {
+					enabled++
+				}
+			}
+
+			Expect(enabled).To(BeNumerically("~", 500, 60))
+		})
+	})
+
+	Context("cache", func() {
+		It("should reload the flags once the cache expires", func() {
+			ctx := context.Background()
+			featureFlagContext := &model.FeatureFlagContext{ProductID: "product-1"}
+
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return(flags, nil)
+			Expect(featureFlagSvc.IsEnabled(ctx, "loan.auto_cancel_expired", featureFlagContext)).To(BeTrue())
+			Expect(featureFlagSvc.IsEnabled(ctx, "loan.auto_cancel_expired", featureFlagContext)).To(BeTrue())
+
+			now = now.Add(time.Minute)
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return([]*model.FeatureFlag{}, nil)
+			Expect(featureFlagSvc.IsEnabled(ctx, "loan.auto_cancel_expired", featureFlagContext)).To(BeFalse())
+		})
+
+		It("should keep the cached flags when a reload fails", func() {
+			ctx := context.Background()
+			featureFlagContext := &model.FeatureFlagContext{ProductID: "product-1"}
+
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return(flags, nil)
+			Expect(featureFlagSvc.IsEnabled(ctx, "loan.auto_cancel_expired", featureFlagContext)).To(BeTrue())
+
+			now = now.Add(time.Minute)
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return(nil, errors.New("db down"))
+			Expect(featureFlagSvc.IsEnabled(ctx, "loan.auto_cancel_expired", featureFlagContext)).To(BeTrue())
+		})
+	})
+
+	Context("UpdateFeatureFlag", func() {
+		It("should store the flag as the user and apply it once the cache expires", func() {
+			ctx := context.WithValue(context.Background(), "userID", "employee-1")
+			featureFlagContext := &model.FeatureFlagContext{UserID: "user-1"}
+
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return(flags, nil)
+			Expect(featureFlagSvc.IsEnabled(ctx, "scoring.candidate_scorecard", featureFlagContext)).To(BeFalse())
+
+			mockFeatureFlagRepo.EXPECT().
+				GetFeatureFlagByKey(ctx, "scoring.candidate_scorecard").
+				Return(flags[1], nil)
+			mockFeatureFlagRepo.EXPECT().
+				UpsertFeatureFlag(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, featureFlag *model.FeatureFlag) error {
+					Expect(featureFlag.Key).To(Equal("scoring.candidate_scorecard"))
+					Expect(featureFlag.UpdatedBy).To(Equal("employee-1"))
+					Expect(featureFlag.Rules).To(Equal([]model.FeatureFlagRule{}))
+					return nil
+				})
+
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityFeatureFlag))
+					Expect(auditLog.EntityID).To(Equal("scoring.candidate_scorecard"))
+					Expect(auditLog.Action).To(Equal(model.AuditActionUpdate))
+					Expect(auditLog.ActorID).To(Equal("employee-1"))
+					changes := map[string]model.AuditChange{}
+					Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+					Expect(changes).To(HaveKeyWithValue("enabled", model.AuditChange{Before: json.RawMessage("false"), After: json.RawMessage("true")}))
+					return nil
+				})
+
+			resp, err := featureFlagSvc.UpdateFeatureFlag(ctx, &model.UpdateFeatureFlagRequest{
+				Key:               "scoring.candidate_scorecard",
+				Enabled:           true,
+				RolloutPercentage: 100,
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.Enabled).To(BeTrue())
+			Expect(featureFlagSvc.IsEnabled(ctx, "scoring.candidate_scorecard", featureFlagContext)).To(BeFalse())
+
+			now = now.Add(time.Minute)
+			mockFeatureFlagRepo.EXPECT().ListFeatureFlags(gomock.Any()).Return([]*model.FeatureFlag{
+				{Key: "scoring.candidate_scorecard", Enabled: true, RolloutPercentage: 100},
+			}, nil)
+			Expect(featureFlagSvc.IsEnabled(ctx, "scoring.candidate_scorecard", featureFlagContext)).To(BeTrue())
+		})
+
+		It("should audit a new flag as created", func() {
+			ctx := context.WithValue(context.Background(), "userID", "employee-1")
+
+			mockFeatureFlagRepo.EXPECT().GetFeatureFlagByKey(ctx, "loan.new_flow").Return(nil, model.ErrorFeatureFlagNotFound)
+			mockFeatureFlagRepo.EXPECT().UpsertFeatureFlag(ctx, gomock.Any()).Return(nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.Action).To(Equal(model.AuditActionCreate))
+					return nil
+				})
+
+			_, err := featureFlagSvc.UpdateFeatureFlag(ctx, &model.UpdateFeatureFlagRequest{Key: "loan.new_flow", Enabled: true})
+			Expect(err).To(BeNil())
+		})
+
+		DescribeTable("should audit only a valid client IP as the source",
+			func(clientIP string, expected string) {
+				ctx := application.WithClientIP(context.WithValue(context.Background(), "userID", "employee-1"), clientIP)
+
+				mockFeatureFlagRepo.EXPECT().GetFeatureFlagByKey(ctx, "loan.new_flow").Return(nil, model.ErrorFeatureFlagNotFound)
+				mockFeatureFlagRepo.EXPECT().UpsertFeatureFlag(ctx, gomock.Any()).Return(nil)
+				mockAuditRepo.EXPECT().
+					CreateAuditLog(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+						Expect(auditLog.SourceIP).To(Equal(expected))
+						return nil
+					})
+
+				_, err := featureFlagSvc.UpdateFeatureFlag(ctx, &model.UpdateFeatureFlagRequest{Key: "loan.new_flow", Enabled: true})
+				Expect(err).To(BeNil())
+			},
+			Entry("IPv4", "203.0.113.7", "203.0.113.7"),
+			Entry("IPv6", "2001:0db8::0007", "2001:db8::7"),
+			Entry("IPv4-mapped IPv6", "::ffff:203.0.113.7", "203.0.113.7"),
+			Entry("IPv6 with a zone", "fe80::1%eth0", "fe80::1"),
+			Entry("not an IP", "client.example.com", ""),
+			Entry("an oversized value", strings.Repeat("203.0.113.7,", 20), ""),
+			Entry("no client address", "", ""),
+		)
+
+		It("should reject an invalid key", func() {
+			_, err := featureFlagSvc.UpdateFeatureFlag(context.Background(), &model.UpdateFeatureFlagRequest{Key: "Auto Cancel"})
+			Expect(err).To(Equal(model.ErrorFeatureFlagKeyInvalid))
+		})
+	})
+})
@@ -1,34 +1,149 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
 	"github.com/frencius/loan-service/model"
 	"github.com/frencius/loan-service/repository"
 )
 
 type IHealthCheckService interface {
-	Ping() (healthCheckResponse *model.HealthCheckResponse, err error)
+	Live(ctx context.Context) (healthCheckResponse *model.HealthCheckResponse)
+	Ready(ctx context.Context) (healthCheckResponse *model.HealthCheckResponse)
+}
+
+// HealthCheck checks one dependency. A failing critical check takes the service down, any other failing
+// check only degrades it. The error is shown in the readiness response, so keep it free of secrets.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
 }
 
 type HealthCheckService struct {
-	HealthCheckRepository repository.IHealthCheckRepository
+	Checks  []HealthCheck
+	Timeout time.Duration
 }
 
 func NewHealthCheckService(app *application.App) IHealthCheckService {
 	return &HealthCheckService{
-		HealthCheckRepository: repository.NewHealthCheckRepository(app),
+		Checks:  DependencyHealthChecks(repository.NewHealthCheckRepository(app), app.Config.HealthCheck),
+		Timeout: app.Config.HealthCheck.Timeout,
 	}
 }
 
-func (hcs *HealthCheckService) Ping() (healthCheckResponse *model.HealthCheckResponse, err error) {
-	ping, err := hcs.HealthCheckRepository.Ping()
-	if err != nil {
-		return
+// DependencyHealthChecks are the checks of the dependencies of the service. Register a check here when a
+// new dependency, such as an external provider, is added.
+func DependencyHealthChecks(healthCheckRepository repository.IHealthCheckRepository, config configuration.HealthCheck) []HealthCheck {
+	return []HealthCheck{
+		{
+			Name:     "database",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				if err := healthCheckRepository.PingDatabase(ctx); err != nil {
+					return errors.New("database is unreachable")
+				}
+				return nil
+			},
+		},
+		{
+			// a migration holds locks and changes the schema under the running code
+			Name:     "migrations",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				migrationState, err := healthCheckRepository.GetMigrationState(ctx)
+				if err != nil {
+					return errors.New("migration state is unreadable")
+				}
+				if migrationState.Dirty {
+					return fmt.Errorf("migration %d is running or failed", migrationState.Version)
+				}
+				return nil
+			},
+		},
+		{
+			// jobs are retried, so a lagging queue delays work without failing requests
+			Name: "job_queue",
+			Check: func(ctx context.Context) error {
+				lag, err := healthCheckRepository.GetJobQueueLag(ctx)
+				if err != nil {
+					return errors.New("job queue is unreadable")
+				}
+				if lag > config.JobQueueMaxLag {
+					return fmt.Errorf("oldest due job has waited %s", lag.Truncate(time.Second))
+				}
+				return nil
+			},
+		},
 	}
+}
+
+// Live reports that the process is able to serve, it checks no dependency so a failing dependency
+// does not get the service restarted.
+func (hcs *HealthCheckService) Live(ctx context.Context) (healthCheckResponse *model.HealthCheckResponse) {
+	return &model.HealthCheckResponse{Status: model.HealthStatusUp}
+}
 
+// Ready runs every check concurrently, each bounded by the timeout.
+func (hcs *HealthCheckService) Ready(ctx context.Context) (healthCheckResponse *model.HealthCheckResponse) {
 	healthCheckResponse = &model.HealthCheckResponse{
-		DatabaseStatus: ping.DatabaseStatus,
-		ServiceStatus:  ping.ServiceStatus,
+		Status: model.HealthStatusUp,
+		Checks: make([]*model.HealthCheckResult, len(hcs.Checks)),
+	}
+
+	wg := sync.WaitGroup{}
+	for i, healthCheck := range hcs.Checks {
+		wg.Add(1)
+		go func(i int, healthCheck HealthCheck) {
+			defer wg.Done()
+			healthCheckResponse.Checks[i] = hcs.runCheck(ctx, healthCheck)
+		}(i, healthCheck)
+	}
+	wg.Wait()
+
+	for _, result := range healthCheckResponse.Checks {
+		switch {
+		case result.Status == model.HealthStatusDown:
+			healthCheckResponse.Status = model.HealthStatusDown
+		case result.Status == model.HealthStatusDegraded && healthCheckResponse.Status == model.HealthStatusUp:
+			healthCheckResponse.Status = model.HealthStatusDegraded
+		}
+	}
+
+	return
+}
+
+func (hcs *HealthCheckService) runCheck(ctx context.Context, healthCheck HealthCheck) (result *model.HealthCheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, hcs.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := healthCheck.Check(ctx)
+
+	result = &model.HealthCheckResult{
+		Name:      healthCheck.Name,
+		Status:    model.HealthStatusUp,
+		Critical:  healthCheck.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err == nil {
+		return
+	}
+
+	result.Status = model.HealthStatusDegraded
+	if healthCheck.Critical {
+		result.Status = model.HealthStatusDown
+	}
+
+	result.Error = err.Error()
+	if ctx.Err() == context.DeadlineExceeded {
+		result.Error = fmt.Sprintf("timed out after %s", hcs.Timeout)
 	}
 
 	return
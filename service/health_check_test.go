@@ -1,8 +1,11 @@
 package service_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/frencius/loan-service/configuration"
 	"github.com/frencius/loan-service/mock"
 	"github.com/frencius/loan-service/model"
 	. "github.com/frencius/loan-service/service"
@@ -18,36 +21,93 @@ var _ = Describe("HealthCheckService", func() {
 	)
 
 	BeforeEach(func() {
-		mockRepo = &mock.MockHealthCheckRepository{}
+		mockRepo = &mock.MockHealthCheckRepository{
+			PingDatabaseFunc: func(ctx context.Context) error {
+				return nil
+			},
+			GetMigrationStateFunc: func(ctx context.Context) (*model.MigrationState, error) {
+				return &model.MigrationState{Version: 12}, nil
+			},
+			GetJobQueueLagFunc: func(ctx context.Context) (time.Duration, error) {
+				return time.Second, nil
+			},
+		}
 		service = &HealthCheckService{
-			HealthCheckRepository: mockRepo,
+			Checks:  DependencyHealthChecks(mockRepo, configuration.HealthCheck{JobQueueMaxLag: time.Minute}),
+			Timeout: 50 * time.Millisecond,
 		}
 	})
 
-	Describe("Ping", func() {
-		It("should return health check response when repository returns successfully", func() {
-			mockRepo.PingFunc = func() (*model.Ping, error) {
-				return &model.Ping{
-					DatabaseStatus: "OK",
-					ServiceStatus:  "OK",
-				}, nil
+	checkStatuses := func(resp *model.HealthCheckResponse) map[string]model.HealthStatus {
+		statuses := map[string]model.HealthStatus{}
+		for _, check := range resp.Checks {
+			statuses[check.Name] = check.Status
+		}
+		return statuses
+	}
+
+	Describe("Live", func() {
+		It("should be up without checking dependencies", func() {
+			mockRepo.PingDatabaseFunc = func(ctx context.Context) error {
+				return errors.New("database down")
+			}
+
+			resp := service.Live(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusUp))
+			Expect(resp.Checks).To(BeEmpty())
+		})
+	})
+
+	Describe("Ready", func() {
+		It("should be up when every dependency is healthy", func() {
+			resp := service.Ready(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusUp))
+			Expect(checkStatuses(resp)).To(Equal(map[string]model.HealthStatus{
+				"database":   model.HealthStatusUp,
+				"migrations": model.HealthStatusUp,
+				"job_queue":  model.HealthStatusUp,
+			}))
+		})
+
+		It("should be down when the database is unreachable", func() {
+			mockRepo.PingDatabaseFunc = func(ctx context.Context) error {
+				return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+			}
+
+			resp := service.Ready(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusDown))
+			Expect(resp.Checks[0].Error).To(Equal("database is unreachable"))
+		})
+
+		It("should be down while a migration runs", func() {
+			mockRepo.GetMigrationStateFunc = func(ctx context.Context) (*model.MigrationState, error) {
+				return &model.MigrationState{Version: 12, Dirty: true}, nil
+			}
+
+			resp := service.Ready(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusDown))
+			Expect(checkStatuses(resp)["migrations"]).To(Equal(model.HealthStatusDown))
+		})
+
+		It("should be degraded when the job queue lags", func() {
+			mockRepo.GetJobQueueLagFunc = func(ctx context.Context) (time.Duration, error) {
+				return 10 * time.Minute, nil
 			}
 
-			resp, err := service.Ping()
-			Expect(err).To(BeNil())
-			Expect(resp).NotTo(BeNil())
-			Expect(resp.DatabaseStatus).To(Equal("OK"))
-			Expect(resp.ServiceStatus).To(Equal("OK"))
+			resp := service.Ready(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusDegraded))
+			Expect(resp.Checks[2].Error).To(Equal("oldest due job has waited 10m0s"))
 		})
 
-		It("should return error when repository returns error", func() {
-			mockRepo.PingFunc = func() (*model.Ping, error) {
-				return nil, errors.New("database down")
+		It("should stop a check at the timeout", func() {
+			mockRepo.PingDatabaseFunc = func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
 			}
 
-			resp, err := service.Ping()
-			Expect(err).To(HaveOccurred())
-			Expect(resp).To(BeNil())
+			resp := service.Ready(context.Background())
+			Expect(resp.Status).To(Equal(model.HealthStatusDown))
+			Expect(resp.Checks[0].Error).To(Equal("timed out after 50ms"))
 		})
 	})
 })
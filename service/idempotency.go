@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type IIdempotencyService interface {
+	BeginRequest(ctx context.Context, beginIdempotentRequest *model.BeginIdempotentRequest) (replay *model.IdempotencyKey, err error)
+	CompleteRequest(ctx context.Context, completeIdempotentRequest *model.CompleteIdempotentRequest) (err error)
+	PurgeExpiredKeys(ctx context.Context) (purged int64, err error)
+}
+
+type IdempotencyService struct {
+	IdempotencyRepository repository.IIdempotencyRepository
+	Config                configuration.Idempotency
+	Now                   func() time.Time
+}
+
+func NewIdempotencyService(app *application.App) IIdempotencyService {
+	return &IdempotencyService{
+		IdempotencyRepository: repository.NewIdempotencyRepository(app),
+		Config:                app.Config.Idempotency,
+		Now:                   time.Now,
+	}
+}
+
+func hashIdempotentRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + "\n" + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func validateIdempotencyKey(key string) (err error) {
+	if len(key) > model.IdempotencyKeyMaxLength {
+		return model.ErrorIdempotencyKeyInvalid
+	}
+
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return model.ErrorIdempotencyKeyInvalid
+		}
+	}
+
+	return
+}
+
+// BeginRequest claims the key for the request. It returns the stored response when an identical request
+// already completed with the key, model.ErrorIdempotencyKeyMismatch when the key was used for another
+// request and model.ErrorIdempotencyKeyInProgress while the first request is still running. A request
+// processing for longer than the lease is taken to have died and its key is claimed again.
+func (is *IdempotencyService) BeginRequest(ctx context.Context, beginIdempotentRequest *model.BeginIdempotentRequest) (replay *model.IdempotencyKey, err error) {
+	err = validateIdempotencyKey(beginIdempotentRequest.Key)
+	if err != nil {
+		return
+	}
+
+	now := is.Now()
+	expiresAt := now.Add(is.Config.KeyTTL)
+	requestHash := hashIdempotentRequest(beginIdempotentRequest.Method, beginIdempotentRequest.Path, beginIdempotentRequest.Body)
+
+	created, err := is.IdempotencyRepository.CreateIdempotencyKey(ctx, &model.IdempotencyKey{
+		Key:         beginIdempotentRequest.Key,
+		UserID:      beginIdempotentRequest.UserID,
+		Method:      beginIdempotentRequest.Method,
+		Path:        beginIdempotentRequest.Path,
+		RequestHash: requestHash,
+		ExpiresAt:   &expiresAt,
+	}, now, now.Add(-is.Config.ProcessingLease))
+	if err != nil || created {
+		return
+	}
+
+	existing, err := is.IdempotencyRepository.GetIdempotencyKey(ctx, beginIdempotentRequest.UserID, beginIdempotentRequest.Key)
+	if err != nil {
+		// the key expired and was purged in between, the client can retry
+		if err == model.ErrorIdempotencyKeyNotFound {
+			err = model.ErrorIdempotencyKeyInProgress
+		}
+		return
+	}
+
+	if existing.RequestHash != requestHash {
+		err = model.ErrorIdempotencyKeyMismatch
+		return
+	}
+
+	if existing.Status != model.IdempotencyKeyStatusCompleted {
+		err = model.ErrorIdempotencyKeyInProgress
+		return
+	}
+
+	replay = existing
+
+	return
+}
+
+// CompleteRequest stores the response for replays. Server errors are not stored, the key is released
+// so the client can retry the request.
+func (is *IdempotencyService) CompleteRequest(ctx context.Context, completeIdempotentRequest *model.CompleteIdempotentRequest) (err error) {
+	if completeIdempotentRequest.ResponseCode >= http.StatusInternalServerError {
+		return is.IdempotencyRepository.DeleteIdempotencyKey(ctx, completeIdempotentRequest.UserID, completeIdempotentRequest.Key)
+	}
+
+	return is.IdempotencyRepository.CompleteIdempotencyKey(ctx,
+		completeIdempotentRequest.UserID,
+		completeIdempotentRequest.Key,
+		completeIdempotentRequest.ResponseCode,
+		completeIdempotentRequest.ResponseHeaders,
+		completeIdempotentRequest.ResponseBody,
+	)
+}
+
+func (is *IdempotencyService) PurgeExpiredKeys(ctx context.Context) (purged int64, err error) {
+	return is.IdempotencyRepository.DeleteExpiredIdempotencyKeys(ctx, is.Now())
+}
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("IdempotencyService", func() {
+	var (
+		mockCtrl            *gomock.Controller
+		mockIdempotencyRepo *mock.MockIIdempotencyRepository
+		idempotencySvc      service.IIdempotencyService
+		now                 time.Time
+		leaseExpiredAt      time.Time
+		request             *model.BeginIdempotentRequest
+		storedHash          string
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIdempotencyRepo = mock.NewMockIIdempotencyRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		leaseExpiredAt = now.Add(-time.Minute)
+
+		idempotencySvc = &service.IdempotencyService{
+			IdempotencyRepository: mockIdempotencyRepo,
+			Config:                configuration.Idempotency{KeyTTL: 24 * time.Hour, ProcessingLease: time.Minute},
+			Now:                   func() time.Time { return now },
+		}
+
+		request = &model.BeginIdempotentRequest{
+			UserID: "user-1",
+			Key:    "key-1",
+			Method: http.MethodPost,
+			Path:   "/v1/loans",
+			Body:   []byte(`{"borrower_id":"borrower-1"}`),
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	// claimedBy records the hash of the first request so later requests can be compared to it
+	claimedBy := func(first *model.BeginIdempotentRequest) {
+		mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), now, leaseExpiredAt).
+			DoAndReturn(func(_ context.Context, idempotencyKey *model.IdempotencyKey, _ time.Time, _ time.Time) (bool, error) {
+				expiresAt := now.Add(24 * time.Hour)
+				Expect(idempotencyKey.ExpiresAt).To(Equal(&expiresAt))
+				storedHash = idempotencyKey.RequestHash
+				return true, nil
+			})
+
+		replay, err := idempotencySvc.BeginRequest(context.Background(), first)
+		Expect(err).To(BeNil())
+		Expect(replay).To(BeNil())
+	}
+
+	Context("BeginRequest", func() {
+		It("should let the first request through", func() {
+			claimedBy(request)
+		})
+
+		It("should replay the stored response of an identical retry", func() {
+			claimedBy(request)
+
+			stored := &model.IdempotencyKey{
+				RequestHash:  storedHash,
+				Status:       model.IdempotencyKeyStatusCompleted,
+				ResponseCode: http.StatusOK,
+				ResponseBody: []byte(`{"code":200}`),
+			}
+			mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), now, leaseExpiredAt).Return(false, nil)
+			mockIdempotencyRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "user-1", "key-1").Return(stored, nil)
+
+			replay, err := idempotencySvc.BeginRequest(context.Background(), request)
+			Expect(err).To(BeNil())
+			Expect(replay).To(Equal(stored))
+		})
+
+		It("should reject a retry with a different body", func() {
+			claimedBy(request)
+
+			mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), now, leaseExpiredAt).Return(false, nil)
+			mockIdempotencyRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "user-1", "key-1").
+				Return(&model.IdempotencyKey{RequestHash: storedHash, Status: model.IdempotencyKeyStatusCompleted}, nil)
+
+			retry := *request
+			retry.Body = []byte(`{"borrower_id":"borrower-2"}`)
+			replay, err := idempotencySvc.BeginRequest(context.Background(), &retry)
+			Expect(err).To(Equal(model.ErrorIdempotencyKeyMismatch))
+			Expect(replay).To(BeNil())
+		})
+
+		It("should reject a retry while the first request is running", func() {
+			claimedBy(request)
+
+			mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), now, leaseExpiredAt).Return(false, nil)
+			mockIdempotencyRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "user-1", "key-1").
+				Return(&model.IdempotencyKey{RequestHash: storedHash, Status: model.IdempotencyKeyStatusProcessing}, nil)
+
+			_, err := idempotencySvc.BeginRequest(context.Background(), request)
+			Expect(err).To(Equal(model.ErrorIdempotencyKeyInProgress))
+		})
+
+		It("should let a retry through once the first request outlived its lease", func() {
+			claimedBy(request)
+
+			now = now.Add(2 * time.Minute)
+			leaseExpiredAt = now.Add(-time.Minute)
+			mockIdempotencyRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any(), now, leaseExpiredAt).Return(true, nil)
+
+			replay, err := idempotencySvc.BeginRequest(context.Background(), request)
+			Expect(err).To(BeNil())
+			Expect(replay).To(BeNil())
+		})
+
+		DescribeTable("should reject invalid keys",
+			func(key string) {
+				request.Key = key
+				_, err := idempotencySvc.BeginRequest(context.Background(), request)
+				Expect(err).To(Equal(model.ErrorIdempotencyKeyInvalid))
+			},
+			Entry("too long", strings.Repeat("k", model.IdempotencyKeyMaxLength+1)),
+			Entry("with spaces", "key 1"),
+		)
+	})
+
+	Context("CompleteRequest", func() {
+		It("should store the response with its headers", func() {
+			ctx := context.Background()
+			headers := map[string]string{"Content-Type": model.ProblemContentType}
+
+			mockIdempotencyRepo.EXPECT().CompleteIdempotencyKey(ctx, "user-1", "key-1", http.StatusBadRequest, headers, []byte(`{"code":400}`)).Return(nil)
+
+			err := idempotencySvc.CompleteRequest(ctx, &model.CompleteIdempotentRequest{
+				UserID:          "user-1",
+				Key:             "key-1",
+				ResponseCode:    http.StatusBadRequest,
+				ResponseHeaders: headers,
+				ResponseBody:    []byte(`{"code":400}`),
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should release the key after a server error", func() {
+			ctx := context.Background()
+
+			mockIdempotencyRepo.EXPECT().DeleteIdempotencyKey(ctx, "user-1", "key-1").Return(nil)
+
+			err := idempotencySvc.CompleteRequest(ctx, &model.CompleteIdempotentRequest{
+				UserID:       "user-1",
+				Key:          "key-1",
+				ResponseCode: http.StatusInternalServerError,
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+})
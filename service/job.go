@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/robfig/cron/v3"
+)
+
+const defaultListJobsLimit = 100
+
+type IJobService interface {
+	EnqueueJob(ctx context.Context, jobType model.JobType, payload any, runAt *time.Time) (jobID string, err error)
+	RegisterRecurringJob(ctx context.Context, name string, schedule string, jobType model.JobType) (err error)
+	ScheduleRecurringJobs(ctx context.Context) (jobIDs []string, err error)
+	ClaimJobs(ctx context.Context, workerID string, limit int) (jobs []*model.Job, err error)
+	CompleteJob(ctx context.Context, job *model.Job) (err error)
+	FailJob(ctx context.Context, job *model.Job, jobErr error) (err error)
+	ListJobs(ctx context.Context, listJobsRequest *model.ListJobsRequest) (jobResponses []*model.JobResponse, err error)
+	RetryJob(ctx context.Context, retryJobRequest *model.RetryJobRequest) (jobResponse *model.JobResponse, err error)
+}
+
+type JobService struct {
+	JobRepository repository.IJobRepository
+	Config        configuration.Job
+	Now           func() time.Time
+}
+
+func NewJobService(app *application.App) IJobService {
+	return &JobService{
+		JobRepository: repository.NewJobRepository(app),
+		Config:        app.Config.Job,
+		Now:           time.Now,
+	}
+}
+
+func (js *JobService) EnqueueJob(ctx context.Context, jobType model.JobType, payload any, runAt *time.Time) (jobID string, err error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "EnqueueJob Marshal error", "error", err)
+		return
+	}
+
+	return js.JobRepository.EnqueueJob(ctx, &model.Job{
+		Type:        jobType,
+		Payload:     payloadBytes,
+		MaxAttempts: js.Config.MaxAttempts,
+		RunAt:       runAt,
+	})
+}
+
+// RegisterRecurringJob stores a cron schedule (standard 5 field syntax or descriptors such as @hourly) for jobType.
+func (js *JobService) RegisterRecurringJob(ctx context.Context, name string, schedule string, jobType model.JobType) (err error) {
+	cronSchedule, err := cron.ParseStandard(schedule)
+	if err != nil {
+		slog.ErrorContext(ctx, "RegisterRecurringJob ParseStandard error", "error", err)
+		return
+	}
+
+	nextRunAt := cronSchedule.Next(js.Now())
+
+	return js.JobRepository.UpsertRecurringJob(ctx, &model.RecurringJob{
+		Name:        name,
+		Schedule:    schedule,
+		JobType:     jobType,
+		Payload:     json.RawMessage(`{}`),
+		MaxAttempts: js.Config.MaxAttempts,
+		NextRunAt:   &nextRunAt,
+	})
+}
+
+// ScheduleRecurringJobs enqueues one job for every due recurring job and moves it to its next run.
+// Runs missed while the service was down are collapsed into a single job.
+func (js *JobService) ScheduleRecurringJobs(ctx context.Context) (jobIDs []string, err error) {
+	now := js.Now()
+	recurringJobs, err := js.JobRepository.ListDueRecurringJobs(ctx, now)
+	if err != nil {
+		return
+	}
+
+	jobIDs = []string{}
+	for _, recurringJob := range recurringJobs {
+		cronSchedule, parseErr := cron.ParseStandard(recurringJob.Schedule)
+		if parseErr != nil {
+			slog.ErrorContext(ctx, "ScheduleRecurringJobs ParseStandard error", "recurring_job", recurringJob.Name, "error", parseErr)
+			continue
+		}
+
+		jobID, enqueueErr := js.JobRepository.EnqueueRecurringJob(ctx, recurringJob, cronSchedule.Next(now))
+		if enqueueErr != nil {
+			if enqueueErr != model.ErrorRecurringJobAlreadyScheduled {
+				slog.ErrorContext(ctx, "ScheduleRecurringJobs EnqueueRecurringJob error", "recurring_job", recurringJob.Name, "error", enqueueErr)
+			}
+			continue
+		}
+
+		jobIDs = append(jobIDs, jobID)
+	}
+
+	return
+}
+
+// ClaimJobs dead-letters the jobs whose lock expired on their last attempt, then claims the runnable jobs.
+func (js *JobService) ClaimJobs(ctx context.Context, workerID string, limit int) (jobs []*model.Job, err error) {
+	deadJobs, err := js.JobRepository.DeadLetterExpiredJobs(ctx, js.Config.LockTimeout)
+	if err != nil {
+		return
+	}
+
+	for _, job := range deadJobs {
+		slog.ErrorContext(ctx, "job is dead", "job_id", job.ID, "job_type", job.Type, "attempts", job.Attempts, "error", job.LastError)
+	}
+
+	return js.JobRepository.ClaimJobs(ctx, workerID, limit, js.Config.LockTimeout)
+}
+
+func (js *JobService) CompleteJob(ctx context.Context, job *model.Job) (err error) {
+	return js.JobRepository.CompleteJob(ctx, job.ID, job.LockedBy)
+}
+
+// FailJob schedules the next attempt with exponential backoff, or dead-letters the job once it has used all its attempts.
+func (js *JobService) FailJob(ctx context.Context, job *model.Job, jobErr error) (err error) {
+	var nextRunAt *time.Time
+	if job.Attempts < job.MaxAttempts {
+		runAt := js.Now().Add(exponentialBackoff(job.Attempts, js.Config.BackoffBase, js.Config.BackoffMax))
+		nextRunAt = &runAt
+	} else {
+		slog.ErrorContext(ctx, "job is dead", "job_id", job.ID, "job_type", job.Type, "attempts", job.Attempts, "error", jobErr)
+	}
+
+	return js.JobRepository.FailJob(ctx, job.ID, job.LockedBy, jobErr.Error(), nextRunAt)
+}
+
+func (js *JobService) ListJobs(ctx context.Context, listJobsRequest *model.ListJobsRequest) (jobResponses []*model.JobResponse, err error) {
+	if listJobsRequest.Status != "" && !model.ValidJobStatus[listJobsRequest.Status] {
+		err = model.ErrorJobStatusInvalid
+		return
+	}
+
+	limit := listJobsRequest.Limit
+	if limit <= 0 || limit > defaultListJobsLimit {
+		limit = defaultListJobsLimit
+	}
+
+	jobs, err := js.JobRepository.ListJobs(ctx, listJobsRequest.Status, limit)
+	if err != nil {
+		return
+	}
+
+	jobResponses = make([]*model.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponses = append(jobResponses, model.ComposeJobResponse(job))
+	}
+
+	return
+}
+
+func (js *JobService) RetryJob(ctx context.Context, retryJobRequest *model.RetryJobRequest) (jobResponse *model.JobResponse, err error) {
+	job, err := js.JobRepository.GetJobByID(ctx, retryJobRequest.JobID)
+	if err != nil {
+		return
+	}
+
+	if job.Status != model.JobStatusDead {
+		err = model.ErrorJobNotDead
+		return
+	}
+
+	err = js.JobRepository.RetryJob(ctx, job.ID)
+	if err != nil {
+		return
+	}
+
+	job, err = js.JobRepository.GetJobByID(ctx, job.ID)
+	if err != nil {
+		return
+	}
+
+	jobResponse = model.ComposeJobResponse(job)
+
+	return
+}
@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("JobService", func() {
+	var (
+		mockCtrl    *gomock.Controller
+		mockJobRepo *mock.MockIJobRepository
+		jobSvc      service.IJobService
+		now         time.Time
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockJobRepo = mock.NewMockIJobRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 10, 0, 30, 0, time.UTC)
+
+		jobSvc = &service.JobService{
+			JobRepository: mockJobRepo,
+			Config: configuration.Job{
+				MaxAttempts: 3,
+				LockTimeout: 5 * time.Minute,
+				BackoffBase: 10 * time.Second,
+				BackoffMax:  30 * time.Second,
+			},
+			Now: func() time.Time { return now },
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("FailJob", func() {
+		DescribeTable("should back off exponentially up to the maximum",
+			func(attempts int, delay time.Duration) {
+				ctx := context.Background()
+				job := &model.Job{ID: "job-1", Attempts: attempts, MaxAttempts: 5, LockedBy: "worker-1"}
+				nextRunAt := now.Add(delay)
+
+				mockJobRepo.EXPECT().FailJob(ctx, "job-1", "worker-1", "boom", &nextRunAt).Return(nil)
+
+				err := jobSvc.FailJob(ctx, job, errors.New("boom"))
+				Expect(err).To(BeNil())
+			},
+			Entry("first attempt", 1, 10*time.Second),
+			Entry("second attempt", 2, 20*time.Second),
+			Entry("capped", 4, 30*time.Second),
+		)
+
+		It("should dead-letter a job that used all its attempts", func() {
+			ctx := context.Background()
+			job := &model.Job{ID: "job-1", Attempts: 5, MaxAttempts: 5, LockedBy: "worker-1"}
+
+			mockJobRepo.EXPECT().FailJob(ctx, "job-1", "worker-1", "boom", nil).Return(nil)
+
+			err := jobSvc.FailJob(ctx, job, errors.New("boom"))
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("ClaimJobs", func() {
+		It("should dead-letter the jobs whose lock expired on their last attempt before claiming", func() {
+			ctx := context.Background()
+			claimed := []*model.Job{{ID: "job-2", Attempts: 1, MaxAttempts: 5}}
+
+			gomock.InOrder(
+				mockJobRepo.EXPECT().DeadLetterExpiredJobs(ctx, 5*time.Minute).Return([]*model.Job{{ID: "job-1", Attempts: 5, MaxAttempts: 5}}, nil),
+				mockJobRepo.EXPECT().ClaimJobs(ctx, "worker-1", 4, 5*time.Minute).Return(claimed, nil),
+			)
+
+			jobs, err := jobSvc.ClaimJobs(ctx, "worker-1", 4)
+			Expect(err).To(BeNil())
+			Expect(jobs).To(Equal(claimed))
+		})
+
+		It("should not claim when dead-lettering fails", func() {
+			ctx := context.Background()
+
+			mockJobRepo.EXPECT().DeadLetterExpiredJobs(ctx, 5*time.Minute).Return(nil, errors.New("connection reset"))
+
+			_, err := jobSvc.ClaimJobs(ctx, "worker-1", 4)
+			Expect(err).To(MatchError("connection reset"))
+		})
+	})
+
+	Context("RegisterRecurringJob", func() {
+		It("should store the next run of the cron schedule", func() {
+			ctx := context.Background()
+			nextRunAt := time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+
+			mockJobRepo.EXPECT().
+				UpsertRecurringJob(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, recurringJob *model.RecurringJob) error {
+					Expect(recurringJob.Name).To(Equal("cancel-expired-loans"))
+					Expect(recurringJob.JobType).To(Equal(model.JobTypeCancelExpiredLoans))
+					Expect(recurringJob.MaxAttempts).To(Equal(3))
+					Expect(*recurringJob.NextRunAt).To(Equal(nextRunAt))
+					return nil
+				})
+
+			err := jobSvc.RegisterRecurringJob(ctx, "cancel-expired-loans", "*/5 * * * *", model.JobTypeCancelExpiredLoans)
+			Expect(err).To(BeNil())
+		})
+
+		It("should reject an invalid schedule", func() {
+			err := jobSvc.RegisterRecurringJob(context.Background(), "broken", "every minute", model.JobTypeCancelExpiredLoans)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Context("ScheduleRecurringJobs", func() {
+		It("should enqueue due jobs and skip the ones scheduled by another instance", func() {
+			ctx := context.Background()
+			due := now.Add(-time.Minute)
+			recurringJobs := []*model.RecurringJob{
+				{Name: "cancel-expired-loans", Schedule: "* * * * *", NextRunAt: &due},
+				{Name: "other", Schedule: "@hourly", NextRunAt: &due},
+			}
+
+			mockJobRepo.EXPECT().ListDueRecurringJobs(ctx, now).Return(recurringJobs, nil)
+			mockJobRepo.EXPECT().
+				EnqueueRecurringJob(ctx, recurringJobs[0], time.Date(2025, 6, 1, 10, 1, 0, 0, time.UTC)).
+				Return("job-1", nil)
+			mockJobRepo.EXPECT().
+				EnqueueRecurringJob(ctx, recurringJobs[1], time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)).
+				Return("", model.ErrorRecurringJobAlreadyScheduled)
+
+			jobIDs, err := jobSvc.ScheduleRecurringJobs(ctx)
+			Expect(err).To(BeNil())
+			Expect(jobIDs).To(Equal([]string{"job-1"}))
+		})
+	})
+
+	Context("ListJobs", func() {
+		It("should reject an unknown status", func() {
+			resp, err := jobSvc.ListJobs(context.Background(), &model.ListJobsRequest{Status: "unknown"})
+			Expect(err).To(Equal(model.ErrorJobStatusInvalid))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should list dead jobs with the default limit", func() {
+			ctx := context.Background()
+			mockJobRepo.EXPECT().
+				ListJobs(ctx, model.JobStatusDead, 100).
+				Return([]*model.Job{{ID: "job-1", Type: model.JobTypeCancelExpiredLoans, Status: model.JobStatusDead}}, nil)
+
+			resp, err := jobSvc.ListJobs(ctx, &model.ListJobsRequest{Status: model.JobStatusDead})
+			Expect(err).To(BeNil())
+			Expect(resp).To(HaveLen(1))
+			Expect(resp[0].Status).To(Equal("dead"))
+		})
+	})
+
+	Context("RetryJob", func() {
+		It("should requeue a dead job", func() {
+			ctx := context.Background()
+			gomock.InOrder(
+				mockJobRepo.EXPECT().GetJobByID(ctx, "job-1").Return(&model.Job{ID: "job-1", Status: model.JobStatusDead}, nil),
+				mockJobRepo.EXPECT().RetryJob(ctx, "job-1").Return(nil),
+				mockJobRepo.EXPECT().GetJobByID(ctx, "job-1").Return(&model.Job{ID: "job-1", Status: model.JobStatusPending}, nil),
+			)
+
+			resp, err := jobSvc.RetryJob(ctx, &model.RetryJobRequest{JobID: "job-1"})
+			Expect(err).To(BeNil())
+			Expect(resp.Status).To(Equal("pending"))
+		})
+
+		It("should not retry a job that is not dead", func() {
+			ctx := context.Background()
+			mockJobRepo.EXPECT().GetJobByID(ctx, "job-1").Return(&model.Job{ID: "job-1", Status: model.JobStatusRunning}, nil)
+
+			resp, err := jobSvc.RetryJob(ctx, &model.RetryJobRequest{JobID: "job-1"})
+			Expect(err).To(Equal(model.ErrorJobNotDead))
+			Expect(resp).To(BeNil())
+		})
+	})
+})
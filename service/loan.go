@@ -2,8 +2,9 @@ package service
 
 import (
 	"context"
-
+	"log/slog"
 	"slices"
+	"time"
 
 	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/model"
@@ -13,55 +14,146 @@ import (
 type ILoanService interface {
 	CreateLoan(ctx context.Context, createLoanRequest *model.CreateLoanRequest) (createLoanResponse *model.CreateLoanResponse, err error)
 	UpdateLoanState(ctx context.Context, updateLoanStateRequest *model.UpdateLoanStateRequest) (updateLoanStateResponse *model.UpdateLoanStateResponse, err error)
+	ForceLoanState(ctx context.Context, forceLoanStateRequest *model.ForceLoanStateRequest) (updateLoanStateResponse *model.UpdateLoanStateResponse, err error)
 	CreateLoanInvestment(ctx context.Context, createLoanInvestmentRequest *model.CreateLoanInvestmentRequest) (createLoanInvestmentResponse *model.CreateLoanInvestmentResponse, err error)
+	GetLoan(ctx context.Context, getLoanRequest *model.GetLoanRequest) (loanResponse *model.LoanResponse, err error)
+	ListPublishedLoans(ctx context.Context, listPublishedLoansRequest *model.ListPublishedLoansRequest) (publishedLoanResponses []*model.PublishedLoanResponse, err error)
+	CancelExpiredLoans(ctx context.Context) (canceledLoanIDs []string, err error)
 }
 
 type LoanService struct {
-	LoanRepository       repository.ILoanRepository
-	BorrowerRepository   repository.IBorrowerRepository
-	InvestorRepository   repository.IInvestorRepository
-	InvestmentRepository repository.IInvestmentRepository
+	LoanRepository        repository.ILoanRepository
+	BorrowerRepository    repository.IBorrowerRepository
+	InvestorRepository    repository.IInvestorRepository
+	InvestmentRepository  repository.IInvestmentRepository
+	EmployeeRepository    repository.IEmployeeRepository
+	LoanProductRepository repository.ILoanProductRepository
+	RiskScorer            IRiskScorer
+	// CandidateRiskScorer replaces RiskScorer where the scoring.candidate_scorecard flag is on, when set
+	CandidateRiskScorer IRiskScorer
+	FeatureFlags        IFeatureFlagService
+	Notifier            INotifier
+	TransactionManager  repository.ITransactionManager
+	OutboxRepository    repository.IOutboxRepository
+	AuditRepository     repository.IAuditRepository
+	Now                 func() time.Time
 }
 
 func NewLoanService(app *application.App) ILoanService {
 	return &LoanService{
-		LoanRepository:       repository.NewLoanRepository(app),
-		BorrowerRepository:   repository.NewBorrowerRepository(app),
-		InvestorRepository:   repository.NewInvestorRepository(app),
-		InvestmentRepository: repository.NewInvestmentRepository(app),
+		LoanRepository:        repository.NewLoanRepository(app),
+		BorrowerRepository:    repository.NewBorrowerRepository(app),
+		InvestorRepository:    repository.NewInvestorRepository(app),
+		InvestmentRepository:  repository.NewInvestmentRepository(app),
+		EmployeeRepository:    repository.NewEmployeeRepository(app),
+		LoanProductRepository: repository.NewLoanProductRepository(app),
+		RiskScorer:            NewRiskScorer(app),
+		CandidateRiskScorer:   NewCandidateRiskScorer(app),
+		FeatureFlags:          NewFeatureFlagService(app),
+		Notifier:              NewNotifier(app),
+		TransactionManager:    repository.NewTransactionManager(app),
+		OutboxRepository:      repository.NewOutboxRepository(app),
+		AuditRepository:       repository.NewAuditRepository(app),
+		Now:                   time.Now,
 	}
 }
 
 func (ls *LoanService) CreateLoan(ctx context.Context, createLoanRequest *model.CreateLoanRequest) (createLoanResponse *model.CreateLoanResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.CreateLoan")
+	defer func() { endSpan(span, err) }()
+
 	// validate borrower_id is exist
-	_, err = ls.BorrowerRepository.GetBorrowerByID(ctx, createLoanRequest.BorrowerID)
+	borrower, err := ls.BorrowerRepository.GetBorrowerByID(ctx, createLoanRequest.BorrowerID)
+	if err != nil {
+		return
+	}
+
+	if borrower.ErasedAt != nil {
+		err = model.ErrorPersonalDataErased
+		return
+	}
+
+	// validate loan terms against the product
+	product, err := ls.LoanProductRepository.GetLoanProductByID(ctx, createLoanRequest.ProductID)
+	if err != nil {
+		return
+	}
+
+	err = product.ValidateLoan(
+		createLoanRequest.PrincipalAmount,
+		createLoanRequest.TenorMonths,
+		createLoanRequest.InterestRate,
+		createLoanRequest.ROIRate,
+	)
+	if err != nil {
+		return
+	}
+
+	// assess credit risk
+	loanStats, err := ls.LoanRepository.GetBorrowerLoanStats(ctx, borrower.ID)
+	if err != nil {
+		return
+	}
+
+	riskScorer := ls.RiskScorer
+	if ls.CandidateRiskScorer != nil && ls.FeatureFlags.IsEnabled(ctx, model.FeatureFlagCandidateScorecard, requestFeatureFlagContext(ctx, product.ID)) {
+		riskScorer = ls.CandidateRiskScorer
+	}
+
+	risk, err := riskScorer.Score(ctx, &model.RiskScoringInput{
+		Borrower:        borrower,
+		LoanStats:       loanStats,
+		Product:         product,
+		PrincipalAmount: createLoanRequest.PrincipalAmount,
+	})
 	if err != nil {
 		return
 	}
 
 	loan := &model.Loan{
 		BorrowerID:      createLoanRequest.BorrowerID,
+		ProductID:       product.ID,
+		TenorMonths:     createLoanRequest.TenorMonths,
 		PrincipalAmount: createLoanRequest.PrincipalAmount,
 		InterestRate:    createLoanRequest.InterestRate,
 		ROIRate:         createLoanRequest.ROIRate,
 		State:           model.LoanStateProposed,
 		CreatedBy:       ctx.Value("userID").(string),
+		Risk:            risk,
 	}
 
-	loanID, err := ls.LoanRepository.CreateLoan(ctx, loan)
+	err = ls.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		loan.ID, err = ls.LoanRepository.CreateLoan(ctx, loan)
+		if err != nil {
+			return
+		}
+
+		err = recordAudit(ctx, ls.AuditRepository, model.AuditEntityLoan, loan.ID, model.AuditActionCreate, nil, model.ComposeLoanResponse(loan, nil))
+		if err != nil {
+			return
+		}
+
+		return recordEvent(ctx, ls.OutboxRepository, model.EventTypeLoanCreated, model.AggregateTypeLoan, loan.ID,
+			model.ComposeLoanEventPayload(loan, "", "", loan.CreatedBy))
+	})
 	if err != nil {
 		return
 	}
 
 	createLoanResponse = &model.CreateLoanResponse{
-		LoanID: loanID,
+		LoanID: loan.ID,
 		State:  string(loan.State),
+		Fees:   product.ComputeFees(loan.PrincipalAmount),
+		Risk:   model.ComposeLoanRiskResponse(risk),
 	}
 
 	return
 }
 
 func (ls *LoanService) UpdateLoanState(ctx context.Context, updateLoanStateRequest *model.UpdateLoanStateRequest) (updateLoanStateResponse *model.UpdateLoanStateResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.UpdateLoanState")
+	defer func() { endSpan(span, err) }()
+
 	loanID := updateLoanStateRequest.LoanID
 	newLoanState := model.LoanState(updateLoanStateRequest.State)
 
@@ -70,6 +162,13 @@ func (ls *LoanService) UpdateLoanState(ctx context.Context, updateLoanStateReque
 	if err != nil {
 		return
 	}
+	before := model.ComposeLoanResponse(loan, nil)
+
+	// validate the client changes the loan version it last read
+	if updateLoanStateRequest.ExpectedVersion != 0 && updateLoanStateRequest.ExpectedVersion != loan.Version {
+		err = model.ErrorLoanVersionMismatch
+		return
+	}
 
 	// validate loan state
 	if !model.ValidLoanState[newLoanState] {
@@ -83,21 +182,148 @@ func (ls *LoanService) UpdateLoanState(ctx context.Context, updateLoanStateReque
 		return
 	}
 
+	// apply request data needed by the target state
+	switch newLoanState {
+	case model.LoanStateCanceled:
+		loan.CanceledReason = updateLoanStateRequest.Reason
+	case model.LoanStateRejected:
+		loan.RejectedReason = updateLoanStateRequest.Reason
+	case model.LoanStatePublished:
+		err = ls.setFundingDeadline(ctx, loan)
+		if err != nil {
+			return
+		}
+	}
+
 	// state validation
 	if !ls.isStateRequirementValid(loan, newLoanState) {
 		err = model.ErrorStateTransitionRequirementNotFulfilled
 		return
 	}
 
-	// update state
-	err = ls.LoanRepository.UpdateLoanState(ctx, loan, newLoanState)
+	err = ls.commitLoanState(ctx, loan, before, newLoanState, updateLoanStateRequest.Reason, false)
+	if err != nil {
+		// internal callers did not send a version, for them any concurrent change is a conflict
+		if err == model.ErrorLoanVersionMismatch && updateLoanStateRequest.ExpectedVersion == 0 {
+			err = model.ErrorLoanUpdateConflict
+		}
+		return
+	}
+
+	updateLoanStateResponse = &model.UpdateLoanStateResponse{
+		LoanID:  loan.ID,
+		State:   string(loan.State),
+		Version: loan.Version,
+	}
+
+	return
+}
+
+// ForceLoanState moves the loan to the requested state without checking the transition or the state
+// requirements. The event it emits is marked forced and carries the reason and the operator.
+func (ls *LoanService) ForceLoanState(ctx context.Context, forceLoanStateRequest *model.ForceLoanStateRequest) (updateLoanStateResponse *model.UpdateLoanStateResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.ForceLoanState")
+	defer func() { endSpan(span, err) }()
+
+	if _, err = model.IsValid(forceLoanStateRequest); err != nil {
+		return
+	}
+
+	newLoanState := model.LoanState(forceLoanStateRequest.State)
+	if !model.ValidLoanState[newLoanState] {
+		err = model.ErrorLoanStateInvalid
+		return
+	}
+
+	loan, err := ls.LoanRepository.GetLoanByID(ctx, forceLoanStateRequest.LoanID)
 	if err != nil {
 		return
 	}
+	before := model.ComposeLoanResponse(loan, nil)
+
+	if loan.State == newLoanState {
+		err = model.ErrorTransitionToTheSameState
+		return
+	}
+
+	switch newLoanState {
+	case model.LoanStateCanceled:
+		loan.CanceledReason = forceLoanStateRequest.Reason
+	case model.LoanStateRejected:
+		loan.RejectedReason = forceLoanStateRequest.Reason
+	case model.LoanStatePublished:
+		err = ls.setFundingDeadline(ctx, loan)
+		if err != nil {
+			return
+		}
+	}
+
+	err = ls.commitLoanState(ctx, loan, before, newLoanState, forceLoanStateRequest.Reason, true)
+	if err != nil {
+		if err == model.ErrorLoanVersionMismatch {
+			err = model.ErrorLoanUpdateConflict
+		}
+		return
+	}
+
+	updateLoanStateResponse = &model.UpdateLoanStateResponse{
+		LoanID:  loan.ID,
+		State:   string(loan.State),
+		Version: loan.Version,
+	}
+
+	return
+}
+
+// commitLoanState updates the state, emits the transition event and audits the change from before atomically.
+func (ls *LoanService) commitLoanState(ctx context.Context, loan *model.Loan, before *model.LoanResponse, newLoanState model.LoanState, reason string, forced bool) (err error) {
+	return ls.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = ls.LoanRepository.UpdateLoanState(ctx, loan, newLoanState)
+		if err != nil {
+			return
+		}
+
+		previousState := loan.State
+		loan.State = newLoanState
+		loan.Version++
+		actorID, _ := ctx.Value("userID").(string)
+
+		err = recordAudit(ctx, ls.AuditRepository, model.AuditEntityLoan, loan.ID, model.AuditActionUpdate, before, model.ComposeLoanResponse(loan, nil))
+		if err != nil {
+			return
+		}
+
+		payload := model.ComposeLoanEventPayload(loan, previousState, reason, actorID)
+		payload.Forced = forced
+
+		return recordEvent(ctx, ls.OutboxRepository, model.LoanStateEventTypes[newLoanState], model.AggregateTypeLoan, loan.ID, payload)
+	})
+}
+
+// setFundingDeadline starts the funding window of the loan product when the loan is published.
+func (ls *LoanService) setFundingDeadline(ctx context.Context, loan *model.Loan) (err error) {
+	product := &model.LoanProduct{FundingWindowDays: model.DefaultFundingWindowDays}
+	if loan.ProductID != "" && loan.ProductID != model.NilUUID {
+		product, err = ls.LoanProductRepository.GetLoanProductByID(ctx, loan.ProductID)
+		if err != nil {
+			return
+		}
+	}
+
+	fundingDeadline := product.FundingDeadline(ls.now())
+	loan.FundingDeadline = &fundingDeadline
 
 	return
 }
 
+func (ls *LoanService) now() time.Time {
+	if ls.Now == nil {
+		return time.Now()
+	}
+
+	return ls.Now()
+}
+
 func (ls *LoanService) canStateTransition(from, to model.LoanState) bool {
 	validNextStates, ok := model.ValidStateTransitions[from]
 	if !ok {
@@ -141,6 +367,9 @@ func (ls *LoanService) isStateRequirementValid(loan *model.Loan, loanState model
 }
 
 func (ls *LoanService) CreateLoanInvestment(ctx context.Context, createLoanInvestmentRequest *model.CreateLoanInvestmentRequest) (createLoanInvestmentResponse *model.CreateLoanInvestmentResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.CreateLoanInvestment")
+	defer func() { endSpan(span, err) }()
+
 	loanID := createLoanInvestmentRequest.LoanID
 	investorID := createLoanInvestmentRequest.InvestorID
 	investedAmount := createLoanInvestmentRequest.InvestmentAmount
@@ -156,12 +385,22 @@ func (ls *LoanService) CreateLoanInvestment(ctx context.Context, createLoanInves
 		return
 	}
 
+	if loan.FundingDeadline != nil && ls.now().After(*loan.FundingDeadline) {
+		err = model.ErrorFundingWindowExpired
+		return
+	}
+
 	// validate investor
 	investor, err := ls.InvestorRepository.GetInvestorByID(ctx, investorID)
 	if err != nil {
 		return
 	}
 
+	if investor.ErasedAt != nil {
+		err = model.ErrorPersonalDataErased
+		return
+	}
+
 	// validate existing investment
 	investment, err := ls.InvestmentRepository.GetInvestmentByInvestorID(ctx, investorID)
 	if err != nil && err != model.ErrorInvestmentNotFound {
@@ -178,43 +417,206 @@ func (ls *LoanService) CreateLoanInvestment(ctx context.Context, createLoanInves
 		LoanID:         loan.ID,
 		InvestorID:     investor.ID,
 		InvestedAmount: investedAmount,
+		Status:         model.InvestmentStatusActive,
+	}
+	before := model.ComposeLoanResponse(loan, nil)
+
+	err = ls.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		newInvestment.ID, err = ls.InvestmentRepository.CreateInvestment(ctx, newInvestment)
+		if err != nil {
+			return
+		}
+
+		err = recordAudit(ctx, ls.AuditRepository, model.AuditEntityInvestment, newInvestment.ID, model.AuditActionCreate, nil, model.ComposeInvestmentResponse(newInvestment))
+		if err != nil {
+			return
+		}
+
+		// update loan total invested amount
+		loan.TotalInvestedAmount += investedAmount
+		err = ls.LoanRepository.UpdateLoanTotalInvestedAmount(ctx, loan)
+		if err != nil {
+			return
+		}
+		loan.Version++
+
+		err = recordAudit(ctx, ls.AuditRepository, model.AuditEntityLoan, loan.ID, model.AuditActionUpdate, before, model.ComposeLoanResponse(loan, nil))
+		if err != nil {
+			return
+		}
+
+		err = recordEvent(ctx, ls.OutboxRepository, model.EventTypeInvestmentCreated, model.AggregateTypeInvestment, newInvestment.ID,
+			&model.InvestmentEventPayload{
+				InvestmentID:        newInvestment.ID,
+				LoanID:              loan.ID,
+				InvestorID:          investor.ID,
+				InvestedAmount:      investedAmount,
+				TotalInvestedAmount: loan.TotalInvestedAmount,
+			})
+		if err != nil {
+			return
+		}
+
+		// update state if eligible
+		if loan.TotalInvestedAmount < loan.PrincipalAmount {
+			return
+		}
+
+		// TODO: generate aggrement letter, investors are emailed the letter url on loan.fully_funded
+		updateLoanStateRequest := model.UpdateLoanStateRequest{
+			LoanID: loan.ID,
+			State:  string(model.LoanStateInvested),
+		}
+
+		_, err = ls.UpdateLoanState(ctx, &updateLoanStateRequest)
+
+		return
+	})
+	if err != nil {
+		return
 	}
 
-	investmentID, err := ls.InvestmentRepository.CreateInvestment(ctx, newInvestment)
+	createLoanInvestmentResponse = &model.CreateLoanInvestmentResponse{
+		InvestmentID: newInvestment.ID,
+	}
+
+	return
+}
+
+func (ls *LoanService) GetLoan(ctx context.Context, getLoanRequest *model.GetLoanRequest) (loanResponse *model.LoanResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.GetLoan")
+	defer func() { endSpan(span, err) }()
+
+	loan, err := ls.LoanRepository.GetLoanByID(ctx, getLoanRequest.LoanID)
 	if err != nil {
 		return
 	}
 
-	// update loan total invested amount
-	loan.TotalInvestedAmount += investedAmount
-	err = ls.LoanRepository.UpdateLoanTotalInvestedAmount(ctx, loan)
+	// resolve actor ids into employee names
+	employees, err := ls.EmployeeRepository.GetEmployeesByIDs(ctx, loan.ActorIDs())
 	if err != nil {
 		return
 	}
 
-	// update state if eligible
-	oldLoanState := loan.State
-	if loan.TotalInvestedAmount >= loan.PrincipalAmount {
-		loan.State = model.LoanStateInvested
-		// TODO:
-		// 1. generate aggrement letter
-		// 2. send aggreement letter url to investors email
+	employeeByID := make(map[string]*model.Employee, len(employees))
+	for _, employee := range employees {
+		employeeByID[employee.ID] = employee
 	}
 
-	if oldLoanState != loan.State {
-		updateLoanStateRequest := model.UpdateLoanStateRequest{
+	loanResponse = model.ComposeLoanResponse(loan, employeeByID)
+
+	return
+}
+
+func (ls *LoanService) ListPublishedLoans(ctx context.Context, listPublishedLoansRequest *model.ListPublishedLoansRequest) (publishedLoanResponses []*model.PublishedLoanResponse, err error) {
+	ctx, span := startSpan(ctx, "LoanService.ListPublishedLoans")
+	defer func() { endSpan(span, err) }()
+
+	loans, err := ls.LoanRepository.ListLoansByState(ctx, model.LoanStatePublished)
+	if err != nil {
+		return
+	}
+
+	publishedLoanResponses = make([]*model.PublishedLoanResponse, 0, len(loans))
+	for _, loan := range loans {
+		publishedLoan := model.ComposePublishedLoanResponse(loan)
+		if listPublishedLoansRequest.RiskGrade != "" && publishedLoan.RiskGrade != listPublishedLoansRequest.RiskGrade {
+			continue
+		}
+		publishedLoanResponses = append(publishedLoanResponses, publishedLoan)
+	}
+
+	return
+}
+
+// CancelExpiredLoans cancels published loans whose funding window ended without full funding,
+// releases the investments placed on them and notifies the borrower and investors.
+func (ls *LoanService) CancelExpiredLoans(ctx context.Context) (canceledLoanIDs []string, err error) {
+	ctx, span := startSpan(ctx, "LoanService.CancelExpiredLoans")
+	defer func() { endSpan(span, err) }()
+
+	loans, err := ls.LoanRepository.ListExpiredPublishedLoans(ctx, ls.now())
+	if err != nil {
+		return
+	}
+
+	// changes are recorded under the system actor
+	systemCtx := context.WithValue(ctx, "userID", model.SystemEmployeeID)
+
+	canceledLoanIDs = []string{}
+	for _, loan := range loans {
+		if !ls.FeatureFlags.IsEnabled(systemCtx, model.FeatureFlagAutoCancelExpiredLoans, requestFeatureFlagContext(systemCtx, loan.ProductID)) {
+			slog.InfoContext(ctx, "CancelExpiredLoans auto cancel is off for the loan", "loan_id", loan.ID)
+			continue
+		}
+
+		cancelErr := ls.cancelExpiredLoan(systemCtx, loan)
+		if cancelErr != nil {
+			slog.ErrorContext(ctx, "CancelExpiredLoans cancelExpiredLoan error", "loan_id", loan.ID, "error", cancelErr)
+			continue
+		}
+		canceledLoanIDs = append(canceledLoanIDs, loan.ID)
+	}
+
+	return
+}
+
+func (ls *LoanService) cancelExpiredLoan(ctx context.Context, loan *model.Loan) (err error) {
+	investorIDs := []string{}
+	err = ls.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		_, err = ls.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{
 			LoanID: loan.ID,
-			State:  string(loan.State),
+			State:  string(model.LoanStateCanceled),
+			Reason: model.ReasonFundingWindowExpired,
+		})
+		if err != nil {
+			return
 		}
 
-		_, err = ls.UpdateLoanState(ctx, &updateLoanStateRequest)
+		investments, err := ls.InvestmentRepository.ReleaseInvestmentsByLoanID(ctx, loan.ID)
 		if err != nil {
 			return
 		}
+
+		if len(investments) == 0 {
+			return
+		}
+
+		for _, investment := range investments {
+			investorIDs = append(investorIDs, investment.InvestorID)
+
+			// only active investments are released
+			active := *investment
+			active.Status = model.InvestmentStatusActive
+			active.ReleasedAt = nil
+			err = recordAudit(ctx, ls.AuditRepository, model.AuditEntityInvestment, investment.ID, model.AuditActionUpdate,
+				model.ComposeInvestmentResponse(&active), model.ComposeInvestmentResponse(investment))
+			if err != nil {
+				return
+			}
+		}
+
+		return recordEvent(ctx, ls.OutboxRepository, model.EventTypeInvestmentsReleased, model.AggregateTypeLoan, loan.ID,
+			&model.InvestmentEventPayload{
+				LoanID:              loan.ID,
+				InvestorIDs:         investorIDs,
+				TotalInvestedAmount: loan.TotalInvestedAmount,
+			})
+	})
+	if err != nil {
+		return
 	}
 
-	createLoanInvestmentResponse = &model.CreateLoanInvestmentResponse{
-		InvestmentID: investmentID,
+	// a failed notification must not undo the cancellation
+	notifyErr := ls.Notifier.Notify(ctx, &model.Notification{
+		Event:       model.NotificationEventLoanCanceled,
+		LoanID:      loan.ID,
+		BorrowerID:  loan.BorrowerID,
+		InvestorIDs: investorIDs,
+		Reason:      model.ReasonFundingWindowExpired,
+	})
+	if notifyErr != nil {
+		slog.ErrorContext(ctx, "cancelExpiredLoan Notify error", "loan_id", loan.ID, "error", notifyErr)
 	}
 
 	return
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type ILoanProductService interface {
+	CreateLoanProduct(ctx context.Context, createLoanProductRequest *model.CreateLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error)
+	GetLoanProduct(ctx context.Context, getLoanProductRequest *model.GetLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error)
+	ListLoanProducts(ctx context.Context, listLoanProductsRequest *model.ListLoanProductsRequest) (loanProductResponses []*model.LoanProductResponse, err error)
+	UpdateLoanProduct(ctx context.Context, updateLoanProductRequest *model.UpdateLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error)
+}
+
+type LoanProductService struct {
+	LoanProductRepository repository.ILoanProductRepository
+	TransactionManager    repository.ITransactionManager
+	AuditRepository       repository.IAuditRepository
+}
+
+func NewLoanProductService(app *application.App) ILoanProductService {
+	return &LoanProductService{
+		LoanProductRepository: repository.NewLoanProductRepository(app),
+		TransactionManager:    repository.NewTransactionManager(app),
+		AuditRepository:       repository.NewAuditRepository(app),
+	}
+}
+
+func (lps *LoanProductService) CreateLoanProduct(ctx context.Context, createLoanProductRequest *model.CreateLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error) {
+	product := &model.LoanProduct{
+		Code:               createLoanProductRequest.Code,
+		Name:               createLoanProductRequest.Name,
+		Description:        createLoanProductRequest.Description,
+		MinPrincipalAmount: createLoanProductRequest.MinPrincipalAmount,
+		MaxPrincipalAmount: createLoanProductRequest.MaxPrincipalAmount,
+		TenorMonths:        createLoanProductRequest.TenorMonths,
+		MinInterestRate:    createLoanProductRequest.MinInterestRate,
+		MaxInterestRate:    createLoanProductRequest.MaxInterestRate,
+		MinROIRate:         createLoanProductRequest.MinROIRate,
+		MaxROIRate:         createLoanProductRequest.MaxROIRate,
+		Fees:               createLoanProductRequest.Fees,
+		FundingWindowDays:  createLoanProductRequest.FundingWindowDays,
+		IsActive:           true,
+	}
+
+	if !product.IsValid() {
+		err = model.ErrorLoanProductInvalid
+		return
+	}
+
+	if product.Fees == nil {
+		product.Fees = []model.LoanProductFee{}
+	}
+	for i := range product.Fees {
+		if product.Fees[i].Trigger == "" {
+			product.Fees[i].Trigger = model.LoanProductFeeTriggerUpfront
+		}
+	}
+
+	if product.FundingWindowDays == 0 {
+		product.FundingWindowDays = model.DefaultFundingWindowDays
+	}
+
+	err = lps.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		product.ID, err = lps.LoanProductRepository.CreateLoanProduct(ctx, product)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, lps.AuditRepository, model.AuditEntityLoanProduct, product.ID, model.AuditActionCreate, nil, model.ComposeLoanProductResponse(product))
+	})
+	if err != nil {
+		return
+	}
+
+	loanProductResponse = model.ComposeLoanProductResponse(product)
+
+	return
+}
+
+func (lps *LoanProductService) GetLoanProduct(ctx context.Context, getLoanProductRequest *model.GetLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error) {
+	product, err := lps.LoanProductRepository.GetLoanProductByID(ctx, getLoanProductRequest.ProductID)
+	if err != nil {
+		return
+	}
+
+	loanProductResponse = model.ComposeLoanProductResponse(product)
+
+	return
+}
+
+func (lps *LoanProductService) ListLoanProducts(ctx context.Context, listLoanProductsRequest *model.ListLoanProductsRequest) (loanProductResponses []*model.LoanProductResponse, err error) {
+	products, err := lps.LoanProductRepository.ListLoanProducts(ctx, listLoanProductsRequest.ActiveOnly)
+	if err != nil {
+		return
+	}
+
+	loanProductResponses = make([]*model.LoanProductResponse, 0, len(products))
+	for _, product := range products {
+		loanProductResponses = append(loanProductResponses, model.ComposeLoanProductResponse(product))
+	}
+
+	return
+}
+
+func (lps *LoanProductService) UpdateLoanProduct(ctx context.Context, updateLoanProductRequest *model.UpdateLoanProductRequest) (loanProductResponse *model.LoanProductResponse, err error) {
+	product, err := lps.LoanProductRepository.GetLoanProductByID(ctx, updateLoanProductRequest.ProductID)
+	if err != nil {
+		return
+	}
+
+	before := model.ComposeLoanProductResponse(product)
+	product.Name = updateLoanProductRequest.Name
+	product.Description = updateLoanProductRequest.Description
+	product.MinPrincipalAmount = updateLoanProductRequest.MinPrincipalAmount
+	product.MaxPrincipalAmount = updateLoanProductRequest.MaxPrincipalAmount
+	product.TenorMonths = updateLoanProductRequest.TenorMonths
+	product.MinInterestRate = updateLoanProductRequest.MinInterestRate
+	product.MaxInterestRate = updateLoanProductRequest.MaxInterestRate
+	product.MinROIRate = updateLoanProductRequest.MinROIRate
+	product.MaxROIRate = updateLoanProductRequest.MaxROIRate
+	product.Fees = updateLoanProductRequest.Fees
+	product.FundingWindowDays = updateLoanProductRequest.FundingWindowDays
+	product.IsActive = updateLoanProductRequest.IsActive
+
+	if !product.IsValid() {
+		err = model.ErrorLoanProductInvalid
+		return
+	}
+
+	if product.Fees == nil {
+		product.Fees = []model.LoanProductFee{}
+	}
+	for i := range product.Fees {
+		if product.Fees[i].Trigger == "" {
+			product.Fees[i].Trigger = model.LoanProductFeeTriggerUpfront
+		}
+	}
+
+	if product.FundingWindowDays == 0 {
+		product.FundingWindowDays = model.DefaultFundingWindowDays
+	}
+
+	err = lps.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = lps.LoanProductRepository.UpdateLoanProduct(ctx, product)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, lps.AuditRepository, model.AuditEntityLoanProduct, product.ID, model.AuditActionUpdate, before, model.ComposeLoanProductResponse(product))
+	})
+	if err != nil {
+		return
+	}
+
+	loanProductResponse = model.ComposeLoanProductResponse(product)
+
+	return
+}
@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("LoanProductService", func() {
+	var (
+		mockCtrl        *gomock.Controller
+		mockProductRepo *mock.MockILoanProductRepository
+		mockAuditRepo   *mock.MockIAuditRepository
+		productSvc      service.ILoanProductService
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockProductRepo = mock.NewMockILoanProductRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+
+		productSvc = &service.LoanProductService{
+			LoanProductRepository: mockProductRepo,
+			TransactionManager:    &mock.MockTransactionManager{},
+			AuditRepository:       mockAuditRepo,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("CreateLoanProduct", func() {
+		It("should create an active product", func() {
+			ctx := context.Background()
+			createReq := &model.CreateLoanProductRequest{
+				Code:               "PRODUCTIVE_SME",
+				Name:               "Productive SME",
+				MinPrincipalAmount: 1000000,
+				MaxPrincipalAmount: 500000000,
+				TenorMonths:        []int64{3, 6},
+				MinInterestRate:    12,
+				MaxInterestRate:    24,
+				MinROIRate:         8,
+				MaxROIRate:         16,
+				Fees: []model.LoanProductFee{
+					{Name: "admin_fee", Type: model.LoanProductFeeTypePercentage, Amount: 2.5},
+					{Name: "late_fee", Type: model.LoanProductFeeTypePercentage, Amount: 0.1, Trigger: model.LoanProductFeeTriggerLate},
+				},
+			}
+
+			mockProductRepo.EXPECT().
+				CreateLoanProduct(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, product *model.LoanProduct) (string, error) {
+					Expect(product.IsActive).To(BeTrue())
+					Expect(product.Fees[0].Trigger).To(Equal(model.LoanProductFeeTriggerUpfront))
+					Expect(product.Fees[1].Trigger).To(Equal(model.LoanProductFeeTriggerLate))
+					return "product-1", nil
+				})
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityLoanProduct))
+					Expect(auditLog.EntityID).To(Equal("product-1"))
+					Expect(auditLog.Action).To(Equal(model.AuditActionCreate))
+					return nil
+				})
+
+			resp, err := productSvc.CreateLoanProduct(ctx, createReq)
+			Expect(err).To(BeNil())
+			Expect(resp.ProductID).To(Equal("product-1"))
+			Expect(resp.TenorMonths).To(Equal([]int64{3, 6}))
+		})
+
+		It("should reject a product without tenors", func() {
+			ctx := context.Background()
+			createReq := &model.CreateLoanProductRequest{
+				Code:               "EMPTY",
+				Name:               "Empty",
+				MinPrincipalAmount: 1000000,
+				MaxPrincipalAmount: 500000000,
+				MaxInterestRate:    24,
+				MaxROIRate:         16,
+			}
+
+			resp, err := productSvc.CreateLoanProduct(ctx, createReq)
+			Expect(err).To(Equal(model.ErrorLoanProductInvalid))
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Context("UpdateLoanProduct", func() {
+		It("should deactivate a product", func() {
+			ctx := context.Background()
+			product := &model.LoanProduct{ID: "product-1", IsActive: true}
+			updateReq := &model.UpdateLoanProductRequest{
+				ProductID:          "product-1",
+				Name:               "Productive SME",
+				MinPrincipalAmount: 1000000,
+				MaxPrincipalAmount: 500000000,
+				TenorMonths:        []int64{3, 6},
+				MaxInterestRate:    24,
+				MaxROIRate:         16,
+				IsActive:           false,
+			}
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(ctx, "product-1").
+				Return(product, nil)
+			mockProductRepo.EXPECT().
+				UpdateLoanProduct(ctx, product).
+				Return(nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.Action).To(Equal(model.AuditActionUpdate))
+					changes := map[string]model.AuditChange{}
+					Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+					Expect(changes).To(HaveKeyWithValue("is_active", model.AuditChange{Before: json.RawMessage("true"), After: json.RawMessage("false")}))
+					return nil
+				})
+
+			resp, err := productSvc.UpdateLoanProduct(ctx, updateReq)
+			Expect(err).To(BeNil())
+			Expect(resp.IsActive).To(BeFalse())
+		})
+
+		It("should return error if product not found", func() {
+			ctx := context.Background()
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(ctx, "product-404").
+				Return(nil, model.ErrorLoanProductNotFound)
+
+			resp, err := productSvc.UpdateLoanProduct(ctx, &model.UpdateLoanProductRequest{ProductID: "product-404"})
+			Expect(err).To(Equal(model.ErrorLoanProductNotFound))
+			Expect(resp).To(BeNil())
+		})
+	})
+})
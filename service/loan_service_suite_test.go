@@ -1,13 +1,40 @@
 package service_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
 )
 
 func TestLoanService(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "LoanService Suite")
 }
+
+// derivedContext matches ctx or a context derived from it, such as the ctx of the span a service method
+// starts, by the user it carries.
+func derivedContext(ctx context.Context) gomock.Matcher {
+	return derivedContextMatcher{ctx: ctx}
+}
+
+type derivedContextMatcher struct {
+	ctx context.Context
+}
+
+func (dcm derivedContextMatcher) Matches(x interface{}) bool {
+	got, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+
+	return got == dcm.ctx || got.Value("userID") == dcm.ctx.Value("userID")
+}
+
+func (dcm derivedContextMatcher) String() string {
+	return fmt.Sprintf("is derived from %v", dcm.ctx)
+}
@@ -2,14 +2,18 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/frencius/loan-service/application"
 	"github.com/frencius/loan-service/mock"
 	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/frencius/loan-service/repository/memory"
 	"github.com/frencius/loan-service/service"
 
 	"github.com/golang/mock/gomock"
@@ -17,26 +21,148 @@ import (
 
 var _ = Describe("LoanService", func() {
 	var (
-		mockCtrl           *gomock.Controller
-		mockLoanRepo       *mock.MockILoanRepository
-		mockBorrowerRepo   *mock.MockIBorrowerRepository
-		mockInvestorRepo   *mock.MockIInvestorRepository
-		mockInvestmentRepo *mock.MockIInvestmentRepository
-		loanSvc            service.ILoanService
+		mockCtrl         *gomock.Controller
+		loanRepo         repository.ILoanRepository
+		borrowerRepo     repository.IBorrowerRepository
+		investorRepo     repository.IInvestorRepository
+		investmentRepo   repository.IInvestmentRepository
+		mockEmployeeRepo *mock.MockIEmployeeRepository
+		mockProductRepo  *mock.MockILoanProductRepository
+		mockNotifier     *mock.MockINotifier
+		mockOutboxRepo   *mock.MockIOutboxRepository
+		mockFeatureFlags *mock.MockIFeatureFlagService
+		mockAuditRepo    *mock.MockIAuditRepository
+		product          *model.LoanProduct
+		now              time.Time
+		ctx              context.Context
+		loanSvc          *service.LoanService
+		expectEvent      func(eventType model.EventType) *gomock.Call
+		expectAudit      func(entityType string, action model.AuditAction) *gomock.Call
+		seedLoan         func(loan *model.Loan, loanStates ...model.LoanState) (loanID string)
+		storedLoan       func(loanID string) *model.Loan
+		withVisitProof   func(validatedBy string) repository.ILoanRepository
 	)
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
-		mockLoanRepo = mock.NewMockILoanRepository(mockCtrl)
-		mockBorrowerRepo = mock.NewMockIBorrowerRepository(mockCtrl)
-		mockInvestorRepo = mock.NewMockIInvestorRepository(mockCtrl)
-		mockInvestmentRepo = mock.NewMockIInvestmentRepository(mockCtrl)
+		loanRepo = memory.NewLoanRepository()
+		borrowerRepo = memory.NewBorrowerRepository()
+		investorRepo = memory.NewInvestorRepository()
+		investmentRepo = memory.NewInvestmentRepository()
+		mockEmployeeRepo = mock.NewMockIEmployeeRepository(mockCtrl)
+		mockProductRepo = mock.NewMockILoanProductRepository(mockCtrl)
+		mockNotifier = mock.NewMockINotifier(mockCtrl)
+		mockOutboxRepo = mock.NewMockIOutboxRepository(mockCtrl)
+		mockFeatureFlags = mock.NewMockIFeatureFlagService(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		ctx = context.WithValue(context.Background(), "userID", "employee-1")
+		product = &model.LoanProduct{
+			ID:                 "product-1",
+			MinPrincipalAmount: 500000,
+			MaxPrincipalAmount: 5000000,
+			TenorMonths:        []int64{3, 6, 12},
+			MinInterestRate:    5,
+			MaxInterestRate:    10,
+			MinROIRate:         1,
+			MaxROIRate:         4,
+			Fees: []model.LoanProductFee{
+				{Name: "admin_fee", Type: model.LoanProductFeeTypePercentage, Amount: 2.5},
+				{Name: "stamp_duty", Type: model.LoanProductFeeTypeFlat, Amount: 10000},
+				{Name: "late_fee", Type: model.LoanProductFeeTypePercentage, Amount: 0.1, Trigger: model.LoanProductFeeTriggerLate},
+			},
+			IsActive: true,
+		}
 
 		loanSvc = &service.LoanService{
-			LoanRepository:       mockLoanRepo,
-			BorrowerRepository:   mockBorrowerRepo,
-			InvestorRepository:   mockInvestorRepo,
-			InvestmentRepository: mockInvestmentRepo,
+			LoanRepository:        loanRepo,
+			BorrowerRepository:    borrowerRepo,
+			InvestorRepository:    investorRepo,
+			InvestmentRepository:  investmentRepo,
+			EmployeeRepository:    mockEmployeeRepo,
+			LoanProductRepository: mockProductRepo,
+			RiskScorer:            &service.ScorecardRiskScorer{Scorecard: model.DefaultScorecard(), Now: time.Now},
+			FeatureFlags:          mockFeatureFlags,
+			Notifier:              mockNotifier,
+			TransactionManager:    &mock.MockTransactionManager{},
+			OutboxRepository:      mockOutboxRepo,
+			AuditRepository:       mockAuditRepo,
+			Now:                   func() time.Time { return now },
+		}
+
+		_, err := borrowerRepo.CreateBorrower(ctx, &model.Borrower{ID: "borrower-1", Name: "Andi", NIK: "3171000000000001"})
+		Expect(err).To(BeNil())
+		_, err = investorRepo.CreateInvestor(ctx, &model.Investor{ID: "inv-1", Name: "Rina", NIK: "3171000000000002", NPWP: "01.000.000.0-000.001", Email: "rina@example.com"})
+		Expect(err).To(BeNil())
+
+		// seedLoan creates the loan and moves it through the states as the employee who created it
+		seedLoan = func(loan *model.Loan, loanStates ...model.LoanState) (loanID string) {
+			if loan.BorrowerID == "" {
+				loan.BorrowerID = "borrower-1"
+			}
+			if loan.CreatedBy == "" {
+				loan.CreatedBy = "employee-1"
+			}
+			loan.State = model.LoanStateProposed
+			seedCtx := context.WithValue(context.Background(), "userID", loan.CreatedBy)
+
+			loanID, err := loanRepo.CreateLoan(seedCtx, loan)
+			Expect(err).To(BeNil())
+
+			for _, loanState := range loanStates {
+				stored := storedLoan(loanID)
+				stored.CanceledReason = loan.CanceledReason
+				stored.RejectedReason = loan.RejectedReason
+				stored.FundingDeadline = loan.FundingDeadline
+				Expect(loanRepo.UpdateLoanState(seedCtx, stored, loanState)).To(Succeed())
+			}
+
+			return
+		}
+
+		storedLoan = func(loanID string) *model.Loan {
+			loan, err := loanRepo.GetLoanByID(context.Background(), loanID)
+			Expect(err).To(BeNil())
+			return loan
+		}
+
+		// withVisitProof serves the loans with the validated visit proof a field officer uploads, which the
+		// loan repository reads but does not write
+		withVisitProof = func(validatedBy string) repository.ILoanRepository {
+			return &stubLoanRepository{
+				ILoanRepository: loanRepo,
+				getLoanByID: func(ctx context.Context, id string) (*model.Loan, error) {
+					loan, err := loanRepo.GetLoanByID(ctx, id)
+					if err != nil {
+						return nil, err
+					}
+
+					validatedAt := now.Add(-time.Hour)
+					loan.VisitProofURL = "http://proof"
+					loan.ValidatedAt = &validatedAt
+					loan.ValidatedBy = validatedBy
+					return loan, nil
+				},
+			}
+		}
+
+		expectEvent = func(eventType model.EventType) *gomock.Call {
+			return mockOutboxRepo.EXPECT().
+				CreateEvent(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, event *model.Event) (string, error) {
+					Expect(event.Type).To(Equal(eventType))
+					return "event-1", nil
+				})
+		}
+
+		expectAudit = func(entityType string, action model.AuditAction) *gomock.Call {
+			return mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(entityType))
+					Expect(auditLog.Action).To(Equal(action))
+					return nil
+				})
 		}
 	})
 
@@ -45,71 +171,153 @@ var _ = Describe("LoanService", func() {
 	})
 
 	Context("CreateLoan", func() {
-		It("should create a loan and return loan id", func() {
-			ctx := context.WithValue(context.Background(), "userID", "user-1")
-			borrowerID := "1"
-			createReq := &model.CreateLoanRequest{
-				BorrowerID:      borrowerID,
+		var createReq *model.CreateLoanRequest
+
+		BeforeEach(func() {
+			createReq = &model.CreateLoanRequest{
+				BorrowerID:      "borrower-1",
+				ProductID:       "product-1",
+				TenorMonths:     6,
 				PrincipalAmount: 1000000,
 				InterestRate:    5.5,
 				ROIRate:         2.0,
 			}
-			borrower := &model.Borrower{ID: borrowerID}
-
-			mockBorrowerRepo.EXPECT().
-				GetBorrowerByID(ctx, borrowerID).
-				Return(borrower, nil)
-			mockLoanRepo.EXPECT().
-				CreateLoan(ctx, gomock.Any()).
-				DoAndReturn(func(_ context.Context, loan *model.Loan) (string, error) {
-					Expect(loan.BorrowerID).To(Equal(borrowerID))
-					Expect(loan.State).To(Equal(model.LoanStateProposed))
-					Expect(loan.CreatedBy).To(Equal("user-1"))
-					return "123", nil
+		})
+
+		It("should create a loan and return loan id", func() {
+			ctx := application.WithClientIP(application.WithRequestID(ctx, "request-1"), "10.0.0.1")
+			var auditLog *model.AuditLog
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
+			mockOutboxRepo.EXPECT().
+				CreateEvent(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, event *model.Event) (string, error) {
+					Expect(event.Type).To(Equal(model.EventTypeLoanCreated))
+					Expect(event.AggregateType).To(Equal(model.AggregateTypeLoan))
+					Expect(event.AggregateID).NotTo(BeEmpty())
+					return "event-1", nil
+				})
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, log *model.AuditLog) error {
+					auditLog = log
+					return nil
 				})
 
 			resp, err := loanSvc.CreateLoan(ctx, createReq)
 			Expect(err).To(BeNil())
 			Expect(resp).NotTo(BeNil())
-			Expect(resp.LoanID).To(Equal("123"))
 			Expect(resp.State).To(Equal("proposed"))
+			Expect(resp.Fees).To(Equal([]model.LoanFeeResponse{
+				{Name: "admin_fee", Amount: 25000},
+				{Name: "stamp_duty", Amount: 10000},
+			}))
+			Expect(resp.Risk).NotTo(BeNil())
+			Expect(resp.Risk.Grade).NotTo(BeEmpty())
+
+			loan := storedLoan(resp.LoanID)
+			Expect(loan.BorrowerID).To(Equal("borrower-1"))
+			Expect(loan.State).To(Equal(model.LoanStateProposed))
+			Expect(loan.CreatedBy).To(Equal("employee-1"))
+			Expect(loan.ProductID).To(Equal("product-1"))
+			Expect(loan.TenorMonths).To(Equal(int64(6)))
+			Expect(loan.Risk).NotTo(BeNil())
+			Expect(string(loan.Risk.Grade)).To(Equal(resp.Risk.Grade))
+
+			Expect(auditLog.EntityType).To(Equal(model.AuditEntityLoan))
+			Expect(auditLog.EntityID).To(Equal(resp.LoanID))
+			Expect(auditLog.Action).To(Equal(model.AuditActionCreate))
+			Expect(auditLog.ActorID).To(Equal("employee-1"))
+			Expect(auditLog.SourceIP).To(Equal("10.0.0.1"))
+			Expect(auditLog.RequestID).To(Equal("request-1"))
+			changes := map[string]model.AuditChange{}
+			Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+			Expect(changes["principal_amount"]).To(Equal(model.AuditChange{After: json.RawMessage("1000000")}))
+			Expect(changes["state"]).To(Equal(model.AuditChange{After: json.RawMessage(`"proposed"`)}))
+		})
+
+		It("should score with the candidate scorecard where its flag is on", func() {
+			ctx := context.WithValue(ctx, "userRole", "employee")
+			candidate := model.DefaultScorecard()
+			candidate.Version = "candidate-v2"
+			loanSvc.CandidateRiskScorer = &service.ScorecardRiskScorer{Scorecard: candidate, Now: time.Now}
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
+			mockFeatureFlags.EXPECT().
+				IsEnabled(derivedContext(ctx), model.FeatureFlagCandidateScorecard, &model.FeatureFlagContext{UserID: "employee-1", Role: "employee", ProductID: "product-1"}).
+				Return(true)
+			expectAudit(model.AuditEntityLoan, model.AuditActionCreate)
+			expectEvent(model.EventTypeLoanCreated)
+
+			resp, err := loanSvc.CreateLoan(ctx, createReq)
+			Expect(err).To(BeNil())
+			Expect(storedLoan(resp.LoanID).Risk.ScorecardVersion).To(Equal("candidate-v2"))
+		})
+
+		It("should return error if product not found", func() {
+			createReq.ProductID = "product-404"
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-404").
+				Return(nil, model.ErrorLoanProductNotFound)
+
+			resp, err := loanSvc.CreateLoan(ctx, createReq)
+			Expect(err).To(Equal(model.ErrorLoanProductNotFound))
+			Expect(resp).To(BeNil())
 		})
 
+		DescribeTable("should reject loan terms outside the product limits",
+			func(modify func(req *model.CreateLoanRequest), deactivate bool, expectedErr error) {
+				modify(createReq)
+				product.IsActive = !deactivate
+
+				mockProductRepo.EXPECT().
+					GetLoanProductByID(derivedContext(ctx), "product-1").
+					Return(product, nil)
+
+				resp, err := loanSvc.CreateLoan(ctx, createReq)
+				Expect(err).To(Equal(expectedErr))
+				Expect(resp).To(BeNil())
+			},
+			Entry("inactive product", func(req *model.CreateLoanRequest) {}, true, model.ErrorLoanProductInactive),
+			Entry("principal below minimum", func(req *model.CreateLoanRequest) { req.PrincipalAmount = 100 }, false, model.ErrorPrincipalAmountOutOfRange),
+			Entry("principal above maximum", func(req *model.CreateLoanRequest) { req.PrincipalAmount = 9000000 }, false, model.ErrorPrincipalAmountOutOfRange),
+			Entry("tenor not offered", func(req *model.CreateLoanRequest) { req.TenorMonths = 7 }, false, model.ErrorTenorNotAllowed),
+			Entry("interest rate out of band", func(req *model.CreateLoanRequest) { req.InterestRate = 12 }, false, model.ErrorInterestRateOutOfBand),
+			Entry("roi rate out of band", func(req *model.CreateLoanRequest) { req.ROIRate = 0.5 }, false, model.ErrorROIRateOutOfBand),
+		)
+
 		It("should return error if borrower not found", func() {
-			ctx := context.WithValue(context.Background(), "userID", "user-1")
-			borrowerID := "99"
-			createReq := &model.CreateLoanRequest{
-				BorrowerID:      borrowerID,
-				PrincipalAmount: 1000000,
-				InterestRate:    5.5,
-				ROIRate:         2.0,
-			}
-			mockBorrowerRepo.EXPECT().
-				GetBorrowerByID(ctx, borrowerID).
-				Return(nil, errors.New("not found"))
+			createReq.BorrowerID = "borrower-404"
+
+			resp, err := loanSvc.CreateLoan(ctx, createReq)
+			Expect(err).To(Equal(model.ErrorBorrowerNotFound))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should reject a borrower whose personal data was erased", func() {
+			Expect(borrowerRepo.EraseBorrower(ctx, "borrower-1", now)).To(Succeed())
 
 			resp, err := loanSvc.CreateLoan(ctx, createReq)
-			Expect(err).To(MatchError("not found"))
+			Expect(err).To(Equal(model.ErrorPersonalDataErased))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if CreateLoan fails", func() {
-			ctx := context.WithValue(context.Background(), "userID", "user-1")
-			borrowerID := "1"
-			createReq := &model.CreateLoanRequest{
-				BorrowerID:      borrowerID,
-				PrincipalAmount: 1000000,
-				InterestRate:    5.5,
-				ROIRate:         2.0,
+			loanSvc.LoanRepository = &stubLoanRepository{
+				ILoanRepository: loanRepo,
+				createLoan: func(ctx context.Context, loan *model.Loan) (string, error) {
+					return "", errors.New("insert failed")
+				},
 			}
-			borrower := &model.Borrower{ID: borrowerID}
 
-			mockBorrowerRepo.EXPECT().
-				GetBorrowerByID(ctx, borrowerID).
-				Return(borrower, nil)
-			mockLoanRepo.EXPECT().
-				CreateLoan(ctx, gomock.Any()).
-				Return("", errors.New("insert failed"))
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
 
 			resp, err := loanSvc.CreateLoan(ctx, createReq)
 			Expect(err).To(MatchError("insert failed"))
@@ -118,18 +326,10 @@ var _ = Describe("LoanService", func() {
 
 		It("should panic if userID is missing from context", func() {
 			ctx := context.Background()
-			borrowerID := "1"
-			createReq := &model.CreateLoanRequest{
-				BorrowerID:      borrowerID,
-				PrincipalAmount: 1000000,
-				InterestRate:    5.5,
-				ROIRate:         2.0,
-			}
-			borrower := &model.Borrower{ID: borrowerID}
 
-			mockBorrowerRepo.EXPECT().
-				GetBorrowerByID(ctx, borrowerID).
-				Return(borrower, nil)
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
 
 			Expect(func() {
 				_, _ = loanSvc.CreateLoan(ctx, createReq)
@@ -138,18 +338,10 @@ var _ = Describe("LoanService", func() {
 
 		It("should panic if userID in context is not a string", func() {
 			ctx := context.WithValue(context.Background(), "userID", 12345)
-			borrowerID := "1"
-			createReq := &model.CreateLoanRequest{
-				BorrowerID:      borrowerID,
-				PrincipalAmount: 1000000,
-				InterestRate:    5.5,
-				ROIRate:         2.0,
-			}
-			borrower := &model.Borrower{ID: borrowerID}
 
-			mockBorrowerRepo.EXPECT().
-				GetBorrowerByID(ctx, borrowerID).
-				Return(borrower, nil)
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
 
 			Expect(func() {
 				_, _ = loanSvc.CreateLoan(ctx, createReq)
@@ -159,313 +351,572 @@ var _ = Describe("LoanService", func() {
 
 	Context("UpdateLoanState", func() {
 		It("should update loan state successfully", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			oldState := model.LoanStateProposed
-			newState := model.LoanStateApproved
-			now := time.Now()
-			loan := &model.Loan{
-				ID:            loanID,
-				State:         oldState,
-				VisitProofURL: "http://proof",
-				ValidatedAt:   &now,
-				ValidatedBy:   "admin",
-			}
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  string(newState),
-			}
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000})
+			loanSvc.LoanRepository = withVisitProof("employee-2")
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockLoanRepo.EXPECT().
-				UpdateLoanState(ctx, loan, newState).
-				Return(nil)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			expectEvent(model.EventTypeLoanApproved)
 
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateApproved)})
 			Expect(err).To(BeNil())
+			Expect(resp).To(Equal(&model.UpdateLoanStateResponse{LoanID: loanID, State: string(model.LoanStateApproved), Version: 2}))
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStateApproved))
+			Expect(loan.ApprovedBy).To(Equal("employee-1"))
+			Expect(loan.ApprovedAt).NotTo(BeNil())
+			Expect(loan.Version).To(Equal(int64(2)))
+		})
+
+		It("should reject a stale If-Match version before updating", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved)
+
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{
+				LoanID:          loanID,
+				ExpectedVersion: 1,
+				State:           string(model.LoanStateCanceled),
+				Reason:          "borrower withdrew",
+			})
+			Expect(err).To(Equal(model.ErrorLoanVersionMismatch))
 			Expect(resp).To(BeNil())
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStateApproved))
 		})
 
-		It("should return error if loan not found", func() {
-			ctx := context.Background()
-			loanID := "loan-404"
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  string(model.LoanStateApproved),
-			}
+		It("should bump the version the client sent", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved)
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(nil, errors.New("not found"))
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			expectEvent(model.EventTypeLoanCanceled)
+
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{
+				LoanID:          loanID,
+				ExpectedVersion: 2,
+				State:           string(model.LoanStateCanceled),
+				Reason:          "borrower withdrew",
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.Version).To(Equal(int64(3)))
+			Expect(storedLoan(loanID).Version).To(Equal(int64(3)))
+		})
+
+		Context("when the loan changes after it was read", func() {
+			var loanID string
+
+			BeforeEach(func() {
+				loanID = seedLoan(&model.Loan{}, model.LoanStateApproved)
+
+				// another request updates the loan between the read and the conditional update
+				loanSvc.LoanRepository = &stubLoanRepository{
+					ILoanRepository: loanRepo,
+					getLoanByID: func(ctx context.Context, id string) (*model.Loan, error) {
+						loan, err := loanRepo.GetLoanByID(ctx, id)
+						if err != nil {
+							return nil, err
+						}
+
+						concurrent := *loan
+						concurrent.TotalInvestedAmount = 100
+						Expect(loanRepo.UpdateLoanTotalInvestedAmount(ctx, &concurrent)).To(Succeed())
+						return loan, nil
+					},
+				}
+			})
+
+			It("should pass a concurrent update through to the client", func() {
+				_, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{
+					LoanID:          loanID,
+					ExpectedVersion: 2,
+					State:           string(model.LoanStateCanceled),
+					Reason:          "borrower withdrew",
+				})
+				Expect(err).To(Equal(model.ErrorLoanVersionMismatch))
+				Expect(storedLoan(loanID).State).To(Equal(model.LoanStateApproved))
+			})
+
+			It("should report a conflict to internal callers without a version", func() {
+				_, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{
+					LoanID: loanID,
+					State:  string(model.LoanStateCanceled),
+					Reason: model.ReasonFundingWindowExpired,
+				})
+				Expect(err).To(Equal(model.ErrorLoanUpdateConflict))
+			})
+		})
 
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
-			Expect(err).To(MatchError("not found"))
+		It("should return error if loan not found", func() {
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: "loan-404", State: string(model.LoanStateApproved)})
+			Expect(err).To(Equal(model.ErrorLoanNotFound))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if new state is invalid", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStateProposed,
-			}
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  "invalid_state",
-			}
-
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
+			loanID := seedLoan(&model.Loan{})
 
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: "invalid_state"})
 			Expect(err).To(Equal(model.ErrorLoanStateInvalid))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if state transition is not allowed", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStateProposed,
-			}
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  string(model.LoanStateDisbursed),
-			}
-
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
+			loanID := seedLoan(&model.Loan{})
 
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateDisbursed)})
 			Expect(err).To(Equal(model.ErrorLoanStateTransitionNotAllowed))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if state requirements are not fulfilled", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStateProposed,
-			}
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  string(model.LoanStateApproved),
-			}
+			loanID := seedLoan(&model.Loan{})
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateApproved)})
 			Expect(err).To(Equal(model.ErrorStateTransitionRequirementNotFulfilled))
 			Expect(resp).To(BeNil())
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStateProposed))
+		})
+
+		It("should set the funding deadline from the product when publishing", func() {
+			loanID := seedLoan(&model.Loan{ProductID: "product-1"}, model.LoanStateApproved)
+			product.FundingWindowDays = 7
+
+			mockProductRepo.EXPECT().
+				GetLoanProductByID(derivedContext(ctx), "product-1").
+				Return(product, nil)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			expectEvent(model.EventTypeLoanPublished)
+
+			_, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStatePublished)})
+			Expect(err).To(BeNil())
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStatePublished))
+			Expect(*loan.FundingDeadline).To(Equal(now.AddDate(0, 0, 7)))
+			Expect(loan.PublishedBy).To(Equal("employee-1"))
+		})
+
+		It("should require a reason when canceling", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved)
+
+			_, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateCanceled)})
+			Expect(err).To(Equal(model.ErrorStateTransitionRequirementNotFulfilled))
+		})
+
+		It("should cancel with the requested reason", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved)
+
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					changes := map[string]model.AuditChange{}
+					Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+					Expect(changes["state"]).To(Equal(model.AuditChange{Before: json.RawMessage(`"approved"`), After: json.RawMessage(`"canceled"`)}))
+					Expect(changes["canceled_reason"]).To(Equal(model.AuditChange{After: json.RawMessage(`"borrower withdrew"`)}))
+					return nil
+				})
+			mockOutboxRepo.EXPECT().
+				CreateEvent(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, event *model.Event) (string, error) {
+					Expect(event.Type).To(Equal(model.EventTypeLoanCanceled))
+					Expect(string(event.Payload)).To(ContainSubstring(`"reason":"borrower withdrew"`))
+					Expect(string(event.Payload)).To(ContainSubstring(`"previous_state":"approved"`))
+					return "event-1", nil
+				})
+
+			_, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateCanceled), Reason: "borrower withdrew"})
+			Expect(err).To(BeNil())
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStateCanceled))
+			Expect(loan.CanceledReason).To(Equal("borrower withdrew"))
+			Expect(loan.CanceledBy).To(Equal("employee-1"))
 		})
 
 		It("should return error if UpdateLoanState repo fails", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			oldState := model.LoanStateProposed
-			newState := model.LoanStateApproved
-			now := time.Now()
-			loan := &model.Loan{
-				ID:            loanID,
-				State:         oldState,
-				VisitProofURL: "http://proof",
-				ValidatedAt:   &now,
-				ValidatedBy:   "admin",
-			}
-			updateReq := &model.UpdateLoanStateRequest{
-				LoanID: loanID,
-				State:  string(newState),
+			loanID := seedLoan(&model.Loan{})
+			loanSvc.LoanRepository = &stubLoanRepository{
+				ILoanRepository: withVisitProof("employee-2"),
+				updateLoanState: func(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) error {
+					return errors.New("update failed")
+				},
 			}
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockLoanRepo.EXPECT().
-				UpdateLoanState(ctx, loan, newState).
-				Return(errors.New("update failed"))
-
-			resp, err := loanSvc.UpdateLoanState(ctx, updateReq)
+			resp, err := loanSvc.UpdateLoanState(ctx, &model.UpdateLoanStateRequest{LoanID: loanID, State: string(model.LoanStateApproved)})
 			Expect(err).To(MatchError("update failed"))
 			Expect(resp).To(BeNil())
 		})
 	})
 
+	Context("ForceLoanState", func() {
+		It("should move the loan outside the state machine and mark the event forced", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved, model.LoanStatePublished)
+
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			mockOutboxRepo.EXPECT().
+				CreateEvent(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, event *model.Event) (string, error) {
+					Expect(event.Type).To(Equal(model.EventTypeLoanProposed))
+					Expect(string(event.Payload)).To(ContainSubstring(`"forced":true`))
+					Expect(string(event.Payload)).To(ContainSubstring(`"reason":"approved by mistake"`))
+					Expect(string(event.Payload)).To(ContainSubstring(`"actor_id":"employee-1"`))
+					return "event-1", nil
+				})
+
+			resp, err := loanSvc.ForceLoanState(ctx, &model.ForceLoanStateRequest{LoanID: loanID, State: string(model.LoanStateProposed), Reason: "approved by mistake"})
+			Expect(err).To(BeNil())
+			Expect(resp.State).To(Equal("proposed"))
+			Expect(resp.Version).To(Equal(int64(4)))
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStateProposed))
+		})
+
+		It("should require a reason", func() {
+			_, err := loanSvc.ForceLoanState(ctx, &model.ForceLoanStateRequest{LoanID: "loan-1", State: string(model.LoanStateProposed)})
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should reject an unknown state", func() {
+			_, err := loanSvc.ForceLoanState(ctx, &model.ForceLoanStateRequest{LoanID: "loan-1", State: "closed", Reason: "typo"})
+			Expect(err).To(Equal(model.ErrorLoanStateInvalid))
+		})
+
+		It("should reject the state the loan is already in", func() {
+			loanID := seedLoan(&model.Loan{}, model.LoanStateApproved)
+
+			_, err := loanSvc.ForceLoanState(ctx, &model.ForceLoanStateRequest{LoanID: loanID, State: string(model.LoanStateApproved), Reason: "retry"})
+			Expect(err).To(Equal(model.ErrorTransitionToTheSameState))
+		})
+	})
+
 	Context("CreateLoanInvestment", func() {
-		It("should return error if loan not found", func() {
-			ctx := context.Background()
-			loanID := "loan-404"
-			investorID := "inv-1"
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
-			}
+		var fundingDeadline time.Time
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(nil, errors.New("not found"))
+		BeforeEach(func() {
+			fundingDeadline = now.AddDate(0, 0, 7)
+		})
 
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
-			Expect(err).To(MatchError("not found"))
+		It("should return error if loan not found", func() {
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: "loan-404", InvestorID: "inv-1", InvestmentAmount: 100})
+			Expect(err).To(Equal(model.ErrorLoanNotFound))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if loan state is not published", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			investorID := "inv-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStateProposed,
-			}
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
-			}
-
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000})
 
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
 			Expect(err).To(Equal(model.ErrorStateMustBePublished))
 			Expect(resp).To(BeNil())
 		})
 
-		It("should return error if investor not found", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			investorID := "inv-404"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStatePublished,
-			}
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
-			}
+		It("should return error if the funding window has expired", func() {
+			deadline := now.Add(-time.Minute)
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &deadline}, model.LoanStateApproved, model.LoanStatePublished)
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockInvestorRepo.EXPECT().
-				GetInvestorByID(ctx, investorID).
-				Return(nil, errors.New("not found"))
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
+			Expect(err).To(Equal(model.ErrorFundingWindowExpired))
+			Expect(resp).To(BeNil())
+		})
 
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
-			Expect(err).To(MatchError("not found"))
+		It("should return error if investor not found", func() {
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-404", InvestmentAmount: 100})
+			Expect(err).To(Equal(model.ErrorInvestorNotFound))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if investment already exists", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			investorID := "inv-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStatePublished,
-			}
-			investor := &model.Investor{ID: investorID}
-			existingInvestment := &model.Investment{LoanID: loanID, InvestorID: investorID}
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
-			}
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+			_, err := investmentRepo.CreateInvestment(ctx, &model.Investment{LoanID: loanID, InvestorID: "inv-1", InvestedAmount: 100})
+			Expect(err).To(BeNil())
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockInvestorRepo.EXPECT().
-				GetInvestorByID(ctx, investorID).
-				Return(investor, nil)
-			mockInvestmentRepo.EXPECT().
-				GetInvestmentByInvestorID(ctx, investorID).
-				Return(existingInvestment, nil)
-
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
 			Expect(err).To(Equal(model.ErrorInvestmentExist))
 			Expect(resp).To(BeNil())
 		})
 
 		It("should return error if CreateInvestment fails", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			investorID := "inv-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStatePublished,
-			}
-			investor := &model.Investor{ID: investorID}
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+			loanSvc.InvestmentRepository = &stubInvestmentRepository{
+				IInvestmentRepository: investmentRepo,
+				createInvestment: func(ctx context.Context, investment *model.Investment) (string, error) {
+					return "", errors.New("fail")
+				},
 			}
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockInvestorRepo.EXPECT().
-				GetInvestorByID(ctx, investorID).
-				Return(investor, nil)
-			mockInvestmentRepo.EXPECT().
-				GetInvestmentByInvestorID(ctx, investorID).
-				Return(nil, model.ErrorInvestmentNotFound)
-			mockInvestmentRepo.EXPECT().
-				CreateInvestment(ctx, gomock.Any()).
-				Return("", errors.New("fail"))
-
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
 			Expect(err).To(MatchError("fail"))
 			Expect(resp).To(BeNil())
+			Expect(storedLoan(loanID).TotalInvestedAmount).To(BeZero())
 		})
 
 		It("should return error if UpdateLoanTotalInvestedAmount fails", func() {
-			ctx := context.Background()
-			loanID := "loan-1"
-			investorID := "inv-1"
-			loan := &model.Loan{
-				ID:    loanID,
-				State: model.LoanStatePublished,
-			}
-			investor := &model.Investor{ID: investorID}
-			createReq := &model.CreateLoanInvestmentRequest{
-				LoanID:           loanID,
-				InvestorID:       investorID,
-				InvestmentAmount: 100,
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+			loanSvc.LoanRepository = &stubLoanRepository{
+				ILoanRepository: loanRepo,
+				updateLoanTotalInvestedAmount: func(ctx context.Context, loan *model.Loan) error {
+					return errors.New("fail")
+				},
 			}
 
-			mockLoanRepo.EXPECT().
-				GetLoanByID(ctx, loanID).
-				Return(loan, nil)
-			mockInvestorRepo.EXPECT().
-				GetInvestorByID(ctx, investorID).
-				Return(investor, nil)
-			mockInvestmentRepo.EXPECT().
-				GetInvestmentByInvestorID(ctx, investorID).
-				Return(nil, model.ErrorInvestmentNotFound)
-			mockInvestmentRepo.EXPECT().
-				CreateInvestment(ctx, gomock.Any()).
-				Return("invst-1", nil)
-			mockLoanRepo.EXPECT().
-				UpdateLoanTotalInvestedAmount(ctx, loan).
-				Return(errors.New("fail"))
-
-			resp, err := loanSvc.CreateLoanInvestment(ctx, createReq)
+			expectAudit(model.AuditEntityInvestment, model.AuditActionCreate)
+
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
 			Expect(err).To(MatchError("fail"))
 			Expect(resp).To(BeNil())
 		})
+
+		It("should add the investment to a loan that is not fully funded yet", func() {
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			expectAudit(model.AuditEntityInvestment, model.AuditActionCreate)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			expectEvent(model.EventTypeInvestmentCreated)
+
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 400})
+			Expect(err).To(BeNil())
+
+			investment, err := investmentRepo.GetInvestmentByInvestorID(ctx, "inv-1")
+			Expect(err).To(BeNil())
+			Expect(resp.InvestmentID).To(Equal(investment.ID))
+			Expect(investment.LoanID).To(Equal(loanID))
+			Expect(investment.InvestedAmount).To(Equal(float64(400)))
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStatePublished))
+			Expect(loan.TotalInvestedAmount).To(Equal(float64(400)))
+		})
+
+		It("should emit investment and fully funded events when the loan is fully funded", func() {
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			expectAudit(model.AuditEntityInvestment, model.AuditActionCreate)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate).Times(2)
+			expectEvent(model.EventTypeInvestmentCreated)
+			expectEvent(model.EventTypeLoanFullyFunded)
+
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 1000})
+			Expect(err).To(BeNil())
+			Expect(resp.InvestmentID).NotTo(BeEmpty())
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStateInvested))
+			Expect(loan.TotalInvestedAmount).To(Equal(float64(1000)))
+			Expect(loan.InvestedAt).NotTo(BeNil())
+		})
+
+		It("should fail the investment when its event cannot be recorded", func() {
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &fundingDeadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			expectAudit(model.AuditEntityInvestment, model.AuditActionCreate)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			mockOutboxRepo.EXPECT().
+				CreateEvent(derivedContext(ctx), gomock.Any()).
+				Return("", errors.New("outbox down"))
+
+			resp, err := loanSvc.CreateLoanInvestment(ctx, &model.CreateLoanInvestmentRequest{LoanID: loanID, InvestorID: "inv-1", InvestmentAmount: 100})
+			Expect(err).To(MatchError("outbox down"))
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Context("GetLoan", func() {
+		It("should resolve actor ids into employee names", func() {
+			loanID := seedLoan(&model.Loan{CreatedBy: "emp-1"})
+			approverCtx := context.WithValue(context.Background(), "userID", "emp-2")
+			Expect(loanRepo.UpdateLoanState(approverCtx, storedLoan(loanID), model.LoanStateApproved)).To(Succeed())
+			loanSvc.LoanRepository = withVisitProof("emp-1")
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeesByIDs(derivedContext(ctx), []string{"emp-1", "emp-2"}).
+				Return([]*model.Employee{
+					{ID: "emp-1", Name: "Budi"},
+					{ID: "emp-2", Name: "Sari"},
+				}, nil)
+
+			resp, err := loanSvc.GetLoan(ctx, &model.GetLoanRequest{LoanID: loanID})
+			Expect(err).To(BeNil())
+			Expect(resp.CreatedBy.Name).To(Equal("Budi"))
+			Expect(resp.ValidatedBy.Name).To(Equal("Budi"))
+			Expect(resp.ApprovedBy.Name).To(Equal("Sari"))
+			Expect(resp.RejectedBy).To(BeNil())
+		})
+
+		It("should return error if loan not found", func() {
+			resp, err := loanSvc.GetLoan(ctx, &model.GetLoanRequest{LoanID: "loan-404"})
+			Expect(err).To(Equal(model.ErrorLoanNotFound))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should return error if employee lookup fails", func() {
+			loanID := seedLoan(&model.Loan{CreatedBy: "emp-1"})
+
+			mockEmployeeRepo.EXPECT().
+				GetEmployeesByIDs(derivedContext(ctx), []string{"emp-1"}).
+				Return(nil, errors.New("query failed"))
+
+			resp, err := loanSvc.GetLoan(ctx, &model.GetLoanRequest{LoanID: loanID})
+			Expect(err).To(MatchError("query failed"))
+			Expect(resp).To(BeNil())
+		})
+	})
+
+	Context("ListPublishedLoans", func() {
+		It("should expose the risk grade and filter by it", func() {
+			gradeA := seedLoan(&model.Loan{Risk: &model.RiskAssessment{Grade: model.RiskGradeA}}, model.LoanStateApproved, model.LoanStatePublished)
+			seedLoan(&model.Loan{Risk: &model.RiskAssessment{Grade: model.RiskGradeC}}, model.LoanStateApproved, model.LoanStatePublished)
+			seedLoan(&model.Loan{Risk: &model.RiskAssessment{Grade: model.RiskGradeA}}, model.LoanStateApproved)
+
+			resp, err := loanSvc.ListPublishedLoans(ctx, &model.ListPublishedLoansRequest{RiskGrade: "A"})
+			Expect(err).To(BeNil())
+			Expect(resp).To(HaveLen(1))
+			Expect(resp[0].LoanID).To(Equal(gradeA))
+			Expect(resp[0].RiskGrade).To(Equal("A"))
+		})
+	})
+
+	Context("CancelExpiredLoans", func() {
+		It("should cancel expired loans as the system actor, release investments and notify", func() {
+			ctx := context.Background()
+			deadline := now.Add(-time.Hour)
+			loanID := seedLoan(&model.Loan{PrincipalAmount: 1000, FundingDeadline: &deadline}, model.LoanStateApproved, model.LoanStatePublished)
+			for _, investorID := range []string{"inv-1", "inv-2"} {
+				_, err := investmentRepo.CreateInvestment(ctx, &model.Investment{LoanID: loanID, InvestorID: investorID, InvestedAmount: 100})
+				Expect(err).To(BeNil())
+			}
+
+			mockFeatureFlags.EXPECT().
+				IsEnabled(gomock.Any(), model.FeatureFlagAutoCancelExpiredLoans, gomock.Any()).
+				Return(true)
+			expectAudit(model.AuditEntityLoan, model.AuditActionUpdate)
+			expectEvent(model.EventTypeLoanCanceled)
+			expectAudit(model.AuditEntityInvestment, model.AuditActionUpdate).Times(2)
+			expectEvent(model.EventTypeInvestmentsReleased)
+			mockNotifier.EXPECT().
+				Notify(gomock.Any(), &model.Notification{
+					Event:       model.NotificationEventLoanCanceled,
+					LoanID:      loanID,
+					BorrowerID:  "borrower-1",
+					InvestorIDs: []string{"inv-1", "inv-2"},
+					Reason:      model.ReasonFundingWindowExpired,
+				}).
+				Return(nil)
+
+			canceledLoanIDs, err := loanSvc.CancelExpiredLoans(ctx)
+			Expect(err).To(BeNil())
+			Expect(canceledLoanIDs).To(Equal([]string{loanID}))
+
+			loan := storedLoan(loanID)
+			Expect(loan.State).To(Equal(model.LoanStateCanceled))
+			Expect(loan.CanceledBy).To(Equal(model.SystemEmployeeID))
+			Expect(loan.CanceledReason).To(Equal(model.ReasonFundingWindowExpired))
+
+			investments, err := investmentRepo.ListInvestmentsByLoanID(ctx, loanID)
+			Expect(err).To(BeNil())
+			Expect(investments).To(HaveLen(2))
+			for _, investment := range investments {
+				Expect(investment.Status).To(Equal(model.InvestmentStatusReleased))
+			}
+		})
+
+		It("should skip loans that fail to cancel", func() {
+			ctx := context.Background()
+			deadline := now.Add(-time.Hour)
+			loanID := seedLoan(&model.Loan{FundingDeadline: &deadline}, model.LoanStateApproved, model.LoanStatePublished)
+			loanSvc.LoanRepository = &stubLoanRepository{
+				ILoanRepository: loanRepo,
+				getLoanByID: func(ctx context.Context, id string) (*model.Loan, error) {
+					return nil, errors.New("db down")
+				},
+			}
+
+			mockFeatureFlags.EXPECT().
+				IsEnabled(gomock.Any(), model.FeatureFlagAutoCancelExpiredLoans, gomock.Any()).
+				Return(true)
+
+			canceledLoanIDs, err := loanSvc.CancelExpiredLoans(ctx)
+			Expect(err).To(BeNil())
+			Expect(canceledLoanIDs).To(BeEmpty())
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStatePublished))
+		})
+
+		It("should leave loans alone where auto cancel is off", func() {
+			ctx := context.Background()
+			deadline := now.Add(-time.Hour)
+			loanID := seedLoan(&model.Loan{ProductID: "product-1", FundingDeadline: &deadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			mockFeatureFlags.EXPECT().
+				IsEnabled(gomock.Any(), model.FeatureFlagAutoCancelExpiredLoans, &model.FeatureFlagContext{UserID: model.SystemEmployeeID, ProductID: "product-1"}).
+				Return(false)
+
+			canceledLoanIDs, err := loanSvc.CancelExpiredLoans(ctx)
+			Expect(err).To(BeNil())
+			Expect(canceledLoanIDs).To(BeEmpty())
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStatePublished))
+		})
+
+		It("should not cancel loans still inside their funding window", func() {
+			deadline := now.Add(time.Hour)
+			loanID := seedLoan(&model.Loan{FundingDeadline: &deadline}, model.LoanStateApproved, model.LoanStatePublished)
+
+			canceledLoanIDs, err := loanSvc.CancelExpiredLoans(context.Background())
+			Expect(err).To(BeNil())
+			Expect(canceledLoanIDs).To(BeEmpty())
+			Expect(storedLoan(loanID).State).To(Equal(model.LoanStatePublished))
+		})
 	})
 })
+
+// stubLoanRepository passes calls through to the wrapped loan repository, except those it has a func for.
+type stubLoanRepository struct {
+	repository.ILoanRepository
+	getLoanByID                   func(ctx context.Context, id string) (*model.Loan, error)
+	createLoan                    func(ctx context.Context, loan *model.Loan) (string, error)
+	updateLoanState               func(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) error
+	updateLoanTotalInvestedAmount func(ctx context.Context, loan *model.Loan) error
+}
+
+func (slr *stubLoanRepository) GetLoanByID(ctx context.Context, id string) (*model.Loan, error) {
+	if slr.getLoanByID != nil {
+		return slr.getLoanByID(ctx, id)
+	}
+
+	return slr.ILoanRepository.GetLoanByID(ctx, id)
+}
+
+func (slr *stubLoanRepository) CreateLoan(ctx context.Context, loan *model.Loan) (string, error) {
+	if slr.createLoan != nil {
+		return slr.createLoan(ctx, loan)
+	}
+
+	return slr.ILoanRepository.CreateLoan(ctx, loan)
+}
+
+func (slr *stubLoanRepository) UpdateLoanState(ctx context.Context, loan *model.Loan, newLoanState model.LoanState) error {
+	if slr.updateLoanState != nil {
+		return slr.updateLoanState(ctx, loan, newLoanState)
+	}
+
+	return slr.ILoanRepository.UpdateLoanState(ctx, loan, newLoanState)
+}
+
+func (slr *stubLoanRepository) UpdateLoanTotalInvestedAmount(ctx context.Context, loan *model.Loan) error {
+	if slr.updateLoanTotalInvestedAmount != nil {
+		return slr.updateLoanTotalInvestedAmount(ctx, loan)
+	}
+
+	return slr.ILoanRepository.UpdateLoanTotalInvestedAmount(ctx, loan)
+}
+
+// stubInvestmentRepository passes calls through to the wrapped investment repository, except those it has a
+// func for.
+type stubInvestmentRepository struct {
+	repository.IInvestmentRepository
+	createInvestment func(ctx context.Context, investment *model.Investment) (string, error)
+}
+
+func (sir *stubInvestmentRepository) CreateInvestment(ctx context.Context, investment *model.Investment) (string, error) {
+	if sir.createInvestment != nil {
+		return sir.createInvestment(ctx, investment)
+	}
+
+	return sir.IInvestmentRepository.CreateInvestment(ctx, investment)
+}
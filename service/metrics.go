@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "loan_service"
+
+// loanCountTimeout bounds the loan count query run on every scrape.
+const loanCountTimeout = 5 * time.Second
+
+// IMetricsService records business metrics from domain events and reports the loans per state when scraped.
+type IMetricsService interface {
+	prometheus.Collector
+	HandleEvent(ctx context.Context, envelope *model.EventEnvelope) (err error)
+}
+
+type MetricsService struct {
+	LoanRepository   repository.ILoanRepository
+	OutboxRepository repository.IOutboxRepository
+	Metrics          *LoanMetrics
+}
+
+// LoanMetrics are the business metrics of the loan lifecycle.
+type LoanMetrics struct {
+	Transitions        *prometheus.CounterVec
+	Investments        prometheus.Counter
+	InvestmentVolume   prometheus.Counter
+	FundingDuration    prometheus.Histogram
+	DisbursementAmount prometheus.Histogram
+	loans              *prometheus.Desc
+}
+
+func NewLoanMetrics() *LoanMetrics {
+	return &LoanMetrics{
+		Transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "loan_transitions_total",
+			Help:      "Loan state transitions.",
+		}, []string{"from", "to"}),
+		Investments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "investments_total",
+			Help:      "Investments placed on loans.",
+		}),
+		InvestmentVolume: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "investment_volume_total",
+			Help:      "Amount invested in loans, in rupiah.",
+		}),
+		FundingDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "loan_funding_duration_seconds",
+			Help:      "Time from publishing a loan to it being fully funded.",
+			// 1 hour to about 3 weeks
+			Buckets: prometheus.ExponentialBuckets(3600, 2, 10),
+		}),
+		DisbursementAmount: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "loan_disbursement_amount",
+			Help:      "Principal of disbursed loans, in rupiah.",
+			Buckets:   []float64{1e6, 5e6, 10e6, 25e6, 50e6, 100e6, 250e6, 500e6, 1e9},
+		}),
+		loans: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "loans"),
+			"Loans per state.",
+			[]string{"state"}, nil,
+		),
+	}
+}
+
+// NewMetricsService creates the business metrics and registers them on the app registry, call it once.
+func NewMetricsService(app *application.App) IMetricsService {
+	metricsService := &MetricsService{
+		LoanRepository:   repository.NewLoanRepository(app),
+		OutboxRepository: repository.NewOutboxRepository(app),
+		Metrics:          NewLoanMetrics(),
+	}
+	app.MetricsRegistry.MustRegister(metricsService)
+
+	return metricsService
+}
+
+func (ms *MetricsService) Describe(ch chan<- *prometheus.Desc) {
+	ms.Metrics.Transitions.Describe(ch)
+	ms.Metrics.Investments.Describe(ch)
+	ms.Metrics.InvestmentVolume.Describe(ch)
+	ms.Metrics.FundingDuration.Describe(ch)
+	ms.Metrics.DisbursementAmount.Describe(ch)
+	ch <- ms.Metrics.loans
+}
+
+func (ms *MetricsService) Collect(ch chan<- prometheus.Metric) {
+	ms.Metrics.Transitions.Collect(ch)
+	ms.Metrics.Investments.Collect(ch)
+	ms.Metrics.InvestmentVolume.Collect(ch)
+	ms.Metrics.FundingDuration.Collect(ch)
+	ms.Metrics.DisbursementAmount.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), loanCountTimeout)
+	defer cancel()
+
+	counts, err := ms.LoanRepository.CountLoansByState(ctx)
+	if err != nil {
+		// the gauge is left out of this scrape
+		slog.ErrorContext(ctx, "MetricsService CountLoansByState error", "error", err)
+		return
+	}
+
+	for state := range model.ValidLoanState {
+		ch <- prometheus.MustNewConstMetric(ms.Metrics.loans, prometheus.GaugeValue, float64(counts[state]), string(state))
+	}
+}
+
+// HandleEvent updates the business metrics. Events are delivered at least once, the event is marked in the
+// outbox when counted so a redelivered or replayed event is skipped.
+func (ms *MetricsService) HandleEvent(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	if envelope.Type != model.EventTypeInvestmentCreated && !isLoanStateEvent(envelope.Type) {
+		return
+	}
+
+	marked, err := ms.OutboxRepository.MarkEventMetricsRecorded(ctx, envelope.EventID)
+	if err != nil || !marked {
+		return
+	}
+
+	switch {
+	case envelope.Type == model.EventTypeInvestmentCreated:
+		payload := model.InvestmentEventPayload{}
+		if unmarshalErr := json.Unmarshal(envelope.Data, &payload); unmarshalErr != nil {
+			slog.ErrorContext(ctx, "MetricsService HandleEvent Unmarshal error", "event_id", envelope.EventID, "error", unmarshalErr)
+			return
+		}
+
+		ms.Metrics.Investments.Inc()
+		ms.Metrics.InvestmentVolume.Add(payload.InvestedAmount)
+	case isLoanStateEvent(envelope.Type):
+		payload := model.LoanEventPayload{}
+		if unmarshalErr := json.Unmarshal(envelope.Data, &payload); unmarshalErr != nil {
+			slog.ErrorContext(ctx, "MetricsService HandleEvent Unmarshal error", "event_id", envelope.EventID, "error", unmarshalErr)
+			return
+		}
+
+		ms.Metrics.Transitions.WithLabelValues(string(payload.PreviousState), string(payload.State)).Inc()
+
+		switch envelope.Type {
+		case model.EventTypeLoanFullyFunded:
+			if payload.PublishedAt != nil && envelope.OccurredAt != nil {
+				ms.Metrics.FundingDuration.Observe(envelope.OccurredAt.Sub(*payload.PublishedAt).Seconds())
+			}
+		case model.EventTypeLoanDisbursed:
+			ms.Metrics.DisbursementAmount.Observe(payload.PrincipalAmount)
+		}
+	}
+
+	return
+}
+
+func isLoanStateEvent(eventType model.EventType) bool {
+	for _, loanStateEventType := range model.LoanStateEventTypes {
+		if eventType == loanStateEventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("MetricsService", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		mockLoanRepo   *mock.MockILoanRepository
+		mockOutboxRepo *mock.MockIOutboxRepository
+		metrics        *service.LoanMetrics
+		metricsSvc     *service.MetricsService
+		publishedAt    time.Time
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockLoanRepo = mock.NewMockILoanRepository(mockCtrl)
+		mockOutboxRepo = mock.NewMockIOutboxRepository(mockCtrl)
+		metrics = service.NewLoanMetrics()
+		metricsSvc = &service.MetricsService{LoanRepository: mockLoanRepo, OutboxRepository: mockOutboxRepo, Metrics: metrics}
+		publishedAt = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	envelope := func(eventType model.EventType, data string, occurredAt time.Time) *model.EventEnvelope {
+		return &model.EventEnvelope{EventID: "event-1", Type: eventType, OccurredAt: &occurredAt, Data: []byte(data)}
+	}
+
+	Context("HandleEvent", func() {
+		It("should count loan state transitions", func() {
+			mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(true, nil)
+
+			err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeLoanApproved,
+				`{"loan_id":"loan-1","state":"approved","previous_state":"proposed"}`, publishedAt))
+			Expect(err).To(BeNil())
+
+			Expect(testutil.ToFloat64(metrics.Transitions.WithLabelValues("proposed", "approved"))).To(Equal(1.0))
+		})
+
+		It("should observe the funding time of fully funded loans", func() {
+			mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(true, nil)
+
+			err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeLoanFullyFunded,
+				`{"loan_id":"loan-1","state":"invested","previous_state":"published","published_at":"2025-06-01T10:00:00Z"}`,
+				publishedAt.Add(36*time.Hour)))
+			Expect(err).To(BeNil())
+
+			histogram := &dto.Metric{}
+			Expect(metrics.FundingDuration.Write(histogram)).To(Succeed())
+			Expect(histogram.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+			Expect(histogram.GetHistogram().GetSampleSum()).To(Equal((36 * time.Hour).Seconds()))
+		})
+
+		It("should add up the investment volume", func() {
+			mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+			for i, amount := range []string{"1000000", "2500000"} {
+				investmentEnvelope := envelope(model.EventTypeInvestmentCreated, `{"loan_id":"loan-1","invested_amount":`+amount+`}`, publishedAt)
+				investmentEnvelope.EventID = fmt.Sprintf("event-%d", i+1)
+
+				err := metricsSvc.HandleEvent(context.Background(), investmentEnvelope)
+				Expect(err).To(BeNil())
+			}
+
+			Expect(testutil.ToFloat64(metrics.Investments)).To(Equal(2.0))
+			Expect(testutil.ToFloat64(metrics.InvestmentVolume)).To(Equal(3500000.0))
+		})
+
+		It("should not count an event again when it is redelivered or replayed", func() {
+			gomock.InOrder(
+				mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(true, nil),
+				mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(false, nil).Times(2),
+			)
+
+			for i := 0; i < 3; i++ {
+				err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeInvestmentCreated,
+					`{"loan_id":"loan-1","invested_amount":1000000}`, publishedAt))
+				Expect(err).To(BeNil())
+			}
+
+			Expect(testutil.ToFloat64(metrics.Investments)).To(Equal(1.0))
+			Expect(testutil.ToFloat64(metrics.InvestmentVolume)).To(Equal(1000000.0))
+		})
+
+		It("should fail the delivery when the event cannot be marked so it is counted on the retry", func() {
+			mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(false, errors.New("db down"))
+
+			err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeLoanApproved,
+				`{"loan_id":"loan-1","state":"approved","previous_state":"proposed"}`, publishedAt))
+			Expect(err).To(MatchError("db down"))
+			Expect(testutil.CollectAndCount(metrics.Transitions)).To(Equal(0))
+		})
+
+		It("should skip events without business metrics", func() {
+			err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeInvestmentsReleased, `{}`, publishedAt))
+			Expect(err).To(BeNil())
+		})
+
+		It("should ignore events it cannot read", func() {
+			mockOutboxRepo.EXPECT().MarkEventMetricsRecorded(gomock.Any(), "event-1").Return(true, nil)
+
+			err := metricsSvc.HandleEvent(context.Background(), envelope(model.EventTypeLoanDisbursed, `not json`, publishedAt))
+			Expect(err).To(BeNil())
+			Expect(testutil.CollectAndCount(metrics.Transitions)).To(Equal(0))
+		})
+	})
+
+	Context("Collect", func() {
+		It("should report the loans per state", func() {
+			mockLoanRepo.EXPECT().CountLoansByState(gomock.Any()).
+				Return(map[model.LoanState]int64{model.LoanStatePublished: 4, model.LoanStateDisbursed: 2}, nil)
+
+			Expect(testutil.CollectAndCompare(metricsSvc, strings.NewReader(`
+				# HELP loan_service_loans Loans per state.
+				# TYPE loan_service_loans gauge
+				loan_service_loans{state="approved"} 0
+				loan_service_loans{state="canceled"} 0
+				loan_service_loans{state="disbursed"} 2
+				loan_service_loans{state="invested"} 0
+				loan_service_loans{state="proposed"} 0
+				loan_service_loans{state="published"} 4
+				loan_service_loans{state="rejected"} 0
+			`), "loan_service_loans")).To(Succeed())
+		})
+
+		It("should leave the loans out when they cannot be counted", func() {
+			mockLoanRepo.EXPECT().CountLoansByState(gomock.Any()).Return(nil, errors.New("db down"))
+
+			Expect(testutil.CollectAndCount(metricsSvc, "loan_service_loans")).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+	"github.com/google/uuid"
+)
+
+type INotificationService interface {
+	INotifier
+	HandleEvent(ctx context.Context, envelope *model.EventEnvelope) (err error)
+	SendEmailNotification(ctx context.Context, notificationID string) (err error)
+	SendRepaymentReminders(ctx context.Context) (notified int, err error)
+	ResendAgreementReady(ctx context.Context, loanID string) (err error)
+	GetNotificationPreference(ctx context.Context, getNotificationPreferenceRequest *model.GetNotificationPreferenceRequest) (notificationPreferenceResponse *model.NotificationPreferenceResponse, err error)
+	UpdateNotificationPreference(ctx context.Context, updateNotificationPreferenceRequest *model.UpdateNotificationPreferenceRequest) (notificationPreferenceResponse *model.NotificationPreferenceResponse, err error)
+}
+
+type NotificationService struct {
+	NotificationRepository repository.INotificationRepository
+	LoanRepository         repository.ILoanRepository
+	BorrowerRepository     repository.IBorrowerRepository
+	InvestorRepository     repository.IInvestorRepository
+	InvestmentRepository   repository.IInvestmentRepository
+	TransactionManager     repository.ITransactionManager
+	AuditRepository        repository.IAuditRepository
+	JobService             IJobService
+	Sender                 IEmailSender
+	Config                 configuration.Notification
+	Now                    func() time.Time
+}
+
+func NewNotificationService(app *application.App) INotificationService {
+	return &NotificationService{
+		NotificationRepository: repository.NewNotificationRepository(app),
+		LoanRepository:         repository.NewLoanRepository(app),
+		BorrowerRepository:     repository.NewBorrowerRepository(app),
+		InvestorRepository:     repository.NewInvestorRepository(app),
+		InvestmentRepository:   repository.NewInvestmentRepository(app),
+		TransactionManager:     repository.NewTransactionManager(app),
+		AuditRepository:        repository.NewAuditRepository(app),
+		JobService:             NewJobService(app),
+		Sender:                 NewEmailSender(app.Config.SMTP),
+		Config:                 app.Config.Notification,
+		Now:                    time.Now,
+	}
+}
+
+type notificationRecipient struct {
+	Type  model.RecipientType
+	ID    string
+	Email string
+	Data  EmailTemplateData
+}
+
+// Notify renders an email for the borrower and every investor of the notification and queues it.
+// A recipient gets one email per event and key, so repeated calls are safe. Recipients without an
+// email address or who opted out of the event are skipped.
+func (ns *NotificationService) Notify(ctx context.Context, notification *model.Notification) (err error) {
+	loan, err := ns.LoanRepository.GetLoanByID(ctx, notification.LoanID)
+	if err != nil {
+		return
+	}
+
+	key := notification.Key
+	if key == "" {
+		key = notification.LoanID
+	}
+
+	data := EmailTemplateData{
+		LoanID:          loan.ID,
+		PrincipalAmount: loan.PrincipalAmount,
+		TenorMonths:     loan.TenorMonths,
+		Reason:          notification.Reason,
+		Installment:     notification.Installment,
+	}
+
+	recipients, err := ns.resolveRecipients(ctx, notification, loan, data)
+	if err != nil {
+		return
+	}
+
+	errs := []error{}
+	for _, recipient := range recipients {
+		notifyErr := ns.notifyRecipient(ctx, notification.Event, key, recipient)
+		if notifyErr != nil {
+			slog.ErrorContext(ctx, "Notify notifyRecipient error", "event", notification.Event, "recipient_type", recipient.Type, "recipient_id", recipient.ID, "error", notifyErr)
+			errs = append(errs, notifyErr)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ns *NotificationService) resolveRecipients(ctx context.Context, notification *model.Notification, loan *model.Loan, data EmailTemplateData) (recipients []*notificationRecipient, err error) {
+	if notification.BorrowerID != "" {
+		borrower, err := ns.BorrowerRepository.GetBorrowerByID(ctx, notification.BorrowerID)
+		if err != nil {
+			return nil, err
+		}
+
+		borrowerData := data
+		borrowerData.RecipientName = borrower.Name
+		borrowerData.AgreementLetterURL = loan.LoanAgreementLetterURL
+		recipients = append(recipients, &notificationRecipient{
+			Type:  model.RecipientTypeBorrower,
+			ID:    borrower.ID,
+			Email: borrower.Email,
+			Data:  borrowerData,
+		})
+	}
+
+	if len(notification.InvestorIDs) == 0 {
+		return
+	}
+
+	investments, err := ns.InvestmentRepository.ListInvestmentsByLoanID(ctx, loan.ID)
+	if err != nil {
+		return
+	}
+
+	investmentByInvestorID := make(map[string]*model.Investment, len(investments))
+	for _, investment := range investments {
+		investmentByInvestorID[investment.InvestorID] = investment
+	}
+
+	for _, investorID := range notification.InvestorIDs {
+		investor, err := ns.InvestorRepository.GetInvestorByID(ctx, investorID)
+		if err != nil {
+			return nil, err
+		}
+
+		investorData := data
+		investorData.RecipientName = investor.Name
+		if investment, ok := investmentByInvestorID[investorID]; ok {
+			investorData.InvestedAmount = investment.InvestedAmount
+			investorData.AgreementLetterURL = investment.InvestmentAgreementLetterURL
+		}
+		recipients = append(recipients, &notificationRecipient{
+			Type:  model.RecipientTypeInvestor,
+			ID:    investor.ID,
+			Email: investor.Email,
+			Data:  investorData,
+		})
+	}
+
+	return
+}
+
+func (ns *NotificationService) notifyRecipient(ctx context.Context, event model.NotificationEvent, key string, recipient *notificationRecipient) (err error) {
+	preference, err := ns.getNotificationPreference(ctx, recipient.Type, recipient.ID)
+	if err != nil {
+		return
+	}
+
+	if recipient.Email == "" || !preference.Allows(event) {
+		return
+	}
+
+	subject, body, err := renderEmail(preference.Language, event, &recipient.Data)
+	if err != nil {
+		return
+	}
+
+	return ns.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		notificationID, err := ns.NotificationRepository.CreateEmailNotification(ctx, &model.EmailNotification{
+			Event:         event,
+			RecipientType: recipient.Type,
+			RecipientID:   recipient.ID,
+			Email:         recipient.Email,
+			Language:      preference.Language,
+			Subject:       subject,
+			Body:          body,
+			DedupeKey:     fmt.Sprintf("%s:%s:%s:%s", event, recipient.Type, recipient.ID, key),
+		})
+		if err != nil {
+			if err == model.ErrorEmailNotificationExist {
+				err = nil
+			}
+			return
+		}
+
+		_, err = ns.JobService.EnqueueJob(ctx, model.JobTypeSendEmailNotification, &model.SendEmailNotificationPayload{NotificationID: notificationID}, nil)
+
+		return
+	})
+}
+
+// getNotificationPreference falls back to email in the default language when the recipient has no preference.
+func (ns *NotificationService) getNotificationPreference(ctx context.Context, recipientType model.RecipientType, recipientID string) (preference *model.NotificationPreference, err error) {
+	preference, err = ns.NotificationRepository.GetNotificationPreference(ctx, recipientType, recipientID)
+	if err != model.ErrorNotificationPreferenceNotFound {
+		return
+	}
+
+	preference = &model.NotificationPreference{
+		RecipientType:  recipientType,
+		RecipientID:    recipientID,
+		Language:       model.Language(ns.Config.DefaultLanguage),
+		EmailEnabled:   true,
+		DisabledEvents: []string{},
+	}
+	err = nil
+
+	return
+}
+
+// HandleEvent turns loan domain events into notifications: a fully funded loan tells the borrower and
+// sends the investors their agreement, a disbursed loan tells both.
+func (ns *NotificationService) HandleEvent(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	if envelope.Type != model.EventTypeLoanFullyFunded && envelope.Type != model.EventTypeLoanDisbursed {
+		return
+	}
+
+	payload := model.LoanEventPayload{}
+	err = json.Unmarshal(envelope.Data, &payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "HandleEvent Unmarshal error", "error", err)
+		return
+	}
+
+	investorIDs, err := ns.activeInvestorIDs(ctx, payload.LoanID)
+	if err != nil {
+		return
+	}
+
+	switch envelope.Type {
+	case model.EventTypeLoanFullyFunded:
+		err = ns.Notify(ctx, &model.Notification{
+			Event:      model.NotificationEventLoanFunded,
+			LoanID:     payload.LoanID,
+			BorrowerID: payload.BorrowerID,
+			Key:        envelope.EventID,
+		})
+		if err != nil {
+			return
+		}
+
+		return ns.Notify(ctx, &model.Notification{
+			Event:       model.NotificationEventAgreementReady,
+			LoanID:      payload.LoanID,
+			InvestorIDs: investorIDs,
+			Key:         envelope.EventID,
+		})
+	case model.EventTypeLoanDisbursed:
+		return ns.Notify(ctx, &model.Notification{
+			Event:       model.NotificationEventLoanDisbursed,
+			LoanID:      payload.LoanID,
+			BorrowerID:  payload.BorrowerID,
+			InvestorIDs: investorIDs,
+			Key:         envelope.EventID,
+		})
+	}
+
+	return
+}
+
+// ResendAgreementReady sends the investors of a funded loan the agreement ready notification again, e.g.
+// after the agreement letter was replaced. Every call is a new occurrence, so it is never deduplicated.
+func (ns *NotificationService) ResendAgreementReady(ctx context.Context, loanID string) (err error) {
+	loan, err := ns.LoanRepository.GetLoanByID(ctx, loanID)
+	if err != nil {
+		return
+	}
+
+	if loan.State != model.LoanStateInvested && loan.State != model.LoanStateDisbursed {
+		err = model.ErrorLoanAgreementNotReady
+		return
+	}
+
+	investorIDs, err := ns.activeInvestorIDs(ctx, loanID)
+	if err != nil {
+		return
+	}
+
+	return ns.Notify(ctx, &model.Notification{
+		Event:       model.NotificationEventAgreementReady,
+		LoanID:      loanID,
+		InvestorIDs: investorIDs,
+		Key:         "resend:" + uuid.NewString(),
+	})
+}
+
+func (ns *NotificationService) activeInvestorIDs(ctx context.Context, loanID string) (investorIDs []string, err error) {
+	investments, err := ns.InvestmentRepository.ListInvestmentsByLoanID(ctx, loanID)
+	if err != nil {
+		return
+	}
+
+	for _, investment := range investments {
+		if investment.Status == model.InvestmentStatusActive {
+			investorIDs = append(investorIDs, investment.InvestorID)
+		}
+	}
+
+	return
+}
+
+// SendEmailNotification delivers a queued email. A failed attempt is recorded and returned so the job queue retries it.
+func (ns *NotificationService) SendEmailNotification(ctx context.Context, notificationID string) (err error) {
+	notification, err := ns.NotificationRepository.GetEmailNotificationByID(ctx, notificationID)
+	if err != nil {
+		return
+	}
+
+	// the email of an erased recipient was redacted before it went out
+	if notification.Status == model.EmailNotificationStatusSent || notification.Email == "" {
+		return
+	}
+
+	sendErr := ns.Sender.Send(ctx, &model.EmailMessage{
+		To:      notification.Email,
+		Subject: notification.Subject,
+		Body:    notification.Body,
+	})
+
+	notification.Attempts++
+	notification.LastError = ""
+	if sendErr != nil {
+		notification.LastError = sendErr.Error()
+	} else {
+		now := ns.Now()
+		notification.Status = model.EmailNotificationStatusSent
+		notification.SentAt = &now
+	}
+
+	err = ns.NotificationRepository.UpdateEmailNotification(ctx, notification)
+	if err != nil {
+		return
+	}
+
+	return sendErr
+}
+
+// SendRepaymentReminders notifies borrowers of disbursed loans about installments due within
+// NOTIFICATION_REPAYMENT_REMINDER_DAYS. Each installment is reminded once.
+func (ns *NotificationService) SendRepaymentReminders(ctx context.Context) (notified int, err error) {
+	loans, err := ns.LoanRepository.ListLoansByState(ctx, model.LoanStateDisbursed)
+	if err != nil {
+		return
+	}
+
+	now := ns.Now()
+	horizon := now.AddDate(0, 0, ns.Config.RepaymentReminderDays)
+	for _, loan := range loans {
+		for _, installment := range model.ComposeRepaymentSchedule(loan) {
+			if installment.DueDate.Before(now) || installment.DueDate.After(horizon) {
+				continue
+			}
+
+			notifyErr := ns.Notify(ctx, &model.Notification{
+				Event:       model.NotificationEventRepaymentDue,
+				LoanID:      loan.ID,
+				BorrowerID:  loan.BorrowerID,
+				Key:         fmt.Sprintf("%s:%d", loan.ID, installment.Number),
+				Installment: installment,
+			})
+			if notifyErr != nil {
+				slog.ErrorContext(ctx, "SendRepaymentReminders Notify error", "loan_id", loan.ID, "error", notifyErr)
+				continue
+			}
+			notified++
+		}
+	}
+
+	return
+}
+
+func (ns *NotificationService) GetNotificationPreference(ctx context.Context, getNotificationPreferenceRequest *model.GetNotificationPreferenceRequest) (notificationPreferenceResponse *model.NotificationPreferenceResponse, err error) {
+	err = ns.validateRecipient(ctx, getNotificationPreferenceRequest.RecipientType, getNotificationPreferenceRequest.RecipientID)
+	if err != nil {
+		return
+	}
+
+	preference, err := ns.getNotificationPreference(ctx, getNotificationPreferenceRequest.RecipientType, getNotificationPreferenceRequest.RecipientID)
+	if err != nil {
+		return
+	}
+
+	notificationPreferenceResponse = model.ComposeNotificationPreferenceResponse(preference)
+
+	return
+}
+
+func (ns *NotificationService) UpdateNotificationPreference(ctx context.Context, updateNotificationPreferenceRequest *model.UpdateNotificationPreferenceRequest) (notificationPreferenceResponse *model.NotificationPreferenceResponse, err error) {
+	for _, event := range updateNotificationPreferenceRequest.DisabledEvents {
+		if !model.ValidNotificationEvents[model.NotificationEvent(event)] {
+			err = model.ErrorNotificationEventInvalid
+			return
+		}
+	}
+
+	err = ns.validateRecipient(ctx, updateNotificationPreferenceRequest.RecipientType, updateNotificationPreferenceRequest.RecipientID)
+	if err != nil {
+		return
+	}
+
+	preference := &model.NotificationPreference{
+		RecipientType:  updateNotificationPreferenceRequest.RecipientType,
+		RecipientID:    updateNotificationPreferenceRequest.RecipientID,
+		Language:       model.Language(updateNotificationPreferenceRequest.Language),
+		EmailEnabled:   updateNotificationPreferenceRequest.EmailEnabled,
+		DisabledEvents: updateNotificationPreferenceRequest.DisabledEvents,
+	}
+
+	err = ns.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		// a recipient without stored preferences has the defaults
+		current, err := ns.getNotificationPreference(ctx, preference.RecipientType, preference.RecipientID)
+		if err != nil {
+			return
+		}
+
+		err = ns.NotificationRepository.UpsertNotificationPreference(ctx, preference)
+		if err != nil {
+			return
+		}
+
+		entityID := string(preference.RecipientType) + ":" + preference.RecipientID
+		return recordAudit(ctx, ns.AuditRepository, model.AuditEntityNotificationPreference, entityID, model.AuditActionUpdate,
+			model.ComposeNotificationPreferenceResponse(current), model.ComposeNotificationPreferenceResponse(preference))
+	})
+	if err != nil {
+		return
+	}
+
+	notificationPreferenceResponse = model.ComposeNotificationPreferenceResponse(preference)
+
+	return
+}
+
+func (ns *NotificationService) validateRecipient(ctx context.Context, recipientType model.RecipientType, recipientID string) (err error) {
+	switch recipientType {
+	case model.RecipientTypeBorrower:
+		_, err = ns.BorrowerRepository.GetBorrowerByID(ctx, recipientID)
+	case model.RecipientTypeInvestor:
+		_, err = ns.InvestorRepository.GetInvestorByID(ctx, recipientID)
+	default:
+		err = model.ErrorRecipientTypeInvalid
+	}
+
+	return
+}
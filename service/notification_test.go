@@ -0,0 +1,371 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("NotificationService", func() {
+	var (
+		mockCtrl             *gomock.Controller
+		mockNotificationRepo *mock.MockINotificationRepository
+		mockLoanRepo         *mock.MockILoanRepository
+		mockBorrowerRepo     *mock.MockIBorrowerRepository
+		mockInvestorRepo     *mock.MockIInvestorRepository
+		mockInvestmentRepo   *mock.MockIInvestmentRepository
+		mockJobSvc           *mock.MockIJobService
+		mockSender           *mock.MockIEmailSender
+		mockAuditRepo        *mock.MockIAuditRepository
+		notificationSvc      service.INotificationService
+		now                  time.Time
+		loan                 *model.Loan
+		borrower             *model.Borrower
+		investor             *model.Investor
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockNotificationRepo = mock.NewMockINotificationRepository(mockCtrl)
+		mockLoanRepo = mock.NewMockILoanRepository(mockCtrl)
+		mockBorrowerRepo = mock.NewMockIBorrowerRepository(mockCtrl)
+		mockInvestorRepo = mock.NewMockIInvestorRepository(mockCtrl)
+		mockInvestmentRepo = mock.NewMockIInvestmentRepository(mockCtrl)
+		mockJobSvc = mock.NewMockIJobService(mockCtrl)
+		mockSender = mock.NewMockIEmailSender(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)
+
+		notificationSvc = &service.NotificationService{
+			NotificationRepository: mockNotificationRepo,
+			LoanRepository:         mockLoanRepo,
+			BorrowerRepository:     mockBorrowerRepo,
+			InvestorRepository:     mockInvestorRepo,
+			InvestmentRepository:   mockInvestmentRepo,
+			TransactionManager:     &mock.MockTransactionManager{},
+			AuditRepository:        mockAuditRepo,
+			JobService:             mockJobSvc,
+			Sender:                 mockSender,
+			Config: configuration.Notification{
+				DefaultLanguage:       "id",
+				RepaymentReminderDays: 3,
+			},
+			Now: func() time.Time { return now },
+		}
+
+		disbursedAt := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+		loan = &model.Loan{
+			ID:              "loan-1",
+			BorrowerID:      "borrower-1",
+			TenorMonths:     3,
+			PrincipalAmount: 1500000,
+			InterestRate:    10,
+			State:           model.LoanStateDisbursed,
+			DisbursedAt:     &disbursedAt,
+		}
+		borrower = &model.Borrower{ID: "borrower-1", Name: "Budi", Email: "budi@example.com"}
+		investor = &model.Investor{ID: "investor-1", Name: "Alice", Email: "alice@example.com"}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("Notify", func() {
+		It("should email the borrower in the default language", func() {
+			ctx := context.Background()
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, notification *model.EmailNotification) (string, error) {
+					Expect(notification.Email).To(Equal("budi@example.com"))
+					Expect(notification.Language).To(Equal(model.LanguageIndonesian))
+					Expect(notification.Subject).To(Equal("Pinjaman Anda telah didanai penuh"))
+					Expect(notification.Body).To(ContainSubstring("Rp1.500.000"))
+					Expect(notification.DedupeKey).To(Equal("loan_funded:borrower:borrower-1:event-1"))
+					return "notification-1", nil
+				})
+			mockJobSvc.EXPECT().EnqueueJob(ctx, model.JobTypeSendEmailNotification, &model.SendEmailNotificationPayload{NotificationID: "notification-1"}, nil).Return("job-1", nil)
+
+			err := notificationSvc.Notify(ctx, &model.Notification{
+				Event:      model.NotificationEventLoanFunded,
+				LoanID:     "loan-1",
+				BorrowerID: "borrower-1",
+				Key:        "event-1",
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should use the investor preference and include the agreement letter", func() {
+			ctx := context.Background()
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByLoanID(ctx, "loan-1").Return([]*model.Investment{
+				{ID: "investment-1", InvestorID: "investor-1", InvestedAmount: 500000, InvestmentAgreementLetterURL: "https://files.example.com/agreement-1.pdf"},
+			}, nil)
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, "investor-1").Return(investor, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeInvestor, "investor-1").
+				Return(&model.NotificationPreference{Language: model.LanguageEnglish, EmailEnabled: true}, nil)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, notification *model.EmailNotification) (string, error) {
+					Expect(notification.Subject).To(Equal("Your investment agreement is ready"))
+					Expect(notification.Body).To(ContainSubstring("https://files.example.com/agreement-1.pdf"))
+					Expect(notification.Body).To(ContainSubstring("Rp500.000"))
+					return "notification-1", nil
+				})
+			mockJobSvc.EXPECT().EnqueueJob(ctx, model.JobTypeSendEmailNotification, gomock.Any(), nil).Return("job-1", nil)
+
+			err := notificationSvc.Notify(ctx, &model.Notification{
+				Event:       model.NotificationEventAgreementReady,
+				LoanID:      "loan-1",
+				InvestorIDs: []string{"investor-1"},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should skip recipients who opted out of the event", func() {
+			ctx := context.Background()
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").
+				Return(&model.NotificationPreference{Language: model.LanguageEnglish, EmailEnabled: true, DisabledEvents: []string{"loan_disbursed"}}, nil)
+
+			err := notificationSvc.Notify(ctx, &model.Notification{
+				Event:      model.NotificationEventLoanDisbursed,
+				LoanID:     "loan-1",
+				BorrowerID: "borrower-1",
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should skip recipients without an email address", func() {
+			ctx := context.Background()
+			borrower.Email = ""
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+
+			err := notificationSvc.Notify(ctx, &model.Notification{
+				Event:      model.NotificationEventLoanCanceled,
+				LoanID:     "loan-1",
+				BorrowerID: "borrower-1",
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("should not queue an email twice", func() {
+			ctx := context.Background()
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).Return("", model.ErrorEmailNotificationExist)
+
+			err := notificationSvc.Notify(ctx, &model.Notification{
+				Event:      model.NotificationEventLoanCanceled,
+				LoanID:     "loan-1",
+				BorrowerID: "borrower-1",
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("HandleEvent", func() {
+		It("should notify the borrower and investors of a fully funded loan", func() {
+			ctx := context.Background()
+			data, _ := json.Marshal(&model.LoanEventPayload{LoanID: "loan-1", BorrowerID: "borrower-1"})
+			investments := []*model.Investment{
+				{ID: "investment-1", InvestorID: "investor-1", Status: model.InvestmentStatusActive},
+				{ID: "investment-2", InvestorID: "investor-2", Status: model.InvestmentStatusReleased},
+			}
+			events := []model.NotificationEvent{}
+
+			mockInvestmentRepo.EXPECT().ListInvestmentsByLoanID(ctx, "loan-1").Return(investments, nil).Times(2)
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil).Times(2)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, "investor-1").Return(investor, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, gomock.Any(), gomock.Any()).Return(nil, model.ErrorNotificationPreferenceNotFound).Times(2)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, notification *model.EmailNotification) (string, error) {
+					events = append(events, notification.Event)
+					return "notification-1", nil
+				}).Times(2)
+			mockJobSvc.EXPECT().EnqueueJob(ctx, model.JobTypeSendEmailNotification, gomock.Any(), nil).Return("job-1", nil).Times(2)
+
+			err := notificationSvc.HandleEvent(ctx, &model.EventEnvelope{EventID: "event-1", Type: model.EventTypeLoanFullyFunded, Data: data})
+			Expect(err).To(BeNil())
+			Expect(events).To(Equal([]model.NotificationEvent{model.NotificationEventLoanFunded, model.NotificationEventAgreementReady}))
+		})
+
+		It("should ignore other events", func() {
+			err := notificationSvc.HandleEvent(context.Background(), &model.EventEnvelope{EventID: "event-1", Type: model.EventTypeLoanApproved})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("ResendAgreementReady", func() {
+		It("should notify the investors again with a new key", func() {
+			ctx := context.Background()
+			investments := []*model.Investment{{ID: "investment-1", InvestorID: "investor-1", Status: model.InvestmentStatusActive}}
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil).Times(2)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByLoanID(ctx, "loan-1").Return(investments, nil).Times(2)
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, "investor-1").Return(investor, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeInvestor, "investor-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, notification *model.EmailNotification) (string, error) {
+					Expect(notification.Event).To(Equal(model.NotificationEventAgreementReady))
+					Expect(notification.DedupeKey).To(HavePrefix("agreement_ready:investor:investor-1:resend:"))
+					return "notification-1", nil
+				})
+			mockJobSvc.EXPECT().EnqueueJob(ctx, model.JobTypeSendEmailNotification, gomock.Any(), nil).Return("job-1", nil)
+
+			err := notificationSvc.ResendAgreementReady(ctx, "loan-1")
+			Expect(err).To(BeNil())
+		})
+
+		It("should reject a loan that is not funded yet", func() {
+			ctx := context.Background()
+			loan.State = model.LoanStatePublished
+
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+
+			err := notificationSvc.ResendAgreementReady(ctx, "loan-1")
+			Expect(err).To(MatchError(model.ErrorLoanAgreementNotReady))
+		})
+	})
+
+	Context("SendEmailNotification", func() {
+		It("should mark the email sent", func() {
+			ctx := context.Background()
+			notification := &model.EmailNotification{ID: "notification-1", Email: "budi@example.com", Subject: "subject", Body: "body", Status: model.EmailNotificationStatusPending}
+
+			mockNotificationRepo.EXPECT().GetEmailNotificationByID(ctx, "notification-1").Return(notification, nil)
+			mockSender.EXPECT().Send(ctx, &model.EmailMessage{To: "budi@example.com", Subject: "subject", Body: "body"}).Return(nil)
+			mockNotificationRepo.EXPECT().UpdateEmailNotification(ctx, notification).Return(nil)
+
+			err := notificationSvc.SendEmailNotification(ctx, "notification-1")
+			Expect(err).To(BeNil())
+			Expect(notification.Status).To(Equal(model.EmailNotificationStatusSent))
+			Expect(notification.SentAt).To(Equal(&now))
+		})
+
+		It("should record a failed attempt and return the error for a retry", func() {
+			ctx := context.Background()
+			notification := &model.EmailNotification{ID: "notification-1", Email: "budi@example.com", Status: model.EmailNotificationStatusPending}
+
+			mockNotificationRepo.EXPECT().GetEmailNotificationByID(ctx, "notification-1").Return(notification, nil)
+			mockSender.EXPECT().Send(ctx, gomock.Any()).Return(errors.New("connection refused"))
+			mockNotificationRepo.EXPECT().UpdateEmailNotification(ctx, notification).Return(nil)
+
+			err := notificationSvc.SendEmailNotification(ctx, "notification-1")
+			Expect(err).To(MatchError("connection refused"))
+			Expect(notification.Status).To(Equal(model.EmailNotificationStatusPending))
+			Expect(notification.Attempts).To(Equal(1))
+			Expect(notification.LastError).To(Equal("connection refused"))
+		})
+
+		It("should not send an email twice", func() {
+			ctx := context.Background()
+			notification := &model.EmailNotification{ID: "notification-1", Status: model.EmailNotificationStatusSent}
+
+			mockNotificationRepo.EXPECT().GetEmailNotificationByID(ctx, "notification-1").Return(notification, nil)
+
+			err := notificationSvc.SendEmailNotification(ctx, "notification-1")
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("SendRepaymentReminders", func() {
+		It("should remind the borrower of an installment due within the reminder window", func() {
+			ctx := context.Background()
+
+			mockLoanRepo.EXPECT().ListLoansByState(ctx, model.LoanStateDisbursed).Return([]*model.Loan{loan}, nil)
+			mockLoanRepo.EXPECT().GetLoanByID(ctx, "loan-1").Return(loan, nil)
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(borrower, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().CreateEmailNotification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, notification *model.EmailNotification) (string, error) {
+					Expect(notification.DedupeKey).To(Equal("repayment_due:borrower:borrower-1:loan-1:1"))
+					Expect(notification.Subject).To(Equal("Cicilan ke-1 pinjaman loan-1 jatuh tempo pada 2025-06-03"))
+					Expect(notification.Body).To(ContainSubstring("Rp550.000"))
+					return "notification-1", nil
+				})
+			mockJobSvc.EXPECT().EnqueueJob(ctx, model.JobTypeSendEmailNotification, gomock.Any(), nil).Return("job-1", nil)
+
+			notified, err := notificationSvc.SendRepaymentReminders(ctx)
+			Expect(err).To(BeNil())
+			Expect(notified).To(Equal(1))
+		})
+
+		It("should not remind installments outside the window", func() {
+			ctx := context.Background()
+			disbursedAt := now.AddDate(0, 0, -10)
+			loan.DisbursedAt = &disbursedAt
+
+			mockLoanRepo.EXPECT().ListLoansByState(ctx, model.LoanStateDisbursed).Return([]*model.Loan{loan}, nil)
+
+			notified, err := notificationSvc.SendRepaymentReminders(ctx)
+			Expect(err).To(BeNil())
+			Expect(notified).To(Equal(0))
+		})
+	})
+
+	Context("UpdateNotificationPreference", func() {
+		It("should reject unknown events", func() {
+			resp, err := notificationSvc.UpdateNotificationPreference(context.Background(), &model.UpdateNotificationPreferenceRequest{
+				RecipientType:  model.RecipientTypeInvestor,
+				RecipientID:    "investor-1",
+				Language:       "en",
+				DisabledEvents: []string{"loan_exploded"},
+			})
+			Expect(err).To(Equal(model.ErrorNotificationEventInvalid))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should store the preference of an existing investor", func() {
+			ctx := context.Background()
+
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, "investor-1").Return(investor, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeInvestor, "investor-1").Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().UpsertNotificationPreference(ctx, gomock.Any()).Return(nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityNotificationPreference))
+					Expect(auditLog.EntityID).To(Equal("investor:investor-1"))
+					changes := map[string]model.AuditChange{}
+					Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+					Expect(changes).To(HaveKey("disabled_events"))
+					Expect(changes).NotTo(HaveKey("email_enabled"))
+					return nil
+				})
+
+			resp, err := notificationSvc.UpdateNotificationPreference(ctx, &model.UpdateNotificationPreferenceRequest{
+				RecipientType:  model.RecipientTypeInvestor,
+				RecipientID:    "investor-1",
+				Language:       "en",
+				EmailEnabled:   true,
+				DisabledEvents: []string{"loan_disbursed"},
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.Language).To(Equal("en"))
+			Expect(resp.DisabledEvents).To(Equal([]string{"loan_disbursed"}))
+		})
+	})
+})
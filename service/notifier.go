@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+// INotifier delivers notifications to the participants of a loan.
+type INotifier interface {
+	Notify(ctx context.Context, notification *model.Notification) (err error)
+}
+
+func NewNotifier(app *application.App) INotifier {
+	return NewNotificationService(app)
+}
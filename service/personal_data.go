@@ -0,0 +1,508 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type IPersonalDataService interface {
+	ReencryptPersonalData(ctx context.Context) (reencrypted int, err error)
+	ExportPersonalData(ctx context.Context, exportPersonalDataRequest *model.ExportPersonalDataRequest) (export *model.PersonalDataExport, err error)
+	CreateErasureRequest(ctx context.Context, createErasureRequestRequest *model.CreateErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error)
+	ListErasureRequests(ctx context.Context, listErasureRequestsRequest *model.ListErasureRequestsRequest) (erasureRequestResponses []*model.ErasureRequestResponse, err error)
+	CompleteErasureRequest(ctx context.Context, completeErasureRequestRequest *model.CompleteErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error)
+	RejectErasureRequest(ctx context.Context, rejectErasureRequestRequest *model.RejectErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error)
+}
+
+// PersonalDataService keeps the personal data of borrowers and investors encrypted and serves the rights of
+// the subjects under the PDP law: exporting their data and erasing it.
+type PersonalDataService struct {
+	BorrowerRepository     repository.IBorrowerRepository
+	InvestorRepository     repository.IInvestorRepository
+	LoanRepository         repository.ILoanRepository
+	InvestmentRepository   repository.IInvestmentRepository
+	NotificationRepository repository.INotificationRepository
+	PersonalDataRepository repository.IPersonalDataRepository
+	TransactionManager     repository.ITransactionManager
+	AuditRepository        repository.IAuditRepository
+	Config                 configuration.Encryption
+	Now                    func() time.Time
+}
+
+func NewPersonalDataService(app *application.App) IPersonalDataService {
+	return &PersonalDataService{
+		BorrowerRepository:     repository.NewBorrowerRepository(app),
+		InvestorRepository:     repository.NewInvestorRepository(app),
+		LoanRepository:         repository.NewLoanRepository(app),
+		InvestmentRepository:   repository.NewInvestmentRepository(app),
+		NotificationRepository: repository.NewNotificationRepository(app),
+		PersonalDataRepository: repository.NewPersonalDataRepository(app),
+		TransactionManager:     repository.NewTransactionManager(app),
+		AuditRepository:        repository.NewAuditRepository(app),
+		Config:                 app.Config.Encryption,
+		Now:                    time.Now,
+	}
+}
+
+// ReencryptPersonalData moves the personal data of borrowers and investors to the active key in batches,
+// rows still in plaintext from before encryption get encrypted and indexed. Every row is written on its
+// own, so a failed run keeps its progress and the next run continues with the rows left.
+func (pds *PersonalDataService) ReencryptPersonalData(ctx context.Context) (reencrypted int, err error) {
+	for afterID := model.NilUUID; ; {
+		var borrowers []*model.Borrower
+		borrowers, err = pds.BorrowerRepository.ListBorrowersToReencrypt(ctx, afterID, pds.Config.ReencryptBatchSize)
+		if err != nil {
+			return
+		}
+
+		for _, borrower := range borrowers {
+			if err = pds.BorrowerRepository.ReencryptBorrower(ctx, borrower); err != nil {
+				return
+			}
+			reencrypted++
+			afterID = borrower.ID
+		}
+
+		if len(borrowers) < pds.Config.ReencryptBatchSize {
+			break
+		}
+	}
+
+	for afterID := model.NilUUID; ; {
+		var investors []*model.Investor
+		investors, err = pds.InvestorRepository.ListInvestorsToReencrypt(ctx, afterID, pds.Config.ReencryptBatchSize)
+		if err != nil {
+			return
+		}
+
+		for _, investor := range investors {
+			if err = pds.InvestorRepository.ReencryptInvestor(ctx, investor); err != nil {
+				return
+			}
+			reencrypted++
+			afterID = investor.ID
+		}
+
+		if len(investors) < pds.Config.ReencryptBatchSize {
+			break
+		}
+	}
+
+	return
+}
+
+// ExportPersonalData builds a zip archive of everything stored about the subject: the profile, the loans or
+// investments, the documents, the notifications and the erasure requests. Documents are listed with their
+// URL, the files themselves stay in the document store. The export is recorded in the audit log with its
+// manifest, no archive is handed out when it cannot be recorded.
+func (pds *PersonalDataService) ExportPersonalData(ctx context.Context, exportPersonalDataRequest *model.ExportPersonalDataRequest) (export *model.PersonalDataExport, err error) {
+	subjectType := exportPersonalDataRequest.SubjectType
+	subjectID := exportPersonalDataRequest.SubjectID
+
+	var (
+		files     = map[string]any{}
+		fileNames []string
+		documents = []*model.PersonalDataDocumentResponse{}
+	)
+	addFile := func(name string, content any) {
+		files[name] = content
+		fileNames = append(fileNames, name)
+	}
+
+	switch subjectType {
+	case model.RecipientTypeBorrower:
+		var borrower *model.Borrower
+		borrower, err = pds.BorrowerRepository.GetBorrowerByID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+		addFile("profile.json", model.ComposeBorrowerResponse(borrower))
+
+		var loans []*model.Loan
+		loans, err = pds.LoanRepository.ListLoansByBorrowerID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+
+		loanResponses := make([]*model.LoanResponse, 0, len(loans))
+		for _, loan := range loans {
+			loanResponses = append(loanResponses, model.ComposeLoanResponse(loan, nil))
+			documents = append(documents, model.ComposeLoanDocuments(loan)...)
+		}
+		addFile("loans.json", loanResponses)
+	case model.RecipientTypeInvestor:
+		var investor *model.Investor
+		investor, err = pds.InvestorRepository.GetInvestorByID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+		addFile("profile.json", model.ComposeInvestorResponse(investor))
+
+		var investments []*model.Investment
+		investments, err = pds.InvestmentRepository.ListInvestmentsByInvestorID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+
+		investmentResponses := make([]*model.InvestmentResponse, 0, len(investments))
+		for _, investment := range investments {
+			investmentResponses = append(investmentResponses, model.ComposeInvestmentResponse(investment))
+			documents = append(documents, model.ComposeInvestmentDocuments(investment)...)
+		}
+		addFile("investments.json", investmentResponses)
+	default:
+		err = model.ErrorRecipientTypeInvalid
+		return
+	}
+	addFile("documents.json", documents)
+
+	notifications, err := pds.exportNotifications(ctx, subjectType, subjectID)
+	if err != nil {
+		return
+	}
+	addFile("notifications.json", notifications)
+
+	erasureRequests, err := pds.PersonalDataRepository.ListErasureRequestsBySubject(ctx, subjectType, subjectID)
+	if err != nil {
+		return
+	}
+
+	erasureRequestResponses := make([]*model.ErasureRequestResponse, 0, len(erasureRequests))
+	for _, erasureRequest := range erasureRequests {
+		erasureRequestResponses = append(erasureRequestResponses, model.ComposeErasureRequestResponse(erasureRequest))
+	}
+	addFile("erasure_requests.json", erasureRequestResponses)
+
+	manifest := &model.PersonalDataManifestResponse{
+		SubjectType: string(subjectType),
+		SubjectID:   subjectID,
+		GeneratedAt: pds.Now().UTC(),
+		Files:       fileNames,
+	}
+
+	content, err := writePersonalDataArchive(manifest, files)
+	if err != nil {
+		return
+	}
+
+	auditEntity := model.AuditEntityBorrower
+	if subjectType == model.RecipientTypeInvestor {
+		auditEntity = model.AuditEntityInvestor
+	}
+
+	err = pds.TransactionManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return recordAudit(ctx, pds.AuditRepository, auditEntity, subjectID, model.AuditActionExport, nil, manifest)
+	})
+	if err != nil {
+		return
+	}
+
+	export = &model.PersonalDataExport{
+		FileName: fmt.Sprintf("personal-data-%s-%s.zip", subjectType, subjectID),
+		Content:  content,
+	}
+
+	return
+}
+
+func (pds *PersonalDataService) exportNotifications(ctx context.Context, subjectType model.RecipientType, subjectID string) (notificationsResponse *model.PersonalDataNotificationsResponse, err error) {
+	notificationsResponse = &model.PersonalDataNotificationsResponse{}
+
+	preference, err := pds.NotificationRepository.GetNotificationPreference(ctx, subjectType, subjectID)
+	switch err {
+	case nil:
+		notificationsResponse.Preference = model.ComposeNotificationPreferenceResponse(preference)
+	case model.ErrorNotificationPreferenceNotFound:
+		err = nil
+	default:
+		return
+	}
+
+	notifications, err := pds.NotificationRepository.ListEmailNotificationsByRecipient(ctx, subjectType, subjectID)
+	if err != nil {
+		return
+	}
+
+	notificationsResponse.Notifications = make([]*model.PersonalDataNotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		notificationsResponse.Notifications = append(notificationsResponse.Notifications, model.ComposePersonalDataNotificationResponse(notification))
+	}
+
+	return
+}
+
+// writePersonalDataArchive zips the manifest and the files as indented JSON, in the order of the manifest.
+func writePersonalDataArchive(manifest *model.PersonalDataManifestResponse, files map[string]any) (content []byte, err error) {
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+
+	writeFile := func(name string, value any) error {
+		valueBytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: manifest.GeneratedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(valueBytes)
+		return err
+	}
+
+	if err = writeFile("manifest.json", manifest); err != nil {
+		return
+	}
+
+	for _, name := range manifest.Files {
+		if err = writeFile(name, files[name]); err != nil {
+			return
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		return
+	}
+
+	content = buffer.Bytes()
+
+	return
+}
+
+// CreateErasureRequest records the request of the subject to erase its personal data, an admin reviews it.
+func (pds *PersonalDataService) CreateErasureRequest(ctx context.Context, createErasureRequestRequest *model.CreateErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error) {
+	erasedAt, err := pds.getSubjectErasedAt(ctx, createErasureRequestRequest.SubjectType, createErasureRequestRequest.SubjectID)
+	if err != nil {
+		return
+	}
+
+	if erasedAt != nil {
+		err = model.ErrorPersonalDataErased
+		return
+	}
+
+	erasureRequest := &model.PersonalDataErasureRequest{
+		SubjectType: createErasureRequestRequest.SubjectType,
+		SubjectID:   createErasureRequestRequest.SubjectID,
+		Status:      model.ErasureRequestStatusPending,
+		Reason:      createErasureRequestRequest.Reason,
+	}
+
+	err = pds.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = pds.PersonalDataRepository.CreateErasureRequest(ctx, erasureRequest)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, pds.AuditRepository, model.AuditEntityErasureRequest, erasureRequest.ID, model.AuditActionCreate, nil, model.ComposeErasureRequestResponse(erasureRequest))
+	})
+	if err != nil {
+		return
+	}
+
+	erasureRequestResponse = model.ComposeErasureRequestResponse(erasureRequest)
+
+	return
+}
+
+func (pds *PersonalDataService) getSubjectErasedAt(ctx context.Context, subjectType model.RecipientType, subjectID string) (erasedAt *time.Time, err error) {
+	switch subjectType {
+	case model.RecipientTypeBorrower:
+		var borrower *model.Borrower
+		borrower, err = pds.BorrowerRepository.GetBorrowerByID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+		erasedAt = borrower.ErasedAt
+	case model.RecipientTypeInvestor:
+		var investor *model.Investor
+		investor, err = pds.InvestorRepository.GetInvestorByID(ctx, subjectID)
+		if err != nil {
+			return
+		}
+		erasedAt = investor.ErasedAt
+	default:
+		err = model.ErrorRecipientTypeInvalid
+	}
+
+	return
+}
+
+func (pds *PersonalDataService) ListErasureRequests(ctx context.Context, listErasureRequestsRequest *model.ListErasureRequestsRequest) (erasureRequestResponses []*model.ErasureRequestResponse, err error) {
+	if listErasureRequestsRequest.Status != "" && !model.ValidErasureRequestStatus[listErasureRequestsRequest.Status] {
+		err = model.ErrorErasureRequestStatusInvalid
+		return
+	}
+
+	erasureRequests, err := pds.PersonalDataRepository.ListErasureRequests(ctx, listErasureRequestsRequest.Status)
+	if err != nil {
+		return
+	}
+
+	erasureRequestResponses = make([]*model.ErasureRequestResponse, 0, len(erasureRequests))
+	for _, erasureRequest := range erasureRequests {
+		erasureRequestResponses = append(erasureRequestResponses, model.ComposeErasureRequestResponse(erasureRequest))
+	}
+
+	return
+}
+
+// CompleteErasureRequest pseudonymizes the subject: its personal fields and blind indexes are cleared, its
+// emails redacted and its notification preference deleted, all in one transaction. The subject keeps its
+// id, so loans, investments, agreements and ledger records stay as financial regulation requires. Subjects
+// with running loans or active investments cannot be erased yet.
+func (pds *PersonalDataService) CompleteErasureRequest(ctx context.Context, completeErasureRequestRequest *model.CompleteErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error) {
+	var erasureRequest *model.PersonalDataErasureRequest
+
+	err = pds.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		erasureRequest, err = pds.PersonalDataRepository.GetErasureRequestByID(ctx, completeErasureRequestRequest.ID)
+		if err != nil {
+			return
+		}
+
+		if erasureRequest.Status != model.ErasureRequestStatusPending {
+			return model.ErrorErasureRequestNotPending
+		}
+		before := model.ComposeErasureRequestResponse(erasureRequest)
+
+		now := pds.Now()
+		subjectType := erasureRequest.SubjectType
+		subjectID := erasureRequest.SubjectID
+
+		switch subjectType {
+		case model.RecipientTypeBorrower:
+			err = pds.checkBorrowerErasable(ctx, subjectID)
+			if err != nil {
+				return
+			}
+
+			err = pds.BorrowerRepository.EraseBorrower(ctx, subjectID, now)
+			if err != nil {
+				return
+			}
+
+			err = recordAudit(ctx, pds.AuditRepository, model.AuditEntityBorrower, subjectID, model.AuditActionUpdate, nil, &model.BorrowerResponse{BorrowerID: subjectID, ErasedAt: &now})
+		case model.RecipientTypeInvestor:
+			err = pds.checkInvestorErasable(ctx, subjectID)
+			if err != nil {
+				return
+			}
+
+			err = pds.InvestorRepository.EraseInvestor(ctx, subjectID, now)
+			if err != nil {
+				return
+			}
+
+			err = recordAudit(ctx, pds.AuditRepository, model.AuditEntityInvestor, subjectID, model.AuditActionUpdate, nil, &model.InvestorResponse{InvestorID: subjectID, ErasedAt: &now})
+		}
+		if err != nil {
+			return
+		}
+
+		err = pds.NotificationRepository.RedactEmailNotifications(ctx, subjectType, subjectID)
+		if err != nil {
+			return
+		}
+
+		err = pds.NotificationRepository.DeleteNotificationPreference(ctx, subjectType, subjectID)
+		if err != nil {
+			return
+		}
+
+		erasureRequest.Status = model.ErasureRequestStatusCompleted
+		erasureRequest.ProcessedAt = &now
+		erasureRequest.ProcessedBy, _ = ctx.Value("userID").(string)
+		err = pds.PersonalDataRepository.UpdateErasureRequest(ctx, erasureRequest)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, pds.AuditRepository, model.AuditEntityErasureRequest, erasureRequest.ID, model.AuditActionUpdate, before, model.ComposeErasureRequestResponse(erasureRequest))
+	})
+	if err != nil {
+		return
+	}
+
+	erasureRequestResponse = model.ComposeErasureRequestResponse(erasureRequest)
+
+	return
+}
+
+func (pds *PersonalDataService) checkBorrowerErasable(ctx context.Context, borrowerID string) (err error) {
+	loans, err := pds.LoanRepository.ListLoansByBorrowerID(ctx, borrowerID)
+	if err != nil {
+		return
+	}
+
+	for _, loan := range loans {
+		if model.ErasureBlockingLoanStates[loan.State] {
+			return model.ErrorPersonalDataErasureBlocked
+		}
+	}
+
+	return
+}
+
+func (pds *PersonalDataService) checkInvestorErasable(ctx context.Context, investorID string) (err error) {
+	investments, err := pds.InvestmentRepository.ListInvestmentsByInvestorID(ctx, investorID)
+	if err != nil {
+		return
+	}
+
+	for _, investment := range investments {
+		if investment.Status == model.InvestmentStatusActive {
+			return model.ErrorPersonalDataErasureBlocked
+		}
+	}
+
+	return
+}
+
+func (pds *PersonalDataService) RejectErasureRequest(ctx context.Context, rejectErasureRequestRequest *model.RejectErasureRequestRequest) (erasureRequestResponse *model.ErasureRequestResponse, err error) {
+	var erasureRequest *model.PersonalDataErasureRequest
+
+	err = pds.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		erasureRequest, err = pds.PersonalDataRepository.GetErasureRequestByID(ctx, rejectErasureRequestRequest.ID)
+		if err != nil {
+			return
+		}
+
+		if erasureRequest.Status != model.ErasureRequestStatusPending {
+			return model.ErrorErasureRequestNotPending
+		}
+		before := model.ComposeErasureRequestResponse(erasureRequest)
+
+		now := pds.Now()
+		erasureRequest.Status = model.ErasureRequestStatusRejected
+		erasureRequest.RejectedReason = rejectErasureRequestRequest.Reason
+		erasureRequest.ProcessedAt = &now
+		erasureRequest.ProcessedBy, _ = ctx.Value("userID").(string)
+		err = pds.PersonalDataRepository.UpdateErasureRequest(ctx, erasureRequest)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, pds.AuditRepository, model.AuditEntityErasureRequest, erasureRequest.ID, model.AuditActionUpdate, before, model.ComposeErasureRequestResponse(erasureRequest))
+	})
+	if err != nil {
+		return
+	}
+
+	erasureRequestResponse = model.ComposeErasureRequestResponse(erasureRequest)
+
+	return
+}
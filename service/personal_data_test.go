@@ -0,0 +1,477 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+func testKeyfile(activeKeyID string, keyIDs ...string) []byte {
+	keys := []string{}
+	for i, keyID := range keyIDs {
+		keys = append(keys, `"`+keyID+`": "`+base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune('a'+i)), 32)))+`"`)
+	}
+
+	return []byte(`{
+		"active_key_id": "` + activeKeyID + `",
+		"keys": {` + strings.Join(keys, ",") + `},
+		"blind_index_key": "` + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("z", 32))) + `"
+	}`)
+}
+
+var _ = Describe("FieldCipher", func() {
+	var fieldCipher *application.FieldCipher
+
+	BeforeEach(func() {
+		var err error
+		fieldCipher, err = application.NewFieldCipher(testKeyfile("2025-01", "2025-01"))
+		Expect(err).To(BeNil())
+	})
+
+	It("should encrypt every value with its own data key under the active key", func() {
+		first, err := fieldCipher.Encrypt("nik", "3171000000000101")
+		Expect(err).To(BeNil())
+		second, err := fieldCipher.Encrypt("nik", "3171000000000101")
+		Expect(err).To(BeNil())
+
+		Expect(first).To(HavePrefix("v1:2025-01:"))
+		Expect(first).NotTo(ContainSubstring("3171000000000101"))
+		Expect(first).NotTo(Equal(second))
+
+		plaintext, err := fieldCipher.Decrypt("nik", first)
+		Expect(err).To(BeNil())
+		Expect(plaintext).To(Equal("3171000000000101"))
+	})
+
+	It("should keep empty values empty", func() {
+		ciphertext, err := fieldCipher.Encrypt("phone_number", "")
+		Expect(err).To(BeNil())
+		Expect(ciphertext).To(BeEmpty())
+		Expect(fieldCipher.BlindIndex("email", " ")).To(BeEmpty())
+	})
+
+	It("should decrypt values of a rotated key and encrypt with the new one", func() {
+		ciphertext, err := fieldCipher.Encrypt("email", "budi@example.com")
+		Expect(err).To(BeNil())
+
+		rotated, err := application.NewFieldCipher(testKeyfile("2025-06", "2025-01", "2025-06"))
+		Expect(err).To(BeNil())
+
+		plaintext, err := rotated.Decrypt("email", ciphertext)
+		Expect(err).To(BeNil())
+		Expect(plaintext).To(Equal("budi@example.com"))
+
+		reencrypted, err := rotated.Encrypt("email", plaintext)
+		Expect(err).To(BeNil())
+		Expect(reencrypted).To(HavePrefix("v1:2025-06:"))
+	})
+
+	It("should reject a value of another column, a tampered value and an unknown key", func() {
+		ciphertext, err := fieldCipher.Encrypt("nik", "3171000000000101")
+		Expect(err).To(BeNil())
+
+		_, err = fieldCipher.Decrypt("npwp", ciphertext)
+		Expect(err).To(MatchError(application.ErrCiphertextInvalid))
+
+		tampered := ciphertext[:len(ciphertext)-2] + "AA"
+		if tampered == ciphertext {
+			tampered = ciphertext[:len(ciphertext)-2] + "BB"
+		}
+		_, err = fieldCipher.Decrypt("nik", tampered)
+		Expect(err).To(MatchError(application.ErrCiphertextInvalid))
+
+		_, err = fieldCipher.Decrypt("nik", strings.Replace(ciphertext, "2025-01", "2024-01", 1))
+		Expect(errors.Is(err, application.ErrEncryptionKeyNotFound)).To(BeTrue())
+	})
+
+	It("should index values regardless of case and surrounding spaces, per column", func() {
+		Expect(fieldCipher.BlindIndex("email", " Budi@Example.com ")).To(Equal(fieldCipher.BlindIndex("email", "budi@example.com")))
+		Expect(fieldCipher.BlindIndex("email", "budi@example.com")).To(HaveLen(64))
+		Expect(fieldCipher.BlindIndex("nik", "3171000000000101")).NotTo(Equal(fieldCipher.BlindIndex("npwp", "3171000000000101")))
+	})
+
+	It("should reject a keyfile without its active key or with short keys", func() {
+		_, err := application.NewFieldCipher(testKeyfile("2025-06", "2025-01"))
+		Expect(err).To(MatchError(ContainSubstring(`active key "2025-06"`)))
+
+		_, err = application.NewFieldCipher([]byte(`{"active_key_id": "k", "keys": {"k": "c2hvcnQ="}}`))
+		Expect(err).To(MatchError(ContainSubstring("must be 32 base64 encoded bytes")))
+	})
+})
+
+var _ = Describe("PersonalDataService", func() {
+	var (
+		mockCtrl             *gomock.Controller
+		mockBorrowerRepo     *mock.MockIBorrowerRepository
+		mockInvestorRepo     *mock.MockIInvestorRepository
+		mockLoanRepo         *mock.MockILoanRepository
+		mockInvestmentRepo   *mock.MockIInvestmentRepository
+		mockNotificationRepo *mock.MockINotificationRepository
+		mockPersonalDataRepo *mock.MockIPersonalDataRepository
+		mockAuditRepo        *mock.MockIAuditRepository
+		personalDataSvc      *service.PersonalDataService
+		now                  time.Time
+		expectAudit          func(entityType string, action model.AuditAction) *gomock.Call
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockBorrowerRepo = mock.NewMockIBorrowerRepository(mockCtrl)
+		mockInvestorRepo = mock.NewMockIInvestorRepository(mockCtrl)
+		mockLoanRepo = mock.NewMockILoanRepository(mockCtrl)
+		mockInvestmentRepo = mock.NewMockIInvestmentRepository(mockCtrl)
+		mockNotificationRepo = mock.NewMockINotificationRepository(mockCtrl)
+		mockPersonalDataRepo = mock.NewMockIPersonalDataRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		now = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		personalDataSvc = &service.PersonalDataService{
+			BorrowerRepository:     mockBorrowerRepo,
+			InvestorRepository:     mockInvestorRepo,
+			LoanRepository:         mockLoanRepo,
+			InvestmentRepository:   mockInvestmentRepo,
+			NotificationRepository: mockNotificationRepo,
+			PersonalDataRepository: mockPersonalDataRepo,
+			TransactionManager:     &mock.MockTransactionManager{},
+			AuditRepository:        mockAuditRepo,
+			Config:                 configuration.Encryption{ReencryptBatchSize: 2},
+			Now:                    func() time.Time { return now },
+		}
+
+		expectAudit = func(entityType string, action model.AuditAction) *gomock.Call {
+			return mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(entityType))
+					Expect(auditLog.Action).To(Equal(action))
+					return nil
+				})
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("ReencryptPersonalData", func() {
+		It("should re-encrypt borrowers and investors batch by batch", func() {
+			ctx := context.Background()
+			borrowers := []*model.Borrower{{ID: "borrower-1"}, {ID: "borrower-2"}, {ID: "borrower-3"}}
+			investor := &model.Investor{ID: "investor-1"}
+
+			gomock.InOrder(
+				mockBorrowerRepo.EXPECT().ListBorrowersToReencrypt(ctx, model.NilUUID, 2).Return(borrowers[:2], nil),
+				mockBorrowerRepo.EXPECT().ReencryptBorrower(ctx, borrowers[0]).Return(nil),
+				mockBorrowerRepo.EXPECT().ReencryptBorrower(ctx, borrowers[1]).Return(nil),
+				mockBorrowerRepo.EXPECT().ListBorrowersToReencrypt(ctx, "borrower-2", 2).Return(borrowers[2:], nil),
+				mockBorrowerRepo.EXPECT().ReencryptBorrower(ctx, borrowers[2]).Return(nil),
+				mockInvestorRepo.EXPECT().ListInvestorsToReencrypt(ctx, model.NilUUID, 2).Return([]*model.Investor{investor}, nil),
+				mockInvestorRepo.EXPECT().ReencryptInvestor(ctx, investor).Return(nil),
+			)
+
+			reencrypted, err := personalDataSvc.ReencryptPersonalData(ctx)
+			Expect(err).To(BeNil())
+			Expect(reencrypted).To(Equal(4))
+		})
+
+		It("should stop at the first row that fails and keep the rows written before", func() {
+			ctx := context.Background()
+			borrowers := []*model.Borrower{{ID: "borrower-1"}, {ID: "borrower-2"}}
+
+			mockBorrowerRepo.EXPECT().ListBorrowersToReencrypt(ctx, model.NilUUID, 2).Return(borrowers, nil)
+			mockBorrowerRepo.EXPECT().ReencryptBorrower(ctx, borrowers[0]).Return(nil)
+			mockBorrowerRepo.EXPECT().ReencryptBorrower(ctx, borrowers[1]).Return(errors.New("db down"))
+
+			reencrypted, err := personalDataSvc.ReencryptPersonalData(ctx)
+			Expect(err).To(MatchError("db down"))
+			Expect(reencrypted).To(Equal(1))
+		})
+	})
+
+	Context("ExportPersonalData", func() {
+		It("should zip the profile, loans, documents, notifications and erasure requests of a borrower", func() {
+			ctx := application.WithClientIP(application.WithRequestID(context.WithValue(context.Background(), "userID", "employee-1"), "request-1"), "10.0.0.1")
+			borrowerID := "7d1b5f3e-4a43-4b8e-9d0a-0c2f7b1e6a11"
+			signedAt := now.AddDate(0, -1, 0)
+
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, borrowerID).Return(&model.Borrower{ID: borrowerID, Name: "Budi", NIK: "3171000000000101", Email: "budi@example.com"}, nil)
+			mockLoanRepo.EXPECT().ListLoansByBorrowerID(ctx, borrowerID).Return([]*model.Loan{
+				{ID: "loan-1", BorrowerID: borrowerID, State: model.LoanStateDisbursed, VisitProofURL: "https://docs.example.com/visit.jpg", LoanAgreementLetterURL: "https://docs.example.com/agreement.pdf", LoanAggrementSignedAt: &signedAt},
+				{ID: "loan-2", BorrowerID: borrowerID, State: model.LoanStateRejected},
+			}, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeBorrower, borrowerID).Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().ListEmailNotificationsByRecipient(ctx, model.RecipientTypeBorrower, borrowerID).Return([]*model.EmailNotification{
+				{ID: "notification-1", Event: model.NotificationEventLoanDisbursed, Email: "budi@example.com", Subject: "Pinjaman dicairkan", Status: model.EmailNotificationStatusSent},
+			}, nil)
+			mockPersonalDataRepo.EXPECT().ListErasureRequestsBySubject(ctx, model.RecipientTypeBorrower, borrowerID).Return([]*model.PersonalDataErasureRequest{}, nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityBorrower))
+					Expect(auditLog.EntityID).To(Equal(borrowerID))
+					Expect(auditLog.Action).To(Equal(model.AuditActionExport))
+					Expect(auditLog.ActorID).To(Equal("employee-1"))
+					Expect(auditLog.SourceIP).To(Equal("10.0.0.1"))
+					Expect(auditLog.RequestID).To(Equal("request-1"))
+					Expect(string(auditLog.Changes)).To(ContainSubstring(`"files":{"after":["profile.json","loans.json"`))
+					Expect(string(auditLog.Changes)).NotTo(ContainSubstring("3171000000000101"))
+					return nil
+				})
+
+			export, err := personalDataSvc.ExportPersonalData(ctx, &model.ExportPersonalDataRequest{
+				SubjectType: model.RecipientTypeBorrower,
+				SubjectID:   borrowerID,
+			})
+			Expect(err).To(BeNil())
+			Expect(export.FileName).To(Equal("personal-data-borrower-" + borrowerID + ".zip"))
+
+			archive, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+			Expect(err).To(BeNil())
+
+			files := map[string][]byte{}
+			for _, file := range archive.File {
+				reader, err := file.Open()
+				Expect(err).To(BeNil())
+				files[file.Name], err = io.ReadAll(reader)
+				Expect(err).To(BeNil())
+			}
+			Expect(files).To(HaveLen(6))
+
+			manifest := model.PersonalDataManifestResponse{}
+			Expect(json.Unmarshal(files["manifest.json"], &manifest)).To(Succeed())
+			Expect(manifest.Files).To(Equal([]string{"profile.json", "loans.json", "documents.json", "notifications.json", "erasure_requests.json"}))
+			Expect(manifest.GeneratedAt).To(Equal(now))
+
+			profile := model.BorrowerResponse{}
+			Expect(json.Unmarshal(files["profile.json"], &profile)).To(Succeed())
+			Expect(profile.NIK).To(Equal("3171000000000101"))
+
+			loans := []*model.LoanResponse{}
+			Expect(json.Unmarshal(files["loans.json"], &loans)).To(Succeed())
+			Expect(loans).To(HaveLen(2))
+
+			documents := []*model.PersonalDataDocumentResponse{}
+			Expect(json.Unmarshal(files["documents.json"], &documents)).To(Succeed())
+			Expect(documents).To(HaveLen(2))
+			Expect(documents[0].Type).To(Equal(model.PersonalDataDocumentVisitProof))
+			Expect(documents[1].Type).To(Equal(model.PersonalDataDocumentLoanAgreement))
+			Expect(documents[1].SignedAt).NotTo(BeNil())
+
+			notifications := model.PersonalDataNotificationsResponse{}
+			Expect(json.Unmarshal(files["notifications.json"], &notifications)).To(Succeed())
+			Expect(notifications.Preference).To(BeNil())
+			Expect(notifications.Notifications).To(HaveLen(1))
+		})
+
+		It("should export the investments and agreements of an investor", func() {
+			ctx := context.Background()
+			investorID := "5b0c1e2d-8f3a-4c6b-a7d9-2e4f6a8b0c13"
+
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, investorID).Return(&model.Investor{ID: investorID, NPWP: "012345678901000"}, nil)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByInvestorID(ctx, investorID).Return([]*model.Investment{
+				{ID: "investment-1", LoanID: "loan-1", InvestorID: investorID, InvestmentAgreementLetterURL: "https://docs.example.com/investment.pdf"},
+			}, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeInvestor, investorID).Return(&model.NotificationPreference{RecipientType: model.RecipientTypeInvestor, RecipientID: investorID, Language: model.LanguageEnglish}, nil)
+			mockNotificationRepo.EXPECT().ListEmailNotificationsByRecipient(ctx, model.RecipientTypeInvestor, investorID).Return([]*model.EmailNotification{}, nil)
+			mockPersonalDataRepo.EXPECT().ListErasureRequestsBySubject(ctx, model.RecipientTypeInvestor, investorID).Return([]*model.PersonalDataErasureRequest{}, nil)
+			expectAudit(model.AuditEntityInvestor, model.AuditActionExport)
+
+			export, err := personalDataSvc.ExportPersonalData(ctx, &model.ExportPersonalDataRequest{
+				SubjectType: model.RecipientTypeInvestor,
+				SubjectID:   investorID,
+			})
+			Expect(err).To(BeNil())
+
+			archive, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, file := range archive.File {
+				names = append(names, file.Name)
+			}
+			Expect(names).To(Equal([]string{"manifest.json", "profile.json", "investments.json", "documents.json", "notifications.json", "erasure_requests.json"}))
+		})
+
+		It("should not hand out the archive when the export cannot be audited", func() {
+			ctx := context.Background()
+			investorID := "5b0c1e2d-8f3a-4c6b-a7d9-2e4f6a8b0c13"
+
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, investorID).Return(&model.Investor{ID: investorID}, nil)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByInvestorID(ctx, investorID).Return([]*model.Investment{}, nil)
+			mockNotificationRepo.EXPECT().GetNotificationPreference(ctx, model.RecipientTypeInvestor, investorID).Return(nil, model.ErrorNotificationPreferenceNotFound)
+			mockNotificationRepo.EXPECT().ListEmailNotificationsByRecipient(ctx, model.RecipientTypeInvestor, investorID).Return([]*model.EmailNotification{}, nil)
+			mockPersonalDataRepo.EXPECT().ListErasureRequestsBySubject(ctx, model.RecipientTypeInvestor, investorID).Return([]*model.PersonalDataErasureRequest{}, nil)
+			mockAuditRepo.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+
+			export, err := personalDataSvc.ExportPersonalData(ctx, &model.ExportPersonalDataRequest{
+				SubjectType: model.RecipientTypeInvestor,
+				SubjectID:   investorID,
+			})
+			Expect(err).To(MatchError("db down"))
+			Expect(export).To(BeNil())
+		})
+
+		It("should return the error of an unknown subject", func() {
+			ctx := context.Background()
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "missing").Return(nil, model.ErrorBorrowerNotFound)
+
+			_, err := personalDataSvc.ExportPersonalData(ctx, &model.ExportPersonalDataRequest{
+				SubjectType: model.RecipientTypeBorrower,
+				SubjectID:   "missing",
+			})
+			Expect(err).To(MatchError(model.ErrorBorrowerNotFound))
+		})
+	})
+
+	Context("CreateErasureRequest", func() {
+		It("should record a pending request", func() {
+			ctx := context.Background()
+			mockInvestorRepo.EXPECT().GetInvestorByID(ctx, "investor-1").Return(&model.Investor{ID: "investor-1"}, nil)
+			mockPersonalDataRepo.EXPECT().CreateErasureRequest(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, erasureRequest *model.PersonalDataErasureRequest) error {
+					Expect(erasureRequest.Status).To(Equal(model.ErasureRequestStatusPending))
+					Expect(erasureRequest.Reason).To(Equal("closing my account"))
+					erasureRequest.ID = "request-1"
+					return nil
+				})
+			expectAudit(model.AuditEntityErasureRequest, model.AuditActionCreate)
+
+			resp, err := personalDataSvc.CreateErasureRequest(ctx, &model.CreateErasureRequestRequest{
+				SubjectType: model.RecipientTypeInvestor,
+				SubjectID:   "investor-1",
+				Reason:      "closing my account",
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.ErasureRequestID).To(Equal("request-1"))
+			Expect(resp.Status).To(Equal(model.ErasureRequestStatusPending))
+		})
+
+		It("should refuse a subject that is erased already", func() {
+			ctx := context.Background()
+			mockBorrowerRepo.EXPECT().GetBorrowerByID(ctx, "borrower-1").Return(&model.Borrower{ID: "borrower-1", ErasedAt: &now}, nil)
+
+			_, err := personalDataSvc.CreateErasureRequest(ctx, &model.CreateErasureRequestRequest{
+				SubjectType: model.RecipientTypeBorrower,
+				SubjectID:   "borrower-1",
+			})
+			Expect(err).To(MatchError(model.ErrorPersonalDataErased))
+		})
+	})
+
+	Context("ListErasureRequests", func() {
+		It("should reject an unknown status", func() {
+			_, err := personalDataSvc.ListErasureRequests(context.Background(), &model.ListErasureRequestsRequest{Status: "done"})
+			Expect(err).To(MatchError(model.ErrorErasureRequestStatusInvalid))
+		})
+	})
+
+	Context("CompleteErasureRequest", func() {
+		It("should erase the borrower, redact its emails and complete the request", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+			erasureRequest := &model.PersonalDataErasureRequest{ID: "request-1", SubjectType: model.RecipientTypeBorrower, SubjectID: "borrower-1", Status: model.ErasureRequestStatusPending}
+
+			gomock.InOrder(
+				mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-1").Return(erasureRequest, nil),
+				mockLoanRepo.EXPECT().ListLoansByBorrowerID(ctx, "borrower-1").Return([]*model.Loan{{ID: "loan-1", State: model.LoanStateCanceled}}, nil),
+				mockBorrowerRepo.EXPECT().EraseBorrower(ctx, "borrower-1", now).Return(nil),
+				mockAuditRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+						Expect(auditLog.EntityType).To(Equal(model.AuditEntityBorrower))
+						Expect(auditLog.EntityID).To(Equal("borrower-1"))
+						Expect(string(auditLog.Changes)).To(ContainSubstring("erased_at"))
+						return nil
+					}),
+				mockNotificationRepo.EXPECT().RedactEmailNotifications(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil),
+				mockNotificationRepo.EXPECT().DeleteNotificationPreference(ctx, model.RecipientTypeBorrower, "borrower-1").Return(nil),
+				mockPersonalDataRepo.EXPECT().UpdateErasureRequest(ctx, erasureRequest).Return(nil),
+				expectAudit(model.AuditEntityErasureRequest, model.AuditActionUpdate),
+			)
+
+			resp, err := personalDataSvc.CompleteErasureRequest(ctx, &model.CompleteErasureRequestRequest{ID: "request-1"})
+			Expect(err).To(BeNil())
+			Expect(resp.Status).To(Equal(model.ErasureRequestStatusCompleted))
+			Expect(resp.ProcessedBy).To(Equal("admin-1"))
+			Expect(*resp.ProcessedAt).To(Equal(now))
+		})
+
+		It("should erase the investor once its investments are released", func() {
+			ctx := context.Background()
+			erasureRequest := &model.PersonalDataErasureRequest{ID: "request-2", SubjectType: model.RecipientTypeInvestor, SubjectID: "investor-1", Status: model.ErasureRequestStatusPending}
+
+			mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-2").Return(erasureRequest, nil)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByInvestorID(ctx, "investor-1").Return([]*model.Investment{{ID: "investment-1", Status: model.InvestmentStatusReleased}}, nil)
+			mockInvestorRepo.EXPECT().EraseInvestor(ctx, "investor-1", now).Return(nil)
+			expectAudit(model.AuditEntityInvestor, model.AuditActionUpdate)
+			mockNotificationRepo.EXPECT().RedactEmailNotifications(ctx, model.RecipientTypeInvestor, "investor-1").Return(nil)
+			mockNotificationRepo.EXPECT().DeleteNotificationPreference(ctx, model.RecipientTypeInvestor, "investor-1").Return(nil)
+			mockPersonalDataRepo.EXPECT().UpdateErasureRequest(ctx, erasureRequest).Return(nil)
+			expectAudit(model.AuditEntityErasureRequest, model.AuditActionUpdate)
+
+			resp, err := personalDataSvc.CompleteErasureRequest(ctx, &model.CompleteErasureRequestRequest{ID: "request-2"})
+			Expect(err).To(BeNil())
+			Expect(resp.Status).To(Equal(model.ErasureRequestStatusCompleted))
+		})
+
+		It("should not erase a borrower with a running loan", func() {
+			ctx := context.Background()
+			erasureRequest := &model.PersonalDataErasureRequest{ID: "request-1", SubjectType: model.RecipientTypeBorrower, SubjectID: "borrower-1", Status: model.ErasureRequestStatusPending}
+
+			mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-1").Return(erasureRequest, nil)
+			mockLoanRepo.EXPECT().ListLoansByBorrowerID(ctx, "borrower-1").Return([]*model.Loan{{ID: "loan-1", State: model.LoanStateDisbursed}}, nil)
+
+			_, err := personalDataSvc.CompleteErasureRequest(ctx, &model.CompleteErasureRequestRequest{ID: "request-1"})
+			Expect(err).To(MatchError(model.ErrorPersonalDataErasureBlocked))
+		})
+
+		It("should not erase an investor with an active investment", func() {
+			ctx := context.Background()
+			erasureRequest := &model.PersonalDataErasureRequest{ID: "request-2", SubjectType: model.RecipientTypeInvestor, SubjectID: "investor-1", Status: model.ErasureRequestStatusPending}
+
+			mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-2").Return(erasureRequest, nil)
+			mockInvestmentRepo.EXPECT().ListInvestmentsByInvestorID(ctx, "investor-1").Return([]*model.Investment{{ID: "investment-1", Status: model.InvestmentStatusActive}}, nil)
+
+			_, err := personalDataSvc.CompleteErasureRequest(ctx, &model.CompleteErasureRequestRequest{ID: "request-2"})
+			Expect(err).To(MatchError(model.ErrorPersonalDataErasureBlocked))
+		})
+
+		It("should not process a request twice", func() {
+			ctx := context.Background()
+			mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-1").Return(&model.PersonalDataErasureRequest{ID: "request-1", Status: model.ErasureRequestStatusRejected}, nil)
+
+			_, err := personalDataSvc.CompleteErasureRequest(ctx, &model.CompleteErasureRequestRequest{ID: "request-1"})
+			Expect(err).To(MatchError(model.ErrorErasureRequestNotPending))
+		})
+	})
+
+	Context("RejectErasureRequest", func() {
+		It("should record the reason and keep the data", func() {
+			ctx := context.Background()
+			erasureRequest := &model.PersonalDataErasureRequest{ID: "request-1", SubjectType: model.RecipientTypeBorrower, SubjectID: "borrower-1", Status: model.ErasureRequestStatusPending}
+
+			mockPersonalDataRepo.EXPECT().GetErasureRequestByID(ctx, "request-1").Return(erasureRequest, nil)
+			mockPersonalDataRepo.EXPECT().UpdateErasureRequest(ctx, erasureRequest).Return(nil)
+			expectAudit(model.AuditEntityErasureRequest, model.AuditActionUpdate)
+
+			resp, err := personalDataSvc.RejectErasureRequest(ctx, &model.RejectErasureRequestRequest{ID: "request-1", Reason: "loan still being repaid"})
+			Expect(err).To(BeNil())
+			Expect(resp.Status).To(Equal(model.ErasureRequestStatusRejected))
+			Expect(resp.RejectedReason).To(Equal("loan still being repaid"))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+)
+
+// rateLimitSweepInterval is how often the buckets of idle clients are dropped.
+const rateLimitSweepInterval = time.Minute
+
+type IRateLimitService interface {
+	Allow(client string, route string) (allowed bool, retryAfter time.Duration)
+}
+
+// RateLimitService gives every client a token bucket per route, kept in memory so every instance limits
+// the requests it serves.
+type RateLimitService struct {
+	Default    configuration.RouteRate
+	RouteRates map[string]configuration.RouteRate
+	Now        func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	rate      configuration.RouteRate
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewRateLimitService(app *application.App) IRateLimitService {
+	// the routes are checked when the configuration is loaded
+	routeRates, _ := app.Config.RateLimit.RouteRates()
+
+	return &RateLimitService{
+		Default:    app.Config.RateLimit.Default(),
+		RouteRates: routeRates,
+		Now:        time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of the client for the route, the route is the method and chi route
+// pattern. A request without a token is allowed again after retryAfter.
+func (rls *RateLimitService) Allow(client string, route string) (allowed bool, retryAfter time.Duration) {
+	rls.mu.Lock()
+	defer rls.mu.Unlock()
+
+	now := rls.Now()
+	rls.sweep(now)
+
+	key := client + " " + route
+	bucket, ok := rls.buckets[key]
+	if !ok {
+		rate, ok := rls.RouteRates[route]
+		if !ok {
+			rate = rls.Default
+		}
+
+		bucket = &tokenBucket{rate: rate, tokens: float64(rate.Burst), updatedAt: now}
+		rls.buckets[key] = bucket
+	}
+
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	retryAfter = time.Duration(math.Ceil((1 - bucket.tokens) / bucket.rate.RequestsPerSecond * float64(time.Second)))
+
+	return false, retryAfter
+}
+
+// sweep drops the buckets refilled to their burst, a new bucket starts full so nothing is lost.
+func (rls *RateLimitService) sweep(now time.Time) {
+	if rls.buckets == nil {
+		rls.buckets = map[string]*tokenBucket{}
+		rls.lastSweep = now
+		return
+	}
+
+	if now.Sub(rls.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+
+	for key, bucket := range rls.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.rate.Burst) {
+			delete(rls.buckets, key)
+		}
+	}
+	rls.lastSweep = now
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.updatedAt).Seconds()
+	if elapsed > 0 {
+		tb.tokens = min(float64(tb.rate.Burst), tb.tokens+elapsed*tb.rate.RequestsPerSecond)
+		tb.updatedAt = now
+	}
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/service"
+)
+
+var _ = Describe("RateLimitService", func() {
+	var (
+		rateLimitSvc *service.RateLimitService
+		now          time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		rateLimitSvc = &service.RateLimitService{
+			Default: configuration.RouteRate{RequestsPerSecond: 10, Burst: 20},
+			RouteRates: map[string]configuration.RouteRate{
+				"POST /v1/loans": {RequestsPerSecond: 0.5, Burst: 2},
+			},
+			Now: func() time.Time { return now },
+		}
+	})
+
+	Context("Allow", func() {
+		It("should allow the burst and then limit until a token is refilled", func() {
+			for range 2 {
+				allowed, _ := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+				Expect(allowed).To(BeTrue())
+			}
+
+			allowed, retryAfter := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+			Expect(allowed).To(BeFalse())
+			Expect(retryAfter).To(Equal(2 * time.Second))
+
+			now = now.Add(time.Second)
+			allowed, retryAfter = rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+			Expect(allowed).To(BeFalse())
+			Expect(retryAfter).To(Equal(time.Second))
+
+			now = now.Add(time.Second)
+			allowed, _ = rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("should limit every client and route on its own", func() {
+			for range 2 {
+				allowed, _ := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+				Expect(allowed).To(BeTrue())
+			}
+
+			allowed, _ := rateLimitSvc.Allow("10.0.0.2", "POST /v1/loans")
+			Expect(allowed).To(BeTrue())
+
+			allowed, _ = rateLimitSvc.Allow("10.0.0.1", "GET /v1/loans/{id}")
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("should use the default rate for routes without an override", func() {
+			for range 20 {
+				allowed, _ := rateLimitSvc.Allow("10.0.0.1", "GET /v1/loans/{id}")
+				Expect(allowed).To(BeTrue())
+			}
+
+			allowed, retryAfter := rateLimitSvc.Allow("10.0.0.1", "GET /v1/loans/{id}")
+			Expect(allowed).To(BeFalse())
+			Expect(retryAfter).To(Equal(100 * time.Millisecond))
+		})
+
+		It("should start idle clients with a full bucket", func() {
+			for range 2 {
+				allowed, _ := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+				Expect(allowed).To(BeTrue())
+			}
+
+			now = now.Add(time.Hour)
+			for range 2 {
+				allowed, _ := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+				Expect(allowed).To(BeTrue())
+			}
+
+			allowed, _ := rateLimitSvc.Allow("10.0.0.1", "POST /v1/loans")
+			Expect(allowed).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+)
+
+// IRiskScorer assesses the credit risk of a loan proposal. Implementations can be swapped on LoanService.
+type IRiskScorer interface {
+	Score(ctx context.Context, input *model.RiskScoringInput) (riskAssessment *model.RiskAssessment, err error)
+}
+
+type ScorecardRiskScorer struct {
+	Scorecard *model.Scorecard
+	Now       func() time.Time
+}
+
+// NewRiskScorer scores with the scorecard loaded by application.SetupApp, which fails to start on a scorecard
+// that does not load, or the built-in scorecard when the app has none.
+func NewRiskScorer(app *application.App) IRiskScorer {
+	scorecard := app.Scorecard
+	if scorecard == nil {
+		scorecard = model.DefaultScorecard()
+	}
+
+	return &ScorecardRiskScorer{
+		Scorecard: scorecard,
+		Now:       time.Now,
+	}
+}
+
+// NewCandidateRiskScorer scores with the scorecard dark launched behind the scoring.candidate_scorecard
+// flag, it is nil when no candidate scorecard is configured.
+func NewCandidateRiskScorer(app *application.App) IRiskScorer {
+	if app.CandidateScorecard == nil {
+		return nil
+	}
+
+	return &ScorecardRiskScorer{
+		Scorecard: app.CandidateScorecard,
+		Now:       time.Now,
+	}
+}
+
+func (srs *ScorecardRiskScorer) Score(ctx context.Context, input *model.RiskScoringInput) (riskAssessment *model.RiskAssessment, err error) {
+	scorecard := srs.Scorecard
+	now := srs.Now()
+
+	score := scorecard.BaseScore
+	explanation := []model.RiskFactor{
+		{Factor: "base_score", Value: scorecard.Version, Points: scorecard.BaseScore},
+	}
+	addFactor := func(factor string, value string, points int) {
+		score += points
+		explanation = append(explanation, model.RiskFactor{Factor: factor, Value: value, Points: points})
+	}
+
+	// borrower attributes
+	if input.Borrower.DOB != nil {
+		age := ageAt(*input.Borrower.DOB, now)
+		addFactor("age", fmt.Sprintf("%d", age), model.BandPoints(scorecard.AgeBands, float64(age)))
+	}
+
+	occupation := strings.ToLower(strings.TrimSpace(input.Borrower.Occupation))
+	occupationPoints, ok := scorecard.Occupations[occupation]
+	if !ok {
+		occupationPoints = scorecard.DefaultOccupationPoints
+	}
+	addFactor("occupation", occupation, occupationPoints)
+
+	// loan history
+	stats := input.LoanStats
+	disbursedPoints := stats.DisbursedLoans * scorecard.DisbursedLoanPoints
+	if scorecard.MaxDisbursedLoanPoints > 0 && disbursedPoints > scorecard.MaxDisbursedLoanPoints {
+		disbursedPoints = scorecard.MaxDisbursedLoanPoints
+	}
+	addFactor("disbursed_loans", fmt.Sprintf("%d", stats.DisbursedLoans), disbursedPoints)
+	addFactor("active_loans", fmt.Sprintf("%d", stats.ActiveLoans), stats.ActiveLoans*scorecard.ActiveLoanPoints)
+	addFactor("rejected_loans", fmt.Sprintf("%d", stats.RejectedLoans), stats.RejectedLoans*scorecard.RejectedLoanPoints)
+	addFactor("canceled_loans", fmt.Sprintf("%d", stats.CanceledLoans), stats.CanceledLoans*scorecard.CanceledLoanPoints)
+
+	// requested amount relative to the product ceiling
+	if input.Product != nil && input.Product.MaxPrincipalAmount > 0 {
+		ratio := input.PrincipalAmount / input.Product.MaxPrincipalAmount * 100
+		addFactor("principal_ratio", fmt.Sprintf("%.2f%%", ratio), model.BandPoints(scorecard.PrincipalRatioBands, ratio))
+	}
+
+	grade := srs.grade(score)
+	minRate, maxRate := grade.MinInterestRate, grade.MaxInterestRate
+	if input.Product != nil {
+		// keep the suggestion inside what the product allows
+		minRate = math.Min(math.Max(minRate, input.Product.MinInterestRate), input.Product.MaxInterestRate)
+		maxRate = math.Max(math.Min(maxRate, input.Product.MaxInterestRate), minRate)
+	}
+
+	riskAssessment = &model.RiskAssessment{
+		Score:                    score,
+		Grade:                    grade.Grade,
+		Explanation:              explanation,
+		ScorecardVersion:         scorecard.Version,
+		SuggestedMinInterestRate: minRate,
+		SuggestedMaxInterestRate: maxRate,
+		AssessedAt:               &now,
+	}
+
+	return
+}
+
+// grade returns the band with the highest cut-off the score reaches, or the lowest band otherwise.
+func (srs *ScorecardRiskScorer) grade(score int) model.ScorecardGradeBand {
+	var best, lowest *model.ScorecardGradeBand
+	for i := range srs.Scorecard.Grades {
+		band := &srs.Scorecard.Grades[i]
+		if lowest == nil || band.MinScore < lowest.MinScore {
+			lowest = band
+		}
+		if score >= band.MinScore && (best == nil || band.MinScore > best.MinScore) {
+			best = band
+		}
+	}
+
+	if best == nil {
+		return *lowest
+	}
+
+	return *best
+}
+
+func ageAt(dob time.Time, now time.Time) int {
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+
+	return age
+}
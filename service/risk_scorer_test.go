@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+)
+
+var _ = Describe("ScorecardRiskScorer", func() {
+	var (
+		now     time.Time
+		product *model.LoanProduct
+		scorer  service.IRiskScorer
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		product = &model.LoanProduct{
+			MinPrincipalAmount: 1000000,
+			MaxPrincipalAmount: 100000000,
+			MinInterestRate:    12,
+			MaxInterestRate:    24,
+		}
+		scorer = &service.ScorecardRiskScorer{
+			Scorecard: model.DefaultScorecard(),
+			Now:       func() time.Time { return now },
+		}
+	})
+
+	It("should grade a good borrower as A with an explained score", func() {
+		dob := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+		input := &model.RiskScoringInput{
+			Borrower:        &model.Borrower{DOB: &dob, Occupation: "PNS"},
+			LoanStats:       &model.BorrowerLoanStats{DisbursedLoans: 2},
+			Product:         product,
+			PrincipalAmount: 10000000,
+		}
+
+		risk, err := scorer.Score(context.Background(), input)
+		Expect(err).To(BeNil())
+		// 500 base + 40 age + 60 occupation + 80 history + 40 principal ratio
+		Expect(risk.Score).To(Equal(720))
+		Expect(risk.Grade).To(Equal(model.RiskGradeA))
+		Expect(risk.ScorecardVersion).To(Equal("default-v1"))
+		Expect(risk.Explanation).To(ContainElement(model.RiskFactor{Factor: "occupation", Value: "pns", Points: 60}))
+		// grade A band 10-13 is clamped to the product minimum of 12
+		Expect(risk.SuggestedMinInterestRate).To(Equal(12.0))
+		Expect(risk.SuggestedMaxInterestRate).To(Equal(13.0))
+	})
+
+	It("should grade a borrower with rejections and a large request as E", func() {
+		dob := time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC)
+		input := &model.RiskScoringInput{
+			Borrower:        &model.Borrower{DOB: &dob, Occupation: "unknown"},
+			LoanStats:       &model.BorrowerLoanStats{RejectedLoans: 2, ActiveLoans: 1},
+			Product:         product,
+			PrincipalAmount: 90000000,
+		}
+
+		risk, err := scorer.Score(context.Background(), input)
+		Expect(err).To(BeNil())
+		// 500 base - 50 age + 0 occupation - 30 active - 120 rejected - 40 principal ratio
+		Expect(risk.Score).To(Equal(260))
+		Expect(risk.Grade).To(Equal(model.RiskGradeE))
+		Expect(risk.SuggestedMinInterestRate).To(Equal(22.0))
+		Expect(risk.SuggestedMaxInterestRate).To(Equal(24.0))
+	})
+
+	It("should load a scorecard from file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "scorecard.json")
+		content := `{"version": "custom-v2", "base_score": 100, "grades": [{"grade": "B", "min_score": 0, "min_interest_rate": 14, "max_interest_rate": 15}]}`
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+
+		scorecard, err := model.LoadScorecard(path)
+		Expect(err).To(BeNil())
+		Expect(scorecard.Version).To(Equal("custom-v2"))
+		Expect(scorecard.Grades).To(HaveLen(1))
+	})
+
+	It("should reject a scorecard without grades", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "scorecard.json")
+		Expect(os.WriteFile(path, []byte(`{"version": "broken"}`), 0o600)).To(Succeed())
+
+		scorecard, err := model.LoadScorecard(path)
+		Expect(err).To(Equal(model.ErrorScorecardInvalid))
+		Expect(scorecard).To(BeNil())
+	})
+})
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+type ISeedService interface {
+	SeedEmployee(ctx context.Context, employee *model.Employee) (ID string, err error)
+	SeedBorrower(ctx context.Context, borrower *model.Borrower) (ID string, err error)
+	SeedInvestor(ctx context.Context, investor *model.Investor) (ID string, err error)
+}
+
+// SeedService creates the fixed records of loanctl seed once, every record it creates is audited under the
+// operator like the ones created through the API.
+type SeedService struct {
+	EmployeeRepository repository.IEmployeeRepository
+	BorrowerRepository repository.IBorrowerRepository
+	InvestorRepository repository.IInvestorRepository
+	TransactionManager repository.ITransactionManager
+	AuditRepository    repository.IAuditRepository
+}
+
+func NewSeedService(app *application.App) ISeedService {
+	return &SeedService{
+		EmployeeRepository: repository.NewEmployeeRepository(app),
+		BorrowerRepository: repository.NewBorrowerRepository(app),
+		InvestorRepository: repository.NewInvestorRepository(app),
+		TransactionManager: repository.NewTransactionManager(app),
+		AuditRepository:    repository.NewAuditRepository(app),
+	}
+}
+
+// SeedEmployee creates the employee unless one with the same employee number exists, and returns its id.
+func (ss *SeedService) SeedEmployee(ctx context.Context, employee *model.Employee) (ID string, err error) {
+	employees, err := ss.EmployeeRepository.ListEmployees(ctx, false)
+	if err != nil {
+		return
+	}
+	for _, existing := range employees {
+		if existing.EmployeeNumber == employee.EmployeeNumber {
+			return existing.ID, nil
+		}
+	}
+
+	err = ss.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		ID, err = ss.EmployeeRepository.CreateEmployee(ctx, employee)
+		if err != nil {
+			return
+		}
+
+		created, err := ss.EmployeeRepository.GetEmployeeByID(ctx, ID)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ss.AuditRepository, model.AuditEntityEmployee, ID, model.AuditActionCreate, nil, model.ComposeEmployeeResponse(created))
+	})
+	if err != nil {
+		ID = ""
+	}
+
+	return
+}
+
+// SeedBorrower creates the borrower unless its NIK is registered, and returns its id. Only the id is audited,
+// the audit log is append-only and personal data in it could not be erased.
+func (ss *SeedService) SeedBorrower(ctx context.Context, borrower *model.Borrower) (ID string, err error) {
+	existing, err := ss.BorrowerRepository.GetBorrowerByNIK(ctx, borrower.NIK)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if err != model.ErrorBorrowerNotFound {
+		return
+	}
+
+	err = ss.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		ID, err = ss.BorrowerRepository.CreateBorrower(ctx, borrower)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ss.AuditRepository, model.AuditEntityBorrower, ID, model.AuditActionCreate, nil, &model.BorrowerResponse{BorrowerID: ID})
+	})
+	if err != nil {
+		ID = ""
+	}
+
+	return
+}
+
+// SeedInvestor creates the investor unless its NIK is registered, and returns its id. Only the id is audited
+// for the same reason as SeedBorrower.
+func (ss *SeedService) SeedInvestor(ctx context.Context, investor *model.Investor) (ID string, err error) {
+	existing, err := ss.InvestorRepository.GetInvestorByNIK(ctx, investor.NIK)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if err != model.ErrorInvestorNotFound {
+		return
+	}
+
+	err = ss.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		ID, err = ss.InvestorRepository.CreateInvestor(ctx, investor)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ss.AuditRepository, model.AuditEntityInvestor, ID, model.AuditActionCreate, nil, &model.InvestorResponse{InvestorID: ID})
+	})
+	if err != nil {
+		ID = ""
+	}
+
+	return
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("SeedService", func() {
+	var (
+		mockCtrl         *gomock.Controller
+		mockEmployeeRepo *mock.MockIEmployeeRepository
+		mockBorrowerRepo *mock.MockIBorrowerRepository
+		mockInvestorRepo *mock.MockIInvestorRepository
+		mockAuditRepo    *mock.MockIAuditRepository
+		seedSvc          service.ISeedService
+		ctx              context.Context
+		expectAudit      func(entityType string, entityID string) *gomock.Call
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEmployeeRepo = mock.NewMockIEmployeeRepository(mockCtrl)
+		mockBorrowerRepo = mock.NewMockIBorrowerRepository(mockCtrl)
+		mockInvestorRepo = mock.NewMockIInvestorRepository(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		ctx = context.WithValue(context.Background(), "userID", "employee-1")
+
+		seedSvc = &service.SeedService{
+			EmployeeRepository: mockEmployeeRepo,
+			BorrowerRepository: mockBorrowerRepo,
+			InvestorRepository: mockInvestorRepo,
+			TransactionManager: &mock.MockTransactionManager{},
+			AuditRepository:    mockAuditRepo,
+		}
+
+		expectAudit = func(entityType string, entityID string) *gomock.Call {
+			return mockAuditRepo.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(entityType))
+					Expect(auditLog.EntityID).To(Equal(entityID))
+					Expect(auditLog.Action).To(Equal(model.AuditActionCreate))
+					Expect(auditLog.ActorID).To(Equal("employee-1"))
+					return nil
+				})
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("SeedEmployee", func() {
+		It("should create and audit a missing employee", func() {
+			employee := &model.Employee{Name: "Field Officer Seed", EmployeeNumber: "SEED-002"}
+
+			mockEmployeeRepo.EXPECT().ListEmployees(ctx, false).Return([]*model.Employee{{ID: "emp-0", EmployeeNumber: "SEED-001"}}, nil)
+			mockEmployeeRepo.EXPECT().CreateEmployee(ctx, employee).Return("emp-2", nil)
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(ctx, "emp-2").Return(&model.Employee{ID: "emp-2", Name: "Field Officer Seed", EmployeeNumber: "SEED-002", IsActive: true}, nil)
+			expectAudit(model.AuditEntityEmployee, "emp-2")
+
+			id, err := seedSvc.SeedEmployee(ctx, employee)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("emp-2"))
+		})
+
+		It("should return the existing employee with the same employee number", func() {
+			mockEmployeeRepo.EXPECT().ListEmployees(ctx, false).Return([]*model.Employee{{ID: "emp-2", EmployeeNumber: "SEED-002"}}, nil)
+
+			id, err := seedSvc.SeedEmployee(ctx, &model.Employee{Name: "Field Officer Seed", EmployeeNumber: "SEED-002"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("emp-2"))
+		})
+
+		It("should return error if the audit log cannot be written", func() {
+			mockEmployeeRepo.EXPECT().ListEmployees(ctx, false).Return(nil, nil)
+			mockEmployeeRepo.EXPECT().CreateEmployee(ctx, gomock.Any()).Return("emp-2", nil)
+			mockEmployeeRepo.EXPECT().GetEmployeeByID(ctx, "emp-2").Return(&model.Employee{ID: "emp-2"}, nil)
+			mockAuditRepo.EXPECT().CreateAuditLog(ctx, gomock.Any()).Return(errors.New("db down"))
+
+			id, err := seedSvc.SeedEmployee(ctx, &model.Employee{Name: "Field Officer Seed", EmployeeNumber: "SEED-002"})
+			Expect(err).To(MatchError("db down"))
+			Expect(id).To(BeEmpty())
+		})
+	})
+
+	Context("SeedBorrower", func() {
+		It("should create a missing borrower and audit only its id", func() {
+			borrower := &model.Borrower{ID: "borrower-1", Name: "Budi Seed", NIK: "3171000000000101", Email: "budi.seed@example.com"}
+
+			mockBorrowerRepo.EXPECT().GetBorrowerByNIK(ctx, borrower.NIK).Return(nil, model.ErrorBorrowerNotFound)
+			mockBorrowerRepo.EXPECT().CreateBorrower(ctx, borrower).Return("borrower-1", nil)
+			expectAudit(model.AuditEntityBorrower, "borrower-1").
+				Do(func(_ context.Context, auditLog *model.AuditLog) {
+					Expect(string(auditLog.Changes)).NotTo(ContainSubstring(borrower.NIK))
+					Expect(string(auditLog.Changes)).NotTo(ContainSubstring(borrower.Email))
+				})
+
+			id, err := seedSvc.SeedBorrower(ctx, borrower)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("borrower-1"))
+		})
+
+		It("should return the borrower already registered with the NIK", func() {
+			mockBorrowerRepo.EXPECT().GetBorrowerByNIK(ctx, "3171000000000101").Return(&model.Borrower{ID: "borrower-0"}, nil)
+
+			id, err := seedSvc.SeedBorrower(ctx, &model.Borrower{NIK: "3171000000000101"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("borrower-0"))
+		})
+
+		It("should return error if the borrower cannot be looked up", func() {
+			mockBorrowerRepo.EXPECT().GetBorrowerByNIK(ctx, "3171000000000101").Return(nil, errors.New("db down"))
+
+			_, err := seedSvc.SeedBorrower(ctx, &model.Borrower{NIK: "3171000000000101"})
+			Expect(err).To(MatchError("db down"))
+		})
+	})
+
+	Context("SeedInvestor", func() {
+		It("should create a missing investor and audit only its id", func() {
+			investor := &model.Investor{ID: "inv-1", Name: "Andi Seed", NIK: "3171000000000201", NPWP: "0000000000000201"}
+
+			mockInvestorRepo.EXPECT().GetInvestorByNIK(ctx, investor.NIK).Return(nil, model.ErrorInvestorNotFound)
+			mockInvestorRepo.EXPECT().CreateInvestor(ctx, investor).Return("inv-1", nil)
+			expectAudit(model.AuditEntityInvestor, "inv-1").
+				Do(func(_ context.Context, auditLog *model.AuditLog) {
+					Expect(string(auditLog.Changes)).NotTo(ContainSubstring(investor.NIK))
+				})
+
+			id, err := seedSvc.SeedInvestor(ctx, investor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("inv-1"))
+		})
+
+		It("should return the investor already registered with the NIK", func() {
+			mockInvestorRepo.EXPECT().GetInvestorByNIK(ctx, "3171000000000201").Return(&model.Investor{ID: "inv-0"}, nil)
+
+			id, err := seedSvc.SeedInvestor(ctx, &model.Investor{NIK: "3171000000000201"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("inv-0"))
+		})
+	})
+})
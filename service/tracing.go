@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/frencius/loan-service/model"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/frencius/loan-service/service")
+
+// tracedTransport starts a client span per outgoing request and sends the W3C trace context headers.
+var tracedTransport = otelhttp.NewTransport(http.DefaultTransport)
+
+// startSpan starts the span of a service method, end it with endSpan and the error the method returns.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+// endSpan records err on the span. Only internal errors mark the span as failed, a rejected request
+// such as an invalid state transition is an expected outcome.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+
+		domainErr := &model.DomainError{}
+		if !errors.As(err, &domainErr) || domainErr.Status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// traceParent returns the W3C traceparent of the span in ctx, empty when there is none.
+func traceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	return carrier.Get("traceparent")
+}
+
+// withTraceParent continues the trace of a stored traceparent, ctx is returned as is when it is empty.
+func withTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/golang/mock/gomock"
+)
+
+// the global tracer provider can only be installed once, every spec of the suite records into it
+var spanRecorder = func() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	return recorder
+}()
+
+var _ = Describe("Tracing", func() {
+	var (
+		mockCtrl     *gomock.Controller
+		mockLoanRepo *mock.MockILoanRepository
+		loanSvc      service.ILoanService
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockLoanRepo = mock.NewMockILoanRepository(mockCtrl)
+		loanSvc = &service.LoanService{LoanRepository: mockLoanRepo}
+		spanRecorder.Reset()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	loanSpan := func(name string) sdktrace.ReadOnlySpan {
+		for _, span := range spanRecorder.Ended() {
+			if span.Name() == name {
+				return span
+			}
+		}
+
+		return nil
+	}
+
+	It("should pass the span of the service method to the repositories", func() {
+		mockLoanRepo.EXPECT().GetLoanByID(gomock.Any(), "loan-1").
+			DoAndReturn(func(ctx context.Context, _ string) (*model.Loan, error) {
+				ctx, span := otel.Tracer("test").Start(ctx, "GetLoanByID")
+				span.End()
+				return nil, model.ErrorLoanNotFound
+			})
+
+		_, err := loanSvc.GetLoan(context.Background(), &model.GetLoanRequest{LoanID: "loan-1"})
+		Expect(err).To(MatchError(model.ErrorLoanNotFound))
+
+		serviceSpan := loanSpan("LoanService.GetLoan")
+		Expect(serviceSpan).NotTo(BeNil())
+		repositorySpan := loanSpan("GetLoanByID")
+		Expect(repositorySpan).NotTo(BeNil())
+		Expect(repositorySpan.Parent().SpanID()).To(Equal(serviceSpan.SpanContext().SpanID()))
+	})
+
+	It("should not fail the span on a client error", func() {
+		mockLoanRepo.EXPECT().GetLoanByID(gomock.Any(), "loan-1").Return(nil, model.ErrorLoanNotFound)
+
+		_, _ = loanSvc.GetLoan(context.Background(), &model.GetLoanRequest{LoanID: "loan-1"})
+
+		serviceSpan := loanSpan("LoanService.GetLoan")
+		Expect(serviceSpan.Status().Code).To(Equal(codes.Unset))
+		Expect(serviceSpan.Events()).To(HaveLen(1))
+	})
+
+	It("should fail the span on an internal error", func() {
+		mockLoanRepo.EXPECT().GetLoanByID(gomock.Any(), "loan-1").Return(nil, errors.New("connection refused"))
+
+		_, _ = loanSvc.GetLoan(context.Background(), &model.GetLoanRequest{LoanID: "loan-1"})
+
+		serviceSpan := loanSpan("LoanService.GetLoan")
+		Expect(serviceSpan.Status().Code).To(Equal(codes.Error))
+		Expect(serviceSpan.Status().Description).To(Equal("connection refused"))
+	})
+})
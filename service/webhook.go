@@ -0,0 +1,481 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/frencius/loan-service/application"
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/repository"
+)
+
+const defaultListWebhookDeliveriesLimit = 100
+
+type IWebhookService interface {
+	CreateWebhookSubscription(ctx context.Context, createWebhookSubscriptionRequest *model.CreateWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error)
+	GetWebhookSubscription(ctx context.Context, getWebhookSubscriptionRequest *model.GetWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error)
+	ListWebhookSubscriptions(ctx context.Context) (webhookSubscriptionResponses []*model.WebhookSubscriptionResponse, err error)
+	UpdateWebhookSubscription(ctx context.Context, updateWebhookSubscriptionRequest *model.UpdateWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error)
+	DeleteWebhookSubscription(ctx context.Context, deleteWebhookSubscriptionRequest *model.DeleteWebhookSubscriptionRequest) (err error)
+	ListWebhookDeliveries(ctx context.Context, listWebhookDeliveriesRequest *model.ListWebhookDeliveriesRequest) (webhookDeliveryResponses []*model.WebhookDeliveryResponse, err error)
+	GetWebhookDelivery(ctx context.Context, getWebhookDeliveryRequest *model.GetWebhookDeliveryRequest) (webhookDeliveryResponse *model.WebhookDeliveryResponse, err error)
+	RedeliverWebhook(ctx context.Context, redeliverWebhookRequest *model.RedeliverWebhookRequest) (webhookDeliveryResponse *model.WebhookDeliveryResponse, err error)
+	FanOutEvent(ctx context.Context, envelope *model.EventEnvelope) (err error)
+	DeliverWebhook(ctx context.Context, deliveryID string) (err error)
+}
+
+type WebhookService struct {
+	WebhookRepository  repository.IWebhookRepository
+	TransactionManager repository.ITransactionManager
+	AuditRepository    repository.IAuditRepository
+	JobService         IJobService
+	Client             *http.Client
+	Config             configuration.Webhook
+	Now                func() time.Time
+}
+
+func NewWebhookService(app *application.App) IWebhookService {
+	return &WebhookService{
+		WebhookRepository:  repository.NewWebhookRepository(app),
+		TransactionManager: repository.NewTransactionManager(app),
+		AuditRepository:    repository.NewAuditRepository(app),
+		JobService:         NewJobService(app),
+		Client:             &http.Client{Timeout: app.Config.Webhook.Timeout, Transport: tracedTransport},
+		Config:             app.Config.Webhook,
+		Now:                time.Now,
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret.
+// Partners recompute it from the X-Webhook-Timestamp header and the raw body, and should reject old timestamps.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateWebhookSecret() (secret string, err error) {
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return
+	}
+
+	secret = "whsec_" + hex.EncodeToString(key)
+
+	return
+}
+
+func validateWebhookEventTypes(eventTypes []string) (err error) {
+	for _, eventType := range eventTypes {
+		if !model.ValidEventTypes[model.EventType(eventType)] {
+			err = model.ErrorWebhookEventTypeInvalid
+			return
+		}
+	}
+
+	return
+}
+
+func (ws *WebhookService) CreateWebhookSubscription(ctx context.Context, createWebhookSubscriptionRequest *model.CreateWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error) {
+	err = validateWebhookEventTypes(createWebhookSubscriptionRequest.EventTypes)
+	if err != nil {
+		return
+	}
+
+	secret := createWebhookSubscriptionRequest.Secret
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			slog.ErrorContext(ctx, "CreateWebhookSubscription generateWebhookSecret error", "error", err)
+			return
+		}
+	}
+
+	subscription := &model.WebhookSubscription{
+		URL:         createWebhookSubscriptionRequest.URL,
+		Description: createWebhookSubscriptionRequest.Description,
+		EventTypes:  createWebhookSubscriptionRequest.EventTypes,
+		Secret:      secret,
+		IsActive:    true,
+		CreatedBy:   ctx.Value("userID").(string),
+	}
+
+	err = ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		subscription.ID, err = ws.WebhookRepository.CreateWebhookSubscription(ctx, subscription)
+		if err != nil {
+			return
+		}
+
+		// the response leaves the secret out, so does the audit log
+		return recordAudit(ctx, ws.AuditRepository, model.AuditEntityWebhookSubscription, subscription.ID, model.AuditActionCreate, nil, model.ComposeWebhookSubscriptionResponse(subscription))
+	})
+	if err != nil {
+		return
+	}
+
+	// the secret is only shown once
+	webhookSubscriptionResponse = model.ComposeWebhookSubscriptionResponse(subscription)
+	webhookSubscriptionResponse.Secret = secret
+
+	return
+}
+
+func (ws *WebhookService) GetWebhookSubscription(ctx context.Context, getWebhookSubscriptionRequest *model.GetWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error) {
+	subscription, err := ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, getWebhookSubscriptionRequest.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	webhookSubscriptionResponse = model.ComposeWebhookSubscriptionResponse(subscription)
+
+	return
+}
+
+func (ws *WebhookService) ListWebhookSubscriptions(ctx context.Context) (webhookSubscriptionResponses []*model.WebhookSubscriptionResponse, err error) {
+	subscriptions, err := ws.WebhookRepository.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return
+	}
+
+	webhookSubscriptionResponses = make([]*model.WebhookSubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		webhookSubscriptionResponses = append(webhookSubscriptionResponses, model.ComposeWebhookSubscriptionResponse(subscription))
+	}
+
+	return
+}
+
+// UpdateWebhookSubscription re-enabling a disabled subscription clears its failure streak.
+func (ws *WebhookService) UpdateWebhookSubscription(ctx context.Context, updateWebhookSubscriptionRequest *model.UpdateWebhookSubscriptionRequest) (webhookSubscriptionResponse *model.WebhookSubscriptionResponse, err error) {
+	err = validateWebhookEventTypes(updateWebhookSubscriptionRequest.EventTypes)
+	if err != nil {
+		return
+	}
+
+	subscription, err := ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, updateWebhookSubscriptionRequest.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	before := model.ComposeWebhookSubscriptionResponse(subscription)
+
+	switch {
+	case updateWebhookSubscriptionRequest.IsActive && !subscription.IsActive:
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledAt = nil
+		subscription.DisabledReason = ""
+	case !updateWebhookSubscriptionRequest.IsActive && subscription.IsActive:
+		now := ws.Now()
+		subscription.DisabledAt = &now
+		subscription.DisabledReason = "disabled by user"
+	}
+
+	subscription.URL = updateWebhookSubscriptionRequest.URL
+	subscription.Description = updateWebhookSubscriptionRequest.Description
+	subscription.EventTypes = updateWebhookSubscriptionRequest.EventTypes
+	subscription.IsActive = updateWebhookSubscriptionRequest.IsActive
+
+	err = ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = ws.WebhookRepository.UpdateWebhookSubscription(ctx, subscription)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ws.AuditRepository, model.AuditEntityWebhookSubscription, subscription.ID, model.AuditActionUpdate, before, model.ComposeWebhookSubscriptionResponse(subscription))
+	})
+	if err != nil {
+		return
+	}
+
+	webhookSubscriptionResponse = model.ComposeWebhookSubscriptionResponse(subscription)
+
+	return
+}
+
+func (ws *WebhookService) DeleteWebhookSubscription(ctx context.Context, deleteWebhookSubscriptionRequest *model.DeleteWebhookSubscriptionRequest) (err error) {
+	subscription, err := ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, deleteWebhookSubscriptionRequest.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	return ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = ws.WebhookRepository.DeleteWebhookSubscription(ctx, subscription.ID)
+		if err != nil {
+			return
+		}
+
+		return recordAudit(ctx, ws.AuditRepository, model.AuditEntityWebhookSubscription, subscription.ID, model.AuditActionDelete, model.ComposeWebhookSubscriptionResponse(subscription), nil)
+	})
+}
+
+func (ws *WebhookService) ListWebhookDeliveries(ctx context.Context, listWebhookDeliveriesRequest *model.ListWebhookDeliveriesRequest) (webhookDeliveryResponses []*model.WebhookDeliveryResponse, err error) {
+	_, err = ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, listWebhookDeliveriesRequest.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	limit := listWebhookDeliveriesRequest.Limit
+	if limit <= 0 || limit > defaultListWebhookDeliveriesLimit {
+		limit = defaultListWebhookDeliveriesLimit
+	}
+
+	deliveries, err := ws.WebhookRepository.ListWebhookDeliveries(ctx, listWebhookDeliveriesRequest.SubscriptionID, limit)
+	if err != nil {
+		return
+	}
+
+	webhookDeliveryResponses = make([]*model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookDeliveryResponses = append(webhookDeliveryResponses, model.ComposeWebhookDeliveryResponse(delivery, nil))
+	}
+
+	return
+}
+
+func (ws *WebhookService) GetWebhookDelivery(ctx context.Context, getWebhookDeliveryRequest *model.GetWebhookDeliveryRequest) (webhookDeliveryResponse *model.WebhookDeliveryResponse, err error) {
+	delivery, err := ws.getSubscriptionDelivery(ctx, getWebhookDeliveryRequest.SubscriptionID, getWebhookDeliveryRequest.DeliveryID)
+	if err != nil {
+		return
+	}
+
+	attempts, err := ws.WebhookRepository.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		return
+	}
+
+	webhookDeliveryResponse = model.ComposeWebhookDeliveryResponse(delivery, attempts)
+
+	return
+}
+
+// RedeliverWebhook queues a delivery again regardless of its status, the attempt log is kept.
+func (ws *WebhookService) RedeliverWebhook(ctx context.Context, redeliverWebhookRequest *model.RedeliverWebhookRequest) (webhookDeliveryResponse *model.WebhookDeliveryResponse, err error) {
+	subscription, err := ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, redeliverWebhookRequest.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	if !subscription.IsActive {
+		err = model.ErrorWebhookSubscriptionInactive
+		return
+	}
+
+	delivery, err := ws.getSubscriptionDelivery(ctx, subscription.ID, redeliverWebhookRequest.DeliveryID)
+	if err != nil {
+		return
+	}
+
+	now := ws.Now()
+	delivery.Status = model.WebhookDeliveryStatusPending
+	delivery.NextAttemptAt = &now
+
+	err = ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = ws.WebhookRepository.UpdateWebhookDelivery(ctx, delivery)
+		if err != nil {
+			return
+		}
+
+		_, err = ws.JobService.EnqueueJob(ctx, model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: delivery.ID}, nil)
+
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	webhookDeliveryResponse = model.ComposeWebhookDeliveryResponse(delivery, nil)
+
+	return
+}
+
+func (ws *WebhookService) getSubscriptionDelivery(ctx context.Context, subscriptionID string, deliveryID string) (delivery *model.WebhookDelivery, err error) {
+	delivery, err = ws.WebhookRepository.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return
+	}
+
+	if delivery.SubscriptionID != subscriptionID {
+		delivery = nil
+		err = model.ErrorWebhookDeliveryNotFound
+		return
+	}
+
+	return
+}
+
+// FanOutEvent creates one delivery per active subscription of the event type and queues it.
+// The event relay may hand over the same event again, deliveries that already exist are skipped.
+func (ws *WebhookService) FanOutEvent(ctx context.Context, envelope *model.EventEnvelope) (err error) {
+	subscriptions, err := ws.WebhookRepository.ListActiveWebhookSubscriptionsByEventType(ctx, envelope.Type)
+	if err != nil {
+		return
+	}
+
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		slog.ErrorContext(ctx, "FanOutEvent Marshal error", "error", err)
+		return
+	}
+
+	now := ws.Now()
+	for _, subscription := range subscriptions {
+		err = ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+			deliveryID, err := ws.WebhookRepository.CreateWebhookDelivery(ctx, &model.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        envelope.EventID,
+				EventType:      envelope.Type,
+				Payload:        payload,
+				NextAttemptAt:  &now,
+			})
+			if err != nil {
+				if err == model.ErrorWebhookDeliveryExist {
+					err = nil
+				}
+				return
+			}
+
+			_, err = ws.JobService.EnqueueJob(ctx, model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: deliveryID}, nil)
+
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// DeliverWebhook makes one delivery attempt and records it. Failed attempts are retried with exponential
+// backoff until WEBHOOK_MAX_ATTEMPTS, and the subscription is disabled after WEBHOOK_DISABLE_AFTER_FAILURES
+// consecutive failures. Only storage errors are returned, so the job queue retries those.
+func (ws *WebhookService) DeliverWebhook(ctx context.Context, deliveryID string) (err error) {
+	ctx, span := startSpan(ctx, "WebhookService.DeliverWebhook")
+	defer func() { endSpan(span, err) }()
+
+	delivery, err := ws.WebhookRepository.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return
+	}
+
+	// a stale job of an already rescheduled delivery
+	if delivery.Status != model.WebhookDeliveryStatusPending || (delivery.NextAttemptAt != nil && ws.Now().Before(*delivery.NextAttemptAt)) {
+		return
+	}
+
+	subscription, err := ws.WebhookRepository.GetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return
+	}
+
+	if !subscription.IsActive {
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		delivery.LastError = model.ErrorWebhookSubscriptionInactive.Error()
+		delivery.NextAttemptAt = nil
+		return ws.WebhookRepository.UpdateWebhookDelivery(ctx, delivery)
+	}
+
+	startedAt := ws.Now()
+	statusCode, sendErr := ws.send(ctx, subscription, delivery)
+	attemptedAt := ws.Now()
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	}
+
+	err = ws.WebhookRepository.CreateWebhookDeliveryAttempt(ctx, &model.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		Error:      delivery.LastError,
+		DurationMS: attemptedAt.Sub(startedAt).Milliseconds(),
+	})
+	if err != nil {
+		return
+	}
+
+	subscription, err = ws.WebhookRepository.RecordWebhookSubscriptionResult(ctx, subscription.ID, sendErr == nil, ws.Config.DisableAfterFailures)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = model.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = &attemptedAt
+		delivery.NextAttemptAt = nil
+	case !subscription.IsActive || delivery.Attempts >= ws.Config.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+	default:
+		nextAttemptAt := attemptedAt.Add(exponentialBackoff(delivery.Attempts, ws.Config.BackoffBase, ws.Config.BackoffMax))
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	return ws.TransactionManager.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		err = ws.WebhookRepository.UpdateWebhookDelivery(ctx, delivery)
+		if err != nil {
+			return
+		}
+
+		if delivery.Status != model.WebhookDeliveryStatusPending {
+			return
+		}
+
+		_, err = ws.JobService.EnqueueJob(ctx, model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: delivery.ID}, delivery.NextAttemptAt)
+
+		return
+	})
+}
+
+func (ws *WebhookService) send(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (statusCode int, err error) {
+	timestamp := ws.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.WebhookHeaderDeliveryID, delivery.ID)
+	req.Header.Set(model.WebhookHeaderEvent, string(delivery.EventType))
+	req.Header.Set(model.WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(model.WebhookHeaderSignature, "v1="+SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := ws.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	statusCode = resp.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		err = fmt.Errorf("endpoint responded with status %d", statusCode)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,288 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frencius/loan-service/configuration"
+	"github.com/frencius/loan-service/mock"
+	"github.com/frencius/loan-service/model"
+	"github.com/frencius/loan-service/service"
+
+	"github.com/golang/mock/gomock"
+)
+
+var _ = Describe("WebhookService", func() {
+	var (
+		mockCtrl        *gomock.Controller
+		mockWebhookRepo *mock.MockIWebhookRepository
+		mockJobSvc      *mock.MockIJobService
+		mockAuditRepo   *mock.MockIAuditRepository
+		webhookSvc      service.IWebhookService
+		server          *httptest.Server
+		statusCode      int
+		received        *http.Request
+		receivedBody    []byte
+		now             time.Time
+		subscription    *model.WebhookSubscription
+		delivery        *model.WebhookDelivery
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockWebhookRepo = mock.NewMockIWebhookRepository(mockCtrl)
+		mockJobSvc = mock.NewMockIJobService(mockCtrl)
+		mockAuditRepo = mock.NewMockIAuditRepository(mockCtrl)
+		now = time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+		statusCode = http.StatusOK
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			receivedBody = make([]byte, r.ContentLength)
+			_, _ = r.Body.Read(receivedBody)
+			w.WriteHeader(statusCode)
+		}))
+
+		webhookSvc = &service.WebhookService{
+			WebhookRepository:  mockWebhookRepo,
+			TransactionManager: &mock.MockTransactionManager{},
+			AuditRepository:    mockAuditRepo,
+			JobService:         mockJobSvc,
+			Client:             server.Client(),
+			Config: configuration.Webhook{
+				MaxAttempts:          3,
+				BackoffBase:          30 * time.Second,
+				BackoffMax:           time.Hour,
+				DisableAfterFailures: 20,
+			},
+			Now: func() time.Time { return now },
+		}
+
+		subscription = &model.WebhookSubscription{
+			ID:         "sub-1",
+			URL:        server.URL,
+			EventTypes: []string{string(model.EventTypeLoanApproved)},
+			Secret:     "whsec_test_secret",
+			IsActive:   true,
+		}
+		delivery = &model.WebhookDelivery{
+			ID:             "delivery-1",
+			SubscriptionID: "sub-1",
+			EventID:        "event-1",
+			EventType:      model.EventTypeLoanApproved,
+			Payload:        json.RawMessage(`{"event_id":"event-1"}`),
+			Status:         model.WebhookDeliveryStatusPending,
+			NextAttemptAt:  &now,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		mockCtrl.Finish()
+	})
+
+	Context("CreateWebhookSubscription", func() {
+		It("should reject unknown event types", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			resp, err := webhookSvc.CreateWebhookSubscription(ctx, &model.CreateWebhookSubscriptionRequest{
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: []string{"loan.unknown"},
+			})
+			Expect(err).To(Equal(model.ErrorWebhookEventTypeInvalid))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should generate a secret and return it once", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockWebhookRepo.EXPECT().CreateWebhookSubscription(derivedContext(ctx), gomock.Any()).Return("sub-1", nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityType).To(Equal(model.AuditEntityWebhookSubscription))
+					Expect(auditLog.Action).To(Equal(model.AuditActionCreate))
+					Expect(string(auditLog.Changes)).NotTo(ContainSubstring("whsec_"))
+					return nil
+				})
+
+			resp, err := webhookSvc.CreateWebhookSubscription(ctx, &model.CreateWebhookSubscriptionRequest{
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: []string{string(model.EventTypeLoanDisbursed)},
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.SubscriptionID).To(Equal("sub-1"))
+			Expect(resp.Secret).To(HavePrefix("whsec_"))
+		})
+	})
+
+	Context("DeleteWebhookSubscription", func() {
+		It("should audit the deleted subscription", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().DeleteWebhookSubscription(derivedContext(ctx), "sub-1").Return(nil)
+			mockAuditRepo.EXPECT().
+				CreateAuditLog(derivedContext(ctx), gomock.Any()).
+				DoAndReturn(func(_ context.Context, auditLog *model.AuditLog) error {
+					Expect(auditLog.EntityID).To(Equal("sub-1"))
+					Expect(auditLog.Action).To(Equal(model.AuditActionDelete))
+					changes := map[string]model.AuditChange{}
+					Expect(json.Unmarshal(auditLog.Changes, &changes)).To(Succeed())
+					Expect(changes["url"].Before).To(MatchJSON(strconv.Quote(server.URL)))
+					Expect(changes["url"].After).To(BeNil())
+					return nil
+				})
+
+			err := webhookSvc.DeleteWebhookSubscription(ctx, &model.DeleteWebhookSubscriptionRequest{SubscriptionID: "sub-1"})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error if the subscription does not exist", func() {
+			ctx := context.WithValue(context.Background(), "userID", "admin-1")
+
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-404").Return(nil, model.ErrorWebhookSubscriptionNotFound)
+
+			err := webhookSvc.DeleteWebhookSubscription(ctx, &model.DeleteWebhookSubscriptionRequest{SubscriptionID: "sub-404"})
+			Expect(err).To(Equal(model.ErrorWebhookSubscriptionNotFound))
+		})
+	})
+
+	Context("DeliverWebhook", func() {
+		It("should send a signed payload and mark the delivery succeeded", func() {
+			ctx := context.Background()
+
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().CreateWebhookDeliveryAttempt(derivedContext(ctx), gomock.Any()).Return(nil)
+			mockWebhookRepo.EXPECT().RecordWebhookSubscriptionResult(derivedContext(ctx), "sub-1", true, 20).Return(subscription, nil)
+			mockWebhookRepo.EXPECT().UpdateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return(nil)
+
+			err := webhookSvc.DeliverWebhook(ctx, "delivery-1")
+			Expect(err).To(BeNil())
+			Expect(delivery.Status).To(Equal(model.WebhookDeliveryStatusSucceeded))
+			Expect(delivery.Attempts).To(Equal(1))
+
+			timestamp := strconv.FormatInt(now.Unix(), 10)
+			Expect(received.Header.Get(model.WebhookHeaderTimestamp)).To(Equal(timestamp))
+			Expect(received.Header.Get(model.WebhookHeaderEvent)).To(Equal(string(model.EventTypeLoanApproved)))
+			Expect(received.Header.Get(model.WebhookHeaderSignature)).To(Equal("v1=" + service.SignWebhookPayload("whsec_test_secret", now.Unix(), receivedBody)))
+			Expect(receivedBody).To(MatchJSON(delivery.Payload))
+		})
+
+		It("should reschedule a failed attempt with backoff", func() {
+			ctx := context.Background()
+			statusCode = http.StatusInternalServerError
+			nextAttemptAt := now.Add(30 * time.Second)
+
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().CreateWebhookDeliveryAttempt(derivedContext(ctx), gomock.Any()).Return(nil)
+			mockWebhookRepo.EXPECT().RecordWebhookSubscriptionResult(derivedContext(ctx), "sub-1", false, 20).Return(subscription, nil)
+			mockWebhookRepo.EXPECT().UpdateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return(nil)
+			mockJobSvc.EXPECT().EnqueueJob(derivedContext(ctx), model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: "delivery-1"}, &nextAttemptAt).Return("job-1", nil)
+
+			err := webhookSvc.DeliverWebhook(ctx, "delivery-1")
+			Expect(err).To(BeNil())
+			Expect(delivery.Status).To(Equal(model.WebhookDeliveryStatusPending))
+			Expect(delivery.LastStatusCode).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("should give up once the attempts are used", func() {
+			ctx := context.Background()
+			statusCode = http.StatusBadGateway
+			delivery.Attempts = 2
+
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().CreateWebhookDeliveryAttempt(derivedContext(ctx), gomock.Any()).Return(nil)
+			mockWebhookRepo.EXPECT().RecordWebhookSubscriptionResult(derivedContext(ctx), "sub-1", false, 20).Return(subscription, nil)
+			mockWebhookRepo.EXPECT().UpdateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return(nil)
+
+			err := webhookSvc.DeliverWebhook(ctx, "delivery-1")
+			Expect(err).To(BeNil())
+			Expect(delivery.Status).To(Equal(model.WebhookDeliveryStatusFailed))
+			Expect(delivery.NextAttemptAt).To(BeNil())
+		})
+
+		It("should stop retrying when the subscription gets disabled", func() {
+			ctx := context.Background()
+			statusCode = http.StatusServiceUnavailable
+			disabled := *subscription
+			disabled.IsActive = false
+
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().CreateWebhookDeliveryAttempt(derivedContext(ctx), gomock.Any()).Return(nil)
+			mockWebhookRepo.EXPECT().RecordWebhookSubscriptionResult(derivedContext(ctx), "sub-1", false, 20).Return(&disabled, nil)
+			mockWebhookRepo.EXPECT().UpdateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return(nil)
+
+			err := webhookSvc.DeliverWebhook(ctx, "delivery-1")
+			Expect(err).To(BeNil())
+			Expect(delivery.Status).To(Equal(model.WebhookDeliveryStatusFailed))
+		})
+
+		It("should skip a delivery that is not due yet", func() {
+			ctx := context.Background()
+			later := now.Add(time.Minute)
+			delivery.NextAttemptAt = &later
+
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+
+			err := webhookSvc.DeliverWebhook(ctx, "delivery-1")
+			Expect(err).To(BeNil())
+			Expect(received).To(BeNil())
+		})
+	})
+
+	Context("FanOutEvent", func() {
+		It("should queue a delivery per subscription and skip existing ones", func() {
+			ctx := context.Background()
+			other := &model.WebhookSubscription{ID: "sub-2", IsActive: true}
+			envelope := &model.EventEnvelope{EventID: "event-1", Type: model.EventTypeLoanApproved}
+
+			mockWebhookRepo.EXPECT().ListActiveWebhookSubscriptionsByEventType(derivedContext(ctx), model.EventTypeLoanApproved).Return([]*model.WebhookSubscription{subscription, other}, nil)
+			mockWebhookRepo.EXPECT().CreateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return("", model.ErrorWebhookDeliveryExist)
+			mockWebhookRepo.EXPECT().CreateWebhookDelivery(derivedContext(ctx), gomock.Any()).Return("delivery-2", nil)
+			mockJobSvc.EXPECT().EnqueueJob(derivedContext(ctx), model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: "delivery-2"}, nil).Return("job-1", nil)
+
+			err := webhookSvc.FanOutEvent(ctx, envelope)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("RedeliverWebhook", func() {
+		It("should not redeliver to an inactive subscription", func() {
+			ctx := context.Background()
+			subscription.IsActive = false
+
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+
+			resp, err := webhookSvc.RedeliverWebhook(ctx, &model.RedeliverWebhookRequest{SubscriptionID: "sub-1", DeliveryID: "delivery-1"})
+			Expect(err).To(Equal(model.ErrorWebhookSubscriptionInactive))
+			Expect(resp).To(BeNil())
+		})
+
+		It("should queue a failed delivery again", func() {
+			ctx := context.Background()
+			delivery.Status = model.WebhookDeliveryStatusFailed
+
+			mockWebhookRepo.EXPECT().GetWebhookSubscriptionByID(derivedContext(ctx), "sub-1").Return(subscription, nil)
+			mockWebhookRepo.EXPECT().GetWebhookDeliveryByID(derivedContext(ctx), "delivery-1").Return(delivery, nil)
+			mockWebhookRepo.EXPECT().UpdateWebhookDelivery(derivedContext(ctx), delivery).Return(nil)
+			mockJobSvc.EXPECT().EnqueueJob(derivedContext(ctx), model.JobTypeDeliverWebhook, &model.DeliverWebhookPayload{DeliveryID: "delivery-1"}, nil).Return("job-1", nil)
+
+			resp, err := webhookSvc.RedeliverWebhook(ctx, &model.RedeliverWebhookRequest{SubscriptionID: "sub-1", DeliveryID: "delivery-1"})
+			Expect(err).To(BeNil())
+			Expect(resp.Status).To(Equal(string(model.WebhookDeliveryStatusPending)))
+		})
+	})
+})